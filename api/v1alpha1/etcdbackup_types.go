@@ -24,6 +24,8 @@ import (
 type EtcdBackupPhase string
 
 const (
+	// EtcdBackupPhaseScheduled indicates the backup is a schedule policy that creates a child backup per run
+	EtcdBackupPhaseScheduled EtcdBackupPhase = "Scheduled"
 	// EtcdBackupPhaseRunning indicates the backup is running
 	EtcdBackupPhaseRunning EtcdBackupPhase = "Running"
 	// EtcdBackupPhaseCompleted indicates the backup is completed
@@ -44,6 +46,41 @@ const (
 	EtcdBackupStorageTypeLocal EtcdBackupStorageType = "Local"
 )
 
+// EtcdBackupConcurrencyPolicy describes how a scheduled run is handled while a previous run is still in progress
+// +kubebuilder:validation:Enum=Forbid;Replace
+type EtcdBackupConcurrencyPolicy string
+
+const (
+	// EtcdBackupConcurrencyForbid skips the new run if the previous one has not finished
+	EtcdBackupConcurrencyForbid EtcdBackupConcurrencyPolicy = "Forbid"
+	// EtcdBackupConcurrencyReplace cancels the running backup and starts the new one
+	EtcdBackupConcurrencyReplace EtcdBackupConcurrencyPolicy = "Replace"
+)
+
+// EtcdBackupMissedRunPolicy describes how runs missed while the operator was down are handled
+// +kubebuilder:validation:Enum=RunOnce;Skip
+type EtcdBackupMissedRunPolicy string
+
+const (
+	// EtcdBackupMissedRunOnce runs the most recent missed run once, however many were missed
+	EtcdBackupMissedRunOnce EtcdBackupMissedRunPolicy = "RunOnce"
+	// EtcdBackupMissedRunSkip skips missed runs and waits for the next fire time
+	EtcdBackupMissedRunSkip EtcdBackupMissedRunPolicy = "Skip"
+)
+
+// EtcdBackupCompressionCodec is the codec used to compress snapshots
+// +kubebuilder:validation:Enum=none;gzip;zstd
+type EtcdBackupCompressionCodec string
+
+const (
+	// EtcdBackupCompressionNone stores snapshots uncompressed
+	EtcdBackupCompressionNone EtcdBackupCompressionCodec = "none"
+	// EtcdBackupCompressionGzip compresses snapshots with gzip
+	EtcdBackupCompressionGzip EtcdBackupCompressionCodec = "gzip"
+	// EtcdBackupCompressionZstd compresses snapshots with zstd
+	EtcdBackupCompressionZstd EtcdBackupCompressionCodec = "zstd"
+)
+
 // EtcdS3BackupSpec defines S3 backup configuration
 type EtcdS3BackupSpec struct {
 	// Bucket is the S3 bucket name
@@ -52,25 +89,59 @@ type EtcdS3BackupSpec struct {
 	// Region is the S3 region
 	Region string `json:"region,omitempty"`
 
-	// Endpoint is the S3 endpoint URL
+	// Endpoint is the S3 endpoint URL. When set (e.g. for MinIO), path-style addressing is used;
+	// the scheme selects http or https.
 	Endpoint string `json:"endpoint,omitempty"`
 
-	// AccessKeySecret is the secret containing S3 access key
+	// AccessKeySecret is the secret containing S3 access key (under key "accessKey").
+	// When both secrets are empty, credentials are taken from the operator's environment or IAM role.
 	AccessKeySecret string `json:"accessKeySecret,omitempty"`
 
-	// SecretKeySecret is the secret containing S3 secret key
+	// SecretKeySecret is the secret containing S3 secret key (under key "secretKey").
+	// It may reference the same secret as AccessKeySecret.
 	SecretKeySecret string `json:"secretKeySecret,omitempty"`
 
 	// Path is the path prefix in the bucket
 	Path string `json:"path,omitempty"`
 }
 
-// EtcdRetentionPolicy defines backup retention policy
+// EtcdGCSBackupSpec defines GCS backup configuration
+type EtcdGCSBackupSpec struct {
+	// Bucket is the GCS bucket name
+	Bucket string `json:"bucket"`
+
+	// Prefix is the object name prefix in the bucket
+	Prefix string `json:"prefix,omitempty"`
+
+	// CredentialsSecret is the secret containing a service account key (under key "credentials.json").
+	// When empty, Application Default Credentials (e.g. Workload Identity) are used.
+	CredentialsSecret string `json:"credentialsSecret,omitempty"`
+}
+
+// EtcdBackupEncryptionSpec defines client-side AES-256-GCM encryption of backup snapshots
+type EtcdBackupEncryptionSpec struct {
+	// KeySecret is the name of the secret holding the encryption keys. Each data entry is one key:
+	// the entry name is the key ID and the value is 32 raw bytes or their base64 encoding.
+	// Keep retired keys in the secret for as long as snapshots encrypted with them are retained.
+	KeySecret string `json:"keySecret"`
+
+	// ActiveKeyID selects the key used to encrypt new snapshots.
+	// It may be omitted when the secret holds exactly one key.
+	ActiveKeyID string `json:"activeKeyID,omitempty"`
+}
+
+// EtcdRetentionPolicy defines backup retention policy.
+// It is enforced after each successful backup against the snapshots this backup created,
+// or for a scheduled backup the snapshots of all its runs. Snapshots are stored as
+// <cluster>/<timestamp>-<backup name>.db in the configured storage location, so manual
+// backups and other schedules of the same cluster are never pruned.
 type EtcdRetentionPolicy struct {
 	// MaxBackups is the maximum number of backups to retain
+	// +kubebuilder:validation:Minimum=0
 	MaxBackups int32 `json:"maxBackups,omitempty"`
 
-	// MaxAge is the maximum age of backups to retain
+	// MaxAge is the maximum age of backups to retain, as a Go duration
+	// with additional "d" (day) and "w" (week) units, e.g. "30d" or "1w12h"
 	MaxAge string `json:"maxAge,omitempty"`
 }
 
@@ -85,17 +156,42 @@ type EtcdBackupSpec struct {
 	// StorageType is the type of storage backend
 	StorageType EtcdBackupStorageType `json:"storageType"`
 
-	// Schedule is the cron schedule for automatic backups
+	// Schedule is the cron schedule for automatic backups.
+	// A backup with a schedule acts as a policy and creates a child EtcdBackup for each run.
 	Schedule string `json:"schedule,omitempty"`
 
+	// ConcurrencyPolicy specifies how to treat a scheduled run while the previous run is still in progress
+	// +kubebuilder:default=Forbid
+	ConcurrencyPolicy EtcdBackupConcurrencyPolicy `json:"concurrencyPolicy,omitempty"`
+
+	// MissedRunPolicy specifies how to treat runs that were missed while the operator was not running
+	// +kubebuilder:default=RunOnce
+	MissedRunPolicy EtcdBackupMissedRunPolicy `json:"missedRunPolicy,omitempty"`
+
 	// S3 configuration for S3 storage
 	S3 *EtcdS3BackupSpec `json:"s3,omitempty"`
 
+	// GCS configuration for GCS storage
+	GCS *EtcdGCSBackupSpec `json:"gcs,omitempty"`
+
 	// RetentionPolicy defines backup retention
 	RetentionPolicy EtcdRetentionPolicy `json:"retentionPolicy,omitempty"`
 
-	// Compression indicates whether to compress the backup
+	// Compression indicates whether to compress the backup.
+	// When true and CompressionCodec is not set, gzip is used.
 	Compression bool `json:"compression,omitempty"`
+
+	// CompressionCodec selects the codec used to compress the snapshot.
+	// It takes precedence over Compression.
+	CompressionCodec EtcdBackupCompressionCodec `json:"compressionCodec,omitempty"`
+
+	// Encryption enables client-side encryption of the snapshot before it is uploaded
+	Encryption *EtcdBackupEncryptionSpec `json:"encryption,omitempty"`
+
+	// VerifySnapshot additionally validates the snapshot contents after upload,
+	// like etcdutl snapshot status: the etcd integrity hash, the bbolt structure and the revision.
+	// The SHA-256 of the stored object is always verified.
+	VerifySnapshot bool `json:"verifySnapshot,omitempty"`
 }
 
 // EtcdBackupStatus defines the observed state of EtcdBackup
@@ -106,9 +202,24 @@ type EtcdBackupStatus struct {
 	// Conditions represent the latest available observations of the backup's state
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 
-	// BackupSize is the size of the backup in bytes
+	// BackupSize is the size of the stored backup object in bytes (after compression)
 	BackupSize int64 `json:"backupSize,omitempty"`
 
+	// Compression is the codec the stored snapshot was compressed with
+	Compression EtcdBackupCompressionCodec `json:"compression,omitempty"`
+
+	// RawSize is the size of the etcd snapshot in bytes before compression
+	RawSize int64 `json:"rawSize,omitempty"`
+
+	// CompressedSize is the size of the snapshot in bytes after compression, as stored
+	CompressedSize int64 `json:"compressedSize,omitempty"`
+
+	// EncryptionKeyID is the ID of the key the stored snapshot was encrypted with
+	EncryptionKeyID string `json:"encryptionKeyID,omitempty"`
+
+	// SHA256 is the hex-encoded SHA-256 checksum of the stored backup object
+	SHA256 string `json:"sha256,omitempty"`
+
 	// StartTime is the time when the backup started
 	StartTime *metav1.Time `json:"startTime,omitempty"`
 
@@ -123,6 +234,27 @@ type EtcdBackupStatus struct {
 
 	// EtcdRevision is the etcd revision that was backed up
 	EtcdRevision int64 `json:"etcdRevision,omitempty"`
+
+	// LastScheduleTime is the scheduled time of the last run (scheduled backups only)
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+
+	// NextScheduleTime is the time of the next scheduled run (scheduled backups only)
+	NextScheduleTime *metav1.Time `json:"nextScheduleTime,omitempty"`
+
+	// LastSuccessfulTime is the completion time of the last successful run (scheduled backups only)
+	LastSuccessfulTime *metav1.Time `json:"lastSuccessfulTime,omitempty"`
+
+	// Active lists the names of child backups that are still in progress (scheduled backups only)
+	Active []string `json:"active,omitempty"`
+
+	// LastPruneTime is the time the retention policy was last enforced
+	LastPruneTime *metav1.Time `json:"lastPruneTime,omitempty"`
+
+	// PrunedBackups lists the storage paths of the snapshots removed by the last retention pass
+	PrunedBackups []string `json:"prunedBackups,omitempty"`
+
+	// ObservedGeneration is the most recent generation observed by the controller (scheduled backups only)
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -132,6 +264,9 @@ type EtcdBackupStatus struct {
 // +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.clusterName"
 // +kubebuilder:printcolumn:name="Storage",type="string",JSONPath=".spec.storageType"
 // +kubebuilder:printcolumn:name="Size",type="string",JSONPath=".status.backupSize"
+// +kubebuilder:printcolumn:name="Compression",type="string",JSONPath=".status.compression",priority=1
+// +kubebuilder:printcolumn:name="Schedule",type="string",JSONPath=".spec.schedule",priority=1
+// +kubebuilder:printcolumn:name="Last Schedule",type="date",JSONPath=".status.lastScheduleTime",priority=1
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // EtcdBackup is the Schema for the etcdbackups API
@@ -71,17 +71,26 @@ type EtcdTLSSpec struct {
 	// +kubebuilder:default=true
 	PeerTLSEnabled bool `json:"peerTLSEnabled,omitempty"`
 
-	// CertificateSecret is the name of the secret containing TLS certificates
+	// CertificateSecret is the name of the secret containing TLS certificates.
+	// Used when AutoTLS is false; the tls.crt and tls.key keys hold a certificate
+	// valid for both server and client authentication that covers every member
+	// host, and with client TLS also the client service and localhost
 	// +kubebuilder:validation:Optional
 	CertificateSecret string `json:"certificateSecret,omitempty"`
 
-	// CASecret is the name of the secret containing the CA certificate
+	// CASecret is the name of the secret containing the CA certificate under the ca.crt key.
+	// Defaults to the ca.crt key of CertificateSecret
 	// +kubebuilder:validation:Optional
 	CASecret string `json:"caSecret,omitempty"`
 
 	// AutoTLS indicates whether to automatically generate TLS certificates
 	// +kubebuilder:default=true
 	AutoTLS bool `json:"autoTLS,omitempty"`
+
+	// RenewBefore is how long before expiry generated certificates are reissued
+	// and the members restarted one at a time to load them. Defaults to 720h
+	// +kubebuilder:validation:Optional
+	RenewBefore *metav1.Duration `json:"renewBefore,omitempty"`
 }
 
 // EtcdSecuritySpec defines security configuration for etcd
@@ -126,6 +135,169 @@ type EtcdClusterSpec struct {
 
 	// Resources configuration
 	Resources EtcdResourceSpec `json:"resources,omitempty"`
+
+	// Upgrade configures how changes to Version are rolled out
+	Upgrade EtcdUpgradeSpec `json:"upgrade,omitempty"`
+
+	// Recovery configures how the operator recovers the cluster after it lost quorum
+	Recovery EtcdRecoverySpec `json:"recovery,omitempty"`
+
+	// Maintenance configures compaction and defragmentation of the member databases
+	Maintenance EtcdMaintenanceSpec `json:"maintenance,omitempty"`
+
+	// Config tunes the etcd members. Changing it restarts the members one at a time
+	Config EtcdConfigSpec `json:"config,omitempty"`
+
+	// Scaling configures how members are added when the cluster scales up
+	Scaling EtcdScalingSpec `json:"scaling,omitempty"`
+}
+
+// EtcdUpgradeSpec defines how changes to the etcd version are rolled out
+type EtcdUpgradeSpec struct {
+	// BackupTemplate is the name of an EtcdBackup in the cluster namespace whose storage settings
+	// are used for the backup taken before each upgrade. Defaults to a scheduled EtcdBackup of the cluster
+	// +kubebuilder:validation:Optional
+	BackupTemplate string `json:"backupTemplate,omitempty"`
+
+	// SkipBackup upgrades the members without taking a backup first
+	// +kubebuilder:validation:Optional
+	SkipBackup bool `json:"skipBackup,omitempty"`
+}
+
+// EtcdRecoveryPolicy is how a cluster that lost quorum is recovered
+type EtcdRecoveryPolicy string
+
+const (
+	// EtcdRecoveryPolicyManual leaves the failed cluster alone until the members recover or an operator intervenes
+	EtcdRecoveryPolicyManual EtcdRecoveryPolicy = "Manual"
+	// EtcdRecoveryPolicyRestoreLatestBackup replaces the cluster data with the latest completed EtcdBackup of the cluster
+	EtcdRecoveryPolicyRestoreLatestBackup EtcdRecoveryPolicy = "RestoreLatestBackup"
+	// EtcdRecoveryPolicyForceNewCluster restarts the surviving member with the highest raft index as a
+	// single member cluster with --force-new-cluster, then adds the other members back with fresh data
+	EtcdRecoveryPolicyForceNewCluster EtcdRecoveryPolicy = "ForceNewCluster"
+)
+
+// EtcdRecoverySpec defines how a cluster that lost quorum is recovered
+type EtcdRecoverySpec struct {
+	// Policy is the recovery the operator runs once the cluster is marked Failed after losing quorum.
+	// Both automatic policies may lose the writes after the backup or the survivor's raft index
+	// +kubebuilder:validation:Enum=Manual;RestoreLatestBackup;ForceNewCluster
+	// +kubebuilder:default=Manual
+	// +kubebuilder:validation:Optional
+	Policy EtcdRecoveryPolicy `json:"policy,omitempty"`
+}
+
+// EtcdLearnerTimeoutPolicy is what the operator does with a learner that did not catch up with the leader in time
+type EtcdLearnerTimeoutPolicy string
+
+const (
+	// EtcdLearnerTimeoutPolicyWait keeps the learner and reports the cluster Degraded until it catches up
+	EtcdLearnerTimeoutPolicyWait EtcdLearnerTimeoutPolicy = "Wait"
+	// EtcdLearnerTimeoutPolicyRetry removes the learner, deletes its volume and adds the member again
+	// as a learner with fresh data
+	EtcdLearnerTimeoutPolicyRetry EtcdLearnerTimeoutPolicy = "Retry"
+)
+
+// EtcdScalingSpec defines how members are added when the cluster scales up. New members join as
+// non-voting learners and are promoted once they caught up with the leader, so quorum is not widened
+// while they are still empty
+type EtcdScalingSpec struct {
+	// LearnerTimeout is how long a learner may take to catch up with the leader before
+	// LearnerTimeoutPolicy applies. Defaults to 10m
+	// +kubebuilder:validation:Optional
+	LearnerTimeout *metav1.Duration `json:"learnerTimeout,omitempty"`
+
+	// LearnerTimeoutPolicy is what the operator does with a learner that did not catch up within LearnerTimeout
+	// +kubebuilder:validation:Enum=Wait;Retry
+	// +kubebuilder:default=Wait
+	// +kubebuilder:validation:Optional
+	LearnerTimeoutPolicy EtcdLearnerTimeoutPolicy `json:"learnerTimeoutPolicy,omitempty"`
+}
+
+// EtcdConfigSpec defines the etcd settings the members are started with. Unset fields keep etcd's defaults
+type EtcdConfigSpec struct {
+	// QuotaBackendBytes is the database size at which etcd raises a NOSPACE alarm. etcd defaults to 2Gi
+	// +kubebuilder:validation:Optional
+	QuotaBackendBytes *resource.Quantity `json:"quotaBackendBytes,omitempty"`
+
+	// SnapshotCount is the number of committed transactions that trigger a snapshot to disk
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Optional
+	SnapshotCount int64 `json:"snapshotCount,omitempty"`
+
+	// HeartbeatInterval is the leader heartbeat interval in milliseconds
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Optional
+	HeartbeatInterval int32 `json:"heartbeatInterval,omitempty"`
+
+	// ElectionTimeout is the election timeout in milliseconds, at least five times the heartbeat interval
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=50000
+	// +kubebuilder:validation:Optional
+	ElectionTimeout int32 `json:"electionTimeout,omitempty"`
+
+	// MaxRequestBytes is the largest client request in bytes the members accept
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Optional
+	MaxRequestBytes int64 `json:"maxRequestBytes,omitempty"`
+
+	// LogLevel is the etcd log level
+	// +kubebuilder:validation:Enum=debug;info;warn;error;panic;fatal
+	// +kubebuilder:validation:Optional
+	LogLevel string `json:"logLevel,omitempty"`
+
+	// ExtraFlags are further etcd config file settings such as experimental-* flags, keyed by the flag name
+	// without the leading dashes. Settings the operator manages or that have a field above are rejected
+	// +kubebuilder:validation:Optional
+	ExtraFlags map[string]string `json:"extraFlags,omitempty"`
+}
+
+// EtcdNoSpacePolicy is how the operator responds to a NOSPACE alarm
+type EtcdNoSpacePolicy string
+
+const (
+	// EtcdNoSpacePolicyNone only reports the alarm in the Degraded condition and events
+	EtcdNoSpacePolicyNone EtcdNoSpacePolicy = "None"
+	// EtcdNoSpacePolicyDefragment compacts the key space history, defragments the members one at a time
+	// and disarms the alarm
+	EtcdNoSpacePolicyDefragment EtcdNoSpacePolicy = "Defragment"
+	// EtcdNoSpacePolicyGrowQuota doubles the backend quota within the volume size, restarts the members one at a time
+	// and disarms the alarm. Falls back to Defragment when the volume has no headroom
+	EtcdNoSpacePolicyGrowQuota EtcdNoSpacePolicy = "GrowQuota"
+)
+
+// EtcdMaintenanceSpec defines how the member databases are compacted and defragmented
+type EtcdMaintenanceSpec struct {
+	// AutoCompactionMode is how etcd picks the revisions to compact: periodic keeps the history of the
+	// retention period, revision keeps the given number of revisions. Changing it restarts the members one at a time
+	// +kubebuilder:validation:Enum=periodic;revision
+	// +kubebuilder:validation:Optional
+	AutoCompactionMode string `json:"autoCompactionMode,omitempty"`
+
+	// AutoCompactionRetention is a duration such as "1h" in periodic mode or a revision count such as "10000"
+	// in revision mode. Auto compaction is disabled when unset
+	// +kubebuilder:validation:Optional
+	AutoCompactionRetention string `json:"autoCompactionRetention,omitempty"`
+
+	// DefragSchedule is a cron expression for when the members are defragmented, one member at a time
+	// with the leader last. Defragmentation is disabled when unset
+	// +kubebuilder:validation:Optional
+	DefragSchedule string `json:"defragSchedule,omitempty"`
+
+	// FragmentationThreshold is the percentage of a member's database file that must be free space
+	// for a scheduled run to defragment the member
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:default=20
+	// +kubebuilder:validation:Optional
+	FragmentationThreshold int32 `json:"fragmentationThreshold,omitempty"`
+
+	// NoSpacePolicy is how the operator responds when a member database exceeds the backend quota
+	// and etcd raises a NOSPACE alarm, leaving the cluster read-only
+	// +kubebuilder:validation:Enum=None;Defragment;GrowQuota
+	// +kubebuilder:default=None
+	// +kubebuilder:validation:Optional
+	NoSpacePolicy EtcdNoSpacePolicy `json:"noSpacePolicy,omitempty"`
 }
 
 // EtcdMember represents an etcd cluster member
@@ -145,10 +317,200 @@ type EtcdMember struct {
 	// Ready indicates if the member is ready
 	Ready bool `json:"ready,omitempty"`
 
-	// Role indicates the role of the member (leader/follower)
+	// Role indicates the role of the member (leader/follower/learner)
 	Role string `json:"role,omitempty"`
+
+	// UnhealthySince is when the member was first seen unhealthy, unset while it is ready
+	UnhealthySince *metav1.Time `json:"unhealthySince,omitempty"`
 }
 
+// EtcdTLSStatus records the transport security the cluster members are configured with.
+// It is set when the cluster is created; later changes to Spec.Security.TLS are applied to
+// the running cluster through a migration instead of switching the scheme at once.
+type EtcdTLSStatus struct {
+	// ClientTLS indicates members serve clients over https
+	ClientTLS bool `json:"clientTLS,omitempty"`
+
+	// PeerTLS indicates members talk to each other over https
+	PeerTLS bool `json:"peerTLS,omitempty"`
+
+	// Certificates lists the certificates the members use and when they expire
+	Certificates []EtcdCertificateStatus `json:"certificates,omitempty"`
+
+	// Revision is a digest of the certificate material the members were last restarted with
+	Revision string `json:"revision,omitempty"`
+
+	// CARotation is the current phase of a generated CA rotation, empty when none is in progress
+	CARotation EtcdCARotationPhase `json:"caRotation,omitempty"`
+
+	// Migration is the current phase of moving the members to the transport security
+	// requested in Spec.Security.TLS, empty when none is in progress
+	Migration EtcdTLSMigrationPhase `json:"migration,omitempty"`
+}
+
+// EtcdUpgradeStatus records a version upgrade or downgrade in progress
+type EtcdUpgradeStatus struct {
+	// FromVersion is the version the members ran before the upgrade
+	FromVersion string `json:"fromVersion"`
+
+	// ToVersion is the version the members are upgraded to
+	ToVersion string `json:"toVersion"`
+
+	// Downgrade indicates ToVersion is one minor version lower than FromVersion. The cluster
+	// version is lowered through the etcd downgrade API before the first member is restarted
+	Downgrade bool `json:"downgrade,omitempty"`
+
+	// BackupName is the EtcdBackup taken before the first member was restarted
+	BackupName string `json:"backupName,omitempty"`
+
+	// MemberRestartTime is when the member currently being upgraded was released for restart
+	MemberRestartTime *metav1.Time `json:"memberRestartTime,omitempty"`
+}
+
+// EtcdMemberReplacementStatus records a failed member being replaced with a fresh one
+type EtcdMemberReplacementStatus struct {
+	// Member is the name of the member being replaced
+	Member string `json:"member"`
+
+	// MemberID is the etcd ID of the failed member removed from the cluster
+	MemberID string `json:"memberID,omitempty"`
+
+	// StartTime is when the replacement started. The member's volume created before it is deleted
+	StartTime metav1.Time `json:"startTime"`
+}
+
+// EtcdScalingStatus records the scale up in progress
+type EtcdScalingStatus struct {
+	// Learner is the member added as a learner and not promoted yet, empty between members
+	Learner string `json:"learner,omitempty"`
+
+	// LearnerID is the etcd ID of the learner
+	LearnerID string `json:"learnerID,omitempty"`
+
+	// StartTime is when the learner was added. LearnerTimeout counts from it
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// RaftIndex is the raft index of the learner at the last check
+	RaftIndex int64 `json:"raftIndex,omitempty"`
+
+	// LeaderRaftIndex is the raft index of the leader at the last check
+	LeaderRaftIndex int64 `json:"leaderRaftIndex,omitempty"`
+
+	// ProgressTime is when RaftIndex and LeaderRaftIndex were last recorded
+	ProgressTime *metav1.Time `json:"progressTime,omitempty"`
+
+	// Retries is how many times a learner that timed out was added again with fresh data
+	Retries int32 `json:"retries,omitempty"`
+}
+
+// EtcdRecoveryStatus records the recovery of a cluster that lost quorum
+type EtcdRecoveryStatus struct {
+	// Policy is the recovery policy in use when the recovery started
+	Policy EtcdRecoveryPolicy `json:"policy"`
+
+	// Member is the surviving member restarted with --force-new-cluster
+	Member string `json:"member,omitempty"`
+
+	// RestoreName is the EtcdRestore replacing the cluster data from the latest backup
+	RestoreName string `json:"restoreName,omitempty"`
+
+	// StartTime is when the recovery started. Volumes of the other members created before it are deleted
+	StartTime metav1.Time `json:"startTime"`
+}
+
+// EtcdMaintenanceStatus records the compaction settings the members run with and their defragmentation
+type EtcdMaintenanceStatus struct {
+	// AutoCompactionMode is the auto compaction mode the members run with
+	AutoCompactionMode string `json:"autoCompactionMode,omitempty"`
+
+	// AutoCompactionRetention is the auto compaction retention the members run with
+	AutoCompactionRetention string `json:"autoCompactionRetention,omitempty"`
+
+	// DefragRunTime is the scheduled time of the defragmentation run in progress
+	DefragRunTime *metav1.Time `json:"defragRunTime,omitempty"`
+
+	// LastDefragRunTime is the scheduled time of the last defragmentation run started
+	LastDefragRunTime *metav1.Time `json:"lastDefragRunTime,omitempty"`
+
+	// Members records the last defragmentation of each member
+	Members []EtcdMemberDefragStatus `json:"members,omitempty"`
+
+	// QuotaBackendBytes is the backend quota the members run with, etcd's default quota when unset
+	QuotaBackendBytes int64 `json:"quotaBackendBytes,omitempty"`
+
+	// NoSpace tracks the active NOSPACE alarm and its remediation
+	NoSpace *EtcdNoSpaceStatus `json:"noSpace,omitempty"`
+}
+
+// EtcdNoSpaceStatus tracks the remediation of a NOSPACE alarm
+type EtcdNoSpaceStatus struct {
+	// StartTime is when the operator first saw the alarm. Members defragmented before it are defragmented again
+	StartTime metav1.Time `json:"startTime"`
+
+	// Action is the remediation in progress, empty when the alarm is left to the user
+	Action EtcdNoSpacePolicy `json:"action,omitempty"`
+
+	// CompactedRevision is the revision the key space history was compacted to before defragmenting
+	CompactedRevision int64 `json:"compactedRevision,omitempty"`
+
+	// QuotaBackendBytes is the grown backend quota the members are restarted with
+	QuotaBackendBytes int64 `json:"quotaBackendBytes,omitempty"`
+}
+
+// EtcdMemberDefragStatus records the last defragmentation of a member
+type EtcdMemberDefragStatus struct {
+	// Name is the name of the member
+	Name string `json:"name"`
+
+	// LastDefragTime is when the member was last defragmented
+	LastDefragTime metav1.Time `json:"lastDefragTime"`
+
+	// ReclaimedBytes is how much the last defragmentation shrank the member's database file
+	ReclaimedBytes int64 `json:"reclaimedBytes"`
+}
+
+// EtcdCertificateStatus describes a certificate used by the cluster members
+type EtcdCertificateStatus struct {
+	// Name identifies the certificate: ca, peer, server, client or external
+	Name string `json:"name"`
+
+	// SecretName is the secret holding the certificate
+	SecretName string `json:"secretName"`
+
+	// NotAfter is the time the certificate expires
+	NotAfter metav1.Time `json:"notAfter"`
+}
+
+// EtcdCARotationPhase is a phase of replacing the CA generated for AutoTLS.
+// Every phase ends once all members have been restarted with its certificates.
+type EtcdCARotationPhase string
+
+const (
+	// EtcdCARotationTrustBoth members trust both the current and the next CA
+	EtcdCARotationTrustBoth EtcdCARotationPhase = "TrustBoth"
+	// EtcdCARotationIssueNew member certificates are reissued by the next CA, both CAs stay trusted
+	EtcdCARotationIssueNew EtcdCARotationPhase = "IssueNew"
+	// EtcdCARotationDropOld the next CA replaces the current one and the old CA is no longer trusted
+	EtcdCARotationDropOld EtcdCARotationPhase = "DropOld"
+)
+
+// EtcdTLSMigrationPhase is a phase of switching a running cluster between http and https.
+// Peer and client transport are migrated separately, and every phase ends once all members
+// have been restarted with its configuration.
+type EtcdTLSMigrationPhase string
+
+const (
+	// EtcdTLSMigrationPeerTrust members get peer certificates so they can reach peers over https,
+	// while still listening on their current peer scheme
+	EtcdTLSMigrationPeerTrust EtcdTLSMigrationPhase = "PeerTrust"
+	// EtcdTLSMigrationPeerSwitch each member's peer URL is updated to the new scheme right before it restarts
+	EtcdTLSMigrationPeerSwitch EtcdTLSMigrationPhase = "PeerSwitch"
+	// EtcdTLSMigrationClientDual members serve clients over both http and https on the client port
+	EtcdTLSMigrationClientDual EtcdTLSMigrationPhase = "ClientDual"
+	// EtcdTLSMigrationClientSwitch members advertise the new client scheme while still serving both
+	EtcdTLSMigrationClientSwitch EtcdTLSMigrationPhase = "ClientSwitch"
+)
+
 // EtcdClusterStatus defines the observed state of EtcdCluster
 type EtcdClusterStatus struct {
 	// Phase is the current phase of the etcd cluster
@@ -172,6 +534,32 @@ type EtcdClusterStatus struct {
 	// ClientEndpoints are the client endpoints of the etcd cluster
 	ClientEndpoints []string `json:"clientEndpoints,omitempty"`
 
+	// TLS is the transport security the members are configured with
+	TLS *EtcdTLSStatus `json:"tls,omitempty"`
+
+	// CurrentVersion is the etcd version the members run. Changes to Spec.Version
+	// reach the members through an upgrade recorded in Upgrade
+	CurrentVersion string `json:"currentVersion,omitempty"`
+
+	// Upgrade tracks the version upgrade or downgrade in progress
+	Upgrade *EtcdUpgradeStatus `json:"upgrade,omitempty"`
+
+	// MemberReplacement tracks the failed member being replaced
+	MemberReplacement *EtcdMemberReplacementStatus `json:"memberReplacement,omitempty"`
+
+	// Recovery tracks the recovery of the cluster after it lost quorum
+	Recovery *EtcdRecoveryStatus `json:"recovery,omitempty"`
+
+	// Scaling tracks the learner added by the scale up in progress
+	Scaling *EtcdScalingStatus `json:"scaling,omitempty"`
+
+	// Maintenance records the compaction settings and defragmentation of the members
+	Maintenance *EtcdMaintenanceStatus `json:"maintenance,omitempty"`
+
+	// Config is the etcd configuration the members run with. Changes to Spec.Config
+	// reach the members by restarting them one at a time
+	Config *EtcdConfigSpec `json:"config,omitempty"`
+
 	// LastBackupTime is the time of the last successful backup
 	LastBackupTime *metav1.Time `json:"lastBackupTime,omitempty"`
 
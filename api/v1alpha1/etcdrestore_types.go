@@ -42,10 +42,93 @@ const (
 	EtcdRestoreTypeNew EtcdRestoreType = "New"
 )
 
+// EtcdRestoreSource defines a snapshot location outside of any EtcdBackup, such as a snapshot
+// taken from an etcd running on VMs or in another Kubernetes cluster. Exactly one of S3, GCS, PVC
+// and HTTP must be set. The snapshot may be a plain etcd snapshot or one written by the operator
+// (compressed and/or encrypted); the format is detected from its header.
+type EtcdRestoreSource struct {
+	// S3 reads the snapshot from an S3 compatible object store
+	S3 *EtcdS3RestoreSource `json:"s3,omitempty"`
+
+	// GCS reads the snapshot from Google Cloud Storage
+	GCS *EtcdGCSRestoreSource `json:"gcs,omitempty"`
+
+	// PVC reads the snapshot from a file on a PersistentVolumeClaim in the restore's namespace
+	PVC *EtcdPVCRestoreSource `json:"pvc,omitempty"`
+
+	// HTTP downloads the snapshot from an HTTP(S) URL
+	HTTP *EtcdHTTPRestoreSource `json:"http,omitempty"`
+
+	// SHA256 is the expected hex encoded SHA-256 of the snapshot object as stored.
+	// When set, the download is rejected on mismatch unless SkipHashCheck is true.
+	// +kubebuilder:validation:Pattern=`^[0-9a-fA-F]{64}$`
+	SHA256 string `json:"sha256,omitempty"`
+
+	// Encryption references the keys used to decrypt an encrypted snapshot.
+	// ActiveKeyID is ignored: the key is selected by the ID recorded in the snapshot header.
+	Encryption *EtcdBackupEncryptionSpec `json:"encryption,omitempty"`
+}
+
+// EtcdS3RestoreSource defines a snapshot object in S3
+type EtcdS3RestoreSource struct {
+	// URL is the snapshot object URL in the form s3://bucket/key
+	// +kubebuilder:validation:Pattern=`^s3://[^/]+/.+$`
+	URL string `json:"url"`
+
+	// Region is the S3 region
+	Region string `json:"region,omitempty"`
+
+	// Endpoint is the S3 endpoint URL, e.g. for MinIO
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// AccessKeySecret is the secret containing S3 access key (under key "accessKey")
+	AccessKeySecret string `json:"accessKeySecret,omitempty"`
+
+	// SecretKeySecret is the secret containing S3 secret key (under key "secretKey")
+	SecretKeySecret string `json:"secretKeySecret,omitempty"`
+}
+
+// EtcdGCSRestoreSource defines a snapshot object in GCS
+type EtcdGCSRestoreSource struct {
+	// URL is the snapshot object URL in the form gs://bucket/object
+	// +kubebuilder:validation:Pattern=`^gs://[^/]+/.+$`
+	URL string `json:"url"`
+
+	// CredentialsSecret is the secret containing a service account key (under key "credentials.json").
+	// When empty, Application Default Credentials are used.
+	CredentialsSecret string `json:"credentialsSecret,omitempty"`
+}
+
+// EtcdPVCRestoreSource defines a snapshot file on a PersistentVolumeClaim.
+// The claim is mounted read-only by a short-lived pod while the snapshot is fetched,
+// so a ReadWriteOnce claim must not be in use on another node.
+type EtcdPVCRestoreSource struct {
+	// ClaimName is the name of the PersistentVolumeClaim
+	ClaimName string `json:"claimName"`
+
+	// Path is the path of the snapshot file relative to the root of the volume
+	Path string `json:"path"`
+}
+
+// EtcdHTTPRestoreSource defines a snapshot downloadable over HTTP(S)
+type EtcdHTTPRestoreSource struct {
+	// URL is the snapshot URL
+	// +kubebuilder:validation:Pattern=`^https?://.+$`
+	URL string `json:"url"`
+
+	// HeadersSecret is the secret whose data entries are sent as request headers,
+	// e.g. an "Authorization" entry
+	HeadersSecret string `json:"headersSecret,omitempty"`
+}
+
 // EtcdRestoreSpec defines the desired state of EtcdRestore
 type EtcdRestoreSpec struct {
-	// BackupName is the name of the EtcdBackup to restore from
-	BackupName string `json:"backupName"`
+	// BackupName is the name of the EtcdBackup to restore from.
+	// Exactly one of BackupName and Source must be set.
+	BackupName string `json:"backupName,omitempty"`
+
+	// Source reads the snapshot directly from storage without an EtcdBackup
+	Source *EtcdRestoreSource `json:"source,omitempty"`
 
 	// BackupNamespace is the namespace of the EtcdBackup
 	BackupNamespace string `json:"backupNamespace,omitempty"`
@@ -56,13 +139,17 @@ type EtcdRestoreSpec struct {
 	// ClusterTemplate is the template for creating a new cluster (for new restore type)
 	ClusterTemplate *EtcdClusterSpec `json:"clusterTemplate,omitempty"`
 
-	// RestoreType is the type of restore operation
+	// RestoreType is the type of restore operation.
+	// New creates the cluster ClusterName from ClusterTemplate with every member seeded from the snapshot
+	// +kubebuilder:validation:Enum=Replace;New
+	// +kubebuilder:default=New
 	RestoreType EtcdRestoreType `json:"restoreType,omitempty"`
 
 	// DataDir is the data directory for etcd
 	DataDir string `json:"dataDir,omitempty"`
 
-	// SkipHashCheck skips hash check during restore
+	// SkipHashCheck skips hash check during restore: both the SHA-256 recorded in the
+	// EtcdBackup status (or Source.SHA256) and the integrity hash etcd appends to snapshots are ignored
 	SkipHashCheck bool `json:"skipHashCheck,omitempty"`
 
 	// WalDir is the WAL directory for etcd
@@ -86,7 +173,7 @@ type EtcdRestoreStatus struct {
 	// RestoredCluster is the name of the restored cluster
 	RestoredCluster string `json:"restoredCluster,omitempty"`
 
-	// RestoredSize is the size of the restored data in bytes
+	// RestoredSize is the size of the restored snapshot in bytes, after decryption and decompression
 	RestoredSize int64 `json:"restoredSize,omitempty"`
 }
 
@@ -97,6 +184,7 @@ type EtcdRestoreStatus struct {
 // +kubebuilder:printcolumn:name="Backup",type="string",JSONPath=".spec.backupName"
 // +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.clusterName"
 // +kubebuilder:printcolumn:name="Type",type="string",JSONPath=".spec.restoreType"
+// +kubebuilder:printcolumn:name="Size",type="integer",JSONPath=".status.restoredSize",priority=1
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // EtcdRestore is the Schema for the etcdrestores API
@@ -53,6 +53,21 @@ func (in *EtcdBackup) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdBackupEncryptionSpec) DeepCopyInto(out *EtcdBackupEncryptionSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdBackupEncryptionSpec.
+func (in *EtcdBackupEncryptionSpec) DeepCopy() *EtcdBackupEncryptionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdBackupEncryptionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EtcdBackupList) DeepCopyInto(out *EtcdBackupList) {
 	*out = *in
@@ -93,7 +108,17 @@ func (in *EtcdBackupSpec) DeepCopyInto(out *EtcdBackupSpec) {
 		*out = new(EtcdS3BackupSpec)
 		**out = **in
 	}
+	if in.GCS != nil {
+		in, out := &in.GCS, &out.GCS
+		*out = new(EtcdGCSBackupSpec)
+		**out = **in
+	}
 	out.RetentionPolicy = in.RetentionPolicy
+	if in.Encryption != nil {
+		in, out := &in.Encryption, &out.Encryption
+		*out = new(EtcdBackupEncryptionSpec)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdBackupSpec.
@@ -124,6 +149,32 @@ func (in *EtcdBackupStatus) DeepCopyInto(out *EtcdBackupStatus) {
 		in, out := &in.CompletionTime, &out.CompletionTime
 		*out = (*in).DeepCopy()
 	}
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.NextScheduleTime != nil {
+		in, out := &in.NextScheduleTime, &out.NextScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastSuccessfulTime != nil {
+		in, out := &in.LastSuccessfulTime, &out.LastSuccessfulTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Active != nil {
+		in, out := &in.Active, &out.Active
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastPruneTime != nil {
+		in, out := &in.LastPruneTime, &out.LastPruneTime
+		*out = (*in).DeepCopy()
+	}
+	if in.PrunedBackups != nil {
+		in, out := &in.PrunedBackups, &out.PrunedBackups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdBackupStatus.
@@ -136,6 +187,22 @@ func (in *EtcdBackupStatus) DeepCopy() *EtcdBackupStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdCertificateStatus) DeepCopyInto(out *EtcdCertificateStatus) {
+	*out = *in
+	in.NotAfter.DeepCopyInto(&out.NotAfter)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdCertificateStatus.
+func (in *EtcdCertificateStatus) DeepCopy() *EtcdCertificateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdCertificateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EtcdCluster) DeepCopyInto(out *EtcdCluster) {
 	*out = *in
@@ -199,8 +266,13 @@ func (in *EtcdClusterList) DeepCopyObject() runtime.Object {
 func (in *EtcdClusterSpec) DeepCopyInto(out *EtcdClusterSpec) {
 	*out = *in
 	in.Storage.DeepCopyInto(&out.Storage)
-	out.Security = in.Security
+	in.Security.DeepCopyInto(&out.Security)
 	in.Resources.DeepCopyInto(&out.Resources)
+	out.Upgrade = in.Upgrade
+	out.Recovery = in.Recovery
+	out.Maintenance = in.Maintenance
+	in.Config.DeepCopyInto(&out.Config)
+	in.Scaling.DeepCopyInto(&out.Scaling)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdClusterSpec.
@@ -226,13 +298,50 @@ func (in *EtcdClusterStatus) DeepCopyInto(out *EtcdClusterStatus) {
 	if in.Members != nil {
 		in, out := &in.Members, &out.Members
 		*out = make([]EtcdMember, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.ClientEndpoints != nil {
 		in, out := &in.ClientEndpoints, &out.ClientEndpoints
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(EtcdTLSStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Upgrade != nil {
+		in, out := &in.Upgrade, &out.Upgrade
+		*out = new(EtcdUpgradeStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MemberReplacement != nil {
+		in, out := &in.MemberReplacement, &out.MemberReplacement
+		*out = new(EtcdMemberReplacementStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Recovery != nil {
+		in, out := &in.Recovery, &out.Recovery
+		*out = new(EtcdRecoveryStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Scaling != nil {
+		in, out := &in.Scaling, &out.Scaling
+		*out = new(EtcdScalingStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Maintenance != nil {
+		in, out := &in.Maintenance, &out.Maintenance
+		*out = new(EtcdMaintenanceStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = new(EtcdConfigSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.LastBackupTime != nil {
 		in, out := &in.LastBackupTime, &out.LastBackupTime
 		*out = (*in).DeepCopy()
@@ -253,9 +362,135 @@ func (in *EtcdClusterStatus) DeepCopy() *EtcdClusterStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdGCSBackupSpec) DeepCopyInto(out *EtcdGCSBackupSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdGCSBackupSpec.
+func (in *EtcdGCSBackupSpec) DeepCopy() *EtcdGCSBackupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdGCSBackupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdGCSRestoreSource) DeepCopyInto(out *EtcdGCSRestoreSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdGCSRestoreSource.
+func (in *EtcdGCSRestoreSource) DeepCopy() *EtcdGCSRestoreSource {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdGCSRestoreSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdHTTPRestoreSource) DeepCopyInto(out *EtcdHTTPRestoreSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdHTTPRestoreSource.
+func (in *EtcdHTTPRestoreSource) DeepCopy() *EtcdHTTPRestoreSource {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdHTTPRestoreSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdConfigSpec) DeepCopyInto(out *EtcdConfigSpec) {
+	*out = *in
+	if in.QuotaBackendBytes != nil {
+		in, out := &in.QuotaBackendBytes, &out.QuotaBackendBytes
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.ExtraFlags != nil {
+		in, out := &in.ExtraFlags, &out.ExtraFlags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdConfigSpec.
+func (in *EtcdConfigSpec) DeepCopy() *EtcdConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdMaintenanceSpec) DeepCopyInto(out *EtcdMaintenanceSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdMaintenanceSpec.
+func (in *EtcdMaintenanceSpec) DeepCopy() *EtcdMaintenanceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdMaintenanceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdMaintenanceStatus) DeepCopyInto(out *EtcdMaintenanceStatus) {
+	*out = *in
+	if in.DefragRunTime != nil {
+		in, out := &in.DefragRunTime, &out.DefragRunTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastDefragRunTime != nil {
+		in, out := &in.LastDefragRunTime, &out.LastDefragRunTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]EtcdMemberDefragStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NoSpace != nil {
+		in, out := &in.NoSpace, &out.NoSpace
+		*out = new(EtcdNoSpaceStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdMaintenanceStatus.
+func (in *EtcdMaintenanceStatus) DeepCopy() *EtcdMaintenanceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdMaintenanceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EtcdMember) DeepCopyInto(out *EtcdMember) {
 	*out = *in
+	if in.UnhealthySince != nil {
+		in, out := &in.UnhealthySince, &out.UnhealthySince
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdMember.
@@ -268,6 +503,100 @@ func (in *EtcdMember) DeepCopy() *EtcdMember {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdMemberDefragStatus) DeepCopyInto(out *EtcdMemberDefragStatus) {
+	*out = *in
+	in.LastDefragTime.DeepCopyInto(&out.LastDefragTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdMemberDefragStatus.
+func (in *EtcdMemberDefragStatus) DeepCopy() *EtcdMemberDefragStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdMemberDefragStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdMemberReplacementStatus) DeepCopyInto(out *EtcdMemberReplacementStatus) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdMemberReplacementStatus.
+func (in *EtcdMemberReplacementStatus) DeepCopy() *EtcdMemberReplacementStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdMemberReplacementStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdPVCRestoreSource) DeepCopyInto(out *EtcdPVCRestoreSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdPVCRestoreSource.
+func (in *EtcdPVCRestoreSource) DeepCopy() *EtcdPVCRestoreSource {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdPVCRestoreSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdNoSpaceStatus) DeepCopyInto(out *EtcdNoSpaceStatus) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdNoSpaceStatus.
+func (in *EtcdNoSpaceStatus) DeepCopy() *EtcdNoSpaceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdNoSpaceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdRecoverySpec) DeepCopyInto(out *EtcdRecoverySpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdRecoverySpec.
+func (in *EtcdRecoverySpec) DeepCopy() *EtcdRecoverySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdRecoverySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdRecoveryStatus) DeepCopyInto(out *EtcdRecoveryStatus) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdRecoveryStatus.
+func (in *EtcdRecoveryStatus) DeepCopy() *EtcdRecoveryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdRecoveryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EtcdResourceSpec) DeepCopyInto(out *EtcdResourceSpec) {
 	*out = *in
@@ -356,9 +685,54 @@ func (in *EtcdRestoreList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdRestoreSource) DeepCopyInto(out *EtcdRestoreSource) {
+	*out = *in
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(EtcdS3RestoreSource)
+		**out = **in
+	}
+	if in.GCS != nil {
+		in, out := &in.GCS, &out.GCS
+		*out = new(EtcdGCSRestoreSource)
+		**out = **in
+	}
+	if in.PVC != nil {
+		in, out := &in.PVC, &out.PVC
+		*out = new(EtcdPVCRestoreSource)
+		**out = **in
+	}
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(EtcdHTTPRestoreSource)
+		**out = **in
+	}
+	if in.Encryption != nil {
+		in, out := &in.Encryption, &out.Encryption
+		*out = new(EtcdBackupEncryptionSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdRestoreSource.
+func (in *EtcdRestoreSource) DeepCopy() *EtcdRestoreSource {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdRestoreSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EtcdRestoreSpec) DeepCopyInto(out *EtcdRestoreSpec) {
 	*out = *in
+	if in.Source != nil {
+		in, out := &in.Source, &out.Source
+		*out = new(EtcdRestoreSource)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ClusterTemplate != nil {
 		in, out := &in.ClusterTemplate, &out.ClusterTemplate
 		*out = new(EtcdClusterSpec)
@@ -436,10 +810,68 @@ func (in *EtcdS3BackupSpec) DeepCopy() *EtcdS3BackupSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdS3RestoreSource) DeepCopyInto(out *EtcdS3RestoreSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdS3RestoreSource.
+func (in *EtcdS3RestoreSource) DeepCopy() *EtcdS3RestoreSource {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdS3RestoreSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdScalingSpec) DeepCopyInto(out *EtcdScalingSpec) {
+	*out = *in
+	if in.LearnerTimeout != nil {
+		in, out := &in.LearnerTimeout, &out.LearnerTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdScalingSpec.
+func (in *EtcdScalingSpec) DeepCopy() *EtcdScalingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdScalingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdScalingStatus) DeepCopyInto(out *EtcdScalingStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ProgressTime != nil {
+		in, out := &in.ProgressTime, &out.ProgressTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdScalingStatus.
+func (in *EtcdScalingStatus) DeepCopy() *EtcdScalingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdScalingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EtcdSecuritySpec) DeepCopyInto(out *EtcdSecuritySpec) {
 	*out = *in
-	out.TLS = in.TLS
+	in.TLS.DeepCopyInto(&out.TLS)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdSecuritySpec.
@@ -481,6 +913,11 @@ func (in *EtcdStorageSpec) DeepCopy() *EtcdStorageSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EtcdTLSSpec) DeepCopyInto(out *EtcdTLSSpec) {
 	*out = *in
+	if in.RenewBefore != nil {
+		in, out := &in.RenewBefore, &out.RenewBefore
+		*out = new(v1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdTLSSpec.
@@ -492,3 +929,59 @@ func (in *EtcdTLSSpec) DeepCopy() *EtcdTLSSpec {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdTLSStatus) DeepCopyInto(out *EtcdTLSStatus) {
+	*out = *in
+	if in.Certificates != nil {
+		in, out := &in.Certificates, &out.Certificates
+		*out = make([]EtcdCertificateStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdTLSStatus.
+func (in *EtcdTLSStatus) DeepCopy() *EtcdTLSStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdTLSStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdUpgradeSpec) DeepCopyInto(out *EtcdUpgradeSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdUpgradeSpec.
+func (in *EtcdUpgradeSpec) DeepCopy() *EtcdUpgradeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdUpgradeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdUpgradeStatus) DeepCopyInto(out *EtcdUpgradeStatus) {
+	*out = *in
+	if in.MemberRestartTime != nil {
+		in, out := &in.MemberRestartTime, &out.MemberRestartTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdUpgradeStatus.
+func (in *EtcdUpgradeStatus) DeepCopy() *EtcdUpgradeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdUpgradeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
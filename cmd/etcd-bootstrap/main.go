@@ -0,0 +1,95 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// etcd-bootstrap 在成员 Pod 的 init 容器中运行，确定成员的启动方式并生成 etcd 配置文件
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+	"github.com/your-org/etcd-k8s-operator/pkg/bootstrap"
+	etcdclient "github.com/your-org/etcd-k8s-operator/pkg/etcd"
+	"github.com/your-org/etcd-k8s-operator/pkg/utils"
+)
+
+func main() {
+	var opts bootstrap.Options
+	var endpoints string
+	flag.StringVar(&opts.Name, "name", os.Getenv("POD_NAME"), "The member name, which is the pod name ending in -<ordinal>.")
+	flag.StringVar(&opts.DataDir, "data-dir", utils.EtcdDataDir, "The etcd data directory, inspected for existing member data.")
+	flag.StringVar(&opts.ConfigFile, "config-file", "/etc/etcd/etcd.conf", "The etcd config file to write.")
+	flag.StringVar(&opts.PeerDomain, "peer-domain", "", "The domain of the member host names, <name>.<peer-domain>.")
+	flag.StringVar(&opts.ClientScheme, "client-scheme", "http", "The scheme of the member client URLs.")
+	flag.StringVar(&opts.PeerScheme, "peer-scheme", "http", "The scheme of the member peer URLs.")
+	flag.StringVar(&opts.ListenClientURLs, "listen-client-urls", fmt.Sprintf("http://0.0.0.0:%d", utils.EtcdClientPort),
+		"The client URLs the member listens on.")
+	flag.StringVar(&opts.ClusterToken, "cluster-token", "", "The initial-cluster-token of a new cluster.")
+	flag.StringVar(&endpoints, "endpoints", "", "Comma separated client endpoints to list the cluster members from.")
+	flag.StringVar(&opts.ServerName, "server-name", "", "The host name to verify the member server certificates with.")
+	flag.StringVar(&opts.CAFile, "ca-file", "", "The CA certificate for client TLS. Leave empty for plaintext endpoints.")
+	flag.StringVar(&opts.CertFile, "cert-file", "", "The client certificate for client TLS.")
+	flag.StringVar(&opts.KeyFile, "key-file", "", "The client key for client TLS.")
+	flag.StringVar(&opts.InitialClusterState, "initial-cluster-state", os.Getenv("INITIAL_CLUSTER_STATE"),
+		"Set to new to let member 0 start a new cluster when no member is reachable. Only set while the cluster is being created.")
+	flag.StringVar(&opts.ForceNewCluster, "force-new-cluster", os.Getenv("FORCE_NEW_CLUSTER"),
+		"Start with force-new-cluster when it equals the member name.")
+	flag.StringVar(&opts.ExtraConfig, "extra-config", os.Getenv("ETCD_EXTRA_CONFIG"), "Config file settings appended to the generated ones.")
+	flag.DurationVar(&opts.JoinTimeout, "join-timeout", 5*time.Minute, "How long to wait for the operator to add the member.")
+	flag.DurationVar(&opts.RetryInterval, "retry-interval", 2*time.Second, "The interval between member list queries.")
+	flag.Parse()
+	if endpoints != "" {
+		opts.Endpoints = strings.Split(endpoints, ",")
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+	if err := run(ctx, opts); err != nil {
+		fmt.Fprintf(os.Stderr, "etcd-bootstrap failed for %s: %v\n", opts.Name, err)
+		os.Exit(1)
+	}
+}
+
+// run 创建查询成员列表的 etcd 客户端，并生成配置文件
+func run(ctx context.Context, opts bootstrap.Options) error {
+	tlsConfig, err := opts.TLSConfig()
+	if err != nil {
+		return err
+	}
+	var etcdClient *etcdclient.Client
+	defer func() {
+		if etcdClient != nil {
+			etcdClient.Close()
+		}
+	}()
+
+	list := func(ctx context.Context) ([]etcdv1alpha1.EtcdMember, error) {
+		if etcdClient == nil {
+			if etcdClient, err = etcdclient.NewClientWithTLS(opts.Endpoints, tlsConfig); err != nil {
+				return nil, err
+			}
+		}
+		return etcdClient.GetClusterMembers(ctx)
+	}
+	return bootstrap.Run(ctx, opts, list, os.Stdout)
+}
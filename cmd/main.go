@@ -17,6 +17,7 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"crypto/tls"
 	"flag"
 	"fmt"
@@ -37,6 +38,10 @@ import (
 
 	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
 	"github.com/your-org/etcd-k8s-operator/internal/controller"
+	"github.com/your-org/etcd-k8s-operator/pkg/backup"
+	"github.com/your-org/etcd-k8s-operator/pkg/k8s"
+	"github.com/your-org/etcd-k8s-operator/pkg/service"
+	"github.com/your-org/etcd-k8s-operator/pkg/utils"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -59,6 +64,10 @@ func main() {
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var backupDir string
+	var snapshotServerAddr string
+	var snapshotServerURL string
+	var bootstrapImage string
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metric endpoint binds to. "+
 		"Use the port :8080. If not set, it will be 0 in order to disable the metrics server")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
@@ -69,6 +78,15 @@ func main() {
 		"If set the metrics endpoint is served securely")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.StringVar(&backupDir, "backup-dir", utils.DefaultBackupDir,
+		"The directory where the backup PVC is mounted. Snapshots with storageType Local are written here.")
+	flag.StringVar(&snapshotServerAddr, "snapshot-server-bind-address", utils.DefaultSnapshotServerAddress,
+		"The address the snapshot server binds to. Restore jobs download staged snapshots from it.")
+	flag.StringVar(&snapshotServerURL, "snapshot-server-url", utils.DefaultSnapshotServerURL,
+		"The in-cluster URL of the snapshot server, as reachable from restore jobs.")
+	flag.StringVar(&bootstrapImage, "bootstrap-image", "",
+		"The image etcd member init containers run etcd-bootstrap from. "+
+			"Defaults to the image of the operator's own pod, looked up through the POD_NAME and POD_NAMESPACE environment variables.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -125,30 +143,59 @@ func main() {
 		os.Exit(1)
 	}
 
+	// 成员 init 容器与 operator 使用同一镜像，未指定时查询 operator 自身的 Pod
+	if bootstrapImage == "" {
+		podName, podNamespace := os.Getenv("POD_NAME"), os.Getenv("POD_NAMESPACE")
+		if podName == "" || podNamespace == "" {
+			setupLog.Error(nil, "--bootstrap-image is required when POD_NAME and POD_NAMESPACE are not set, "+
+				"e.g. when the operator runs outside the cluster")
+			os.Exit(1)
+		}
+		bootstrapImage, err = k8s.OperatorImage(context.Background(), mgr.GetAPIReader(), podNamespace, podName, "manager")
+		if err != nil {
+			setupLog.Error(err, "unable to determine the bootstrap image, set --bootstrap-image")
+			os.Exit(1)
+		}
+	}
+	statefulSetOptions := k8s.StatefulSetOptions{BootstrapImage: bootstrapImage}
+	setupLog.Info("etcd member init containers use the bootstrap image", "image", bootstrapImage)
+
 	// 使用新的重构后的控制器
 	clusterController := controller.NewClusterController(
 		mgr.GetClient(),
 		mgr.GetScheme(),
 		mgr.GetEventRecorderFor("etcdcluster-controller"),
+		statefulSetOptions,
 	)
 	if err = clusterController.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "EtcdCluster")
 		os.Exit(1)
 	}
-	if err = (&controller.EtcdBackupReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr); err != nil {
+	if err = controller.NewEtcdBackupReconciler(
+		mgr.GetClient(),
+		mgr.GetScheme(),
+		mgr.GetEventRecorderFor("etcdbackup-controller"),
+		backupDir,
+	).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "EtcdBackup")
 		os.Exit(1)
 	}
-	if err = (&controller.EtcdRestoreReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr); err != nil {
+	if err = controller.NewEtcdRestoreReconciler(
+		mgr.GetClient(),
+		mgr.GetScheme(),
+		mgr.GetEventRecorderFor("etcdrestore-controller"),
+		backupDir,
+		snapshotServerURL,
+		statefulSetOptions,
+	).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "EtcdRestore")
 		os.Exit(1)
 	}
+	// 恢复任务从该服务下载 Operator 暂存的快照
+	if err = mgr.Add(backup.NewSnapshotServer(snapshotServerAddr, service.NewRestoreStager(backupDir))); err != nil {
+		setupLog.Error(err, "unable to set up snapshot server")
+		os.Exit(1)
+	}
 	// +kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
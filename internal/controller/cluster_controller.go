@@ -27,10 +27,13 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
 	clientpkg "github.com/your-org/etcd-k8s-operator/pkg/client"
+	"github.com/your-org/etcd-k8s-operator/pkg/k8s"
 	"github.com/your-org/etcd-k8s-operator/pkg/resource"
 	"github.com/your-org/etcd-k8s-operator/pkg/service"
 	"github.com/your-org/etcd-k8s-operator/pkg/utils"
@@ -49,21 +52,23 @@ type ClusterController struct {
 }
 
 // NewClusterController 创建集群控制器
+// statefulSetOptions 为构建成员 StatefulSet 使用的 operator 配置
 func NewClusterController(
 	client client.Client,
 	scheme *runtime.Scheme,
 	recorder record.EventRecorder,
+	statefulSetOptions k8s.StatefulSetOptions,
 ) *ClusterController {
 	// 创建客户端层
 	k8sClient := clientpkg.NewKubernetesClient(client, recorder)
 
 	// 创建资源层
-	resourceManager := resource.NewResourceManager(k8sClient)
+	resourceManager := resource.NewResourceManager(k8sClient, statefulSetOptions)
 
 	// 创建服务层
 	clusterService := service.NewClusterService(k8sClient, resourceManager)
-	scalingService := service.NewScalingService(client, resourceManager)
-	// TODO: 创建其他服务
+	healthService := service.NewHealthService(k8sClient)
+	scalingService := service.NewScalingService(client, resourceManager, healthService)
 
 	return &ClusterController{
 		Client:   client,
@@ -72,19 +77,21 @@ func NewClusterController(
 
 		clusterService: clusterService,
 		scalingService: scalingService,
-		// healthService:  healthService,
+		healthService:  healthService,
 	}
 }
 
 // +kubebuilder:rbac:groups=etcd.etcd.io,resources=etcdclusters,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=etcd.etcd.io,resources=etcdclusters/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=etcd.etcd.io,resources=etcdclusters/finalizers,verbs=update
+// +kubebuilder:rbac:groups=etcd.etcd.io,resources=etcdbackups,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups=etcd.etcd.io,resources=etcdrestores,verbs=get;list;watch;create
 // +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;create;update;patch;delete
-// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;delete
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 // Reconcile 主要的调谐逻辑 (大幅简化)
@@ -109,7 +116,13 @@ func (r *ClusterController) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		return r.handleDeletion(ctx, cluster)
 	}
 
-	// 3. 确保 Finalizer
+	// 3. 暂停期间 (例如 EtcdRestore 原地恢复) 由暂停方负责集群资源，这里不做任何调谐
+	if pausedBy := cluster.Annotations[utils.AnnotationPaused]; pausedBy != "" {
+		logger.Info("EtcdCluster reconciliation is paused", "pausedBy", pausedBy)
+		return ctrl.Result{}, nil
+	}
+
+	// 4. 确保 Finalizer
 	if !controllerutil.ContainsFinalizer(cluster, utils.EtcdFinalizer) {
 		logger.Info("Adding finalizer to EtcdCluster")
 		controllerutil.AddFinalizer(cluster, utils.EtcdFinalizer)
@@ -120,10 +133,10 @@ func (r *ClusterController) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		return ctrl.Result{Requeue: true}, nil
 	}
 
-	// 4. 设置默认值
+	// 5. 设置默认值
 	r.clusterService.SetDefaults(cluster)
 
-	// 5. 状态机处理 (委托给服务层)
+	// 6. 状态机处理 (委托给服务层)
 	return r.handleStateMachine(ctx, cluster)
 }
 
@@ -160,6 +173,13 @@ func (r *ClusterController) handleStateMachine(ctx context.Context, cluster *etc
 		}
 		return ctrl.Result{RequeueAfter: 30 * 1000000000}, nil
 
+	case etcdv1alpha1.EtcdClusterPhaseUpgrading:
+		logger.Info("Upgrading EtcdCluster")
+		if r.scalingService != nil {
+			return r.scalingService.HandleUpgrading(ctx, cluster)
+		}
+		return ctrl.Result{RequeueAfter: 30 * 1000000000}, nil
+
 	case etcdv1alpha1.EtcdClusterPhaseStopped:
 		logger.Info("EtcdCluster is stopped, checking if restart needed")
 		if r.scalingService != nil {
@@ -199,12 +219,50 @@ func (r *ClusterController) handleDeletion(ctx context.Context, cluster *etcdv1a
 	return ctrl.Result{}, nil
 }
 
+// clusterSecretsIndex 按集群引用的证书和 CA Secret 名称索引 EtcdCluster
+const clusterSecretsIndex = "spec.security.tls.secrets"
+
+// clusterSecrets 返回集群引用的证书和 CA Secret 名称，作为 clusterSecretsIndex 的索引值
+func clusterSecrets(obj client.Object) []string {
+	tls := obj.(*etcdv1alpha1.EtcdCluster).Spec.Security.TLS
+	var names []string
+	for _, name := range []string{tls.CertificateSecret, tls.CASecret} {
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
 // SetupWithManager 设置控制器管理器
+// 用户提供的证书 Secret 不归集群所有，单独监听以便证书更新后重新校验
 func (r *ClusterController) SetupWithManager(mgr ctrl.Manager) error {
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &etcdv1alpha1.EtcdCluster{},
+		clusterSecretsIndex, clusterSecrets); err != nil {
+		return err
+	}
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&etcdv1alpha1.EtcdCluster{}).
 		Owns(&appsv1.StatefulSet{}).
 		Owns(&corev1.Service{}).
 		Owns(&corev1.ConfigMap{}).
+		Owns(&corev1.Secret{}).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.clustersForSecret)).
 		Complete(r)
 }
+
+// clustersForSecret 通过 clusterSecretsIndex 索引返回引用该 Secret 作为证书或 CA 的集群
+func (r *ClusterController) clustersForSecret(ctx context.Context, obj client.Object) []reconcile.Request {
+	clusters := &etcdv1alpha1.EtcdClusterList{}
+	if err := r.List(ctx, clusters, client.InNamespace(obj.GetNamespace()),
+		client.MatchingFields{clusterSecretsIndex: obj.GetName()}); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list EtcdClusters for secret", "secret", obj.GetName())
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(clusters.Items))
+	for i := range clusters.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&clusters.Items[i])})
+	}
+	return requests
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+	"github.com/your-org/etcd-k8s-operator/pkg/k8s"
+)
+
+// TestClustersForSecret 测试通过索引找到引用 Secret 作为证书或 CA 的集群，不返回其他命名空间或未引用的集群
+func TestClustersForSecret(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, etcdv1alpha1.AddToScheme(scheme))
+	newCluster := func(namespace, name, certificateSecret, caSecret string) *etcdv1alpha1.EtcdCluster {
+		cluster := &etcdv1alpha1.EtcdCluster{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+		cluster.Spec.Security.TLS.CertificateSecret = certificateSecret
+		cluster.Spec.Security.TLS.CASecret = caSecret
+		return cluster
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).
+		WithIndex(&etcdv1alpha1.EtcdCluster{}, clusterSecretsIndex, clusterSecrets).
+		WithObjects(
+			newCluster("default", "by-cert", "shared-tls", ""),
+			newCluster("default", "by-ca", "own-tls", "shared-tls"),
+			newCluster("default", "unrelated", "other-tls", ""),
+			newCluster("other", "other-namespace", "shared-tls", ""),
+		).Build()
+	r := NewClusterController(c, scheme, record.NewFakeRecorder(10), k8s.StatefulSetOptions{})
+
+	requests := r.clustersForSecret(context.Background(), &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "shared-tls", Namespace: "default"}})
+	assert.ElementsMatch(t, []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Name: "by-cert", Namespace: "default"}},
+		{NamespacedName: types.NamespacedName{Name: "by-ca", Namespace: "default"}},
+	}, requests)
+	assert.Empty(t, r.clustersForSecret(context.Background(), &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "missing", Namespace: "default"}}))
+}
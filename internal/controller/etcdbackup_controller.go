@@ -19,44 +19,87 @@ package controller
 import (
 	"context"
 
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+	clientpkg "github.com/your-org/etcd-k8s-operator/pkg/client"
+	"github.com/your-org/etcd-k8s-operator/pkg/service"
 )
 
+// maxConcurrentBackupReconciles 备份控制器的最大并发调谐数
+const maxConcurrentBackupReconciles = 4
+
 // EtcdBackupReconciler reconciles a EtcdBackup object
 type EtcdBackupReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+
+	// 服务层依赖
+	backupService service.BackupService
+}
+
+// NewEtcdBackupReconciler 创建备份控制器
+// backupDir 为 Operator 备份 PVC 的挂载目录，Local 存储类型的快照写入该目录
+func NewEtcdBackupReconciler(
+	client client.Client,
+	scheme *runtime.Scheme,
+	recorder record.EventRecorder,
+	backupDir string,
+) *EtcdBackupReconciler {
+	k8sClient := clientpkg.NewKubernetesClient(client, recorder)
+
+	return &EtcdBackupReconciler{
+		Client:   client,
+		Scheme:   scheme,
+		Recorder: recorder,
+
+		backupService: service.NewBackupService(k8sClient, backupDir),
+	}
 }
 
 // +kubebuilder:rbac:groups=etcd.etcd.io,resources=etcdbackups,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=etcd.etcd.io,resources=etcdbackups/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=etcd.etcd.io,resources=etcdbackups/finalizers,verbs=update
+// +kubebuilder:rbac:groups=etcd.etcd.io,resources=etcdclusters,verbs=get;list;watch
+// +kubebuilder:rbac:groups=etcd.etcd.io,resources=etcdclusters/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
 
-// Reconcile is part of the main kubernetes reconciliation loop which aims to
-// move the current state of the cluster closer to the desired state.
-// TODO(user): Modify the Reconcile function to compare the state specified by
-// the EtcdBackup object against the actual cluster state, and then
-// perform operations to make the cluster state reflect the state specified by
-// the user.
-//
-// For more details, check Reconcile and its Result here:
-// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.18.2/pkg/reconcile
+// Reconcile 备份调谐逻辑，具体的快照流程委托给备份服务
 func (r *EtcdBackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	_ = log.FromContext(ctx)
+	logger := log.FromContext(ctx).WithValues("etcdbackup", req.NamespacedName)
+
+	backup := &etcdv1alpha1.EtcdBackup{}
+	if err := r.Get(ctx, req.NamespacedName, backup); err != nil {
+		if errors.IsNotFound(err) {
+			logger.Info("EtcdBackup resource not found, ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		logger.Error(err, "Failed to get EtcdBackup")
+		return ctrl.Result{}, err
+	}
 
-	// TODO(user): your logic here
+	if backup.DeletionTimestamp != nil {
+		return ctrl.Result{}, nil
+	}
 
-	return ctrl.Result{}, nil
+	return r.backupService.HandleBackup(log.IntoContext(ctx, logger), backup)
 }
 
 // SetupWithManager sets up the controller with the Manager.
+// 定时备份创建的子备份由父对象拥有，子备份状态变化时父对象会重新调谐
 func (r *EtcdBackupReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&etcdv1alpha1.EtcdBackup{}).
+		Owns(&etcdv1alpha1.EtcdBackup{}).
+		// 快照上传在调谐中同步执行，允许并发调谐以免阻塞定时策略和其他备份
+		WithOptions(controller.Options{MaxConcurrentReconciles: maxConcurrentBackupReconciles}).
 		Complete(r)
 }
@@ -23,6 +23,7 @@ import (
 	. "github.com/onsi/gomega"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -51,7 +52,10 @@ var _ = Describe("EtcdBackup Controller", func() {
 						Name:      resourceName,
 						Namespace: "default",
 					},
-					// TODO(user): Specify other spec details if needed.
+					Spec: etcdv1alpha1.EtcdBackupSpec{
+						ClusterName: "missing-cluster",
+						StorageType: etcdv1alpha1.EtcdBackupStorageTypeLocal,
+					},
 				}
 				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
 			}
@@ -68,17 +72,21 @@ var _ = Describe("EtcdBackup Controller", func() {
 		})
 		It("should successfully reconcile the resource", func() {
 			By("Reconciling the created resource")
-			controllerReconciler := &EtcdBackupReconciler{
-				Client: k8sClient,
-				Scheme: k8sClient.Scheme(),
-			}
+			controllerReconciler := NewEtcdBackupReconciler(
+				k8sClient,
+				k8sClient.Scheme(),
+				record.NewFakeRecorder(10),
+				GinkgoT().TempDir(),
+			)
 
 			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
 				NamespacedName: typeNamespacedName,
 			})
 			Expect(err).NotTo(HaveOccurred())
-			// TODO(user): Add more specific assertions depending on your controller's reconciliation logic.
-			// Example: If you expect a certain status condition after reconciliation, verify it here.
+
+			By("Marking the backup as failed because the target cluster does not exist")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, etcdbackup)).To(Succeed())
+			Expect(etcdbackup.Status.Phase).To(Equal(etcdv1alpha1.EtcdBackupPhaseFailed))
 		})
 	})
 })
@@ -48,6 +48,9 @@ type EtcdClusterReconciler struct {
 	client.Client
 	Scheme   *runtime.Scheme
 	Recorder record.EventRecorder
+
+	// StatefulSetOptions holds the operator settings used to build member StatefulSets
+	StatefulSetOptions k8s.StatefulSetOptions
 }
 
 // +kubebuilder:rbac:groups=etcd.etcd.io,resources=etcdclusters,verbs=get;list;watch;create;update;patch;delete
@@ -676,10 +679,10 @@ func (r *EtcdClusterReconciler) ensureStatefulSet(ctx context.Context, cluster *
 	var desired *appsv1.StatefulSet
 	if cluster.Spec.Size > 1 {
 		// 多节点集群：初始创建时只启动第一个节点
-		desired = k8s.BuildStatefulSetWithReplicas(cluster, 1)
+		desired = k8s.BuildStatefulSetWithReplicas(cluster, 1, r.StatefulSetOptions)
 	} else {
 		// 单节点集群：直接创建
-		desired = k8s.BuildStatefulSet(cluster)
+		desired = k8s.BuildStatefulSet(cluster, r.StatefulSetOptions)
 	}
 
 	existing := &appsv1.StatefulSet{}
@@ -19,44 +19,95 @@ package controller
 import (
 	"context"
 
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+	clientpkg "github.com/your-org/etcd-k8s-operator/pkg/client"
+	"github.com/your-org/etcd-k8s-operator/pkg/k8s"
+	"github.com/your-org/etcd-k8s-operator/pkg/resource"
+	"github.com/your-org/etcd-k8s-operator/pkg/service"
 )
 
 // EtcdRestoreReconciler reconciles a EtcdRestore object
 type EtcdRestoreReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+
+	// 服务层依赖
+	restoreService service.RestoreService
+}
+
+// NewEtcdRestoreReconciler 创建恢复控制器
+// backupDir 为 Operator 备份 PVC 的挂载目录，恢复前快照暂存在该目录下；
+// snapshotServerURL 为成员 PVC 初始化任务下载暂存快照的地址；
+// statefulSetOptions 为构建成员 StatefulSet 使用的 operator 配置
+func NewEtcdRestoreReconciler(
+	client client.Client,
+	scheme *runtime.Scheme,
+	recorder record.EventRecorder,
+	backupDir string,
+	snapshotServerURL string,
+	statefulSetOptions k8s.StatefulSetOptions,
+) *EtcdRestoreReconciler {
+	k8sClient := clientpkg.NewKubernetesClient(client, recorder)
+	clusterService := service.NewClusterService(k8sClient, resource.NewResourceManager(k8sClient, statefulSetOptions))
+
+	return &EtcdRestoreReconciler{
+		Client:   client,
+		Scheme:   scheme,
+		Recorder: recorder,
+
+		restoreService: service.NewRestoreService(k8sClient, clusterService, backupDir, snapshotServerURL),
+	}
 }
 
 // +kubebuilder:rbac:groups=etcd.etcd.io,resources=etcdrestores,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=etcd.etcd.io,resources=etcdrestores/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=etcd.etcd.io,resources=etcdrestores/finalizers,verbs=update
+// +kubebuilder:rbac:groups=etcd.etcd.io,resources=etcdbackups,verbs=get;list;watch
+// +kubebuilder:rbac:groups=etcd.etcd.io,resources=etcdclusters,verbs=get;list;watch;create;update
+// +kubebuilder:rbac:groups=etcd.etcd.io,resources=etcdclusters/status,verbs=get;update
+// +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;update
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
-// Reconcile is part of the main kubernetes reconciliation loop which aims to
-// move the current state of the cluster closer to the desired state.
-// TODO(user): Modify the Reconcile function to compare the state specified by
-// the EtcdRestore object against the actual cluster state, and then
-// perform operations to make the cluster state reflect the state specified by
-// the user.
-//
-// For more details, check Reconcile and its Result here:
-// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.18.2/pkg/reconcile
+// Reconcile 恢复调谐逻辑，具体的恢复流程委托给恢复服务
 func (r *EtcdRestoreReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	_ = log.FromContext(ctx)
+	logger := log.FromContext(ctx).WithValues("etcdrestore", req.NamespacedName)
+
+	restore := &etcdv1alpha1.EtcdRestore{}
+	if err := r.Get(ctx, req.NamespacedName, restore); err != nil {
+		if errors.IsNotFound(err) {
+			logger.Info("EtcdRestore resource not found, ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		logger.Error(err, "Failed to get EtcdRestore")
+		return ctrl.Result{}, err
+	}
 
-	// TODO(user): your logic here
+	if restore.DeletionTimestamp != nil {
+		return ctrl.Result{}, nil
+	}
 
-	return ctrl.Result{}, nil
+	return r.restoreService.HandleRestore(log.IntoContext(ctx, logger), restore)
 }
 
 // SetupWithManager sets up the controller with the Manager.
+// 成员 PVC 的初始化任务由恢复对象拥有，任务完成或失败时恢复对象会重新调谐
 func (r *EtcdRestoreReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&etcdv1alpha1.EtcdRestore{}).
+		Owns(&batchv1.Job{}).
 		Complete(r)
 }
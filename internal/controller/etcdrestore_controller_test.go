@@ -23,11 +23,13 @@ import (
 	. "github.com/onsi/gomega"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+	"github.com/your-org/etcd-k8s-operator/pkg/k8s"
 )
 
 var _ = Describe("EtcdRestore Controller", func() {
@@ -51,7 +53,11 @@ var _ = Describe("EtcdRestore Controller", func() {
 						Name:      resourceName,
 						Namespace: "default",
 					},
-					// TODO(user): Specify other spec details if needed.
+					Spec: etcdv1alpha1.EtcdRestoreSpec{
+						BackupName:  "missing-backup",
+						ClusterName: "restored-cluster",
+						RestoreType: etcdv1alpha1.EtcdRestoreTypeNew,
+					},
 				}
 				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
 			}
@@ -68,17 +74,23 @@ var _ = Describe("EtcdRestore Controller", func() {
 		})
 		It("should successfully reconcile the resource", func() {
 			By("Reconciling the created resource")
-			controllerReconciler := &EtcdRestoreReconciler{
-				Client: k8sClient,
-				Scheme: k8sClient.Scheme(),
-			}
+			controllerReconciler := NewEtcdRestoreReconciler(
+				k8sClient,
+				k8sClient.Scheme(),
+				record.NewFakeRecorder(10),
+				GinkgoT().TempDir(),
+				"http://127.0.0.1:8082",
+				k8s.StatefulSetOptions{},
+			)
 
 			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
 				NamespacedName: typeNamespacedName,
 			})
 			Expect(err).NotTo(HaveOccurred())
-			// TODO(user): Add more specific assertions depending on your controller's reconciliation logic.
-			// Example: If you expect a certain status condition after reconciliation, verify it here.
+
+			By("Marking the restore as failed because no cluster template is given")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, etcdrestore)).To(Succeed())
+			Expect(etcdrestore.Status.Phase).To(Equal(etcdv1alpha1.EtcdRestorePhaseFailed))
 		})
 	})
 })
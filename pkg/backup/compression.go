@@ -0,0 +1,146 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Codec 快照压缩算法
+type Codec string
+
+const (
+	// CodecNone 不压缩
+	CodecNone Codec = "none"
+	// CodecGzip gzip 压缩
+	CodecGzip Codec = "gzip"
+	// CodecZstd zstd 压缩
+	CodecZstd Codec = "zstd"
+)
+
+// MetadataKeyCompression 对象元数据中记录压缩算法的键
+const MetadataKeyCompression = "compression"
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// ParseCodec 解析压缩算法名称，空字符串视为不压缩
+func ParseCodec(name string) (Codec, error) {
+	switch Codec(name) {
+	case "", CodecNone:
+		return CodecNone, nil
+	case CodecGzip, CodecZstd:
+		return Codec(name), nil
+	default:
+		return "", fmt.Errorf("unsupported compression codec %q", name)
+	}
+}
+
+// Extension 返回追加在快照 key 之后的扩展名
+func (c Codec) Extension() string {
+	switch c {
+	case CodecGzip:
+		return ".gz"
+	case CodecZstd:
+		return ".zst"
+	default:
+		return ""
+	}
+}
+
+// CompressReader 返回一个读取时即时压缩 r 的流，压缩在后台 goroutine 中进行。
+// 调用方必须关闭返回的 ReadCloser，提前关闭会终止压缩
+func CompressReader(r io.Reader, codec Codec) (io.ReadCloser, error) {
+	if codec == CodecNone {
+		return io.NopCloser(r), nil
+	}
+
+	pr, pw := io.Pipe()
+	compressor, err := newCompressor(pw, codec)
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		_, err := io.Copy(compressor, r)
+		if closeErr := compressor.Close(); err == nil {
+			err = closeErr
+		}
+		pw.CloseWithError(err)
+	}()
+
+	return pr, nil
+}
+
+// newCompressor 创建写入 w 的压缩器
+func newCompressor(w io.Writer, codec Codec) (io.WriteCloser, error) {
+	switch codec {
+	case CodecGzip:
+		return gzip.NewWriter(w), nil
+	case CodecZstd:
+		return zstd.NewWriter(w)
+	default:
+		return nil, fmt.Errorf("unsupported compression codec %q", codec)
+	}
+}
+
+// DecompressReader 根据数据头部的魔数识别压缩算法并返回解压后的流，
+// 未压缩的快照原样返回，因此恢复时无需预先知道备份使用的压缩算法
+func DecompressReader(r io.Reader) (io.ReadCloser, Codec, error) {
+	br := bufio.NewReader(r)
+	header, err := br.Peek(len(zstdMagic))
+	if err != nil && err != io.EOF {
+		return nil, "", fmt.Errorf("failed to read snapshot header: %w", err)
+	}
+
+	switch {
+	case bytes.HasPrefix(header, gzipMagic):
+		gr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to open gzip stream: %w", err)
+		}
+		return gr, CodecGzip, nil
+	case bytes.HasPrefix(header, zstdMagic):
+		zr, err := zstd.NewReader(br)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to open zstd stream: %w", err)
+		}
+		return zr.IOReadCloser(), CodecZstd, nil
+	default:
+		return io.NopCloser(br), CodecNone, nil
+	}
+}
+
+// CountingReader 统计读取的字节数
+type CountingReader struct {
+	R io.Reader
+	N int64
+}
+
+func (c *CountingReader) Read(p []byte) (int, error) {
+	n, err := c.R.Read(p)
+	c.N += int64(n)
+	return n, err
+}
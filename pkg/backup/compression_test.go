@@ -0,0 +1,115 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCompressionRoundTrip 测试压缩后的快照可以被自动识别并解压
+func TestCompressionRoundTrip(t *testing.T) {
+	// 模拟 bbolt 文件开头的零字节页头
+	snapshot := append(make([]byte, 16), bytes.Repeat([]byte("etcd-snapshot"), 4096)...)
+
+	for _, codec := range []Codec{CodecNone, CodecGzip, CodecZstd} {
+		t.Run(string(codec), func(t *testing.T) {
+			raw := &CountingReader{R: bytes.NewReader(snapshot)}
+			compressed, err := CompressReader(raw, codec)
+			require.NoError(t, err)
+			stored, err := io.ReadAll(compressed)
+			require.NoError(t, err)
+			require.NoError(t, compressed.Close())
+
+			assert.Equal(t, int64(len(snapshot)), raw.N)
+			if codec != CodecNone {
+				assert.Less(t, len(stored), len(snapshot))
+			}
+
+			rc, detected, err := DecompressReader(bytes.NewReader(stored))
+			require.NoError(t, err)
+			defer rc.Close()
+			assert.Equal(t, codec, detected)
+
+			restored, err := io.ReadAll(rc)
+			require.NoError(t, err)
+			assert.Equal(t, snapshot, restored)
+		})
+	}
+}
+
+// TestDecompressReaderShortInput 测试极短的输入不会报错
+func TestDecompressReaderShortInput(t *testing.T) {
+	rc, codec, err := DecompressReader(bytes.NewReader([]byte{0x01}))
+	require.NoError(t, err)
+	assert.Equal(t, CodecNone, codec)
+	data, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x01}, data)
+}
+
+// TestParseCodec 测试压缩算法解析
+func TestParseCodec(t *testing.T) {
+	codec, err := ParseCodec("")
+	require.NoError(t, err)
+	assert.Equal(t, CodecNone, codec)
+
+	codec, err = ParseCodec("zstd")
+	require.NoError(t, err)
+	assert.Equal(t, ".zst", codec.Extension())
+
+	_, err = ParseCodec("lz4")
+	assert.Error(t, err)
+}
+
+// TestSnapshotTimeWithCodecExtension 测试解析带压缩扩展名的快照时间
+func TestSnapshotTimeWithCodecExtension(t *testing.T) {
+	want := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+	for _, key := range []string{"a/b/20250801-120000.db", "a/b/20250801-120000.db.gz", "20250801-120000.db.zst"} {
+		got, ok := SnapshotTime(key)
+		require.True(t, ok, key)
+		assert.Equal(t, want, got, key)
+	}
+
+	for _, key := range []string{"a/b/20250801-120000.dbx", "a/b/notes.txt", "a/b/latest.db", "a/b/20250801-120000-.db", "a/b/20250801-120000x.db"} {
+		_, ok := SnapshotTime(key)
+		assert.False(t, ok, key)
+	}
+}
+
+// TestParseSnapshotKeyOwner 测试解析快照 key 中的 owner，早期不带 owner 的快照 owner 为空
+func TestParseSnapshotKeyOwner(t *testing.T) {
+	want := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+	for key, owner := range map[string]string{
+		"a/b/20250801-120000-hourly.db":       "hourly",
+		"a/b/20250801-120000-hourly.db.zst":   "hourly",
+		"a/b/20250801-120000-nightly-eu.db":   "nightly-eu",
+		"a/b/20250801-120000-backup.db.db.gz": "backup.db",
+		"a/b/20250801-120000.db.gz":           "",
+	} {
+		got, gotOwner, ok := ParseSnapshotKey(key)
+		require.True(t, ok, key)
+		assert.Equal(t, want, got, key)
+		assert.Equal(t, owner, gotOwner, key)
+	}
+	assert.Equal(t, "a/b/20250801-120000-hourly.db", SnapshotKey("a", "b", "hourly", want))
+}
@@ -0,0 +1,306 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// 加密快照格式:
+//
+//	header: magic(8) | keyIDLen(1) | keyID | noncePrefix(7)
+//	chunk:  ciphertextLen(4, big endian) | AES-256-GCM(plaintext <= encryptChunkSize)
+//
+// 每个分块的 nonce 为 noncePrefix | counter(4) | lastFlag(1)，最后一个分块的 lastFlag 为 1，
+// 用于发现被截断或重排的数据；header 作为每个分块的附加认证数据，防止 key ID 被篡改
+const (
+	// EncryptedExtension 追加在加密快照 key 之后的扩展名
+	EncryptedExtension = ".enc"
+
+	// MetadataKeyEncryptionKeyID 对象元数据中记录加密密钥 ID 的键
+	MetadataKeyEncryptionKeyID = "encryption-key-id"
+
+	// EncryptionKeySize AES-256 密钥长度
+	EncryptionKeySize = 32
+
+	encryptChunkSize      = 64 * 1024
+	noncePrefixSize       = 7
+	maxKeyIDLength        = 255
+	lastChunkFlag    byte = 1
+)
+
+var encryptionMagic = []byte("ETCDENC1")
+
+// ErrUnknownKeyID 快照使用的密钥不在密钥环中
+var ErrUnknownKeyID = errors.New("unknown encryption key id")
+
+// KeyRing 按 key ID 索引的 AES-256 密钥集合，支持密钥轮换后继续解密旧快照
+type KeyRing map[string][]byte
+
+// ParseKeyRing 从 Secret 数据构造密钥环。每个数据项的键为 key ID，值为 32 字节原始密钥或其 base64 编码
+func ParseKeyRing(data map[string][]byte) (KeyRing, error) {
+	ring := make(KeyRing, len(data))
+	for id, value := range data {
+		if len(id) > maxKeyIDLength {
+			return nil, fmt.Errorf("encryption key id %q is too long", id)
+		}
+		key, err := decodeKey(value)
+		if err != nil {
+			return nil, fmt.Errorf("encryption key %q: %w", id, err)
+		}
+		ring[id] = key
+	}
+	if len(ring) == 0 {
+		return nil, fmt.Errorf("no encryption keys found")
+	}
+	return ring, nil
+}
+
+// IDs 返回排序后的 key ID 列表
+func (r KeyRing) IDs() []string {
+	ids := make([]string, 0, len(r))
+	for id := range r {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// decodeKey 解码密钥，接受原始字节或 base64 文本
+func decodeKey(value []byte) ([]byte, error) {
+	if len(value) == EncryptionKeySize {
+		return value, nil
+	}
+	decoded, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(value)))
+	if err != nil || len(decoded) != EncryptionKeySize {
+		return nil, fmt.Errorf("must be %d raw bytes or their base64 encoding", EncryptionKeySize)
+	}
+	return decoded, nil
+}
+
+// EncryptReader 返回一个读取时即时加密 r 的流
+func EncryptReader(r io.Reader, keyID string, key []byte) (io.Reader, error) {
+	if keyID == "" || len(keyID) > maxKeyIDLength {
+		return nil, fmt.Errorf("invalid encryption key id %q", keyID)
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := make([]byte, noncePrefixSize)
+	if _, err := rand.Read(prefix); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	header := make([]byte, 0, len(encryptionMagic)+1+len(keyID)+noncePrefixSize)
+	header = append(header, encryptionMagic...)
+	header = append(header, byte(len(keyID)))
+	header = append(header, keyID...)
+	header = append(header, prefix...)
+
+	return &encryptReader{
+		src:     bufio.NewReaderSize(r, encryptChunkSize),
+		aead:    aead,
+		header:  header,
+		prefix:  prefix,
+		pending: append([]byte(nil), header...),
+		buf:     make([]byte, encryptChunkSize),
+	}, nil
+}
+
+type encryptReader struct {
+	src     *bufio.Reader
+	aead    cipher.AEAD
+	header  []byte
+	prefix  []byte
+	counter uint32
+	pending []byte
+	buf     []byte
+	done    bool
+}
+
+func (e *encryptReader) Read(p []byte) (int, error) {
+	for len(e.pending) == 0 {
+		if e.done {
+			return 0, io.EOF
+		}
+		if err := e.sealNext(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, e.pending)
+	e.pending = e.pending[n:]
+	return n, nil
+}
+
+// sealNext 读取下一个明文分块并加密，通过预读判断是否为最后一个分块
+func (e *encryptReader) sealNext() error {
+	n, err := io.ReadFull(e.src, e.buf)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return err
+	}
+	last := err != nil
+	if !last {
+		if _, peekErr := e.src.Peek(1); peekErr == io.EOF {
+			last = true
+		} else if peekErr != nil {
+			return peekErr
+		}
+	}
+
+	sealed := e.aead.Seal(nil, chunkNonce(e.prefix, e.counter, last), e.buf[:n], e.header)
+	e.counter++
+
+	e.pending = binary.BigEndian.AppendUint32(e.pending[:0], uint32(len(sealed)))
+	e.pending = append(e.pending, sealed...)
+	e.done = last
+	return nil
+}
+
+// DecryptReader 识别加密快照并使用密钥环中对应的密钥解密，返回快照使用的 key ID。
+// 未加密的快照原样返回，key ID 为空
+func DecryptReader(r io.Reader, keys KeyRing) (io.Reader, string, error) {
+	br := bufio.NewReaderSize(r, encryptChunkSize)
+	magic, err := br.Peek(len(encryptionMagic))
+	if err != nil && err != io.EOF {
+		return nil, "", fmt.Errorf("failed to read snapshot header: %w", err)
+	}
+	if !bytes.Equal(magic, encryptionMagic) {
+		return br, "", nil
+	}
+
+	header := make([]byte, len(encryptionMagic)+1)
+	if _, err := io.ReadFull(br, header); err != nil {
+		return nil, "", fmt.Errorf("failed to read encryption header: %w", err)
+	}
+	rest := make([]byte, int(header[len(header)-1])+noncePrefixSize)
+	if _, err := io.ReadFull(br, rest); err != nil {
+		return nil, "", fmt.Errorf("failed to read encryption header: %w", err)
+	}
+	header = append(header, rest...)
+	keyID := string(rest[:len(rest)-noncePrefixSize])
+
+	key, ok := keys[keyID]
+	if !ok {
+		return nil, keyID, fmt.Errorf("%w %q", ErrUnknownKeyID, keyID)
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, keyID, err
+	}
+
+	return &decryptReader{
+		src:    br,
+		aead:   aead,
+		header: header,
+		prefix: rest[len(rest)-noncePrefixSize:],
+	}, keyID, nil
+}
+
+type decryptReader struct {
+	src     *bufio.Reader
+	aead    cipher.AEAD
+	header  []byte
+	prefix  []byte
+	counter uint32
+	pending []byte
+	done    bool
+}
+
+func (d *decryptReader) Read(p []byte) (int, error) {
+	for len(d.pending) == 0 {
+		if d.done {
+			return 0, io.EOF
+		}
+		if err := d.openNext(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, d.pending)
+	d.pending = d.pending[n:]
+	return n, nil
+}
+
+// openNext 读取并解密下一个分块，数据在最后一个分块之前结束视为被截断
+func (d *decryptReader) openNext() error {
+	var size [4]byte
+	if _, err := io.ReadFull(d.src, size[:]); err != nil {
+		if err == io.EOF {
+			return fmt.Errorf("encrypted snapshot is truncated")
+		}
+		return err
+	}
+
+	length := binary.BigEndian.Uint32(size[:])
+	if length > encryptChunkSize+uint32(d.aead.Overhead()) {
+		return fmt.Errorf("encrypted snapshot is corrupted: chunk too large")
+	}
+	sealed := make([]byte, length)
+	if _, err := io.ReadFull(d.src, sealed); err != nil {
+		return fmt.Errorf("encrypted snapshot is truncated: %w", err)
+	}
+
+	// 先按普通分块解密，失败再按最后一个分块尝试
+	plain, err := d.aead.Open(sealed[:0:0], chunkNonce(d.prefix, d.counter, false), sealed, d.header)
+	if err != nil {
+		plain, err = d.aead.Open(sealed[:0:0], chunkNonce(d.prefix, d.counter, true), sealed, d.header)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt snapshot: wrong key or corrupted data")
+		}
+		d.done = true
+		if _, err := d.src.Peek(1); err != io.EOF {
+			return fmt.Errorf("encrypted snapshot is corrupted: unexpected data after last chunk")
+		}
+	}
+	d.counter++
+	d.pending = plain
+	return nil
+}
+
+// chunkNonce 计算分块的 nonce
+func chunkNonce(prefix []byte, counter uint32, last bool) []byte {
+	nonce := make([]byte, 0, noncePrefixSize+5)
+	nonce = append(nonce, prefix...)
+	nonce = binary.BigEndian.AppendUint32(nonce, counter)
+	if last {
+		return append(nonce, lastChunkFlag)
+	}
+	return append(nonce, 0)
+}
+
+// newAEAD 创建 AES-256-GCM
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != EncryptionKeySize {
+		return nil, fmt.Errorf("encryption key must be %d bytes, got %d", EncryptionKeySize, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestKey(t *testing.T) []byte {
+	key := make([]byte, EncryptionKeySize)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	return key
+}
+
+func encryptAll(t *testing.T, data []byte, keyID string, key []byte) []byte {
+	r, err := EncryptReader(bytes.NewReader(data), keyID, key)
+	require.NoError(t, err)
+	out, err := io.ReadAll(r)
+	require.NoError(t, err)
+	return out
+}
+
+// TestEncryptionRoundTrip 测试不同大小的数据加密后可以解密还原
+func TestEncryptionRoundTrip(t *testing.T) {
+	oldKey, newKey := newTestKey(t), newTestKey(t)
+	keys := KeyRing{"2024-q4": oldKey, "2025-q1": newKey}
+
+	for _, size := range []int{0, 1, encryptChunkSize, encryptChunkSize + 1, 3*encryptChunkSize + 17} {
+		data := make([]byte, size)
+		_, err := rand.Read(data)
+		require.NoError(t, err)
+
+		encrypted := encryptAll(t, data, "2024-q4", oldKey)
+		assert.False(t, bytes.Contains(encrypted, data[:min(size, 64)]) && size > 0)
+
+		r, keyID, err := DecryptReader(bytes.NewReader(encrypted), keys)
+		require.NoError(t, err, "size %d", size)
+		assert.Equal(t, "2024-q4", keyID)
+		decrypted, err := io.ReadAll(r)
+		require.NoError(t, err, "size %d", size)
+		assert.Equal(t, data, decrypted, "size %d", size)
+	}
+}
+
+// TestDecryptPassesThroughPlaintext 测试未加密的快照原样返回
+func TestDecryptPassesThroughPlaintext(t *testing.T) {
+	r, keyID, err := DecryptReader(bytes.NewReader([]byte("plain snapshot")), nil)
+	require.NoError(t, err)
+	assert.Empty(t, keyID)
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, "plain snapshot", string(data))
+}
+
+// TestDecryptFailures 测试未知密钥、错误密钥、截断和篡改都会被发现
+func TestDecryptFailures(t *testing.T) {
+	key := newTestKey(t)
+	data := bytes.Repeat([]byte("secret"), 3*encryptChunkSize/6)
+	encrypted := encryptAll(t, data, "k1", key)
+
+	_, _, err := DecryptReader(bytes.NewReader(encrypted), KeyRing{"k2": key})
+	assert.ErrorIs(t, err, ErrUnknownKeyID)
+
+	readAll := func(b []byte, keys KeyRing) error {
+		r, _, err := DecryptReader(bytes.NewReader(b), keys)
+		if err != nil {
+			return err
+		}
+		_, err = io.ReadAll(r)
+		return err
+	}
+
+	assert.Error(t, readAll(encrypted, KeyRing{"k1": newTestKey(t)}), "wrong key")
+	assert.Error(t, readAll(encrypted[:len(encrypted)-encryptChunkSize/2], KeyRing{"k1": key}), "truncated")
+
+	// 整块截掉最后一个分块
+	lastChunk := 4 + (len(data) - 2*encryptChunkSize) + 16
+	assert.Error(t, readAll(encrypted[:len(encrypted)-lastChunk], KeyRing{"k1": key}), "missing last chunk")
+
+	tampered := append([]byte(nil), encrypted...)
+	tampered[len(tampered)/2] ^= 0xff
+	assert.Error(t, readAll(tampered, KeyRing{"k1": key}), "tampered")
+
+	assert.Error(t, readAll(append(append([]byte(nil), encrypted...), 0x00), KeyRing{"k1": key}), "trailing data")
+}
+
+// TestParseKeyRing 测试从 Secret 数据解析密钥
+func TestParseKeyRing(t *testing.T) {
+	raw := newTestKey(t)
+	encoded := []byte(base64.StdEncoding.EncodeToString(newTestKey(t)) + "\n")
+
+	ring, err := ParseKeyRing(map[string][]byte{"raw": raw, "encoded": encoded})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"encoded", "raw"}, ring.IDs())
+	assert.Equal(t, raw, ring["raw"])
+	assert.Len(t, ring["encoded"], EncryptionKeySize)
+
+	_, err = ParseKeyRing(map[string][]byte{"short": []byte("too-short")})
+	assert.Error(t, err)
+
+	_, err = ParseKeyRing(map[string][]byte{})
+	assert.Error(t, err)
+}
+
+// TestOpenSnapshotReader 测试压缩并加密的快照可以透明地还原
+func TestOpenSnapshotReader(t *testing.T) {
+	key := newTestKey(t)
+	snapshot := append(make([]byte, 16), bytes.Repeat([]byte("etcd"), 50000)...)
+
+	compressed, err := CompressReader(bytes.NewReader(snapshot), CodecZstd)
+	require.NoError(t, err)
+	defer compressed.Close()
+	encrypted, err := EncryptReader(compressed, "k1", key)
+	require.NoError(t, err)
+	stored, err := io.ReadAll(encrypted)
+	require.NoError(t, err)
+
+	rc, format, err := OpenSnapshotReader(bytes.NewReader(stored), KeyRing{"k1": key})
+	require.NoError(t, err)
+	defer rc.Close()
+	assert.Equal(t, SnapshotFormat{Codec: CodecZstd, KeyID: "k1"}, format)
+
+	restored, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	assert.Equal(t, snapshot, restored)
+}
@@ -0,0 +1,223 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// fakeGCSServer 内存中的 GCS JSON API，只实现可恢复上传和对象下载
+type fakeGCSServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	objects  map[string][]byte
+	sessions map[string]*fakeGCSSession
+	nextID   int
+	chunks   int
+
+	// persistLimit 每个上传请求最多持久化的字节数，0 表示不限制，用于模拟服务端只持久化了部分分块
+	persistLimit int
+	// discardChunks 丢弃收到的分块数据，模拟服务端没有持久化任何数据
+	discardChunks bool
+}
+
+type fakeGCSSession struct {
+	object string
+	data   []byte
+}
+
+func newFakeGCSServer() *fakeGCSServer {
+	f := &fakeGCSServer{
+		objects:  map[string][]byte{},
+		sessions: map[string]*fakeGCSSession{},
+	}
+	f.Server = httptest.NewServer(http.HandlerFunc(f.handle))
+	return f
+}
+
+// object 返回已完成上传的对象内容
+func (f *fakeGCSServer) object(bucket, name string) ([]byte, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	data, ok := f.objects[bucket+"/"+name]
+	return data, ok
+}
+
+// chunkCount 返回收到的上传分块数
+func (f *fakeGCSServer) chunkCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.chunks
+}
+
+func (f *fakeGCSServer) handle(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	path := r.URL.EscapedPath()
+	switch {
+	case r.Method == http.MethodPost && strings.HasPrefix(path, "/upload/storage/v1/b/"):
+		if r.URL.Query().Get("uploadType") != "resumable" {
+			http.Error(w, "only resumable uploads are supported", http.StatusBadRequest)
+			return
+		}
+		bucket := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/upload/storage/v1/b/"), "/o")
+		f.nextID++
+		id := fmt.Sprintf("session-%d", f.nextID)
+		f.sessions[id] = &fakeGCSSession{object: bucket + "/" + r.URL.Query().Get("name")}
+		w.Header().Set("Location", f.URL+"/upload/session/"+id)
+
+	case r.Method == http.MethodPut && strings.HasPrefix(path, "/upload/session/"):
+		session, ok := f.sessions[strings.TrimPrefix(path, "/upload/session/")]
+		if !ok {
+			http.Error(w, "no such session", http.StatusNotFound)
+			return
+		}
+		data, _ := io.ReadAll(r.Body)
+		f.chunks++
+
+		// Content-Range: bytes <first>-<last>/<total|*> 或 bytes */<total>
+		contentRange := r.Header.Get("Content-Range")
+		total := contentRange[strings.LastIndex(contentRange, "/")+1:]
+		if !strings.HasPrefix(contentRange, "bytes */") {
+			first, _ := strconv.Atoi(strings.TrimPrefix(contentRange[:strings.Index(contentRange, "-")], "bytes "))
+			if first != len(session.data) {
+				http.Error(w, "chunk does not continue the persisted data", http.StatusBadRequest)
+				return
+			}
+		}
+		partial := f.discardChunks || f.persistLimit > 0 && len(data) > f.persistLimit
+		switch {
+		case f.discardChunks:
+			data = nil
+		case partial:
+			data = data[:f.persistLimit]
+		}
+		session.data = append(session.data, data...)
+
+		if total == "*" || partial {
+			if len(session.data) > 0 {
+				w.Header().Set("Range", fmt.Sprintf("bytes=0-%d", len(session.data)-1))
+			}
+			w.WriteHeader(http.StatusPermanentRedirect)
+			return
+		}
+		if size, _ := strconv.Atoi(total); size != len(session.data) {
+			http.Error(w, "size mismatch", http.StatusBadRequest)
+			return
+		}
+		f.objects[session.object] = session.data
+		delete(f.sessions, strings.TrimPrefix(path, "/upload/session/"))
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"name":%q,"size":"%d"}`, session.object, len(session.data))
+
+	case r.Method == http.MethodDelete && strings.HasPrefix(path, "/upload/session/"):
+		delete(f.sessions, strings.TrimPrefix(path, "/upload/session/"))
+		w.WriteHeader(499)
+
+	case r.Method == http.MethodGet && strings.HasPrefix(path, "/storage/v1/b/") && strings.HasSuffix(path, "/o"):
+		f.listObjects(w, r)
+
+	case r.Method == http.MethodDelete && strings.HasPrefix(path, "/storage/v1/b/"):
+		parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/storage/v1/b/"), "/o/", 2)
+		if len(parts) != 2 {
+			http.Error(w, "unsupported request", http.StatusBadRequest)
+			return
+		}
+		if _, ok := f.objects[parts[0]+"/"+parts[1]]; !ok {
+			http.Error(w, `{"error":{"code":404,"message":"No such object"}}`, http.StatusNotFound)
+			return
+		}
+		delete(f.objects, parts[0]+"/"+parts[1])
+		w.WriteHeader(http.StatusNoContent)
+
+	case r.Method == http.MethodGet && strings.HasPrefix(path, "/storage/v1/b/"):
+		// /storage/v1/b/<bucket>/o/<escaped object>
+		parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/storage/v1/b/"), "/o/", 2)
+		if len(parts) != 2 || r.URL.Query().Get("alt") != "media" {
+			http.Error(w, "unsupported request", http.StatusBadRequest)
+			return
+		}
+		data, ok := f.objects[parts[0]+"/"+parts[1]]
+		if !ok {
+			http.Error(w, `{"error":{"code":404,"message":"No such object"}}`, http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(data)
+
+	default:
+		http.Error(w, "unsupported request", http.StatusNotImplemented)
+	}
+}
+
+// listObjects 实现对象列表接口，每页最多返回 pageSize 个对象以覆盖分页逻辑
+func (f *fakeGCSServer) listObjects(w http.ResponseWriter, r *http.Request) {
+	const pageSize = 2
+
+	bucket := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/storage/v1/b/"), "/o")
+	prefix := r.URL.Query().Get("prefix")
+
+	var names []string
+	for name := range f.objects {
+		if strings.HasPrefix(name, bucket+"/"+prefix) {
+			names = append(names, strings.TrimPrefix(name, bucket+"/"))
+		}
+	}
+	sort.Strings(names)
+
+	start, _ := strconv.Atoi(r.URL.Query().Get("pageToken"))
+	end := start + pageSize
+	if end > len(names) {
+		end = len(names)
+	}
+
+	type item struct {
+		Name    string    `json:"name"`
+		Size    string    `json:"size"`
+		Updated time.Time `json:"updated"`
+	}
+	page := struct {
+		Items         []item `json:"items,omitempty"`
+		NextPageToken string `json:"nextPageToken,omitempty"`
+	}{}
+	for _, name := range names[start:end] {
+		page.Items = append(page.Items, item{Name: name, Size: strconv.Itoa(len(f.objects[bucket+"/"+name])), Updated: time.Now().UTC()})
+	}
+	if end < len(names) {
+		page.NextPageToken = strconv.Itoa(end)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(page)
+}
+
+// putObject 直接写入对象，用于准备测试数据
+func (f *fakeGCSServer) putObject(bucket, name string, data []byte) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.objects[bucket+"/"+name] = data
+}
@@ -0,0 +1,402 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+const (
+	// DefaultGCSEndpoint GCS JSON API 地址
+	DefaultGCSEndpoint = "https://storage.googleapis.com"
+
+	// DefaultGCSChunkSize 可恢复上传的分块大小，必须是 256KiB 的整数倍
+	DefaultGCSChunkSize = 16 * 1024 * 1024
+
+	// gcsChunkAlignment GCS 要求非最后分块的大小按 256KiB 对齐
+	gcsChunkAlignment = 256 * 1024
+
+	// gcsScope 读写对象所需的 OAuth2 范围
+	gcsScope = "https://www.googleapis.com/auth/devstorage.read_write"
+)
+
+// GCSConfig GCS 存储的连接配置
+type GCSConfig struct {
+	// Bucket 存储桶名称
+	Bucket string
+	// CredentialsJSON 服务账号密钥，为空时使用 Application Default Credentials
+	CredentialsJSON []byte
+	// Endpoint JSON API 地址，为空时使用 DefaultGCSEndpoint，主要用于测试
+	Endpoint string
+	// HTTPClient 自定义 HTTP 客户端，设置后忽略 CredentialsJSON
+	HTTPClient *http.Client
+	// ChunkSize 可恢复上传的分块大小，为空时使用 DefaultGCSChunkSize
+	ChunkSize int
+}
+
+// GCSStorage 通过 GCS JSON API 读写快照
+type GCSStorage struct {
+	httpClient *http.Client
+	endpoint   string
+	bucket     string
+	chunkSize  int
+}
+
+var _ Storage = (*GCSStorage)(nil)
+
+// NewGCSStorage 创建 GCS 存储
+func NewGCSStorage(ctx context.Context, cfg GCSConfig) (*GCSStorage, error) {
+	if cfg.Bucket == "" {
+		return nil, fmt.Errorf("gcs bucket cannot be empty")
+	}
+
+	chunkSize := cfg.ChunkSize
+	if chunkSize == 0 {
+		chunkSize = DefaultGCSChunkSize
+	}
+	if chunkSize%gcsChunkAlignment != 0 {
+		return nil, fmt.Errorf("gcs chunk size must be a multiple of 256KiB, got %d", chunkSize)
+	}
+
+	endpoint := strings.TrimSuffix(cfg.Endpoint, "/")
+	if endpoint == "" {
+		endpoint = DefaultGCSEndpoint
+	}
+
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		var err error
+		httpClient, err = newGCSHTTPClient(ctx, cfg.CredentialsJSON)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &GCSStorage{
+		httpClient: httpClient,
+		endpoint:   endpoint,
+		bucket:     cfg.Bucket,
+		chunkSize:  chunkSize,
+	}, nil
+}
+
+// newGCSHTTPClient 创建带 OAuth2 认证的 HTTP 客户端
+func newGCSHTTPClient(ctx context.Context, credentialsJSON []byte) (*http.Client, error) {
+	// 令牌刷新使用的 ctx 必须在存储对象的整个生命周期内有效，不能使用调用方的请求 ctx
+	baseCtx := context.WithoutCancel(ctx)
+
+	if len(credentialsJSON) == 0 {
+		httpClient, err := google.DefaultClient(baseCtx, gcsScope)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find default gcs credentials: %w", err)
+		}
+		return httpClient, nil
+	}
+
+	creds, err := google.CredentialsFromJSON(baseCtx, credentialsJSON, gcsScope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse gcs credentials: %w", err)
+	}
+	return oauth2.NewClient(baseCtx, creds.TokenSource), nil
+}
+
+// Put 使用可恢复上传按分块流式写入快照，无需预先知道快照大小
+func (s *GCSStorage) Put(ctx context.Context, key string, r io.Reader, metadata map[string]string) (int64, error) {
+	session, err := s.startResumableUpload(ctx, key, metadata)
+	if err != nil {
+		return 0, err
+	}
+
+	buf := make([]byte, s.chunkSize)
+	var offset int64
+	for {
+		n, readErr := io.ReadFull(r, buf)
+		last := readErr == io.EOF || readErr == io.ErrUnexpectedEOF
+		if readErr != nil && !last {
+			s.cancelUpload(session)
+			return 0, fmt.Errorf("failed to read snapshot: %w", readErr)
+		}
+
+		if err := s.uploadChunk(ctx, session, buf[:n], offset, last); err != nil {
+			s.cancelUpload(session)
+			return 0, fmt.Errorf("failed to upload %s: %w", s.URL(key), err)
+		}
+		offset += int64(n)
+
+		if last {
+			return offset, nil
+		}
+	}
+}
+
+// Get 下载快照对象
+func (s *GCSStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	objectURL := fmt.Sprintf("%s/storage/v1/b/%s/o/%s?alt=media",
+		s.endpoint, url.PathEscape(s.bucket), url.PathEscape(key))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, objectURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", s.URL(key), err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, fmt.Errorf("failed to download %s: %w", s.URL(key), gcsError(resp))
+	}
+	return resp.Body, nil
+}
+
+// URL 返回 gs://bucket/key 形式的地址
+func (s *GCSStorage) URL(key string) string {
+	return fmt.Sprintf("gs://%s/%s", s.bucket, key)
+}
+
+// Key 从 gs://bucket/key 形式的地址中解析出对象 key
+func (s *GCSStorage) Key(url string) (string, error) {
+	prefix := fmt.Sprintf("gs://%s/", s.bucket)
+	if !strings.HasPrefix(url, prefix) || len(url) == len(prefix) {
+		return "", fmt.Errorf("backup url %q does not belong to bucket %s", url, s.bucket)
+	}
+	return strings.TrimPrefix(url, prefix), nil
+}
+
+// gcsObjectList 对象列表接口的响应
+type gcsObjectList struct {
+	Items []struct {
+		Name    string    `json:"name"`
+		Size    string    `json:"size"`
+		Updated time.Time `json:"updated"`
+	} `json:"items"`
+	NextPageToken string `json:"nextPageToken"`
+}
+
+// List 分页列出 prefix 下的所有对象
+func (s *GCSStorage) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+	pageToken := ""
+	for {
+		query := url.Values{}
+		query.Set("prefix", prefix)
+		query.Set("fields", "items(name,size,updated),nextPageToken")
+		if pageToken != "" {
+			query.Set("pageToken", pageToken)
+		}
+		listURL := fmt.Sprintf("%s/storage/v1/b/%s/o?%s", s.endpoint, url.PathEscape(s.bucket), query.Encode())
+
+		page, err := s.listPage(ctx, listURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", s.URL(prefix), err)
+		}
+		for _, item := range page.Items {
+			size, _ := strconv.ParseInt(item.Size, 10, 64)
+			objects = append(objects, ObjectInfo{Key: item.Name, Size: size, LastModified: item.Updated})
+		}
+
+		if page.NextPageToken == "" {
+			return objects, nil
+		}
+		pageToken = page.NextPageToken
+	}
+}
+
+// listPage 获取一页对象列表
+func (s *GCSStorage) listPage(ctx context.Context, listURL string) (*gcsObjectList, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, listURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, gcsError(resp)
+	}
+
+	page := &gcsObjectList{}
+	if err := json.NewDecoder(resp.Body).Decode(page); err != nil {
+		return nil, fmt.Errorf("failed to decode object list: %w", err)
+	}
+	return page, nil
+}
+
+// Delete 删除对象，对象不存在时视为成功
+func (s *GCSStorage) Delete(ctx context.Context, key string) error {
+	objectURL := fmt.Sprintf("%s/storage/v1/b/%s/o/%s", s.endpoint, url.PathEscape(s.bucket), url.PathEscape(key))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, objectURL, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to delete %s: %w", s.URL(key), err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("failed to delete %s: %w", s.URL(key), gcsError(resp))
+	}
+	return nil
+}
+
+// startResumableUpload 创建可恢复上传会话，返回会话地址
+func (s *GCSStorage) startResumableUpload(ctx context.Context, key string, metadata map[string]string) (string, error) {
+	uploadURL := fmt.Sprintf("%s/upload/storage/v1/b/%s/o?uploadType=resumable&name=%s",
+		s.endpoint, url.PathEscape(s.bucket), url.QueryEscape(key))
+
+	body, err := json.Marshal(map[string]interface{}{
+		"contentType": "application/octet-stream",
+		"metadata":    metadata,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, uploadURL, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+	req.Header.Set("X-Upload-Content-Type", "application/octet-stream")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to start upload of %s: %w", s.URL(key), err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to start upload of %s: %w", s.URL(key), gcsError(resp))
+	}
+
+	session := resp.Header.Get("Location")
+	if session == "" {
+		return "", fmt.Errorf("failed to start upload of %s: no session location returned", s.URL(key))
+	}
+	return session, nil
+}
+
+// uploadChunk 上传一个分块。最后一个分块需要声明对象总大小以完成上传。
+// 服务端以 308 响应时按 Range 头确认已持久化的字节数，只持久化了部分分块时重新发送剩余部分
+func (s *GCSStorage) uploadChunk(ctx context.Context, session string, chunk []byte, offset int64, last bool) error {
+	end := offset + int64(len(chunk))
+	for {
+		resp, err := s.putChunk(ctx, session, chunk, offset, last)
+		if err != nil {
+			return err
+		}
+		if last && (resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusCreated) {
+			resp.Body.Close()
+			return nil
+		}
+		if resp.StatusCode != http.StatusPermanentRedirect {
+			err := gcsError(resp)
+			resp.Body.Close()
+			return err
+		}
+
+		// 308 Resume Incomplete 表示会话仍在等待数据，Range 头给出已持久化的范围
+		persisted, err := persistedSize(resp)
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+		switch {
+		case persisted == end && !last:
+			return nil
+		case persisted == end && len(chunk) > 0:
+			// 数据已全部持久化但上传未完成，单独声明总大小结束上传
+			chunk, offset = nil, end
+		case persisted <= offset || persisted > end:
+			return fmt.Errorf("upload session persisted %d bytes after sending bytes up to offset %d", persisted, end)
+		default:
+			chunk, offset = chunk[persisted-offset:], persisted
+		}
+	}
+}
+
+// persistedSize 解析 308 响应的 Range 头（bytes=0-N），返回会话已持久化的字节数，没有 Range 头时为 0
+func persistedSize(resp *http.Response) (int64, error) {
+	header := resp.Header.Get("Range")
+	if header == "" {
+		return 0, nil
+	}
+	last, err := strconv.ParseInt(strings.TrimPrefix(header, "bytes=0-"), 10, 64)
+	if err != nil || !strings.HasPrefix(header, "bytes=0-") {
+		return 0, fmt.Errorf("invalid Range header %q in upload response", header)
+	}
+	return last + 1, nil
+}
+
+// putChunk 发送从 offset 开始的分块数据，调用方负责关闭响应
+func (s *GCSStorage) putChunk(ctx context.Context, session string, chunk []byte, offset int64, last bool) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, session, bytes.NewReader(chunk))
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = int64(len(chunk))
+
+	total := "*"
+	if last {
+		total = fmt.Sprintf("%d", offset+int64(len(chunk)))
+	}
+	if len(chunk) == 0 {
+		req.Header.Set("Content-Range", fmt.Sprintf("bytes */%s", total))
+	} else {
+		req.Header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%s", offset, offset+int64(len(chunk))-1, total))
+	}
+
+	return s.httpClient.Do(req)
+}
+
+// cancelUpload 尽力取消未完成的上传会话，避免残留
+func (s *GCSStorage) cancelUpload(session string) {
+	req, err := http.NewRequest(http.MethodDelete, session, nil)
+	if err != nil {
+		return
+	}
+	if resp, err := s.httpClient.Do(req); err == nil {
+		resp.Body.Close()
+	}
+}
+
+// gcsError 将失败的响应转换为错误
+func gcsError(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	return fmt.Errorf("gcs returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+}
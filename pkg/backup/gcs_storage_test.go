@@ -0,0 +1,162 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestGCSStorage(t *testing.T, server *fakeGCSServer, chunkSize int) *GCSStorage {
+	storage, err := NewGCSStorage(context.Background(), GCSConfig{
+		Bucket:     "etcd-backups",
+		Endpoint:   server.URL,
+		HTTPClient: server.Client(),
+		ChunkSize:  chunkSize,
+	})
+	require.NoError(t, err)
+	return storage
+}
+
+// TestGCSStorageResumableRoundTrip 测试分块可恢复上传并读回快照
+func TestGCSStorageResumableRoundTrip(t *testing.T) {
+	server := newFakeGCSServer()
+	defer server.Close()
+	storage := newTestGCSStorage(t, server, 256*1024)
+
+	// 640KiB 的快照应被切分为 3 个分块
+	data := bytes.Repeat([]byte("etcd"), 160*1024)
+	key := "gcp/test-cluster/20250801-123000.db"
+
+	size, err := storage.Put(context.Background(), key, bytes.NewReader(data), nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(data)), size)
+	assert.Equal(t, "gs://etcd-backups/gcp/test-cluster/20250801-123000.db", storage.URL(key))
+	parsed, err := storage.Key(storage.URL(key))
+	require.NoError(t, err)
+	assert.Equal(t, key, parsed)
+	assert.Equal(t, 3, server.chunkCount())
+
+	stored, ok := server.object("etcd-backups", key)
+	require.True(t, ok)
+	assert.Equal(t, data, stored)
+
+	rc, err := storage.Get(context.Background(), key)
+	require.NoError(t, err)
+	defer rc.Close()
+	downloaded, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	assert.Equal(t, data, downloaded)
+}
+
+// TestGCSStorageChunkAlignedSize 测试快照大小恰好为分块整数倍时以空分块结束上传
+func TestGCSStorageChunkAlignedSize(t *testing.T) {
+	server := newFakeGCSServer()
+	defer server.Close()
+	storage := newTestGCSStorage(t, server, 256*1024)
+
+	data := bytes.Repeat([]byte{0x42}, 2*256*1024)
+	size, err := storage.Put(context.Background(), "aligned.db", bytes.NewReader(data), nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(data)), size)
+
+	stored, ok := server.object("etcd-backups", "aligned.db")
+	require.True(t, ok)
+	assert.Equal(t, data, stored)
+}
+
+// TestGCSStorageResendsUnpersistedBytes 测试服务端只持久化了部分分块时按 Range 头重新发送剩余部分
+func TestGCSStorageResendsUnpersistedBytes(t *testing.T) {
+	server := newFakeGCSServer()
+	defer server.Close()
+	server.persistLimit = 100 * 1024
+	storage := newTestGCSStorage(t, server, 256*1024)
+
+	data := bytes.Repeat([]byte("etcd"), 160*1024)
+	size, err := storage.Put(context.Background(), "partial.db", bytes.NewReader(data), nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(data)), size)
+
+	stored, ok := server.object("etcd-backups", "partial.db")
+	require.True(t, ok)
+	assert.Equal(t, data, stored)
+	// 两个 256KiB 分块各分三次、最后 128KiB 的分块分两次才全部持久化
+	assert.Equal(t, 8, server.chunkCount())
+}
+
+// TestGCSStorageFailsWhenNothingPersisted 测试服务端没有持久化分块中的任何数据时上传失败，而不是跳过该分块
+func TestGCSStorageFailsWhenNothingPersisted(t *testing.T) {
+	server := newFakeGCSServer()
+	defer server.Close()
+	server.discardChunks = true
+	storage := newTestGCSStorage(t, server, 256*1024)
+
+	_, err := storage.Put(context.Background(), "lost.db", bytes.NewReader(bytes.Repeat([]byte("etcd"), 160*1024)), nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "persisted 0 bytes after sending bytes up to offset 262144")
+	_, ok := server.object("etcd-backups", "lost.db")
+	assert.False(t, ok)
+}
+
+// TestGCSStorageGetMissingObject 测试读取不存在的对象时返回错误
+func TestGCSStorageGetMissingObject(t *testing.T) {
+	server := newFakeGCSServer()
+	defer server.Close()
+	storage := newTestGCSStorage(t, server, 0)
+
+	_, err := storage.Get(context.Background(), "missing.db")
+	assert.Error(t, err)
+}
+
+// TestNewGCSStorageRejectsUnalignedChunkSize 测试拒绝未按 256KiB 对齐的分块大小
+func TestNewGCSStorageRejectsUnalignedChunkSize(t *testing.T) {
+	_, err := NewGCSStorage(context.Background(), GCSConfig{
+		Bucket:     "etcd-backups",
+		HTTPClient: &http.Client{},
+		ChunkSize:  1000,
+	})
+	assert.Error(t, err)
+}
+
+// TestGCSStorageListAndDelete 测试分页列出和删除快照
+func TestGCSStorageListAndDelete(t *testing.T) {
+	server := newFakeGCSServer()
+	defer server.Close()
+	storage := newTestGCSStorage(t, server, 0)
+
+	for _, name := range []string{"100000", "110000", "120000"} {
+		server.putObject("etcd-backups", "gcp/test-cluster/20250801-"+name+".db", []byte(name))
+	}
+	server.putObject("etcd-backups", "gcp/other-cluster/20250801-100000.db", []byte("other"))
+
+	objects, err := storage.List(context.Background(), "gcp/test-cluster/")
+	require.NoError(t, err)
+	require.Len(t, objects, 3)
+	assert.Equal(t, "gcp/test-cluster/20250801-120000.db", objects[2].Key)
+	assert.Equal(t, int64(6), objects[2].Size)
+
+	require.NoError(t, storage.Delete(context.Background(), "gcp/test-cluster/20250801-100000.db"))
+	require.NoError(t, storage.Delete(context.Background(), "gcp/test-cluster/20250801-100000.db"), "deleting a missing object should succeed")
+	_, ok := server.object("etcd-backups", "gcp/test-cluster/20250801-100000.db")
+	assert.False(t, ok)
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// ErrReadOnlyStorage 只读存储不支持写入、列举和删除
+var ErrReadOnlyStorage = errors.New("storage is read-only")
+
+// HTTPConfig HTTP 存储的请求配置
+type HTTPConfig struct {
+	// Header 附加到每个请求的请求头，例如认证信息
+	Header http.Header
+	// HTTPClient 自定义 HTTP 客户端，为空时使用 http.DefaultClient
+	HTTPClient *http.Client
+}
+
+// HTTPStorage 通过 HTTP(S) GET 下载快照的只读存储，对象 key 即完整的下载地址。
+// 用于从 Operator 之外产生的快照恢复
+type HTTPStorage struct {
+	httpClient *http.Client
+	header     http.Header
+}
+
+var _ Storage = (*HTTPStorage)(nil)
+
+// NewHTTPStorage 创建 HTTP 存储
+func NewHTTPStorage(cfg HTTPConfig) *HTTPStorage {
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &HTTPStorage{
+		httpClient: httpClient,
+		header:     cfg.Header.Clone(),
+	}
+}
+
+// Put 实现 Storage 接口，HTTP 存储不支持写入
+func (s *HTTPStorage) Put(ctx context.Context, key string, r io.Reader, metadata map[string]string) (int64, error) {
+	return 0, ErrReadOnlyStorage
+}
+
+// Get 下载 key 对应的快照
+func (s *HTTPStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	if _, err := s.Key(key); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range s.header {
+		req.Header[name] = values
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download snapshot: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("failed to download snapshot %s: %s", redactURL(key), resp.Status)
+	}
+	return resp.Body, nil
+}
+
+// URL 实现 Storage 接口，key 本身就是下载地址
+func (s *HTTPStorage) URL(key string) string {
+	return key
+}
+
+// Key 校验下载地址并原样返回
+func (s *HTTPStorage) Key(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid snapshot url: %w", err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("snapshot url %s is not an http(s) url", redactURL(rawURL))
+	}
+	return rawURL, nil
+}
+
+// List 实现 Storage 接口，HTTP 存储不支持列举
+func (s *HTTPStorage) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	return nil, ErrReadOnlyStorage
+}
+
+// Delete 实现 Storage 接口，HTTP 存储不支持删除
+func (s *HTTPStorage) Delete(ctx context.Context, key string) error {
+	return ErrReadOnlyStorage
+}
+
+// redactURL 去掉地址中的用户信息和查询参数 (可能包含预签名凭据)，用于错误信息
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "<invalid url>"
+	}
+	u.User = nil
+	u.RawQuery = ""
+	u.Fragment = ""
+	return u.String()
+}
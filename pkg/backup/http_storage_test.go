@@ -0,0 +1,85 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestHTTPStorageGet 测试 HTTP 存储下载快照并携带配置的请求头
+func TestHTTPStorageGet(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/snapshots/etcd.db" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = io.WriteString(w, "snapshot")
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	storage := NewHTTPStorage(HTTPConfig{Header: http.Header{"Authorization": {"Bearer secret"}}})
+
+	key, err := storage.Key(server.URL + "/snapshots/etcd.db")
+	require.NoError(t, err)
+	assert.Equal(t, server.URL+"/snapshots/etcd.db", storage.URL(key))
+
+	rc, err := storage.Get(ctx, key)
+	require.NoError(t, err)
+	data, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	require.NoError(t, rc.Close())
+	assert.Equal(t, "snapshot", string(data))
+
+	_, err = storage.Get(ctx, server.URL+"/snapshots/missing.db?X-Amz-Signature=abc")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "404")
+	assert.NotContains(t, err.Error(), "X-Amz-Signature")
+
+	_, err = NewHTTPStorage(HTTPConfig{}).Get(ctx, key)
+	assert.Contains(t, err.Error(), "401")
+}
+
+// TestHTTPStorageReadOnly 测试 HTTP 存储拒绝写入和非 HTTP 地址
+func TestHTTPStorageReadOnly(t *testing.T) {
+	ctx := context.Background()
+	storage := NewHTTPStorage(HTTPConfig{})
+
+	_, err := storage.Put(ctx, "http://example.com/etcd.db", strings.NewReader("x"), nil)
+	assert.True(t, errors.Is(err, ErrReadOnlyStorage))
+	_, err = storage.List(ctx, "")
+	assert.True(t, errors.Is(err, ErrReadOnlyStorage))
+	assert.True(t, errors.Is(storage.Delete(ctx, "http://example.com/etcd.db"), ErrReadOnlyStorage))
+
+	for _, rawURL := range []string{"s3://bucket/etcd.db", "file:///etc/passwd", "/etcd.db"} {
+		_, err := storage.Key(rawURL)
+		assert.Error(t, err, rawURL)
+	}
+}
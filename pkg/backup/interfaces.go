@@ -0,0 +1,55 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// ObjectInfo 存储中一个快照对象的信息
+type ObjectInfo struct {
+	// Key 对象 key
+	Key string
+	// Size 对象大小 (字节)
+	Size int64
+	// LastModified 对象最后修改时间
+	LastModified time.Time
+}
+
+// Storage 快照存储后端接口，不同的 StorageType 对应不同实现
+type Storage interface {
+	// Put 将快照流写入 key 对应的位置，返回写入的字节数。
+	// metadata 作为对象元数据保存，不支持元数据的后端可以忽略
+	Put(ctx context.Context, key string, r io.Reader, metadata map[string]string) (int64, error)
+
+	// Get 打开 key 对应的快照用于读取，调用方负责关闭
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// URL 返回 key 对应对象的可读地址，记录在备份状态中
+	URL(key string) string
+
+	// Key 是 URL 的逆操作，从备份状态中记录的地址解析出对象 key
+	Key(url string) (string, error)
+
+	// List 列出 key 以 prefix 开头的所有对象
+	List(ctx context.Context, prefix string) ([]ObjectInfo, error)
+
+	// Delete 删除 key 对应的对象，对象不存在时不返回错误
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,204 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LocalStorage 将快照保存到 Operator 挂载的 PVC 目录中
+type LocalStorage struct {
+	rootDir string
+}
+
+// NewLocalStorage 创建本地存储
+func NewLocalStorage(rootDir string) *LocalStorage {
+	return &LocalStorage{
+		rootDir: rootDir,
+	}
+}
+
+var _ Storage = (*LocalStorage)(nil)
+
+// Put 以原子方式写入快照：先写入临时文件，完成后再重命名。
+// 本地文件不保存元数据，压缩算法等信息可从扩展名和备份状态中获得
+func (s *LocalStorage) Put(ctx context.Context, key string, r io.Reader, _ map[string]string) (int64, error) {
+	target, err := s.resolve(key)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0o750); err != nil {
+		return 0, fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(target), filepath.Base(target)+".part-*")
+	if err != nil {
+		return 0, fmt.Errorf("failed to create temporary backup file: %w", err)
+	}
+	// 出错时清理临时文件，重命名成功后 Remove 会返回 NotExist，忽略即可
+	defer os.Remove(tmp.Name())
+
+	written, err := io.Copy(tmp, &contextReader{ctx: ctx, r: r})
+	if err != nil {
+		tmp.Close()
+		return 0, fmt.Errorf("failed to write backup file: %w", err)
+	}
+
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return 0, fmt.Errorf("failed to sync backup file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return 0, fmt.Errorf("failed to close backup file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		return 0, fmt.Errorf("failed to move backup file into place: %w", err)
+	}
+
+	return written, nil
+}
+
+// Get 打开本地快照文件
+func (s *LocalStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	target, err := s.resolve(key)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open backup file: %w", err)
+	}
+	return f, nil
+}
+
+// URL 本地存储直接使用文件的绝对路径作为地址
+func (s *LocalStorage) URL(key string) string {
+	return s.Path(key)
+}
+
+// Key 将快照文件路径还原为相对于根目录的 key
+func (s *LocalStorage) Key(url string) (string, error) {
+	root := filepath.Clean(s.rootDir)
+	target := filepath.Clean(url)
+	if !strings.HasPrefix(target, root+string(filepath.Separator)) {
+		return "", fmt.Errorf("backup path %q is outside of %s", url, root)
+	}
+	return filepath.ToSlash(strings.TrimPrefix(target, root+string(filepath.Separator))), nil
+}
+
+// List 遍历根目录下以 prefix 开头的快照文件，跳过未完成的临时文件
+func (s *LocalStorage) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	root := filepath.Clean(s.rootDir)
+	// 从 prefix 所在的目录开始遍历，避免扫描整个备份卷
+	dir := root
+	if i := strings.LastIndex(prefix, "/"); i > 0 {
+		target, err := s.resolve(prefix[:i])
+		if err != nil {
+			return nil, err
+		}
+		dir = target
+	}
+
+	var objects []ObjectInfo
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if d.IsDir() || strings.Contains(d.Name(), ".part-") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		objects = append(objects, ObjectInfo{Key: key, Size: info.Size(), LastModified: info.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list backups under %s: %w", prefix, err)
+	}
+	return objects, nil
+}
+
+// Delete 删除本地快照文件
+func (s *LocalStorage) Delete(_ context.Context, key string) error {
+	target, err := s.resolve(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(target); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete backup file: %w", err)
+	}
+	return nil
+}
+
+// Path 返回对象在本地文件系统中的绝对路径
+func (s *LocalStorage) Path(key string) string {
+	target, err := s.resolve(key)
+	if err != nil {
+		return filepath.Join(s.rootDir, key)
+	}
+	return target
+}
+
+// resolve 将对象 key 转换为根目录下的路径，拒绝逃逸出根目录的 key
+func (s *LocalStorage) resolve(key string) (string, error) {
+	root := filepath.Clean(s.rootDir)
+	target := filepath.Join(root, filepath.FromSlash(key))
+	if target == root || !strings.HasPrefix(target, root+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid backup key %q", key)
+	}
+	return target, nil
+}
+
+// contextReader 在每次读取前检查 ctx，使长时间的拷贝可以被取消
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (cr *contextReader) Read(p []byte) (int, error) {
+	if err := cr.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return cr.r.Read(p)
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLocalStorageSave 测试快照写入本地目录
+func TestLocalStorageSave(t *testing.T) {
+	root := t.TempDir()
+	storage := NewLocalStorage(root)
+
+	key := SnapshotKey("default", "test-cluster", "nightly", time.Date(2025, 8, 1, 12, 30, 0, 0, time.UTC))
+	assert.Equal(t, "default/test-cluster/20250801-123000-nightly.db", key)
+
+	size, err := storage.Put(context.Background(), key, strings.NewReader("snapshot-data"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len("snapshot-data")), size)
+
+	path := storage.Path(key)
+	assert.Equal(t, filepath.Join(root, "default", "test-cluster", "20250801-123000-nightly.db"), path)
+
+	parsed, err := storage.Key(storage.URL(key))
+	require.NoError(t, err)
+	assert.Equal(t, key, parsed)
+	_, err = storage.Key("/elsewhere/20250801-123000.db")
+	assert.Error(t, err)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "snapshot-data", string(data))
+
+	// 不应残留临时文件
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+// TestLocalStorageRejectsEscapingKeys 测试拒绝逃逸出根目录的 key
+func TestLocalStorageRejectsEscapingKeys(t *testing.T) {
+	storage := NewLocalStorage(t.TempDir())
+
+	for _, key := range []string{"../outside.db", "a/../../outside.db", ""} {
+		_, err := storage.Put(context.Background(), key, strings.NewReader("data"), nil)
+		assert.Error(t, err, "key %q should be rejected", key)
+	}
+}
+
+// TestLocalStorageCancelled 测试取消的上下文会中断写入
+func TestLocalStorageCancelled(t *testing.T) {
+	root := t.TempDir()
+	storage := NewLocalStorage(root)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := storage.Put(ctx, "default/test/a.db", strings.NewReader("data"), nil)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	_, statErr := os.Stat(filepath.Join(root, "default", "test", "a.db"))
+	assert.True(t, os.IsNotExist(statErr))
+}
+
+// TestLocalStorageListAndDelete 测试列出和删除本地快照
+func TestLocalStorageListAndDelete(t *testing.T) {
+	root := t.TempDir()
+	storage := NewLocalStorage(root)
+	ctx := context.Background()
+
+	for _, key := range []string{
+		"default/test-cluster/20250801-100000.db",
+		"default/test-cluster/20250801-110000.db",
+		"default/other-cluster/20250801-100000.db",
+	} {
+		_, err := storage.Put(ctx, key, strings.NewReader("data"), nil)
+		require.NoError(t, err)
+	}
+	// 未完成的临时文件不应被列出
+	require.NoError(t, os.WriteFile(filepath.Join(root, "default", "test-cluster", "20250801-120000.db.part-1"), []byte("x"), 0o600))
+
+	objects, err := storage.List(ctx, "default/test-cluster/")
+	require.NoError(t, err)
+	require.Len(t, objects, 2)
+	assert.Equal(t, "default/test-cluster/20250801-100000.db", objects[0].Key)
+	assert.Equal(t, int64(4), objects[0].Size)
+
+	objects, err = storage.List(ctx, "default/missing-cluster/")
+	require.NoError(t, err)
+	assert.Empty(t, objects)
+
+	require.NoError(t, storage.Delete(ctx, "default/test-cluster/20250801-100000.db"))
+	require.NoError(t, storage.Delete(ctx, "default/test-cluster/20250801-100000.db"))
+	objects, err = storage.List(ctx, "default/test-cluster/")
+	require.NoError(t, err)
+	assert.Len(t, objects, 1)
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"path"
+	"strings"
+	"time"
+)
+
+// SnapshotTimeFormat 快照文件名中使用的时间格式 (UTC)
+const SnapshotTimeFormat = "20060102-150405"
+
+// SnapshotExtension 快照文件扩展名
+const SnapshotExtension = ".db"
+
+// SnapshotKey 生成快照对象 key，格式为 <prefix>/<cluster>/<timestamp>-<owner>.db。
+// owner 为创建快照的 EtcdBackup 名称，定时备份的各次执行使用父对象的名称，保留策略按 owner 区分各自的快照
+func SnapshotKey(prefix, clusterName, owner string, t time.Time) string {
+	return path.Join(ClusterSnapshotDir(prefix, clusterName), t.UTC().Format(SnapshotTimeFormat)+"-"+owner+SnapshotExtension)
+}
+
+// ClusterSnapshotDir 返回集群的快照所在的目录
+func ClusterSnapshotDir(prefix, clusterName string) string {
+	return path.Join(prefix, clusterName)
+}
+
+// SnapshotTime 从快照 key 中解析快照时间，key 不符合命名格式时返回 false
+func SnapshotTime(key string) (time.Time, bool) {
+	t, _, ok := ParseSnapshotKey(key)
+	return t, ok
+}
+
+// ParseSnapshotKey 从快照 key 中解析快照时间和 owner，key 不符合命名格式时返回 false。
+// 早期版本写入的快照名为 <timestamp>.db，不带 owner，此时 owner 为空。
+// 快照扩展名之后可以带有压缩等附加扩展名，例如 20250801-120000-hourly.db.gz
+func ParseSnapshotKey(key string) (time.Time, string, bool) {
+	name := path.Base(key)
+	i := strings.LastIndex(name, SnapshotExtension)
+	if i < len(SnapshotTimeFormat) {
+		return time.Time{}, "", false
+	}
+	if rest := name[i+len(SnapshotExtension):]; rest != "" && !strings.HasPrefix(rest, ".") {
+		return time.Time{}, "", false
+	}
+	t, err := time.Parse(SnapshotTimeFormat, name[:len(SnapshotTimeFormat)])
+	if err != nil {
+		return time.Time{}, "", false
+	}
+
+	owner := name[len(SnapshotTimeFormat):i]
+	switch {
+	case owner == "":
+		return t, "", true
+	case strings.HasPrefix(owner, "-") && len(owner) > 1:
+		return t, owner[1:], true
+	default:
+		return time.Time{}, "", false
+	}
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"io"
+)
+
+// SnapshotFormat 存储中快照的编码信息
+type SnapshotFormat struct {
+	// Codec 压缩算法
+	Codec Codec
+	// KeyID 加密密钥 ID，未加密时为空
+	KeyID string
+}
+
+// OpenSnapshotReader 将存储中的快照还原为原始 etcd 快照流：先按 key ID 解密，再解压。
+// 压缩和加密都从数据头部识别，因此可以直接读取任意来源的快照
+func OpenSnapshotReader(r io.Reader, keys KeyRing) (io.ReadCloser, SnapshotFormat, error) {
+	decrypted, keyID, err := DecryptReader(r, keys)
+	if err != nil {
+		return nil, SnapshotFormat{KeyID: keyID}, err
+	}
+
+	decompressed, codec, err := DecompressReader(decrypted)
+	if err != nil {
+		return nil, SnapshotFormat{KeyID: keyID}, err
+	}
+
+	return decompressed, SnapshotFormat{Codec: codec, KeyID: keyID}, nil
+}
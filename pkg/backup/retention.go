@@ -0,0 +1,100 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// dayWeekPattern 匹配 MaxAge 中的天 (d) 和周 (w) 单位
+var dayWeekPattern = regexp.MustCompile(`(\d+(?:\.\d+)?)([dw])`)
+
+// ParseMaxAge 解析保留时长，在 Go duration 的基础上支持 d (天) 和 w (周)，例如 "30d"、"1w12h"
+func ParseMaxAge(s string) (time.Duration, error) {
+	var convErr error
+	normalized := dayWeekPattern.ReplaceAllStringFunc(s, func(m string) string {
+		parts := dayWeekPattern.FindStringSubmatch(m)
+		n, err := strconv.ParseFloat(parts[1], 64)
+		if err != nil {
+			convErr = err
+			return m
+		}
+		hours := n * 24
+		if parts[2] == "w" {
+			hours *= 7
+		}
+		return strconv.FormatFloat(hours, 'f', -1, 64) + "h"
+	})
+	if convErr != nil {
+		return 0, fmt.Errorf("invalid max age %q: %w", s, convErr)
+	}
+
+	d, err := time.ParseDuration(normalized)
+	if err != nil {
+		return 0, fmt.Errorf("invalid max age %q: %w", s, err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("invalid max age %q: must be positive", s)
+	}
+	return d, nil
+}
+
+// RetentionPolicy 快照保留策略，零值字段表示不限制
+type RetentionPolicy struct {
+	// MaxBackups 最多保留的快照数
+	MaxBackups int
+	// MaxAge 快照最长保留时间
+	MaxAge time.Duration
+}
+
+// SelectExpired 返回超出保留策略的快照。快照时间取自 key 中的时间戳，
+// 不符合快照命名格式的对象被忽略，避免误删前缀下的其他文件
+func SelectExpired(objects []ObjectInfo, policy RetentionPolicy, now time.Time) []ObjectInfo {
+	type snapshot struct {
+		ObjectInfo
+		taken time.Time
+	}
+
+	snapshots := make([]snapshot, 0, len(objects))
+	for _, obj := range objects {
+		taken, ok := SnapshotTime(obj.Key)
+		if !ok {
+			continue
+		}
+		snapshots = append(snapshots, snapshot{ObjectInfo: obj, taken: taken})
+	}
+
+	// 按时间从新到旧排序
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].taken.After(snapshots[j].taken)
+	})
+
+	var expired []ObjectInfo
+	for i, snap := range snapshots {
+		switch {
+		case policy.MaxBackups > 0 && i >= policy.MaxBackups:
+			expired = append(expired, snap.ObjectInfo)
+		case policy.MaxAge > 0 && now.Sub(snap.taken) > policy.MaxAge:
+			expired = append(expired, snap.ObjectInfo)
+		}
+	}
+	return expired
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseMaxAge 测试保留时长解析
+func TestParseMaxAge(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{input: "36h", want: 36 * time.Hour},
+		{input: "30d", want: 30 * 24 * time.Hour},
+		{input: "2w", want: 14 * 24 * time.Hour},
+		{input: "1w12h", want: 7*24*time.Hour + 12*time.Hour},
+		{input: "1.5d", want: 36 * time.Hour},
+		{input: "", wantErr: true},
+		{input: "0d", wantErr: true},
+		{input: "30days", wantErr: true},
+		{input: "d", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseMaxAge(tt.input)
+		if tt.wantErr {
+			assert.Error(t, err, tt.input)
+			continue
+		}
+		require.NoError(t, err, tt.input)
+		assert.Equal(t, tt.want, got, tt.input)
+	}
+}
+
+// TestSelectExpired 测试按数量和时间选择需要清理的快照
+func TestSelectExpired(t *testing.T) {
+	now := time.Date(2025, 8, 10, 0, 0, 0, 0, time.UTC)
+	objects := []ObjectInfo{
+		{Key: "default/test/20250801-000000.db"},
+		{Key: "default/test/20250809-000000.db"},
+		{Key: "default/test/20250805-000000.db"},
+		{Key: "default/test/20250807-000000.db"},
+		{Key: "default/test/notes.txt"},
+	}
+
+	keys := func(objs []ObjectInfo) []string {
+		var out []string
+		for _, o := range objs {
+			out = append(out, o.Key)
+		}
+		return out
+	}
+
+	// 只按数量：保留最新的 2 个
+	expired := SelectExpired(objects, RetentionPolicy{MaxBackups: 2}, now)
+	assert.Equal(t, []string{"default/test/20250805-000000.db", "default/test/20250801-000000.db"}, keys(expired))
+
+	// 只按时间：删除 4 天前的快照
+	expired = SelectExpired(objects, RetentionPolicy{MaxAge: 4 * 24 * time.Hour}, now)
+	assert.Equal(t, []string{"default/test/20250805-000000.db", "default/test/20250801-000000.db"}, keys(expired))
+
+	// 同时设置时取并集
+	expired = SelectExpired(objects, RetentionPolicy{MaxBackups: 3, MaxAge: 2 * 24 * time.Hour}, now)
+	assert.Equal(t, []string{"default/test/20250807-000000.db", "default/test/20250805-000000.db", "default/test/20250801-000000.db"}, keys(expired))
+
+	// 未设置策略时不清理
+	assert.Empty(t, SelectExpired(objects, RetentionPolicy{}, now))
+}
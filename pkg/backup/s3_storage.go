@@ -0,0 +1,202 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+const (
+	// DefaultS3Region 未指定区域时使用的默认区域
+	DefaultS3Region = "us-east-1"
+
+	// DefaultS3Endpoint 未指定 Endpoint 时使用 AWS S3
+	DefaultS3Endpoint = "s3.amazonaws.com"
+
+	// DefaultS3PartSize 分片上传的分片大小，快照大小未知时按该大小切分
+	DefaultS3PartSize uint64 = 16 * 1024 * 1024
+)
+
+// S3Config S3 兼容存储的连接配置
+type S3Config struct {
+	// Bucket 存储桶名称
+	Bucket string
+	// Region 区域，为空时使用 DefaultS3Region
+	Region string
+	// Endpoint 自定义端点 (如 MinIO)，可带 http:// 或 https:// 前缀，无前缀时使用 https
+	Endpoint string
+	// AccessKey/SecretKey 静态凭据，为空时从环境变量或 IAM 获取
+	AccessKey string
+	SecretKey string
+	// PartSize 分片大小，为空时使用 DefaultS3PartSize
+	PartSize uint64
+}
+
+// S3Storage 将快照上传到 S3 兼容的对象存储
+type S3Storage struct {
+	client   *minio.Client
+	bucket   string
+	partSize uint64
+}
+
+var _ Storage = (*S3Storage)(nil)
+
+// NewS3Storage 创建 S3 存储。指定自定义 Endpoint 时使用 path-style 寻址，以兼容 MinIO 等实现
+func NewS3Storage(cfg S3Config) (*S3Storage, error) {
+	if cfg.Bucket == "" {
+		return nil, fmt.Errorf("s3 bucket cannot be empty")
+	}
+
+	region := cfg.Region
+	if region == "" {
+		region = DefaultS3Region
+	}
+
+	endpoint, secure, err := parseS3Endpoint(cfg.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	lookup := minio.BucketLookupAuto
+	if cfg.Endpoint != "" {
+		lookup = minio.BucketLookupPath
+	}
+
+	var creds *credentials.Credentials
+	if cfg.AccessKey != "" || cfg.SecretKey != "" {
+		creds = credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, "")
+	} else {
+		creds = credentials.NewChainCredentials([]credentials.Provider{
+			&credentials.EnvAWS{},
+			&credentials.EnvMinio{},
+			&credentials.IAM{},
+		})
+	}
+
+	client, err := minio.New(endpoint, &minio.Options{
+		Creds:        creds,
+		Secure:       secure,
+		Region:       region,
+		BucketLookup: lookup,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create s3 client: %w", err)
+	}
+
+	partSize := cfg.PartSize
+	if partSize == 0 {
+		partSize = DefaultS3PartSize
+	}
+
+	return &S3Storage{
+		client:   client,
+		bucket:   cfg.Bucket,
+		partSize: partSize,
+	}, nil
+}
+
+// Put 以分片上传方式流式写入快照，无需预先知道快照大小
+func (s *S3Storage) Put(ctx context.Context, key string, r io.Reader, metadata map[string]string) (int64, error) {
+	info, err := s.client.PutObject(ctx, s.bucket, key, r, -1, minio.PutObjectOptions{
+		ContentType:  "application/octet-stream",
+		PartSize:     s.partSize,
+		UserMetadata: metadata,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to upload %s: %w", s.URL(key), err)
+	}
+	return info.Size, nil
+}
+
+// Get 下载快照对象
+func (s *S3Storage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	obj, err := s.client.GetObject(ctx, s.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", s.URL(key), err)
+	}
+	// GetObject 是惰性的，先 Stat 一次以便尽早暴露对象不存在等错误
+	if _, err := obj.Stat(); err != nil {
+		obj.Close()
+		return nil, fmt.Errorf("failed to download %s: %w", s.URL(key), err)
+	}
+	return obj, nil
+}
+
+// URL 返回 s3://bucket/key 形式的地址
+func (s *S3Storage) URL(key string) string {
+	return fmt.Sprintf("s3://%s/%s", s.bucket, key)
+}
+
+// Key 从 s3://bucket/key 形式的地址中解析出对象 key
+func (s *S3Storage) Key(url string) (string, error) {
+	prefix := fmt.Sprintf("s3://%s/", s.bucket)
+	if !strings.HasPrefix(url, prefix) || len(url) == len(prefix) {
+		return "", fmt.Errorf("backup url %q does not belong to bucket %s", url, s.bucket)
+	}
+	return strings.TrimPrefix(url, prefix), nil
+}
+
+// List 列出 prefix 下的所有对象
+func (s *S3Storage) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+	for obj := range s.client.ListObjects(ctx, s.bucket, minio.ListObjectsOptions{Prefix: prefix, Recursive: true}) {
+		if obj.Err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", s.URL(prefix), obj.Err)
+		}
+		objects = append(objects, ObjectInfo{Key: obj.Key, Size: obj.Size, LastModified: obj.LastModified})
+	}
+	return objects, nil
+}
+
+// Delete 删除对象，S3 删除不存在的对象时同样返回成功
+func (s *S3Storage) Delete(ctx context.Context, key string) error {
+	if err := s.client.RemoveObject(ctx, s.bucket, key, minio.RemoveObjectOptions{}); err != nil {
+		return fmt.Errorf("failed to delete %s: %w", s.URL(key), err)
+	}
+	return nil
+}
+
+// parseS3Endpoint 解析端点，返回 host[:port] 以及是否使用 TLS
+func parseS3Endpoint(endpoint string) (string, bool, error) {
+	if endpoint == "" {
+		return DefaultS3Endpoint, true, nil
+	}
+
+	if !strings.Contains(endpoint, "://") {
+		return strings.TrimSuffix(endpoint, "/"), true, nil
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", false, fmt.Errorf("invalid s3 endpoint %q: %w", endpoint, err)
+	}
+	switch u.Scheme {
+	case "http":
+		return u.Host, false, nil
+	case "https":
+		return u.Host, true, nil
+	default:
+		return "", false, fmt.Errorf("invalid s3 endpoint %q: unsupported scheme %q", endpoint, u.Scheme)
+	}
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestS3StorageMultipartRoundTrip 测试通过 path-style 分片上传并读回快照
+func TestS3StorageMultipartRoundTrip(t *testing.T) {
+	server := newFakeS3Server()
+	defer server.Close()
+
+	storage, err := NewS3Storage(S3Config{
+		Bucket:    "etcd-backups",
+		Endpoint:  server.URL,
+		AccessKey: "minio",
+		SecretKey: "minio123",
+		PartSize:  5 * 1024 * 1024,
+	})
+	require.NoError(t, err)
+
+	// 12MiB 的快照应被切分为 3 个分片
+	data := bytes.Repeat([]byte("etcd"), 3*1024*1024)
+	key := "prod/test-cluster/20250801-123000.db"
+
+	size, err := storage.Put(context.Background(), key, bytes.NewReader(data), nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(data)), size)
+	assert.Equal(t, "s3://etcd-backups/prod/test-cluster/20250801-123000.db", storage.URL(key))
+	parsed, err := storage.Key(storage.URL(key))
+	require.NoError(t, err)
+	assert.Equal(t, key, parsed)
+	_, err = storage.Key("s3://other-bucket/" + key)
+	assert.Error(t, err)
+
+	stored, ok := server.object("etcd-backups", key)
+	require.True(t, ok)
+	assert.Equal(t, data, stored)
+
+	var partUploads int
+	for _, req := range server.requestLog() {
+		assert.True(t, strings.HasPrefix(strings.SplitN(req, " ", 2)[1], "/etcd-backups/"), "expected path-style request, got %s", req)
+		if strings.HasPrefix(req, "PUT ") && strings.Contains(req, "partNumber=") {
+			partUploads++
+		}
+	}
+	assert.Equal(t, 3, partUploads)
+
+	rc, err := storage.Get(context.Background(), key)
+	require.NoError(t, err)
+	defer rc.Close()
+	downloaded, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	assert.Equal(t, data, downloaded)
+}
+
+// TestS3StorageGetMissingObject 测试读取不存在的对象时返回错误
+func TestS3StorageGetMissingObject(t *testing.T) {
+	server := newFakeS3Server()
+	defer server.Close()
+
+	storage, err := NewS3Storage(S3Config{
+		Bucket:    "etcd-backups",
+		Endpoint:  server.URL,
+		AccessKey: "minio",
+		SecretKey: "minio123",
+	})
+	require.NoError(t, err)
+
+	_, err = storage.Get(context.Background(), "missing.db")
+	assert.Error(t, err)
+}
+
+// TestParseS3Endpoint 测试端点解析
+func TestParseS3Endpoint(t *testing.T) {
+	tests := []struct {
+		endpoint string
+		host     string
+		secure   bool
+		wantErr  bool
+	}{
+		{endpoint: "", host: DefaultS3Endpoint, secure: true},
+		{endpoint: "http://minio.storage:9000", host: "minio.storage:9000", secure: false},
+		{endpoint: "https://minio.example.com", host: "minio.example.com", secure: true},
+		{endpoint: "minio.example.com:9000", host: "minio.example.com:9000", secure: true},
+		{endpoint: "ftp://minio", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		host, secure, err := parseS3Endpoint(tt.endpoint)
+		if tt.wantErr {
+			assert.Error(t, err, tt.endpoint)
+			continue
+		}
+		require.NoError(t, err, tt.endpoint)
+		assert.Equal(t, tt.host, host, tt.endpoint)
+		assert.Equal(t, tt.secure, secure, tt.endpoint)
+	}
+}
+
+// TestS3StorageListAndDelete 测试列出和删除快照
+func TestS3StorageListAndDelete(t *testing.T) {
+	server := newFakeS3Server()
+	defer server.Close()
+
+	storage, err := NewS3Storage(S3Config{
+		Bucket:    "etcd-backups",
+		Endpoint:  server.URL,
+		AccessKey: "minio",
+		SecretKey: "minio123",
+	})
+	require.NoError(t, err)
+
+	server.putObject("etcd-backups", "prod/test-cluster/20250801-100000.db", []byte("one"))
+	server.putObject("etcd-backups", "prod/test-cluster/20250801-110000.db", []byte("two"))
+	server.putObject("etcd-backups", "prod/other-cluster/20250801-100000.db", []byte("other"))
+
+	objects, err := storage.List(context.Background(), "prod/test-cluster/")
+	require.NoError(t, err)
+	require.Len(t, objects, 2)
+	assert.Equal(t, "prod/test-cluster/20250801-100000.db", objects[0].Key)
+	assert.Equal(t, int64(3), objects[0].Size)
+
+	require.NoError(t, storage.Delete(context.Background(), "prod/test-cluster/20250801-100000.db"))
+	_, ok := server.object("etcd-backups", "prod/test-cluster/20250801-100000.db")
+	assert.False(t, ok)
+}
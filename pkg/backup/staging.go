@@ -0,0 +1,220 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// SnapshotServerPathPrefix 快照下载地址的路径前缀，完整路径为 /snapshots/<id>
+	SnapshotServerPathPrefix = "/snapshots/"
+
+	// stagedTokenExtension 暂存快照对应的下载令牌文件扩展名
+	stagedTokenExtension = ".token"
+)
+
+// stagedIDPattern 暂存 ID 只允许出现在文件名中安全的字符
+var stagedIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+// StagedSnapshot 暂存在 Operator 备份卷上、等待恢复任务下载的明文快照
+type StagedSnapshot struct {
+	// ID 暂存 ID，通常为 EtcdRestore 的 UID
+	ID string
+	// Path 快照文件路径
+	Path string
+	// Token 下载快照时需要携带的令牌
+	Token string
+	// Size 快照大小 (字节)
+	Size int64
+}
+
+// SnapshotStager 管理恢复过程中暂存的快照。
+// 快照在 Operator 内完成解密、解压和校验后写入暂存目录，成员 PVC 的初始化任务再通过 SnapshotServer 下载
+type SnapshotStager struct {
+	storage *LocalStorage
+}
+
+// NewSnapshotStager 创建快照暂存器
+func NewSnapshotStager(dir string) *SnapshotStager {
+	return &SnapshotStager{
+		storage: NewLocalStorage(dir),
+	}
+}
+
+// Stage 将快照流写入暂存目录并生成下载令牌，同一 ID 重复暂存会覆盖之前的内容
+func (s *SnapshotStager) Stage(ctx context.Context, id string, r io.Reader) (*StagedSnapshot, error) {
+	if !stagedIDPattern.MatchString(id) {
+		return nil, fmt.Errorf("invalid staged snapshot id %q", id)
+	}
+
+	size, err := s.storage.Put(ctx, id+SnapshotExtension, r, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, fmt.Errorf("failed to generate download token: %w", err)
+	}
+	token := hex.EncodeToString(buf)
+	// 令牌最后写入，Lookup 只有在令牌存在时才认为暂存完成
+	if _, err := s.storage.Put(ctx, id+stagedTokenExtension, strings.NewReader(token), nil); err != nil {
+		return nil, err
+	}
+
+	return &StagedSnapshot{
+		ID:    id,
+		Path:  s.storage.Path(id + SnapshotExtension),
+		Token: token,
+		Size:  size,
+	}, nil
+}
+
+// Lookup 返回已暂存的快照，未暂存时返回的错误满足 os.IsNotExist
+func (s *SnapshotStager) Lookup(id string) (*StagedSnapshot, error) {
+	if !stagedIDPattern.MatchString(id) {
+		return nil, fmt.Errorf("invalid staged snapshot id %q: %w", id, os.ErrNotExist)
+	}
+
+	token, err := os.ReadFile(s.storage.Path(id + stagedTokenExtension))
+	if err != nil {
+		return nil, err
+	}
+	path := s.storage.Path(id + SnapshotExtension)
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StagedSnapshot{
+		ID:    id,
+		Path:  path,
+		Token: string(token),
+		Size:  info.Size(),
+	}, nil
+}
+
+// Remove 删除暂存的快照和令牌，未暂存时不返回错误
+func (s *SnapshotStager) Remove(ctx context.Context, id string) error {
+	if !stagedIDPattern.MatchString(id) {
+		return nil
+	}
+	// 先删除令牌，使正在进行的下载之外不再接受新的请求
+	if err := s.storage.Delete(ctx, id+stagedTokenExtension); err != nil {
+		return err
+	}
+	return s.storage.Delete(ctx, id+SnapshotExtension)
+}
+
+// SnapshotServer 通过 HTTP 向恢复任务提供暂存的快照。
+// 请求需要在 Authorization 头中携带暂存时生成的令牌: "Bearer <token>"
+type SnapshotServer struct {
+	addr   string
+	stager *SnapshotStager
+}
+
+// NewSnapshotServer 创建快照下载服务
+func NewSnapshotServer(addr string, stager *SnapshotStager) *SnapshotServer {
+	return &SnapshotServer{
+		addr:   addr,
+		stager: stager,
+	}
+}
+
+// ServeHTTP 处理 GET/HEAD /snapshots/<id> 请求
+func (s *SnapshotServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	id, ok := strings.CutPrefix(r.URL.Path, SnapshotServerPathPrefix)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	staged, err := s.stager.Lookup(id)
+	if err != nil {
+		// 未暂存和令牌错误返回相同的响应，避免泄露暂存 ID 是否存在
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+	token, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(staged.Token)) != 1 {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	f, err := os.Open(staged.Path)
+	if err != nil {
+		http.Error(w, "snapshot is no longer available", http.StatusGone)
+		return
+	}
+	defer f.Close()
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	http.ServeContent(w, r, filepath.Base(staged.Path), time.Time{}, f)
+}
+
+// Start 启动 HTTP 服务，ctx 结束时优雅退出，实现 manager.Runnable
+func (s *SnapshotServer) Start(ctx context.Context) error {
+	listener, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.addr, err)
+	}
+
+	server := &http.Server{
+		Handler:           s,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.Serve(listener)
+	}()
+
+	select {
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+		defer cancel()
+		return server.Shutdown(shutdownCtx)
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	}
+}
+
+// NeedLeaderElection 快照只在 leader 上暂存，下载服务也只在 leader 上运行
+func (s *SnapshotServer) NeedLeaderElection() bool {
+	return true
+}
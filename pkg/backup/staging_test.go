@@ -0,0 +1,90 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSnapshotStagerLifecycle 测试快照暂存、查找和删除
+func TestSnapshotStagerLifecycle(t *testing.T) {
+	stager := NewSnapshotStager(t.TempDir())
+	ctx := context.Background()
+
+	_, err := stager.Lookup("restore-uid")
+	assert.True(t, os.IsNotExist(err))
+
+	staged, err := stager.Stage(ctx, "restore-uid", strings.NewReader("snapshot-data"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(len("snapshot-data")), staged.Size)
+	assert.Len(t, staged.Token, 64)
+
+	found, err := stager.Lookup("restore-uid")
+	require.NoError(t, err)
+	assert.Equal(t, staged, found)
+
+	require.NoError(t, stager.Remove(ctx, "restore-uid"))
+	_, err = stager.Lookup("restore-uid")
+	assert.True(t, os.IsNotExist(err))
+	require.NoError(t, stager.Remove(ctx, "restore-uid"))
+
+	_, err = stager.Stage(ctx, "../escape", strings.NewReader("x"))
+	assert.Error(t, err)
+}
+
+// TestSnapshotServerRequiresToken 测试下载服务校验令牌
+func TestSnapshotServerRequiresToken(t *testing.T) {
+	stager := NewSnapshotStager(t.TempDir())
+	staged, err := stager.Stage(context.Background(), "restore-uid", strings.NewReader("snapshot-data"))
+	require.NoError(t, err)
+
+	server := httptest.NewServer(NewSnapshotServer("", stager))
+	defer server.Close()
+
+	get := func(path, token string) *http.Response {
+		req, err := http.NewRequest(http.MethodGet, server.URL+path, nil)
+		require.NoError(t, err)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+
+	resp := get(SnapshotServerPathPrefix+"restore-uid", staged.Token)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int64(len("snapshot-data")), resp.ContentLength)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "snapshot-data", string(body))
+
+	assert.Equal(t, http.StatusForbidden, get(SnapshotServerPathPrefix+"restore-uid", "").StatusCode)
+	assert.Equal(t, http.StatusForbidden, get(SnapshotServerPathPrefix+"restore-uid", "wrong").StatusCode)
+	assert.Equal(t, http.StatusForbidden, get(SnapshotServerPathPrefix+"other-uid", staged.Token).StatusCode)
+	assert.Equal(t, http.StatusNotFound, get("/healthz", staged.Token).StatusCode)
+}
@@ -0,0 +1,187 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"os"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	// etcdSnapshotHashSize etcd 在快照流末尾追加的 SHA-256 长度
+	etcdSnapshotHashSize = sha256.Size
+
+	// boltPageAlignment bbolt 数据库文件大小按该值对齐，多出的 32 字节即为 etcd 追加的哈希
+	boltPageAlignment = 512
+
+	// keyBucket etcd mvcc 存储键值修订版本的 bucket
+	keyBucket = "key"
+)
+
+// SnapshotStatus 快照内容的校验结果，与 etcdutl snapshot status 的输出一致
+type SnapshotStatus struct {
+	// Hash 所有 bucket 键值的 CRC32 哈希
+	Hash uint32
+	// Revision 快照中最大的修订版本
+	Revision int64
+	// TotalKey 键值总数
+	TotalKey int
+	// TotalSize 数据库大小 (字节)
+	TotalSize int64
+}
+
+// HashingReader 计算读取内容的 SHA-256
+type HashingReader struct {
+	r io.Reader
+	h hash.Hash
+}
+
+// NewHashingReader 创建 HashingReader
+func NewHashingReader(r io.Reader) *HashingReader {
+	return &HashingReader{r: r, h: sha256.New()}
+}
+
+func (h *HashingReader) Read(p []byte) (int, error) {
+	n, err := h.r.Read(p)
+	h.h.Write(p[:n])
+	return n, err
+}
+
+// Sum 返回已读取内容的十六进制 SHA-256
+func (h *HashingReader) Sum() string {
+	return hex.EncodeToString(h.h.Sum(nil))
+}
+
+// ChecksumReader 返回一个读到结尾时校验 SHA-256 的流，校验失败时在最后一次读取返回错误
+func ChecksumReader(r io.Reader, expected string) io.Reader {
+	return &checksumReader{HashingReader: NewHashingReader(r), expected: expected}
+}
+
+type checksumReader struct {
+	*HashingReader
+	expected string
+}
+
+func (c *checksumReader) Read(p []byte) (int, error) {
+	n, err := c.HashingReader.Read(p)
+	if err == io.EOF {
+		if sum := c.Sum(); sum != c.expected {
+			return n, fmt.Errorf("snapshot checksum mismatch: expected sha256 %s, got %s", c.expected, sum)
+		}
+	}
+	return n, err
+}
+
+// VerifySnapshotFile 校验原始 etcd 快照文件：如果文件末尾带有 etcd 追加的 SHA-256 则先校验并截掉，
+// 然后以只读方式打开 bbolt 数据库，计算哈希并读取修订版本
+func VerifySnapshotFile(path string) (*SnapshotStatus, error) {
+	if err := verifyAppendedHash(path); err != nil {
+		return nil, err
+	}
+
+	db, err := bolt.Open(path, 0o400, &bolt.Options{ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open snapshot database: %w", err)
+	}
+	defer db.Close()
+
+	status := &SnapshotStatus{}
+	err = db.View(func(tx *bolt.Tx) error {
+		// 检查页面结构的一致性，需读完通道以免检查协程阻塞
+		var checkErr error
+		for err := range tx.Check() {
+			if checkErr == nil {
+				checkErr = err
+			}
+		}
+		if checkErr != nil {
+			return fmt.Errorf("snapshot database is corrupted: %w", checkErr)
+		}
+
+		h := crc32.New(crc32.MakeTable(crc32.Castagnoli))
+		c := tx.Cursor()
+		for name, _ := c.First(); name != nil; name, _ = c.Next() {
+			b := tx.Bucket(name)
+			if b == nil {
+				return fmt.Errorf("snapshot database has no bucket %q", name)
+			}
+			h.Write(name)
+			isKeyBucket := bytes.Equal(name, []byte(keyBucket))
+			if err := b.ForEach(func(k, v []byte) error {
+				h.Write(k)
+				h.Write(v)
+				if isKeyBucket && len(k) >= 8 {
+					if rev := int64(binary.BigEndian.Uint64(k[:8])); rev > status.Revision {
+						status.Revision = rev
+					}
+				}
+				status.TotalKey++
+				return nil
+			}); err != nil {
+				return err
+			}
+		}
+		status.Hash = h.Sum32()
+		status.TotalSize = tx.Size()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// verifyAppendedHash 校验并移除 etcd 在快照末尾追加的 SHA-256
+func verifyAppendedHash(path string) error {
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	size := info.Size()
+	if size%boltPageAlignment != etcdSnapshotHashSize {
+		return nil
+	}
+
+	h := sha256.New()
+	if _, err := io.Copy(h, io.LimitReader(f, size-etcdSnapshotHashSize)); err != nil {
+		return fmt.Errorf("failed to hash snapshot: %w", err)
+	}
+	appended := make([]byte, etcdSnapshotHashSize)
+	if _, err := io.ReadFull(f, appended); err != nil {
+		return fmt.Errorf("failed to read snapshot hash: %w", err)
+	}
+	if !bytes.Equal(h.Sum(nil), appended) {
+		return fmt.Errorf("snapshot integrity hash mismatch")
+	}
+
+	return f.Truncate(size - etcdSnapshotHashSize)
+}
@@ -0,0 +1,367 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package bootstrap 生成成员启动使用的 etcd 配置文件，由成员 Pod 的 init 容器运行。
+// initial-cluster 和 initial-cluster-state 根据数据目录和集群的实际成员列表确定：
+// 数据目录已有数据时成员按保存的成员关系重新加入，否则等待 operator 将成员加入集群后按成员列表加入。
+// 只有 operator 明确指定 initial-cluster-state 为 new 时，序号为 0 的成员才在集群不可访问时以单成员集群启动
+package bootstrap
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+	"github.com/your-org/etcd-k8s-operator/pkg/utils"
+)
+
+const (
+	// ClusterStateNew 以新集群启动
+	ClusterStateNew = "new"
+	// ClusterStateExisting 加入已有集群
+	ClusterStateExisting = "existing"
+)
+
+// Options 生成成员配置需要的参数，由 operator 在 init 容器的参数中传入
+type Options struct {
+	// Name 成员名称，与 Pod 名称一致，以 -<序号> 结尾
+	Name string
+	// DataDir etcd 数据目录
+	DataDir string
+	// ConfigFile 写入的配置文件路径
+	ConfigFile string
+	// PeerDomain 成员主机名的域名部分，成员主机名为 <Name>.<PeerDomain>
+	PeerDomain string
+	// ClientScheme 成员客户端地址的协议
+	ClientScheme string
+	// PeerScheme 成员 peer 地址的协议
+	PeerScheme string
+	// ListenClientURLs 成员监听的客户端地址
+	ListenClientURLs string
+	// ClusterToken 新集群使用的 initial-cluster-token
+	ClusterToken string
+	// Endpoints 查询成员列表使用的客户端端点
+	Endpoints []string
+	// ServerName 校验成员服务端证书使用的主机名
+	ServerName string
+	// CAFile、CertFile、KeyFile 客户端 TLS 使用的证书，CAFile 为空时以明文访问
+	CAFile   string
+	CertFile string
+	KeyFile  string
+	// InitialClusterState 为 new 时允许序号为 0 的成员以新集群启动，operator 只在集群创建期间设置
+	InitialClusterState string
+	// ForceNewCluster 等于 Name 时成员以 force-new-cluster 启动，用于法定人数丢失后的恢复
+	ForceNewCluster string
+	// ExtraConfig 追加到配置文件的配置段，包括证书、维护和调优配置
+	ExtraConfig string
+	// JoinTimeout 等待成员被加入集群的最长时间
+	JoinTimeout time.Duration
+	// RetryInterval 查询成员列表的间隔
+	RetryInterval time.Duration
+}
+
+// MemberLister 返回集群当前的成员列表
+type MemberLister func(ctx context.Context) ([]etcdv1alpha1.EtcdMember, error)
+
+// Plan 成员的启动方式
+type Plan struct {
+	// State 为 initial-cluster-state
+	State string
+	// InitialCluster 为 initial-cluster
+	InitialCluster string
+	// Reason 说明选择该启动方式的原因
+	Reason string
+}
+
+// Run 确定成员的启动方式并写入配置文件，过程输出到 out
+func Run(ctx context.Context, opts Options, list MemberLister, out io.Writer) error {
+	if err := opts.validate(); err != nil {
+		return err
+	}
+	plan, err := Decide(ctx, opts, list, out)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Starting %s with initial-cluster-state %s: %s\n", opts.Name, plan.State, plan.Reason)
+
+	config := RenderConfig(opts, plan)
+	if err := writeFile(opts.ConfigFile, config); err != nil {
+		return fmt.Errorf("failed to write %s: %w", opts.ConfigFile, err)
+	}
+	fmt.Fprintf(out, "Generated %s:\n%s", opts.ConfigFile, config)
+	return nil
+}
+
+// validate 检查必需的参数
+func (o Options) validate() error {
+	var missing []string
+	for flag, value := range map[string]string{
+		"name":          o.Name,
+		"data-dir":      o.DataDir,
+		"config-file":   o.ConfigFile,
+		"peer-domain":   o.PeerDomain,
+		"cluster-token": o.ClusterToken,
+	} {
+		if value == "" {
+			missing = append(missing, "--"+flag)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("missing required flags: %s", strings.Join(missing, ", "))
+	}
+	if len(o.Endpoints) == 0 {
+		return fmt.Errorf("missing required flags: --endpoints")
+	}
+	if o.InitialClusterState != "" && o.InitialClusterState != ClusterStateNew && o.InitialClusterState != ClusterStateExisting {
+		return fmt.Errorf("--initial-cluster-state must be %s or %s, got %q", ClusterStateNew, ClusterStateExisting, o.InitialClusterState)
+	}
+	if _, err := MemberOrdinal(o.Name); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Decide 确定成员的启动方式。数据目录已有数据时直接按保存的成员关系启动；否则查询成员列表，
+// 成员已被加入集群时按成员列表加入。InitialClusterState 为 new 时序号为 0 的成员在集群不可访问时以单成员集群启动，
+// 其他情况等待 operator 将成员加入集群，超过 JoinTimeout 后返回错误。
+// 集群不可访问不能说明集群不存在，已有集群的成员丢失数据后不能自行创建新集群
+func Decide(ctx context.Context, opts Options, list MemberLister, out io.Writer) (*Plan, error) {
+	self := fmt.Sprintf("%s=%s", opts.Name, opts.PeerURL())
+	initialized, err := HasData(opts.DataDir)
+	if err != nil {
+		return nil, err
+	}
+	if initialized {
+		// 已有数据时 etcd 忽略 initial-cluster，使用数据中保存的成员关系
+		return &Plan{State: ClusterStateExisting, InitialCluster: self,
+			Reason: fmt.Sprintf("data dir %s already holds member data", opts.DataDir)}, nil
+	}
+	ordinal, err := MemberOrdinal(opts.Name)
+	if err != nil {
+		return nil, err
+	}
+	bootstrapping := ordinal == 0 && opts.InitialClusterState == ClusterStateNew
+
+	deadline := time.Now().Add(opts.JoinTimeout)
+	for attempt := 1; ; attempt++ {
+		members, err := list(ctx)
+		if err == nil {
+			plan, err := JoinPlan(opts, members)
+			if err != nil || plan != nil {
+				return plan, err
+			}
+			err = fmt.Errorf("member %s is not in the member list yet, waiting for the operator to add it", opts.Name)
+			fmt.Fprintf(out, "Attempt %d: %v\n", attempt, err)
+		} else {
+			fmt.Fprintf(out, "Attempt %d: failed to list members from %s: %v\n", attempt, strings.Join(opts.Endpoints, ","), err)
+			if bootstrapping && Unreachable(err) {
+				return &Plan{State: ClusterStateNew, InitialCluster: self,
+					Reason: "the cluster is being created and no member is reachable yet, starting a new single member cluster"}, nil
+			}
+		}
+
+		if !time.Now().Add(opts.RetryInterval).Before(deadline) {
+			return nil, fmt.Errorf("member %s could not join the cluster within %s: %w", opts.Name, opts.JoinTimeout, err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(opts.RetryInterval):
+		}
+	}
+}
+
+// JoinPlan 根据成员列表返回加入已有集群的启动方式，成员还不在列表中时返回 nil。
+// 成员已经启动过但数据目录为空时返回错误，etcd 会拒绝以空数据重新加入
+func JoinPlan(opts Options, members []etcdv1alpha1.EtcdMember) (*Plan, error) {
+	selfURL := opts.PeerURL()
+	var self *etcdv1alpha1.EtcdMember
+	for i := range members {
+		if members[i].Name == opts.Name || members[i].PeerURL == selfURL {
+			self = &members[i]
+			break
+		}
+	}
+	if self == nil {
+		return nil, nil
+	}
+	if self.Name != "" {
+		return nil, fmt.Errorf("member %s (ID %s) has started before but data dir %s is empty; "+
+			"remove the member from the cluster so that the operator adds it again", opts.Name, self.ID, opts.DataDir)
+	}
+
+	var entries []string
+	for _, m := range members {
+		name := m.Name
+		switch {
+		case m.PeerURL == "":
+			continue
+		case m.PeerURL == selfURL:
+			name = opts.Name
+		case name == "":
+			// 其他尚未启动的成员没有名称，按 peer 地址的主机名推断
+			name = memberNameFromPeerURL(m.PeerURL)
+		}
+		if name == "" {
+			return nil, fmt.Errorf("cannot determine the name of member %s with peer URL %s", m.ID, m.PeerURL)
+		}
+		entries = append(entries, fmt.Sprintf("%s=%s", name, m.PeerURL))
+	}
+	sort.Strings(entries)
+	return &Plan{State: ClusterStateExisting, InitialCluster: strings.Join(entries, ","),
+		Reason: fmt.Sprintf("member was added to the cluster as %s, joining %d members", self.ID, len(entries))}, nil
+}
+
+// RenderConfig 返回成员的 etcd 配置文件内容
+func RenderConfig(opts Options, plan *Plan) string {
+	config := fmt.Sprintf(`# etcd configuration for %s
+name: %s
+data-dir: %s
+listen-client-urls: %s
+listen-peer-urls: %s://0.0.0.0:%d
+advertise-client-urls: %s://%s:%d
+initial-advertise-peer-urls: %s
+initial-cluster-token: %s
+initial-cluster-state: %s
+initial-cluster: %s
+`,
+		opts.Name,
+		opts.Name,
+		opts.DataDir,
+		opts.ListenClientURLs,
+		opts.PeerScheme, utils.EtcdPeerPort,
+		opts.ClientScheme, opts.host(), utils.EtcdClientPort,
+		opts.PeerURL(),
+		opts.ClusterToken,
+		plan.State,
+		plan.InitialCluster,
+	)
+	config += opts.ExtraConfig
+	if opts.ForceNewCluster != "" && opts.ForceNewCluster == opts.Name {
+		// 法定人数丢失后的恢复：指定的成员以 --force-new-cluster 启动，成为单成员集群
+		config += "force-new-cluster: true\n"
+	}
+	return config
+}
+
+// PeerURL 返回成员的 peer 地址
+func (o Options) PeerURL() string {
+	return fmt.Sprintf("%s://%s:%d", o.PeerScheme, o.host(), utils.EtcdPeerPort)
+}
+
+// host 返回成员的主机名
+func (o Options) host() string {
+	return o.Name + "." + o.PeerDomain
+}
+
+// TLSConfig 返回查询成员列表使用的客户端 TLS 配置，没有配置 CA 时返回 nil
+func (o Options) TLSConfig() (*tls.Config, error) {
+	if o.CAFile == "" {
+		return nil, nil
+	}
+	cert, err := tls.LoadX509KeyPair(o.CertFile, o.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load client certificate %s: %w", o.CertFile, err)
+	}
+	ca, err := os.ReadFile(o.CAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA %s: %w", o.CAFile, err)
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(ca) {
+		return nil, fmt.Errorf("%s has no valid CA certificate", o.CAFile)
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      roots,
+		ServerName:   o.ServerName,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+// MemberOrdinal 返回成员名称中的 StatefulSet 序号
+func MemberOrdinal(name string) (int32, error) {
+	i := strings.LastIndex(name, "-")
+	if i < 0 {
+		return 0, fmt.Errorf("member name %q does not end with -<ordinal>", name)
+	}
+	ordinal, err := strconv.ParseInt(name[i+1:], 10, 32)
+	if err != nil || ordinal < 0 {
+		return 0, fmt.Errorf("member name %q does not end with -<ordinal>", name)
+	}
+	return int32(ordinal), nil
+}
+
+// HasData 返回数据目录中是否已有 etcd 的 WAL，成员曾经启动过时才会有
+func HasData(dataDir string) (bool, error) {
+	entries, err := os.ReadDir(filepath.Join(dataDir, "member", "wal"))
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to inspect data dir %s: %w", dataDir, err)
+	}
+	return len(entries) > 0, nil
+}
+
+// Unreachable 返回错误是否表示集群不可访问，其他错误（例如证书被拒绝）说明集群存在，不能以新集群启动
+func Unreachable(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	}
+	return false
+}
+
+// memberNameFromPeerURL 返回 peer 地址主机名的第一段，即成员的 Pod 名称
+func memberNameFromPeerURL(peerURL string) string {
+	u, err := url.Parse(peerURL)
+	if err != nil {
+		return ""
+	}
+	name, _, _ := strings.Cut(u.Hostname(), ".")
+	return name
+}
+
+// writeFile 先写入临时文件再重命名，init 容器被中断时不会留下不完整的配置
+func writeFile(path, content string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(content), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrap
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+)
+
+// newTestOptions 返回指定成员的参数，数据目录和配置文件位于临时目录
+func newTestOptions(t *testing.T, name string) Options {
+	dir := t.TempDir()
+	return Options{
+		Name:             name,
+		DataDir:          filepath.Join(dir, "data"),
+		ConfigFile:       filepath.Join(dir, "etc", "etcd.conf"),
+		PeerDomain:       "demo-peer.prod.svc.cluster.local",
+		ClientScheme:     "http",
+		PeerScheme:       "http",
+		ListenClientURLs: "http://0.0.0.0:2379",
+		ClusterToken:     "demo",
+		Endpoints:        []string{"http://demo-client.prod.svc:2379"},
+		JoinTimeout:      time.Second,
+		RetryInterval:    10 * time.Millisecond,
+	}
+}
+
+// unavailable 模拟集群不可访问时列出成员的结果
+func unavailable(context.Context) ([]etcdv1alpha1.EtcdMember, error) {
+	return nil, fmt.Errorf("failed to list members: %w", status.Error(codes.Unavailable, "connection refused"))
+}
+
+// TestRunStartsNewClusterFromFirstMember 测试集群创建期间成员 0 在数据目录为空、集群不可访问时以单成员集群启动
+func TestRunStartsNewClusterFromFirstMember(t *testing.T) {
+	opts := newTestOptions(t, "demo-0")
+	opts.InitialClusterState = ClusterStateNew
+	opts.ExtraConfig = "snapshot-count: 5000\n"
+	out := &bytes.Buffer{}
+	require.NoError(t, Run(context.Background(), opts, unavailable, out))
+
+	config, err := os.ReadFile(opts.ConfigFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(config), "name: demo-0\n")
+	assert.Contains(t, string(config), "advertise-client-urls: http://demo-0.demo-peer.prod.svc.cluster.local:2379\n")
+	assert.Contains(t, string(config), "initial-cluster-state: new\n")
+	assert.Contains(t, string(config), "initial-cluster: demo-0=http://demo-0.demo-peer.prod.svc.cluster.local:2380\n")
+	assert.Contains(t, string(config), "snapshot-count: 5000\n")
+	assert.NotContains(t, string(config), "force-new-cluster")
+	assert.Contains(t, out.String(), "starting a new single member cluster")
+	assert.Contains(t, out.String(), "Attempt 1: failed to list members")
+}
+
+// TestRunJoinsAfterMemberIsAdded 测试新成员等待 operator 将其加入集群，再按成员列表以 existing 加入
+func TestRunJoinsAfterMemberIsAdded(t *testing.T) {
+	opts := newTestOptions(t, "demo-2")
+	calls := 0
+	list := func(context.Context) ([]etcdv1alpha1.EtcdMember, error) {
+		calls++
+		members := []etcdv1alpha1.EtcdMember{
+			{Name: "demo-1", ID: "b", PeerURL: "http://demo-1.demo-peer.prod.svc.cluster.local:2380"},
+			{Name: "demo-0", ID: "a", PeerURL: "http://demo-0.demo-peer.prod.svc.cluster.local:2380"},
+		}
+		if calls < 3 {
+			return members, nil
+		}
+		return append(members, etcdv1alpha1.EtcdMember{ID: "c", PeerURL: "http://demo-2.demo-peer.prod.svc.cluster.local:2380"}), nil
+	}
+	out := &bytes.Buffer{}
+	require.NoError(t, Run(context.Background(), opts, list, out))
+	assert.Equal(t, 3, calls)
+	assert.Contains(t, out.String(), "member demo-2 is not in the member list yet")
+
+	config, err := os.ReadFile(opts.ConfigFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(config), "initial-cluster-state: existing\n")
+	assert.Contains(t, string(config), "initial-cluster: demo-0=http://demo-0.demo-peer.prod.svc.cluster.local:2380,"+
+		"demo-1=http://demo-1.demo-peer.prod.svc.cluster.local:2380,demo-2=http://demo-2.demo-peer.prod.svc.cluster.local:2380\n")
+}
+
+// TestRunWithExistingData 测试数据目录已有数据时不查询成员列表，按保存的成员关系启动，并按恢复设置追加 force-new-cluster
+func TestRunWithExistingData(t *testing.T) {
+	opts := newTestOptions(t, "demo-1")
+	opts.ForceNewCluster = "demo-1"
+	require.NoError(t, os.MkdirAll(filepath.Join(opts.DataDir, "member", "wal"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(opts.DataDir, "member", "wal", "0000000000000000-0000000000000000.wal"), nil, 0o600))
+	list := func(context.Context) ([]etcdv1alpha1.EtcdMember, error) {
+		return nil, fmt.Errorf("unexpected member list")
+	}
+
+	require.NoError(t, Run(context.Background(), opts, list, &bytes.Buffer{}))
+	config, err := os.ReadFile(opts.ConfigFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(config), "initial-cluster-state: existing\n")
+	assert.Contains(t, string(config), "force-new-cluster: true\n")
+}
+
+// TestRunFailures 测试无法确定启动方式时返回说明原因的错误，并且不写入配置文件
+func TestRunFailures(t *testing.T) {
+	for name, tc := range map[string]struct {
+		member string
+		state  string
+		list   MemberLister
+		err    string
+	}{
+		"first member of an existing cluster cannot reach it": {
+			member: "demo-0",
+			list:   unavailable,
+			err:    "member demo-0 could not join the cluster within 1s",
+		},
+		"other member told to start a new cluster": {
+			member: "demo-1",
+			state:  ClusterStateNew,
+			list:   unavailable,
+			err:    "member demo-1 could not join the cluster within 1s",
+		},
+		"unknown initial cluster state": {
+			member: "demo-0",
+			state:  "fresh",
+			list:   unavailable,
+			err:    "--initial-cluster-state must be new or existing",
+		},
+		"joining member times out": {
+			member: "demo-1",
+			list:   unavailable,
+			err:    "member demo-1 could not join the cluster within 1s",
+		},
+		"first member rejected by the cluster": {
+			member: "demo-0",
+			state:  ClusterStateNew,
+			list: func(context.Context) ([]etcdv1alpha1.EtcdMember, error) {
+				return nil, status.Error(codes.PermissionDenied, "permission denied")
+			},
+			err: "permission denied",
+		},
+		"started member lost its data": {
+			member: "demo-1",
+			list: func(context.Context) ([]etcdv1alpha1.EtcdMember, error) {
+				return []etcdv1alpha1.EtcdMember{{Name: "demo-1", ID: "b", PeerURL: "http://demo-1.demo-peer.prod.svc.cluster.local:2380"}}, nil
+			},
+			err: "member demo-1 (ID b) has started before but data dir",
+		},
+		"name without ordinal": {
+			member: "demo",
+			list:   unavailable,
+			err:    `member name "demo" does not end with -<ordinal>`,
+		},
+	} {
+		opts := newTestOptions(t, tc.member)
+		opts.InitialClusterState = tc.state
+		err := Run(context.Background(), opts, tc.list, &bytes.Buffer{})
+		if assert.Error(t, err, name) {
+			assert.Contains(t, err.Error(), tc.err, name)
+		}
+		_, statErr := os.Stat(opts.ConfigFile)
+		assert.True(t, os.IsNotExist(statErr), name)
+	}
+}
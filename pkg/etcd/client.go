@@ -18,27 +18,54 @@ package etcd
 
 import (
 	"context"
+	"crypto/tls"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
+	"strings"
 	"time"
 
 	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
 	clientv3 "go.etcd.io/etcd/client/v3"
 )
 
 // Client wraps etcd client with additional functionality
 type Client struct {
 	*clientv3.Client
+
+	// httpClient 用于访问成员的 HTTP 接口，在客户端的生命周期内复用连接
+	httpClient *http.Client
+}
+
+// Versions is the version report a member serves on /version
+type Versions struct {
+	// Server is the etcd version the member runs
+	Server string `json:"etcdserver"`
+	// Cluster is the version the cluster agreed on
+	Cluster string `json:"etcdcluster"`
+	// Storage is the version of the member's data schema, only reported by etcd 3.6 and later
+	Storage string `json:"storage,omitempty"`
 }
 
 // Close closes the etcd client
 func (c *Client) Close() error {
+	c.httpClient.CloseIdleConnections()
 	return c.Client.Close()
 }
 
 // NewClient creates a new etcd client
 func NewClient(endpoints []string) (*Client, error) {
+	return NewClientWithTLS(endpoints, nil)
+}
+
+// NewClientWithTLS creates a new etcd client, using tlsConfig for https endpoints
+func NewClientWithTLS(endpoints []string, tlsConfig *tls.Config) (*Client, error) {
 	cli, err := clientv3.New(clientv3.Config{
 		Endpoints:            endpoints,
+		TLS:                  tlsConfig,
 		DialTimeout:          5 * time.Second,
 		DialKeepAliveTime:    30 * time.Second,
 		DialKeepAliveTimeout: 5 * time.Second,
@@ -49,7 +76,8 @@ func NewClient(endpoints []string) (*Client, error) {
 		return nil, fmt.Errorf("failed to create etcd client: %w", err)
 	}
 
-	return &Client{Client: cli}, nil
+	httpClient := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+	return &Client{Client: cli, httpClient: httpClient}, nil
 }
 
 // GetClusterMembers returns the list of cluster members
@@ -81,6 +109,11 @@ func (c *Client) GetClusterMembers(ctx context.Context) ([]etcdv1alpha1.EtcdMemb
 			etcdMember.ClientURL = member.ClientURLs[0]
 		}
 
+		// learner 不参与投票，在提升之前以角色标出
+		if member.IsLearner {
+			etcdMember.Role = "learner"
+		}
+
 		members = append(members, etcdMember)
 	}
 
@@ -100,6 +133,31 @@ func (c *Client) AddMember(ctx context.Context, peerURL string) (*clientv3.Membe
 	return resp, nil
 }
 
+// AddLearner adds a new member to the etcd cluster as a non-voting learner.
+// learner 不计入法定人数，追上 leader 之后通过 PromoteMember 提升为投票成员
+func (c *Client) AddLearner(ctx context.Context, peerURL string) (*clientv3.MemberAddResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	resp, err := c.MemberAddAsLearner(ctx, []string{peerURL})
+	if err != nil {
+		return nil, fmt.Errorf("failed to add learner: %w", err)
+	}
+	return resp, nil
+}
+
+// PromoteMember promotes a learner to a voting member. etcd refuses with
+// rpctypes.ErrMemberLearnerNotReady while the learner still trails the leader
+func (c *Client) PromoteMember(ctx context.Context, memberID uint64) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	if _, err := c.MemberPromote(ctx, memberID); err != nil {
+		return fmt.Errorf("failed to promote member %x: %w", memberID, err)
+	}
+	return nil
+}
+
 // RemoveMember removes a member from the etcd cluster
 func (c *Client) RemoveMember(ctx context.Context, memberID uint64) error {
 	_, err := c.MemberRemove(ctx, memberID)
@@ -109,6 +167,83 @@ func (c *Client) RemoveMember(ctx context.Context, memberID uint64) error {
 	return nil
 }
 
+// UpdateMember replaces the peer URLs a member advertises to the rest of the cluster.
+// 成员需要重启后才会监听新的地址
+func (c *Client) UpdateMember(ctx context.Context, memberID uint64, peerURLs []string) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	if _, err := c.MemberUpdate(ctx, memberID, peerURLs); err != nil {
+		return fmt.Errorf("failed to update member: %w", err)
+	}
+	return nil
+}
+
+// TransferLeadership asks the current leader to hand leadership to the given member.
+// 请求必须发送给当前 leader，调用方需要使用只连接 leader 的客户端
+func (c *Client) TransferLeadership(ctx context.Context, transfereeID uint64) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	if _, err := c.MoveLeader(ctx, transfereeID); err != nil {
+		return fmt.Errorf("failed to transfer leadership: %w", err)
+	}
+	return nil
+}
+
+// DowngradeValidate checks that the cluster can be downgraded to the given major.minor version
+func (c *Client) DowngradeValidate(ctx context.Context, version string) error {
+	if _, err := c.downgrade(ctx, pb.DowngradeRequest_VALIDATE, version); err != nil {
+		return fmt.Errorf("failed to validate downgrade to %s: %w", version, err)
+	}
+	return nil
+}
+
+// DowngradeEnable lowers the cluster version to the given major.minor version so members
+// running it can join. etcd 在全部成员运行目标版本后自动结束降级
+func (c *Client) DowngradeEnable(ctx context.Context, version string) error {
+	if _, err := c.downgrade(ctx, pb.DowngradeRequest_ENABLE, version); err != nil {
+		return fmt.Errorf("failed to enable downgrade to %s: %w", version, err)
+	}
+	return nil
+}
+
+// downgrade 调用维护接口中的降级请求，client v3.5 的 Maintenance 没有封装该请求
+func (c *Client) downgrade(ctx context.Context, action pb.DowngradeRequest_DowngradeAction, version string) (*pb.DowngradeResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	return clientv3.RetryMaintenanceClient(c.Client, c.ActiveConnection()).Downgrade(ctx, &pb.DowngradeRequest{
+		Action:  action,
+		Version: version,
+	})
+}
+
+// GetVersions returns the versions reported by the member serving the given endpoint
+func (c *Client) GetVersions(ctx context.Context, endpoint string) (*Versions, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(endpoint, "/")+"/version", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build version request for %s: %w", endpoint, err)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get versions of %s: %w", endpoint, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get versions of %s: %s", endpoint, resp.Status)
+	}
+	versions := &Versions{}
+	if err := json.NewDecoder(resp.Body).Decode(versions); err != nil {
+		return nil, fmt.Errorf("failed to decode versions of %s: %w", endpoint, err)
+	}
+	return versions, nil
+}
+
 // GetLeader returns the current leader information
 func (c *Client) GetLeader(ctx context.Context) (string, error) {
 	// 创建带超时的上下文
@@ -135,6 +270,80 @@ func (c *Client) HealthCheck(ctx context.Context) error {
 	return nil
 }
 
+// MemberStatus returns the status reported by the member serving the given endpoint
+func (c *Client) MemberStatus(ctx context.Context, endpoint string) (*clientv3.StatusResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	resp, err := c.Status(ctx, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status of %s: %w", endpoint, err)
+	}
+	return resp, nil
+}
+
+// Defragment rebuilds the backend database of the member serving the given endpoint to release free space.
+// 碎片整理期间成员不处理请求，数据库越大耗时越长
+func (c *Client) Defragment(ctx context.Context, endpoint string) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+
+	if _, err := c.Client.Defragment(ctx, endpoint); err != nil {
+		return fmt.Errorf("failed to defragment %s: %w", endpoint, err)
+	}
+	return nil
+}
+
+// CompactToCurrentRevision discards the key space history before the current revision and returns the revision
+func (c *Client) CompactToCurrentRevision(ctx context.Context) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	// 任意读请求的响应头都带有当前版本
+	resp, err := c.Get(ctx, "compact", clientv3.WithCountOnly())
+	if err != nil {
+		return 0, fmt.Errorf("failed to get current revision: %w", err)
+	}
+	revision := resp.Header.Revision
+	if _, err := c.Compact(ctx, revision, clientv3.WithCompactPhysical()); err != nil && err != rpctypes.ErrCompacted {
+		return 0, fmt.Errorf("failed to compact to revision %d: %w", revision, err)
+	}
+	return revision, nil
+}
+
+// ListAlarms returns the alarms raised in the cluster
+func (c *Client) ListAlarms(ctx context.Context) ([]*pb.AlarmMember, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	resp, err := c.AlarmList(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alarms: %w", err)
+	}
+	return resp.Alarms, nil
+}
+
+// DisarmAlarm clears an alarm once its cause is resolved. 数据库仍然超出配额时 etcd 在下一次写入时再次触发告警
+func (c *Client) DisarmAlarm(ctx context.Context, alarm *pb.AlarmMember) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if _, err := c.AlarmDisarm(ctx, (*clientv3.AlarmMember)(alarm)); err != nil {
+		return fmt.Errorf("failed to disarm %s alarm of member %x: %w", alarm.Alarm, alarm.MemberID, err)
+	}
+	return nil
+}
+
+// OpenSnapshot opens a stream of the backend database through the maintenance API.
+// 调用方负责关闭返回的 ReadCloser；流的生命周期受 ctx 控制
+func (c *Client) OpenSnapshot(ctx context.Context) (io.ReadCloser, error) {
+	rc, err := c.Snapshot(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open snapshot stream: %w", err)
+	}
+	return rc, nil
+}
+
 // GetClusterID returns the cluster ID
 func (c *Client) GetClusterID(ctx context.Context) (string, error) {
 	if len(c.Endpoints()) == 0 {
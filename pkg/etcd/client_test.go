@@ -17,9 +17,15 @@ limitations under the License.
 package etcd
 
 import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -98,3 +104,29 @@ func (suite *EtcdClientTestSuite) TestClientConfiguration() {
 		assert.Contains(suite.T(), clientEndpoints, endpoint)
 	}
 }
+
+// TestGetVersionsReusesConnection 测试多次读取成员版本复用同一个 HTTP 连接
+func (suite *EtcdClientTestSuite) TestGetVersionsReusesConnection() {
+	var connections atomic.Int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"etcdserver":"3.5.10","etcdcluster":"3.5.0"}`))
+	}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			connections.Add(1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	client, err := NewClient([]string{"http://localhost:2379"})
+	require.NoError(suite.T(), err)
+	defer client.Close()
+
+	for i := 0; i < 3; i++ {
+		versions, err := client.GetVersions(context.Background(), server.URL)
+		require.NoError(suite.T(), err)
+		assert.Equal(suite.T(), &Versions{Server: "3.5.10", Cluster: "3.5.0"}, versions)
+	}
+	assert.Equal(suite.T(), int32(1), connections.Load())
+}
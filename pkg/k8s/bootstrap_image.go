@@ -0,0 +1,41 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// OperatorImage 查询 operator 自身的 Pod，返回 operator 容器的镜像，成员 init 容器使用同一镜像运行 etcd-bootstrap。
+// Pod 中有多个容器时取名为 container 的容器
+func OperatorImage(ctx context.Context, reader client.Reader, namespace, name, container string) (string, error) {
+	pod := &corev1.Pod{}
+	if err := reader.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, pod); err != nil {
+		return "", fmt.Errorf("failed to get operator pod %s/%s: %w", namespace, name, err)
+	}
+	for _, c := range pod.Spec.Containers {
+		if c.Name == container || len(pod.Spec.Containers) == 1 {
+			return c.Image, nil
+		}
+	}
+	return "", fmt.Errorf("operator pod %s/%s has no container named %s", namespace, name, container)
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// TestOperatorImage 测试从 operator 自身的 Pod 中读取 manager 容器的镜像
+func TestOperatorImage(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "operator-7d9f", Namespace: "etcd-system"},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{
+			{Name: "kube-rbac-proxy", Image: "gcr.io/kubebuilder/kube-rbac-proxy:v0.16.0"},
+			{Name: "manager", Image: "registry.example.com/etcd-operator:v1.2.0"},
+		}},
+	}
+	single := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "operator-custom", Namespace: "etcd-system"},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "operator", Image: "registry.example.com/etcd-operator:v1.2.0"}}},
+	}
+	reader := fake.NewClientBuilder().WithObjects(pod, single).Build()
+	ctx := context.Background()
+
+	image, err := OperatorImage(ctx, reader, "etcd-system", "operator-7d9f", "manager")
+	require.NoError(t, err)
+	assert.Equal(t, "registry.example.com/etcd-operator:v1.2.0", image)
+
+	image, err = OperatorImage(ctx, reader, "etcd-system", "operator-custom", "manager")
+	require.NoError(t, err)
+	assert.Equal(t, "registry.example.com/etcd-operator:v1.2.0", image)
+
+	_, err = OperatorImage(ctx, reader, "etcd-system", "operator-7d9f", "operator")
+	assert.ErrorContains(t, err, "no container named operator")
+	_, err = OperatorImage(ctx, reader, "etcd-system", "missing", "manager")
+	assert.ErrorContains(t, err, "failed to get operator pod etcd-system/missing")
+}
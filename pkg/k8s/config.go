@@ -0,0 +1,192 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8s
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+)
+
+// extraFlagKeyPattern 是 etcd 配置文件中设置项名称的格式
+var extraFlagKeyPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// literalValuePattern 匹配可以原样写入配置文件的数字和布尔值，其余值都按字符串加引号写入
+var literalValuePattern = regexp.MustCompile(`^(-?[0-9]+(\.[0-9]+)?|true|false)$`)
+
+// managedFlags 是 operator 根据集群拓扑和传输安全配置生成的设置，不能通过 extraFlags 覆盖
+var managedFlags = map[string]bool{
+	"name":                        true,
+	"data-dir":                    true,
+	"wal-dir":                     true,
+	"listen-client-urls":          true,
+	"listen-peer-urls":            true,
+	"listen-metrics-urls":         true,
+	"advertise-client-urls":       true,
+	"initial-advertise-peer-urls": true,
+	"initial-cluster":             true,
+	"initial-cluster-state":       true,
+	"initial-cluster-token":       true,
+	"client-transport-security":   true,
+	"peer-transport-security":     true,
+	"force-new-cluster":           true,
+	"discovery":                   true,
+	"discovery-srv":               true,
+	"enable-v2":                   true,
+}
+
+// typedFlags 是 EtcdConfigSpec 中有对应字段的设置
+var typedFlags = map[string]string{
+	"quota-backend-bytes": "quotaBackendBytes",
+	"snapshot-count":      "snapshotCount",
+	"heartbeat-interval":  "heartbeatInterval",
+	"election-timeout":    "electionTimeout",
+	"max-request-bytes":   "maxRequestBytes",
+	"log-level":           "logLevel",
+}
+
+// knownFlags 是可以通过 extraFlags 设置的 etcd 配置项，experimental-* 设置随 etcd 版本变化，不在此列出
+var knownFlags = map[string]bool{
+	"max-snapshots":                 true,
+	"max-wals":                      true,
+	"snapshot-catchup-entries":      true,
+	"grpc-keepalive-min-time":       true,
+	"grpc-keepalive-interval":       true,
+	"grpc-keepalive-timeout":        true,
+	"max-txn-ops":                   true,
+	"max-concurrent-streams":        true,
+	"strict-reconfig-check":         true,
+	"pre-vote":                      true,
+	"enable-pprof":                  true,
+	"metrics":                       true,
+	"logger":                        true,
+	"log-outputs":                   true,
+	"log-format":                    true,
+	"enable-log-rotation":           true,
+	"log-rotation-config-json":      true,
+	"auth-token":                    true,
+	"bcrypt-cost":                   true,
+	"auth-token-ttl":                true,
+	"cipher-suites":                 true,
+	"tls-min-version":               true,
+	"tls-max-version":               true,
+	"cors":                          true,
+	"host-whitelist":                true,
+	"socket-reuse-port":             true,
+	"socket-reuse-address":          true,
+	"unsafe-no-fsync":               true,
+	"backend-batch-interval":        true,
+	"backend-batch-limit":           true,
+	"backend-bbolt-freelist-type":   true,
+	"self-signed-cert-validity":     true,
+	"v2-deprecation":                true,
+	"max-learners":                  true,
+	"enable-grpc-gateway":           true,
+	"initial-election-tick-advance": true,
+}
+
+// MemberConfig 返回成员应使用的 etcd 调优配置，以状态中记录的配置为准。
+// spec 中的配置变化写入状态后才会修改 Pod 模板，由控制器逐个重启成员生效
+func MemberConfig(cluster *etcdv1alpha1.EtcdCluster) *etcdv1alpha1.EtcdConfigSpec {
+	if cluster.Status.Config != nil {
+		return cluster.Status.Config
+	}
+	return &cluster.Spec.Config
+}
+
+// ValidateConfig 验证 etcd 调优配置：选举超时至少是心跳间隔的五倍，extraFlags 只能设置已知的、
+// 不由 operator 管理的 etcd 设置。设置值按 YAML 双引号字符串转义后写入配置文件，只要求是合法的 UTF-8
+func ValidateConfig(config *etcdv1alpha1.EtcdConfigSpec) error {
+	if config.QuotaBackendBytes != nil && config.QuotaBackendBytes.Value() <= 0 {
+		return fmt.Errorf("config quotaBackendBytes must be positive")
+	}
+	heartbeat, election := config.HeartbeatInterval, config.ElectionTimeout
+	if heartbeat == 0 {
+		heartbeat = 100
+	}
+	if election == 0 {
+		election = 1000
+	}
+	if election < 5*heartbeat {
+		return fmt.Errorf("config electionTimeout %dms must be at least five times the heartbeatInterval %dms", election, heartbeat)
+	}
+
+	for key, value := range config.ExtraFlags {
+		switch {
+		case !extraFlagKeyPattern.MatchString(key):
+			return fmt.Errorf("config extraFlags key %q must be an etcd flag name without the leading dashes", key)
+		case managedFlags[key]:
+			return fmt.Errorf("config extraFlags key %q is managed by the operator", key)
+		case strings.HasPrefix(key, "auto-compaction-"):
+			return fmt.Errorf("config extraFlags key %q must be set through spec.maintenance", key)
+		case typedFlags[key] != "":
+			return fmt.Errorf("config extraFlags key %q must be set through spec.config.%s", key, typedFlags[key])
+		case !strings.HasPrefix(key, "experimental-") && !knownFlags[key]:
+			return fmt.Errorf("config extraFlags key %q is not a supported etcd flag", key)
+		}
+		if !utf8.ValidString(value) {
+			return fmt.Errorf("config extraFlags value of %q must be valid UTF-8", key)
+		}
+	}
+	return nil
+}
+
+// buildTuningConfig 返回 etcd 配置文件中的调优配置段，后端配额和自动压缩一起由 buildMaintenanceConfig 生成。
+// extraFlags 按名称排序，保证配置不变时 Pod 模板也不变
+func buildTuningConfig(cluster *etcdv1alpha1.EtcdCluster) string {
+	config := MemberConfig(cluster)
+	var out string
+	if config.SnapshotCount > 0 {
+		out += fmt.Sprintf("snapshot-count: %d\n", config.SnapshotCount)
+	}
+	if config.HeartbeatInterval > 0 {
+		out += fmt.Sprintf("heartbeat-interval: %d\n", config.HeartbeatInterval)
+	}
+	if config.ElectionTimeout > 0 {
+		out += fmt.Sprintf("election-timeout: %d\n", config.ElectionTimeout)
+	}
+	if config.MaxRequestBytes > 0 {
+		out += fmt.Sprintf("max-request-bytes: %d\n", config.MaxRequestBytes)
+	}
+	if config.LogLevel != "" {
+		out += fmt.Sprintf("log-level: %s\n", config.LogLevel)
+	}
+
+	keys := make([]string, 0, len(config.ExtraFlags))
+	for key := range config.ExtraFlags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		out += fmt.Sprintf("%s: %s\n", key, extraFlagValue(config.ExtraFlags[key]))
+	}
+	return out
+}
+
+// extraFlagValue 返回 extraFlags 的值在配置文件中的写法。数字和布尔值原样写入，
+// 其余值写成双引号字符串，避免 ": "、" #" 或以 [、{、*、& 开头的值破坏 YAML 结构
+func extraFlagValue(value string) string {
+	if literalValuePattern.MatchString(value) {
+		return value
+	}
+	return strconv.Quote(value)
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/yaml"
+
+	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+)
+
+// TestBuildTuningConfig 测试调优配置以状态中记录的配置为准，extraFlags 按名称排序写入成员的配置文件
+func TestBuildTuningConfig(t *testing.T) {
+	cluster := newRestoreTestCluster()
+	assert.Empty(t, buildTuningConfig(cluster))
+
+	cluster.Spec.Config = etcdv1alpha1.EtcdConfigSpec{SnapshotCount: 5000}
+	assert.Equal(t, "snapshot-count: 5000\n", buildTuningConfig(cluster))
+
+	cluster.Status.Config = &etcdv1alpha1.EtcdConfigSpec{
+		HeartbeatInterval: 200,
+		ElectionTimeout:   2000,
+		LogLevel:          "warn",
+		ExtraFlags: map[string]string{
+			"max-txn-ops":                        "256",
+			"experimental-initial-corrupt-check": "true",
+		},
+	}
+	want := "heartbeat-interval: 200\nelection-timeout: 2000\nlog-level: warn\n" +
+		"experimental-initial-corrupt-check: true\nmax-txn-ops: 256\n"
+	assert.Equal(t, want, buildTuningConfig(cluster))
+	assert.Contains(t, BuildConfigMap(cluster).Data["etcd.conf"], want)
+	assert.Contains(t, extraConfig(BuildStatefulSet(cluster, StatefulSetOptions{}).Spec.Template.Spec), want)
+}
+
+// TestBuildTuningConfigQuotesValues 测试 extraFlags 中的字符串值加引号写入，数字和布尔值原样写入，生成的配置是合法的 YAML
+func TestBuildTuningConfigQuotesValues(t *testing.T) {
+	values := map[string]string{
+		"cipher-suites":         "[TLS_AES_128_GCM_SHA256]",
+		"cors":                  "{a}",
+		"discovery-srv":         "*.example.com",
+		"initial-cluster-token": "&token",
+		"log-outputs":           "stderr # comment",
+		"metrics":               "extensive: yes",
+		"experimental-distributed-tracing-service-name": `say "hi"`,
+		"max-txn-ops":             "256",
+		"strict-reconfig-check":   "false",
+		"grpc-keepalive-interval": "2h",
+		"host-whitelist":          "$(id) `id` C:\\etcd\nline\ttab",
+	}
+	cluster := newRestoreTestCluster()
+	cluster.Spec.Config = etcdv1alpha1.EtcdConfigSpec{ExtraFlags: values}
+
+	out := buildTuningConfig(cluster)
+	assert.Contains(t, out, "cipher-suites: \"[TLS_AES_128_GCM_SHA256]\"\n")
+	assert.Contains(t, out, "experimental-distributed-tracing-service-name: \"say \\\"hi\\\"\"\n")
+	assert.Contains(t, out, "max-txn-ops: 256\n")
+	assert.Contains(t, out, "strict-reconfig-check: false\n")
+
+	parsed := map[string]interface{}{}
+	require.NoError(t, yaml.Unmarshal([]byte(out), &parsed))
+	for key, value := range values {
+		switch value {
+		case "256":
+			assert.Equal(t, float64(256), parsed[key], key)
+		case "false":
+			assert.Equal(t, false, parsed[key], key)
+		default:
+			assert.Equal(t, value, parsed[key], key)
+		}
+	}
+}
+
+// TestQuotaBackendBytesFromConfig 测试配置的后端配额与 NOSPACE 告警处理中调大的配额取较大者，只写入一行
+func TestQuotaBackendBytesFromConfig(t *testing.T) {
+	cluster := newRestoreTestCluster()
+	quota := resource.MustParse("3Gi")
+	cluster.Status.Config = &etcdv1alpha1.EtcdConfigSpec{QuotaBackendBytes: &quota}
+	assert.Equal(t, int64(3*1024*1024*1024), QuotaBackendBytes(cluster))
+	assert.Equal(t, "quota-backend-bytes: 3221225472\n", buildMaintenanceConfig(cluster))
+
+	cluster.Status.Maintenance = &etcdv1alpha1.EtcdMaintenanceStatus{QuotaBackendBytes: 6 * 1024 * 1024 * 1024}
+	assert.Equal(t, int64(6*1024*1024*1024), QuotaBackendBytes(cluster))
+	assert.Equal(t, "quota-backend-bytes: 6442450944\n", buildMaintenanceConfig(cluster))
+}
+
+// TestValidateConfig 测试拒绝 operator 管理的设置、有对应字段的设置、未知设置和不安全的设置值
+func TestValidateConfig(t *testing.T) {
+	assert.NoError(t, ValidateConfig(&etcdv1alpha1.EtcdConfigSpec{}))
+	assert.NoError(t, ValidateConfig(&etcdv1alpha1.EtcdConfigSpec{
+		HeartbeatInterval: 250,
+		ElectionTimeout:   1250,
+		ExtraFlags: map[string]string{
+			"experimental-compact-hash-check-enabled": "true",
+			"grpc-keepalive-interval":                 "2h",
+			"log-outputs":                             "$(id) `id` C:\\etcd\nline",
+		},
+	}))
+
+	for name, tc := range map[string]struct {
+		config etcdv1alpha1.EtcdConfigSpec
+		err    string
+	}{
+		"election timeout too short": {
+			config: etcdv1alpha1.EtcdConfigSpec{HeartbeatInterval: 300},
+			err:    "at least five times",
+		},
+		"managed flag": {
+			config: etcdv1alpha1.EtcdConfigSpec{ExtraFlags: map[string]string{"initial-cluster-state": "existing"}},
+			err:    "managed by the operator",
+		},
+		"compaction flag": {
+			config: etcdv1alpha1.EtcdConfigSpec{ExtraFlags: map[string]string{"auto-compaction-retention": "1h"}},
+			err:    "spec.maintenance",
+		},
+		"typed flag": {
+			config: etcdv1alpha1.EtcdConfigSpec{ExtraFlags: map[string]string{"snapshot-count": "100"}},
+			err:    "spec.config.snapshotCount",
+		},
+		"unknown flag": {
+			config: etcdv1alpha1.EtcdConfigSpec{ExtraFlags: map[string]string{"snapshot-counts": "100"}},
+			err:    "not a supported etcd flag",
+		},
+		"flag with dashes": {
+			config: etcdv1alpha1.EtcdConfigSpec{ExtraFlags: map[string]string{"--max-txn-ops": "256"}},
+			err:    "without the leading dashes",
+		},
+		"invalid utf-8 value": {
+			config: etcdv1alpha1.EtcdConfigSpec{ExtraFlags: map[string]string{"log-outputs": "std\xffout"}},
+			err:    "valid UTF-8",
+		},
+	} {
+		err := ValidateConfig(&tc.config)
+		if assert.Error(t, err, name) {
+			assert.Contains(t, err.Error(), tc.err, name)
+		}
+	}
+}
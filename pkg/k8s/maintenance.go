@@ -0,0 +1,74 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8s
+
+import (
+	"fmt"
+
+	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+	"github.com/your-org/etcd-k8s-operator/pkg/utils"
+)
+
+// AutoCompaction 返回成员应使用的自动压缩模式和保留值，以状态中记录的设置为准。
+// spec 中的设置变化写入状态后才会修改 Pod 模板，由控制器逐个重启成员生效
+func AutoCompaction(cluster *etcdv1alpha1.EtcdCluster) (string, string) {
+	if cluster.Status.Maintenance != nil {
+		return cluster.Status.Maintenance.AutoCompactionMode, cluster.Status.Maintenance.AutoCompactionRetention
+	}
+	return cluster.Spec.Maintenance.AutoCompactionMode, cluster.Spec.Maintenance.AutoCompactionRetention
+}
+
+// QuotaBackendBytes 返回成员使用的后端配额：配置的配额和 NOSPACE 告警处理中调大的配额取较大者，
+// 都没有设置时为 etcd 的默认配额
+func QuotaBackendBytes(cluster *etcdv1alpha1.EtcdCluster) int64 {
+	if quota := max(configuredQuota(cluster), recordedQuota(cluster)); quota > 0 {
+		return quota
+	}
+	return utils.DefaultQuotaBackendBytes
+}
+
+// configuredQuota 返回成员配置中设置的后端配额，没有设置时为 0
+func configuredQuota(cluster *etcdv1alpha1.EtcdCluster) int64 {
+	if quota := MemberConfig(cluster).QuotaBackendBytes; quota != nil {
+		return quota.Value()
+	}
+	return 0
+}
+
+// recordedQuota 返回状态中记录的调大的后端配额，没有调大时为 0
+func recordedQuota(cluster *etcdv1alpha1.EtcdCluster) int64 {
+	if cluster.Status.Maintenance != nil {
+		return cluster.Status.Maintenance.QuotaBackendBytes
+	}
+	return 0
+}
+
+// buildMaintenanceConfig 返回 etcd 配置文件中的自动压缩和后端配额配置段，均使用默认值时为空
+func buildMaintenanceConfig(cluster *etcdv1alpha1.EtcdCluster) string {
+	var config string
+	if mode, retention := AutoCompaction(cluster); retention != "" {
+		if mode != "" {
+			config += fmt.Sprintf("auto-compaction-mode: %s\n", mode)
+		}
+		// 保留值可能是纯数字，加引号避免被解析为整数
+		config += fmt.Sprintf("auto-compaction-retention: %q\n", retention)
+	}
+	if configuredQuota(cluster) > 0 || recordedQuota(cluster) > 0 {
+		config += fmt.Sprintf("quota-backend-bytes: %d\n", QuotaBackendBytes(cluster))
+	}
+	return config
+}
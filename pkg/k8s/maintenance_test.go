@@ -0,0 +1,45 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+	"github.com/your-org/etcd-k8s-operator/pkg/utils"
+)
+
+// TestBuildMaintenanceConfig 测试自动压缩和后端配额配置以状态中记录的设置为准，并写入成员的配置文件
+func TestBuildMaintenanceConfig(t *testing.T) {
+	cluster := newRestoreTestCluster()
+	assert.Empty(t, buildMaintenanceConfig(cluster))
+
+	cluster.Spec.Maintenance = etcdv1alpha1.EtcdMaintenanceSpec{AutoCompactionMode: "revision", AutoCompactionRetention: "10000"}
+	assert.Equal(t, "auto-compaction-mode: revision\nauto-compaction-retention: \"10000\"\n", buildMaintenanceConfig(cluster))
+
+	cluster.Status.Maintenance = &etcdv1alpha1.EtcdMaintenanceStatus{AutoCompactionMode: "periodic", AutoCompactionRetention: "1h"}
+	assert.Equal(t, "auto-compaction-mode: periodic\nauto-compaction-retention: \"1h\"\n", buildMaintenanceConfig(cluster))
+	assert.Contains(t, BuildConfigMap(cluster).Data["etcd.conf"], "auto-compaction-retention: \"1h\"\n")
+	assert.Contains(t, extraConfig(BuildStatefulSet(cluster, StatefulSetOptions{}).Spec.Template.Spec), "auto-compaction-mode: periodic\n")
+
+	assert.Equal(t, int64(utils.DefaultQuotaBackendBytes), QuotaBackendBytes(cluster))
+	cluster.Status.Maintenance.QuotaBackendBytes = 4 * 1024 * 1024 * 1024
+	assert.Equal(t, int64(4*1024*1024*1024), QuotaBackendBytes(cluster))
+	assert.Contains(t, buildMaintenanceConfig(cluster), "quota-backend-bytes: 4294967296\n")
+}
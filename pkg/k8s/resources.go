@@ -18,6 +18,7 @@ package k8s
 
 import (
 	"fmt"
+	"path"
 	"strings"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -30,14 +31,21 @@ import (
 	"github.com/your-org/etcd-k8s-operator/pkg/utils"
 )
 
+// StatefulSetOptions holds the operator settings used to build member StatefulSets
+type StatefulSetOptions struct {
+	// BootstrapImage is the image the member init container runs etcd-bootstrap from, the operator's own image
+	BootstrapImage string
+}
+
 // BuildStatefulSet creates a StatefulSet for the EtcdCluster
-func BuildStatefulSet(cluster *etcdv1alpha1.EtcdCluster) *appsv1.StatefulSet {
-	return BuildStatefulSetWithReplicas(cluster, cluster.Spec.Size)
+func BuildStatefulSet(cluster *etcdv1alpha1.EtcdCluster, opts StatefulSetOptions) *appsv1.StatefulSet {
+	return BuildStatefulSetWithReplicas(cluster, cluster.Spec.Size, opts)
 }
 
 // BuildStatefulSetWithReplicas creates a StatefulSet with specified replica count
-func BuildStatefulSetWithReplicas(cluster *etcdv1alpha1.EtcdCluster, replicas int32) *appsv1.StatefulSet {
+func BuildStatefulSetWithReplicas(cluster *etcdv1alpha1.EtcdCluster, replicas int32, opts StatefulSetOptions) *appsv1.StatefulSet {
 	labels := utils.LabelsForEtcdCluster(cluster)
+	labels[utils.LabelAppVersion] = MemberVersion(cluster)
 	selectorLabels := utils.SelectorLabelsForEtcdCluster(cluster)
 
 	sts := &appsv1.StatefulSet{
@@ -56,9 +64,9 @@ func BuildStatefulSetWithReplicas(cluster *etcdv1alpha1.EtcdCluster, replicas in
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
 					Labels:      labels,
-					Annotations: utils.AnnotationsForEtcdCluster(cluster),
+					Annotations: podAnnotations(cluster),
 				},
-				Spec: buildPodSpec(cluster),
+				Spec: buildPodSpec(cluster, opts),
 			},
 			VolumeClaimTemplates: buildVolumeClaimTemplates(cluster),
 			PodManagementPolicy:  appsv1.ParallelPodManagement,
@@ -72,10 +80,10 @@ func BuildStatefulSetWithReplicas(cluster *etcdv1alpha1.EtcdCluster, replicas in
 }
 
 // buildPodSpec creates the pod specification for etcd
-func buildPodSpec(cluster *etcdv1alpha1.EtcdCluster) corev1.PodSpec {
+func buildPodSpec(cluster *etcdv1alpha1.EtcdCluster, opts StatefulSetOptions) corev1.PodSpec {
 	// Build init containers for all clusters (single and multi-node)
 	var initContainers []corev1.Container
-	initContainers = append(initContainers, buildEtcdInitContainer(cluster))
+	initContainers = append(initContainers, buildEtcdInitContainer(cluster, opts.BootstrapImage))
 
 	containers := []corev1.Container{
 		buildEtcdContainer(cluster, 0), // StatefulSet 模板中使用默认配置
@@ -107,6 +115,7 @@ func buildPodSpec(cluster *etcdv1alpha1.EtcdCluster) corev1.PodSpec {
 			EmptyDir: &corev1.EmptyDirVolumeSource{},
 		},
 	})
+	volumes = append(volumes, buildTLSVolumes(cluster)...)
 
 	return corev1.PodSpec{
 		InitContainers:                initContainers,
@@ -123,11 +132,9 @@ func buildPodSpec(cluster *etcdv1alpha1.EtcdCluster) corev1.PodSpec {
 
 // buildEtcdContainer creates the etcd container specification
 func buildEtcdContainer(cluster *etcdv1alpha1.EtcdCluster, podIndex int) corev1.Container {
-	image := fmt.Sprintf("%s:%s", cluster.Spec.Repository, cluster.Spec.Version)
-
 	container := corev1.Container{
 		Name:  "etcd",
-		Image: image,
+		Image: MemberImage(cluster),
 		// 使用配置文件启动 etcd（由 Init Container 生成）
 		Command: []string{"/usr/local/bin/etcd"},
 		Args:    []string{"--config-file=/etc/etcd/etcd.conf"},
@@ -180,6 +187,7 @@ func buildVolumeMounts(cluster *etcdv1alpha1.EtcdCluster) []corev1.VolumeMount {
 		MountPath: "/etc/etcd",
 		ReadOnly:  true,
 	})
+	mounts = append(mounts, buildTLSVolumeMounts(cluster)...)
 
 	return mounts
 }
@@ -210,19 +218,19 @@ func buildEtcdEnvironment(cluster *etcdv1alpha1.EtcdCluster, podIndex int) []cor
 		},
 		{
 			Name:  "ETCD_LISTEN_CLIENT_URLS",
-			Value: fmt.Sprintf("http://0.0.0.0:%d", utils.EtcdClientPort),
+			Value: ListenClientURLs(cluster),
 		},
 		{
 			Name:  "ETCD_LISTEN_PEER_URLS",
-			Value: fmt.Sprintf("http://0.0.0.0:%d", utils.EtcdPeerPort),
+			Value: fmt.Sprintf("%s://0.0.0.0:%d", PeerScheme(cluster), utils.EtcdPeerPort),
 		},
 		{
 			Name:  "ETCD_ADVERTISE_CLIENT_URLS",
-			Value: fmt.Sprintf("http://$(ETCD_NAME).%s-peer.%s.svc.cluster.local:%d", cluster.Name, cluster.Namespace, utils.EtcdClientPort),
+			Value: fmt.Sprintf("%s://$(ETCD_NAME).%s-peer.%s.svc.cluster.local:%d", ClientScheme(cluster), cluster.Name, cluster.Namespace, utils.EtcdClientPort),
 		},
 		{
 			Name:  "ETCD_INITIAL_ADVERTISE_PEER_URLS",
-			Value: fmt.Sprintf("http://$(ETCD_NAME).%s-peer.%s.svc.cluster.local:%d", cluster.Name, cluster.Namespace, utils.EtcdPeerPort),
+			Value: fmt.Sprintf("%s://$(ETCD_NAME).%s-peer.%s.svc.cluster.local:%d", PeerScheme(cluster), cluster.Name, cluster.Namespace, utils.EtcdPeerPort),
 		},
 		{
 			Name:  "ETCD_INITIAL_CLUSTER_STATE",
@@ -248,16 +256,12 @@ func buildEtcdEnvironment(cluster *etcdv1alpha1.EtcdCluster, podIndex int) []cor
 
 // buildLivenessProbe 创建存活检查探针
 func buildLivenessProbe(cluster *etcdv1alpha1.EtcdCluster) *corev1.Probe {
-	// 官方镜像使用 etcdctl 进行健康检查
+	// 官方镜像使用 etcdctl 进行健康检查。扩容时新成员以 learner 身份同步数据，learner 拒绝线性一致读，
+	// endpoint health 会失败，因此使用本地的串行读确认成员进程在响应
 	return &corev1.Probe{
 		ProbeHandler: corev1.ProbeHandler{
 			Exec: &corev1.ExecAction{
-				Command: []string{
-					"etcdctl",
-					"--endpoints=http://localhost:2379",
-					"endpoint",
-					"health",
-				},
+				Command: append(etcdctlCommand(cluster), "get", "health", "--consistency=s"),
 			},
 		},
 		InitialDelaySeconds: 30,
@@ -290,12 +294,7 @@ func buildReadinessProbe(cluster *etcdv1alpha1.EtcdCluster) *corev1.Probe {
 	return &corev1.Probe{
 		ProbeHandler: corev1.ProbeHandler{
 			Exec: &corev1.ExecAction{
-				Command: []string{
-					"etcdctl",
-					"--endpoints=http://localhost:2379",
-					"endpoint",
-					"health",
-				},
+				Command: append(etcdctlCommand(cluster), "endpoint", "health"),
 			},
 		},
 		InitialDelaySeconds: 15, // 官方镜像启动较快
@@ -309,10 +308,7 @@ func buildReadinessProbe(cluster *etcdv1alpha1.EtcdCluster) *corev1.Probe {
 func buildInitialCluster(cluster *etcdv1alpha1.EtcdCluster) string {
 	var members []string
 	for i := int32(0); i < cluster.Spec.Size; i++ {
-		memberName := fmt.Sprintf("%s-%d", cluster.Name, i)
-		memberURL := fmt.Sprintf("http://%s.%s-peer.%s.svc.cluster.local:%d",
-			memberName, cluster.Name, cluster.Namespace, utils.EtcdPeerPort)
-		members = append(members, fmt.Sprintf("%s=%s", memberName, memberURL))
+		members = append(members, fmt.Sprintf("%s=%s", MemberName(cluster, i), MemberPeerURL(cluster, i)))
 	}
 	return strings.Join(members, ",")
 }
@@ -323,152 +319,77 @@ func buildInitialCluster(cluster *etcdv1alpha1.EtcdCluster) string {
 func buildDynamicInitialCluster(cluster *etcdv1alpha1.EtcdCluster) string {
 	// 对于多节点集群，第一个节点以单节点模式启动
 	// 这样避免了等待其他节点的问题
-	firstNodeName := fmt.Sprintf("%s-0", cluster.Name)
-	firstNodeURL := fmt.Sprintf("http://%s.%s-peer.%s.svc.cluster.local:%d",
-		firstNodeName, cluster.Name, cluster.Namespace, utils.EtcdPeerPort)
+	firstNodeName := MemberName(cluster, 0)
+	firstNodeURL := MemberPeerURL(cluster, 0)
 
 	// 只返回第一个节点的配置，其他节点将通过动态扩容添加
 	return fmt.Sprintf("%s=%s", firstNodeName, firstNodeURL)
 }
 
-// buildEtcdInitContainer creates an init container for multi-node etcd setup
-func buildEtcdInitContainer(cluster *etcdv1alpha1.EtcdCluster) corev1.Container {
-	script := `#!/bin/sh
-set -e
-
-# 获取当前节点信息
-HOSTNAME=$(hostname)
-POD_INDEX=$(echo $HOSTNAME | sed 's/.*-//')
-
-echo "Current hostname: $HOSTNAME"
-echo "Pod index: $POD_INDEX"
-
-# 创建配置目录
-mkdir -p /etc/etcd
-
-# 根据节点索引设置集群配置
-if [ "$POD_INDEX" = "0" ]; then
-    # 第一个节点：使用 new 模式启动单节点集群
-    echo "Configuring as first node (new cluster)"
-    cat > /etc/etcd/etcd.conf << EOF
-# etcd configuration for $HOSTNAME
-name: $HOSTNAME
-data-dir: /data
-listen-client-urls: http://0.0.0.0:2379
-listen-peer-urls: http://0.0.0.0:2380
-advertise-client-urls: http://$HOSTNAME.` + cluster.Name + `-peer.` + cluster.Namespace + `.svc.cluster.local:2379
-initial-advertise-peer-urls: http://$HOSTNAME.` + cluster.Name + `-peer.` + cluster.Namespace + `.svc.cluster.local:2380
-initial-cluster-token: ` + cluster.Name + `
-initial-cluster-state: new
-initial-cluster: $HOSTNAME=http://$HOSTNAME.` + cluster.Name + `-peer.` + cluster.Namespace + `.svc.cluster.local:2380
-EOF
-else
-    # 后续节点：使用 existing 模式，但只包含第一个节点
-    # 控制器会在启动前添加这个节点到集群中
-    echo "Configuring as additional node (joining existing cluster)"
-
-    # 等待第一个节点就绪
-    echo "Waiting for first node to be ready..."
-    while ! nslookup ` + cluster.Name + `-0.` + cluster.Name + `-peer.` + cluster.Namespace + `.svc.cluster.local; do
-        echo "First node not ready, waiting..."
-        sleep 2
-    done
-
-    # 查询当前etcd集群的实际成员列表
-    echo "Querying current etcd cluster members..."
-
-    # 尝试从第一个节点获取成员列表
-    FIRST_NODE="` + cluster.Name + `-0.` + cluster.Name + `-peer.` + cluster.Namespace + `.svc.cluster.local:2379"
-
-    # 使用wget查询etcd v3 API获取成员列表
-    echo "Using wget to query existing members from $FIRST_NODE..."
-
-    # 查询成员列表，使用etcd v3 API
-    # 创建临时文件存储POST数据
-    echo '{}' > /tmp/post_data.json
-    members_json=$(wget -q -O - --post-file=/tmp/post_data.json --header="Content-Type: application/json" "http://$FIRST_NODE/v3/cluster/member/list" 2>/dev/null)
-
-    if [ $? -eq 0 ] && [ -n "$members_json" ]; then
-        echo "Successfully queried etcd v3 members API"
-        echo "API response: $members_json"
-
-        # 解析JSON获取成员的peerURLs（改进版本，正确处理多个成员）
-        # 使用grep和sed提取所有peerURLs
-        peer_urls=$(echo "$members_json" | grep -o '"peerURLs":\["[^"]*"\]' | sed 's/"peerURLs":\["\([^"]*\)"\]/\1/')
-
-        if [ -n "$peer_urls" ]; then
-            echo "Found existing peer URLs: $peer_urls"
-            existing_members=""
-
-            # 从每个peerURL中提取节点名并构建成员配置
-            for url in $peer_urls; do
-                # 从URL中提取节点名，例如从 http://test-single-node-0.test-single-node-peer.default.svc.cluster.local:2380 提取 test-single-node-0
-                node_name=$(echo "$url" | sed 's|http://\([^.]*\)\..*|\1|')
-                if [ -n "$node_name" ]; then
-                    member_url="$node_name=$url"
-                    if [ -z "$existing_members" ]; then
-                        existing_members="$member_url"
-                    else
-                        existing_members="$existing_members,$member_url"
-                    fi
-                fi
-            done
-
-            # 检查当前节点是否已经在集群中
-            current_node_url="http://` + cluster.Name + `-$POD_INDEX.` + cluster.Name + `-peer.` + cluster.Namespace + `.svc.cluster.local:2380"
-            if echo "$peer_urls" | grep -q "$current_node_url"; then
-                # 当前节点已在集群中，只使用现有成员列表
-                members="$existing_members"
-                echo "Current node already in cluster, using existing members: $members"
-            else
-                # 当前节点不在集群中，添加到成员列表
-                members="$existing_members,` + cluster.Name + `-$POD_INDEX=http://` + cluster.Name + `-$POD_INDEX.` + cluster.Name + `-peer.` + cluster.Namespace + `.svc.cluster.local:2380"
-                echo "Current node not in cluster, adding to members: $members"
-            fi
-        else
-            echo "Could not parse member names from API response, using fallback"
-            # 回退方法：假设只有第一个节点存在
-            members="` + cluster.Name + `-0=http://` + cluster.Name + `-0.` + cluster.Name + `-peer.` + cluster.Namespace + `.svc.cluster.local:2380,` + cluster.Name + `-$POD_INDEX=http://` + cluster.Name + `-$POD_INDEX.` + cluster.Name + `-peer.` + cluster.Namespace + `.svc.cluster.local:2380"
-        fi
-    else
-        echo "Failed to query etcd v3 API, using fallback method"
-        # 回退方法：假设只有第一个节点存在
-        members="` + cluster.Name + `-0=http://` + cluster.Name + `-0.` + cluster.Name + `-peer.` + cluster.Namespace + `.svc.cluster.local:2380,` + cluster.Name + `-$POD_INDEX=http://` + cluster.Name + `-$POD_INDEX.` + cluster.Name + `-peer.` + cluster.Namespace + `.svc.cluster.local:2380"
-    fi
-
-    echo "Using member list: $members"
-
-
-    cat > /etc/etcd/etcd.conf << EOF
-# etcd configuration for $HOSTNAME
-name: $HOSTNAME
-data-dir: /data
-listen-client-urls: http://0.0.0.0:2379
-listen-peer-urls: http://0.0.0.0:2380
-advertise-client-urls: http://$HOSTNAME.` + cluster.Name + `-peer.` + cluster.Namespace + `.svc.cluster.local:2379
-initial-advertise-peer-urls: http://$HOSTNAME.` + cluster.Name + `-peer.` + cluster.Namespace + `.svc.cluster.local:2380
-initial-cluster-token: ` + cluster.Name + `
-initial-cluster-state: existing
-initial-cluster: $members
-EOF
-
-    echo "Configuration completed for additional node"
-fi
-
-echo "Generated etcd configuration:"
-cat /etc/etcd/etcd.conf
-echo "Init container completed successfully"
-`
+// buildEtcdInitContainer 创建运行 etcd-bootstrap 的 init 容器。etcd-bootstrap 根据数据目录和集群的成员列表
+// 确定 initial-cluster 和 initial-cluster-state，再写入成员的配置文件。image 为 operator 自身的镜像
+func buildEtcdInitContainer(cluster *etcdv1alpha1.EtcdCluster, image string) corev1.Container {
+	mounts := []corev1.VolumeMount{
+		{
+			Name:      "etcd-config",
+			MountPath: "/etc/etcd",
+		},
+		{
+			// 只用于判断成员是否已有数据
+			Name:      "data",
+			MountPath: utils.EtcdDataDir,
+			ReadOnly:  true,
+		},
+	}
+	mounts = append(mounts, buildTLSVolumeMounts(cluster)...)
 
 	return corev1.Container{
 		Name:    "etcd-init",
-		Image:   "busybox:1.35",
-		Command: []string{"/bin/sh", "-c", script},
-		VolumeMounts: []corev1.VolumeMount{
+		Image:   image,
+		Command: []string{utils.EtcdBootstrapCommand},
+		Args:    buildBootstrapArgs(cluster),
+		Env: []corev1.EnvVar{
 			{
-				Name:      "etcd-config",
-				MountPath: "/etc/etcd",
+				Name: "POD_NAME",
+				ValueFrom: &corev1.EnvVarSource{
+					FieldRef: &corev1.ObjectFieldSelector{
+						FieldPath: "metadata.name",
+					},
+				},
 			},
+			{
+				// Pod 创建时读取，恢复期间更新 ConfigMap 后重建 Pod 才会生效
+				Name: "FORCE_NEW_CLUSTER",
+				ValueFrom: &corev1.EnvVarSource{
+					ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: ConfigMapName(cluster)},
+						Key:                  utils.ConfigKeyForceNewCluster,
+						Optional:             &[]bool{true}[0],
+					},
+				},
+			},
+			{
+				// Pod 创建时读取，只有集群创建期间的成员 0 会以新集群启动
+				Name: "INITIAL_CLUSTER_STATE",
+				ValueFrom: &corev1.EnvVarSource{
+					ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: ConfigMapName(cluster)},
+						Key:                  utils.ConfigKeyInitialClusterState,
+						Optional:             &[]bool{true}[0],
+					},
+				},
+			},
+			{
+				// 证书、维护和调优配置随 Pod 模板变化，由控制器逐个重启成员生效
+				Name:  "ETCD_EXTRA_CONFIG",
+				Value: buildTLSConfig(cluster) + buildMaintenanceConfig(cluster) + buildTuningConfig(cluster),
+			},
+		},
+		VolumeMounts: mounts,
+		// etcd 数据目录只允许属主访问，以 root 运行才能检查已有数据
+		SecurityContext: &corev1.SecurityContext{
+			RunAsUser:    &[]int64{0}[0],
+			RunAsNonRoot: &[]bool{false}[0],
 		},
 		Resources: corev1.ResourceRequirements{
 			Requests: corev1.ResourceList{
@@ -483,6 +404,32 @@ echo "Init container completed successfully"
 	}
 }
 
+// buildBootstrapArgs 返回 etcd-bootstrap 的参数。成员列表从客户端服务和成员 0 查询，
+// 扩容时新成员在 operator 将其加入集群之前等待
+func buildBootstrapArgs(cluster *etcdv1alpha1.EtcdCluster) []string {
+	args := []string{
+		"--name=$(POD_NAME)",
+		"--data-dir=" + utils.EtcdDataDir,
+		"--config-file=/etc/etcd/etcd.conf",
+		"--peer-domain=" + PeerDomain(cluster),
+		"--client-scheme=" + ClientScheme(cluster),
+		"--peer-scheme=" + PeerScheme(cluster),
+		"--listen-client-urls=" + ListenClientURLs(cluster),
+		"--cluster-token=" + cluster.Name,
+		fmt.Sprintf("--endpoints=%s://%s:%d,%s", ClientScheme(cluster), ClientServiceHost(cluster), utils.EtcdClientPort, MemberClientURL(cluster, 0)),
+	}
+	if ClientTLSEnabled(cluster) {
+		dir := tlsMountPath(CertificateClient)
+		args = append(args,
+			"--server-name="+ClientServiceHost(cluster),
+			"--ca-file="+path.Join(dir, utils.SecretKeyCACert),
+			"--cert-file="+path.Join(dir, corev1.TLSCertKey),
+			"--key-file="+path.Join(dir, corev1.TLSPrivateKeyKey),
+		)
+	}
+	return args
+}
+
 // buildResourceRequirements creates resource requirements for etcd container
 func buildResourceRequirements(cluster *etcdv1alpha1.EtcdCluster) corev1.ResourceRequirements {
 	// Use cluster-specific resources if provided, otherwise use defaults
@@ -625,21 +572,44 @@ func BuildNodePortService(cluster *etcdv1alpha1.EtcdCluster) *corev1.Service {
 	}
 }
 
+// ConfigMapName 返回集群配置 ConfigMap 的名称
+func ConfigMapName(cluster *etcdv1alpha1.EtcdCluster) string {
+	return fmt.Sprintf("%s-config", cluster.Name)
+}
+
 // BuildConfigMap 创建etcd配置的ConfigMap
 func BuildConfigMap(cluster *etcdv1alpha1.EtcdCluster) *corev1.ConfigMap {
 	labels := utils.LabelsForEtcdCluster(cluster)
+	data := map[string]string{
+		"etcd.conf": buildEtcdConfig(cluster),
+	}
+	if state := InitialClusterState(cluster); state != "" {
+		data[utils.ConfigKeyInitialClusterState] = state
+	}
 
 	return &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:        fmt.Sprintf("%s-config", cluster.Name),
+			Name:        ConfigMapName(cluster),
 			Namespace:   cluster.Namespace,
 			Labels:      labels,
 			Annotations: utils.AnnotationsForEtcdCluster(cluster),
 		},
-		Data: map[string]string{
-			"etcd.conf": buildEtcdConfig(cluster),
-		},
+		Data: data,
+	}
+}
+
+// InitialClusterState 返回成员 0 的 init 容器可以使用的 initial-cluster-state。
+// 只有集群处于创建阶段且还没有记录任何成员 ID 时返回 new，其他情况返回空，数据目录为空的成员只能等待加入已有集群
+func InitialClusterState(cluster *etcdv1alpha1.EtcdCluster) string {
+	if cluster.Status.Phase != "" && cluster.Status.Phase != etcdv1alpha1.EtcdClusterPhaseCreating {
+		return ""
+	}
+	for _, m := range cluster.Status.Members {
+		if m.ID != "" {
+			return ""
+		}
 	}
+	return "new"
 }
 
 // buildEtcdConfig creates etcd configuration content
@@ -647,22 +617,25 @@ func buildEtcdConfig(cluster *etcdv1alpha1.EtcdCluster) string {
 	config := fmt.Sprintf(`# etcd configuration for cluster %s
 name: $(ETCD_NAME)
 data-dir: %s
-listen-client-urls: http://0.0.0.0:%d
-listen-peer-urls: http://0.0.0.0:%d
-advertise-client-urls: http://$(ETCD_NAME).%s-peer.%s.svc.cluster.local:%d
-initial-advertise-peer-urls: http://$(ETCD_NAME).%s-peer.%s.svc.cluster.local:%d
+listen-client-urls: %s
+listen-peer-urls: %s://0.0.0.0:%d
+advertise-client-urls: %s://$(ETCD_NAME).%s-peer.%s.svc.cluster.local:%d
+initial-advertise-peer-urls: %s://$(ETCD_NAME).%s-peer.%s.svc.cluster.local:%d
 initial-cluster-state: new
 initial-cluster-token: %s
 initial-cluster: %s
-`,
+%s%s%s`,
 		cluster.Name,
 		utils.EtcdDataDir,
-		utils.EtcdClientPort,
-		utils.EtcdPeerPort,
-		cluster.Name, cluster.Namespace, utils.EtcdClientPort,
-		cluster.Name, cluster.Namespace, utils.EtcdPeerPort,
+		ListenClientURLs(cluster),
+		PeerScheme(cluster), utils.EtcdPeerPort,
+		ClientScheme(cluster), cluster.Name, cluster.Namespace, utils.EtcdClientPort,
+		PeerScheme(cluster), cluster.Name, cluster.Namespace, utils.EtcdPeerPort,
 		cluster.Name,
 		buildInitialCluster(cluster),
+		buildTLSConfig(cluster),
+		buildMaintenanceConfig(cluster),
+		buildTuningConfig(cluster),
 	)
 
 	return config
@@ -692,18 +665,12 @@ func buildInitialClusterForNode(cluster *etcdv1alpha1.EtcdCluster, podIndex int)
 	// 多节点集群：根据节点索引构建正确的初始集群配置
 	if podIndex == 0 {
 		// 第一个节点：只包含自己
-		firstNodeName := fmt.Sprintf("%s-0", cluster.Name)
-		firstNodeURL := fmt.Sprintf("http://%s.%s-peer.%s.svc.cluster.local:%d",
-			firstNodeName, cluster.Name, cluster.Namespace, utils.EtcdPeerPort)
-		return fmt.Sprintf("%s=%s", firstNodeName, firstNodeURL)
+		return fmt.Sprintf("%s=%s", MemberName(cluster, 0), MemberPeerURL(cluster, 0))
 	} else {
 		// 后续节点：包含从0到当前节点的所有节点
 		var members []string
-		for i := 0; i <= podIndex; i++ {
-			memberName := fmt.Sprintf("%s-%d", cluster.Name, i)
-			memberURL := fmt.Sprintf("http://%s.%s-peer.%s.svc.cluster.local:%d",
-				memberName, cluster.Name, cluster.Namespace, utils.EtcdPeerPort)
-			members = append(members, fmt.Sprintf("%s=%s", memberName, memberURL))
+		for i := int32(0); i <= int32(podIndex); i++ {
+			members = append(members, fmt.Sprintf("%s=%s", MemberName(cluster, i), MemberPeerURL(cluster, i)))
 		}
 		return strings.Join(members, ",")
 	}
@@ -61,7 +61,7 @@ func (suite *ResourcesTestSuite) SetupTest() {
 
 // TestStatefulSetBuilder 测试 StatefulSet 构建器
 func (suite *ResourcesTestSuite) TestStatefulSetBuilder() {
-	sts := BuildStatefulSet(suite.cluster)
+	sts := BuildStatefulSet(suite.cluster, StatefulSetOptions{})
 
 	// 验证基本属性
 	assert.Equal(suite.T(), suite.cluster.Name, sts.Name)
@@ -271,3 +271,70 @@ func (suite *ResourcesTestSuite) TestVolumeClaimTemplatesWithStorageClass() {
 func TestResourcesTestSuite(t *testing.T) {
 	suite.Run(t, new(ResourcesTestSuite))
 }
+
+// extraConfig 返回 init 容器追加到成员配置文件的配置段
+func extraConfig(pod corev1.PodSpec) string {
+	for _, env := range pod.InitContainers[0].Env {
+		if env.Name == "ETCD_EXTRA_CONFIG" {
+			return env.Value
+		}
+	}
+	return ""
+}
+
+// TestBuildEtcdInitContainer 测试 init 容器以 operator 镜像运行 etcd-bootstrap，并挂载数据目录检查已有数据
+func TestBuildEtcdInitContainer(t *testing.T) {
+	cluster := &etcdv1alpha1.EtcdCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "prod"},
+		Spec:       etcdv1alpha1.EtcdClusterSpec{Size: 3, Version: "v3.5.21"},
+	}
+
+	pod := BuildStatefulSet(cluster, StatefulSetOptions{BootstrapImage: "registry.example.com/etcd-operator:v1"}).Spec.Template.Spec
+	init := pod.InitContainers[0]
+	assert.Equal(t, "registry.example.com/etcd-operator:v1", init.Image)
+	assert.Equal(t, []string{utils.EtcdBootstrapCommand}, init.Command)
+	assert.Equal(t, []string{
+		"--name=$(POD_NAME)",
+		"--data-dir=/data",
+		"--config-file=/etc/etcd/etcd.conf",
+		"--peer-domain=demo-peer.prod.svc.cluster.local",
+		"--client-scheme=http",
+		"--peer-scheme=http",
+		"--listen-client-urls=http://0.0.0.0:2379",
+		"--cluster-token=demo",
+		"--endpoints=http://demo-client.prod.svc:2379,http://demo-0.demo-peer.prod.svc.cluster.local:2379",
+	}, init.Args)
+	assert.Contains(t, init.VolumeMounts, corev1.VolumeMount{Name: "data", MountPath: "/data", ReadOnly: true})
+	assert.Empty(t, extraConfig(pod))
+}
+
+// TestInitialClusterState 测试只有创建阶段且没有记录成员 ID 时 ConfigMap 才允许成员 0 以新集群启动
+func TestInitialClusterState(t *testing.T) {
+	cluster := &etcdv1alpha1.EtcdCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "prod"},
+		Spec:       etcdv1alpha1.EtcdClusterSpec{Size: 3, Version: "v3.5.21"},
+	}
+	assert.Equal(t, "new", BuildConfigMap(cluster).Data[utils.ConfigKeyInitialClusterState])
+
+	cluster.Status.Phase = etcdv1alpha1.EtcdClusterPhaseCreating
+	cluster.Status.Members = []etcdv1alpha1.EtcdMember{{Name: "demo-0"}}
+	assert.Equal(t, "new", InitialClusterState(cluster))
+
+	cluster.Status.Members[0].ID = "a"
+	assert.Empty(t, InitialClusterState(cluster))
+	assert.NotContains(t, BuildConfigMap(cluster).Data, utils.ConfigKeyInitialClusterState)
+
+	cluster.Status.Members = nil
+	for _, phase := range []etcdv1alpha1.EtcdClusterPhase{etcdv1alpha1.EtcdClusterPhaseRunning, etcdv1alpha1.EtcdClusterPhaseFailed, etcdv1alpha1.EtcdClusterPhaseStopped} {
+		cluster.Status.Phase = phase
+		assert.Empty(t, InitialClusterState(cluster), phase)
+	}
+
+	var stateKey string
+	for _, env := range BuildStatefulSet(cluster, StatefulSetOptions{}).Spec.Template.Spec.InitContainers[0].Env {
+		if env.Name == "INITIAL_CLUSTER_STATE" {
+			stateKey = env.ValueFrom.ConfigMapKeyRef.Key
+		}
+	}
+	assert.Equal(t, utils.ConfigKeyInitialClusterState, stateKey)
+}
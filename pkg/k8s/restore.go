@@ -0,0 +1,256 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8s
+
+import (
+	"fmt"
+	"path"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+	"github.com/your-org/etcd-k8s-operator/pkg/utils"
+)
+
+const (
+	// restoreHelperImage 下载和搬移快照数据使用的镜像，与成员 init 容器保持一致
+	restoreHelperImage = "busybox:1.35"
+
+	// RestoreSourcePort 快照源 Pod 提供下载的端口
+	RestoreSourcePort = 8080
+
+	// RestoreSourceUser 快照源 Pod 基本认证的用户名，密码为恢复令牌 Secret 中的 sourceToken
+	RestoreSourceUser = "restore"
+
+	// restoreSourceMountPath 源 PVC 在快照源 Pod 中的挂载路径
+	restoreSourceMountPath = "/source"
+)
+
+// RestoreSeedJobOptions 成员 PVC 初始化任务的参数
+type RestoreSeedJobOptions struct {
+	// SnapshotURL 暂存快照的下载地址
+	SnapshotURL string
+	// TokenSecret 保存下载令牌的 Secret 名称
+	TokenSecret string
+	// ClusterToken 恢复后集群使用的 initial-cluster-token
+	ClusterToken string
+	// SkipHashCheck 跳过 etcdutl 对快照完整性哈希的校验
+	SkipHashCheck bool
+}
+
+// MemberName 返回指定序号成员的名称，与 StatefulSet Pod 的主机名一致
+func MemberName(cluster *etcdv1alpha1.EtcdCluster, ordinal int32) string {
+	return fmt.Sprintf("%s-%d", cluster.Name, ordinal)
+}
+
+// MemberPeerURL 返回指定序号成员的 peer 地址
+func MemberPeerURL(cluster *etcdv1alpha1.EtcdCluster, ordinal int32) string {
+	return fmt.Sprintf("%s://%s:%d", PeerScheme(cluster), MemberHost(cluster, ordinal), utils.EtcdPeerPort)
+}
+
+// MemberPVCName 返回指定序号成员的数据 PVC 名称，与 StatefulSet 的 volumeClaimTemplate 命名规则一致
+func MemberPVCName(cluster *etcdv1alpha1.EtcdCluster, ordinal int32) string {
+	return fmt.Sprintf("data-%s", MemberName(cluster, ordinal))
+}
+
+// BuildMemberPVC 按 volumeClaimTemplate 预先创建成员的数据 PVC，StatefulSet 启动后会直接使用它
+func BuildMemberPVC(cluster *etcdv1alpha1.EtcdCluster, ordinal int32) *corev1.PersistentVolumeClaim {
+	pvc := buildVolumeClaimTemplates(cluster)[0]
+	pvc.Name = MemberPVCName(cluster, ordinal)
+	pvc.Namespace = cluster.Namespace
+	return &pvc
+}
+
+// RestoreSeedJobName 返回初始化指定序号成员 PVC 的任务名称
+func RestoreSeedJobName(restore *etcdv1alpha1.EtcdRestore, ordinal int32) string {
+	return fmt.Sprintf("%s-seed-%d", restore.Name, ordinal)
+}
+
+// BuildRestoreSeedJob 创建初始化成员 PVC 的任务：
+// 下载暂存的快照，用 etcdutl snapshot restore 按成员身份生成数据目录，再替换 PVC 中原有的数据
+func BuildRestoreSeedJob(restore *etcdv1alpha1.EtcdRestore, cluster *etcdv1alpha1.EtcdCluster, ordinal int32, opts RestoreSeedJobOptions) *batchv1.Job {
+	memberName := MemberName(cluster, ordinal)
+	// 工作目录位于数据卷上，与最终的 member 目录同卷，搬移时只需重命名
+	workDir := path.Join(utils.EtcdDataDir, ".restore")
+	snapshotFile := path.Join(workDir, "snapshot.db")
+	restoredDir := path.Join(workDir, "data")
+
+	// 不使用集群的选择器标签，避免任务 Pod 被 Service 和 StatefulSet 选中
+	labels := map[string]string{
+		utils.LabelAppName:      "etcd-restore",
+		utils.LabelAppInstance:  restore.Name,
+		utils.LabelAppManagedBy: "etcd-operator",
+		utils.LabelEtcdRestore:  restore.Name,
+		utils.LabelEtcdMember:   memberName,
+	}
+
+	restoreArgs := []string{
+		"snapshot", "restore", snapshotFile,
+		"--name", memberName,
+		"--initial-cluster", buildInitialCluster(cluster),
+		"--initial-cluster-token", opts.ClusterToken,
+		"--initial-advertise-peer-urls", MemberPeerURL(cluster, ordinal),
+		"--data-dir", restoredDir,
+	}
+	if opts.SkipHashCheck {
+		restoreArgs = append(restoreArgs, "--skip-hash-check")
+	}
+
+	dataMount := []corev1.VolumeMount{{
+		Name:      "data",
+		MountPath: utils.EtcdDataDir,
+	}}
+	backoffLimit := int32(utils.DefaultRestoreJobBackoffLimit)
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      RestoreSeedJobName(restore, ordinal),
+			Namespace: restore.Namespace,
+			Labels:    labels,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					InitContainers: []corev1.Container{
+						{
+							// 每次重试都从头下载，避免使用上次失败留下的半截文件
+							Name:  "fetch",
+							Image: restoreHelperImage,
+							Command: []string{"/bin/sh", "-c", fmt.Sprintf(
+								`set -e; rm -rf %[1]s; mkdir -p %[1]s; wget -q -O %[2]s --header "Authorization: Bearer $SNAPSHOT_TOKEN" "$SNAPSHOT_URL"`,
+								workDir, snapshotFile)},
+							Env: []corev1.EnvVar{
+								{Name: "SNAPSHOT_URL", Value: opts.SnapshotURL},
+								{
+									Name: "SNAPSHOT_TOKEN",
+									ValueFrom: &corev1.EnvVarSource{
+										SecretKeyRef: &corev1.SecretKeySelector{
+											LocalObjectReference: corev1.LocalObjectReference{Name: opts.TokenSecret},
+											Key:                  utils.SecretKeyRestoreToken,
+										},
+									},
+								},
+							},
+							VolumeMounts: dataMount,
+						},
+						{
+							// etcd 镜像没有 shell，直接调用 etcdutl
+							Name:         "restore",
+							Image:        MemberImage(cluster),
+							Command:      append([]string{"etcdutl"}, restoreArgs...),
+							VolumeMounts: dataMount,
+						},
+					},
+					Containers: []corev1.Container{
+						{
+							Name:  "install",
+							Image: restoreHelperImage,
+							Command: []string{"/bin/sh", "-c", fmt.Sprintf(
+								`set -e; rm -rf %[1]s; mv %[2]s %[1]s; rm -rf %[3]s`,
+								path.Join(utils.EtcdDataDir, "member"), path.Join(restoredDir, "member"), workDir)},
+							VolumeMounts: dataMount,
+						},
+					},
+					Volumes: []corev1.Volume{{
+						Name: "data",
+						VolumeSource: corev1.VolumeSource{
+							PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+								ClaimName: MemberPVCName(cluster, ordinal),
+							},
+						},
+					}},
+				},
+			},
+		},
+	}
+}
+
+// RestoreSourcePodName 返回读取 PVC 快照源的 Pod 名称
+func RestoreSourcePodName(restore *etcdv1alpha1.EtcdRestore) string {
+	return fmt.Sprintf("%s-source", restore.Name)
+}
+
+// BuildRestoreSourcePod 创建以只读方式挂载快照源 PVC 并通过 HTTP 提供下载的 Pod，
+// Operator 经由它读取 PVC 中的快照。访问需要基本认证，密码取自 tokenSecret
+func BuildRestoreSourcePod(restore *etcdv1alpha1.EtcdRestore, source *etcdv1alpha1.EtcdPVCRestoreSource, tokenSecret string) *corev1.Pod {
+	labels := map[string]string{
+		utils.LabelAppName:      "etcd-restore",
+		utils.LabelAppInstance:  restore.Name,
+		utils.LabelAppManagedBy: "etcd-operator",
+		utils.LabelEtcdRestore:  restore.Name,
+	}
+
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      RestoreSourcePodName(restore),
+			Namespace: restore.Namespace,
+			Labels:    labels,
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyNever,
+			Containers: []corev1.Container{{
+				Name:  "source",
+				Image: restoreHelperImage,
+				// busybox httpd 的配置行 /:user:pass 为整个目录启用基本认证
+				Command: []string{"/bin/sh", "-c", fmt.Sprintf(
+					`set -e; echo "/:%s:$SOURCE_TOKEN" > /tmp/httpd.conf; exec httpd -f -p %d -h %s -c /tmp/httpd.conf`,
+					RestoreSourceUser, RestoreSourcePort, restoreSourceMountPath)},
+				Env: []corev1.EnvVar{{
+					Name: "SOURCE_TOKEN",
+					ValueFrom: &corev1.EnvVarSource{
+						SecretKeyRef: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: tokenSecret},
+							Key:                  utils.SecretKeyRestoreSourceToken,
+						},
+					},
+				}},
+				Ports: []corev1.ContainerPort{{
+					Name:          "http",
+					ContainerPort: RestoreSourcePort,
+				}},
+				ReadinessProbe: &corev1.Probe{
+					ProbeHandler: corev1.ProbeHandler{
+						TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt(RestoreSourcePort)},
+					},
+					PeriodSeconds: 2,
+				},
+				VolumeMounts: []corev1.VolumeMount{{
+					Name:      "source",
+					MountPath: restoreSourceMountPath,
+					ReadOnly:  true,
+				}},
+			}},
+			Volumes: []corev1.Volume{{
+				Name: "source",
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+						ClaimName: source.ClaimName,
+						ReadOnly:  true,
+					},
+				},
+			}},
+		},
+	}
+}
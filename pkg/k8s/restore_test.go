@@ -0,0 +1,94 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8s
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+	"github.com/your-org/etcd-k8s-operator/pkg/utils"
+)
+
+func newRestoreTestCluster() *etcdv1alpha1.EtcdCluster {
+	return &etcdv1alpha1.EtcdCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "restored", Namespace: "prod"},
+		Spec: etcdv1alpha1.EtcdClusterSpec{
+			Size:       3,
+			Version:    "v3.5.21",
+			Repository: "quay.io/coreos/etcd",
+			Storage:    etcdv1alpha1.EtcdStorageSpec{Size: resource.MustParse("2Gi")},
+		},
+	}
+}
+
+// TestBuildMemberPVC 测试预建的 PVC 与 StatefulSet 的 volumeClaimTemplate 命名一致
+func TestBuildMemberPVC(t *testing.T) {
+	cluster := newRestoreTestCluster()
+
+	pvc := BuildMemberPVC(cluster, 2)
+	assert.Equal(t, "data-restored-2", pvc.Name)
+	assert.Equal(t, "prod", pvc.Namespace)
+	assert.Equal(t, resource.MustParse("2Gi"), pvc.Spec.Resources.Requests[corev1.ResourceStorage])
+
+	sts := BuildStatefulSet(cluster, StatefulSetOptions{})
+	assert.Equal(t, pvc.Name, sts.Spec.VolumeClaimTemplates[0].Name+"-"+sts.Name+"-2")
+}
+
+// TestBuildRestoreSeedJob 测试初始化任务按成员序号生成 etcdutl 参数
+func TestBuildRestoreSeedJob(t *testing.T) {
+	cluster := newRestoreTestCluster()
+	restore := &etcdv1alpha1.EtcdRestore{ObjectMeta: metav1.ObjectMeta{Name: "restore-1", Namespace: "prod"}}
+
+	job := BuildRestoreSeedJob(restore, cluster, 1, RestoreSeedJobOptions{
+		SnapshotURL:  "http://operator:8082/snapshots/uid",
+		TokenSecret:  "restore-1-token",
+		ClusterToken: "restored",
+	})
+
+	assert.Equal(t, "restore-1-seed-1", job.Name)
+	assert.Equal(t, "restore-1", job.Labels[utils.LabelEtcdRestore])
+	assert.NotEqual(t, "etcd", job.Labels[utils.LabelAppName], "seed pods must not match the cluster selector")
+
+	pod := job.Spec.Template.Spec
+	assert.Equal(t, corev1.RestartPolicyNever, pod.RestartPolicy)
+	assert.Equal(t, "data-restored-1", pod.Volumes[0].PersistentVolumeClaim.ClaimName)
+	require.Len(t, pod.InitContainers, 2)
+
+	fetch := pod.InitContainers[0]
+	assert.Equal(t, "restore-1-token", fetch.Env[1].ValueFrom.SecretKeyRef.Name)
+
+	restoreCmd := strings.Join(pod.InitContainers[1].Command, " ")
+	assert.Equal(t, "quay.io/coreos/etcd:v3.5.21", pod.InitContainers[1].Image)
+	assert.Contains(t, restoreCmd, "etcdutl snapshot restore /data/.restore/snapshot.db")
+	assert.Contains(t, restoreCmd, "--name restored-1")
+	assert.Contains(t, restoreCmd, "--initial-advertise-peer-urls http://restored-1.restored-peer.prod.svc.cluster.local:2380")
+	assert.Contains(t, restoreCmd, "--initial-cluster restored-0=http://restored-0.restored-peer.prod.svc.cluster.local:2380,"+
+		"restored-1=http://restored-1.restored-peer.prod.svc.cluster.local:2380,"+
+		"restored-2=http://restored-2.restored-peer.prod.svc.cluster.local:2380")
+	assert.Contains(t, restoreCmd, "--initial-cluster-token restored")
+	assert.NotContains(t, restoreCmd, "--skip-hash-check")
+
+	job = BuildRestoreSeedJob(restore, cluster, 0, RestoreSeedJobOptions{ClusterToken: "restored", SkipHashCheck: true})
+	assert.Contains(t, job.Spec.Template.Spec.InitContainers[1].Command, "--skip-hash-check")
+}
@@ -0,0 +1,351 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8s
+
+import (
+	"fmt"
+	"path"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+	"github.com/your-org/etcd-k8s-operator/pkg/utils"
+)
+
+// 证书 Secret 的用途，同时作为挂载目录名
+const (
+	CertificatePeer   = "peer"
+	CertificateServer = "server"
+	CertificateClient = "client"
+)
+
+// tlsSecretMode 证书 Secret 卷中文件的权限，私钥只允许属主和 fsGroup 读取
+const tlsSecretMode int32 = 0o440
+
+// DesiredTLSStatus 返回 spec 要求的传输安全配置
+func DesiredTLSStatus(cluster *etcdv1alpha1.EtcdCluster) *etcdv1alpha1.EtcdTLSStatus {
+	tls := cluster.Spec.Security.TLS
+	return &etcdv1alpha1.EtcdTLSStatus{
+		ClientTLS: tls.Enabled && tls.ClientTLSEnabled,
+		PeerTLS:   tls.Enabled && tls.PeerTLSEnabled,
+	}
+}
+
+// ClientTLSEnabled 返回成员是否以 https 提供客户端服务，以状态中记录的配置为准
+func ClientTLSEnabled(cluster *etcdv1alpha1.EtcdCluster) bool {
+	return cluster.Status.TLS != nil && cluster.Status.TLS.ClientTLS
+}
+
+// PeerTLSEnabled 返回成员之间是否以 https 通信，以状态中记录的配置为准
+func PeerTLSEnabled(cluster *etcdv1alpha1.EtcdCluster) bool {
+	return cluster.Status.TLS != nil && cluster.Status.TLS.PeerTLS
+}
+
+// AutoTLSEnabled 返回 Operator 是否需要为集群生成证书
+func AutoTLSEnabled(cluster *etcdv1alpha1.EtcdCluster) bool {
+	return cluster.Spec.Security.TLS.AutoTLS && TLSRequired(cluster)
+}
+
+// ExternalTLSEnabled 返回集群是否使用用户提供的证书
+func ExternalTLSEnabled(cluster *etcdv1alpha1.EtcdCluster) bool {
+	return !cluster.Spec.Security.TLS.AutoTLS && TLSRequired(cluster)
+}
+
+// TLSRequired 返回集群是否需要证书：spec 要求启用 TLS、成员已经以 TLS 运行或正在迁移
+func TLSRequired(cluster *etcdv1alpha1.EtcdCluster) bool {
+	desired := DesiredTLSStatus(cluster)
+	return desired.ClientTLS || desired.PeerTLS || ClientTLSEnabled(cluster) || PeerTLSEnabled(cluster) ||
+		TLSMigration(cluster) != ""
+}
+
+// TLSMigration 返回集群当前的传输安全迁移阶段
+func TLSMigration(cluster *etcdv1alpha1.EtcdCluster) etcdv1alpha1.EtcdTLSMigrationPhase {
+	if cluster.Status.TLS == nil {
+		return ""
+	}
+	return cluster.Status.TLS.Migration
+}
+
+// clientDualListen 返回成员是否同时以 http 和 https 提供客户端服务，客户端协议迁移期间使用
+func clientDualListen(cluster *etcdv1alpha1.EtcdCluster) bool {
+	migration := TLSMigration(cluster)
+	return migration == etcdv1alpha1.EtcdTLSMigrationClientDual || migration == etcdv1alpha1.EtcdTLSMigrationClientSwitch
+}
+
+// clientTLSConfigured 返回成员是否配置了客户端证书
+func clientTLSConfigured(cluster *etcdv1alpha1.EtcdCluster) bool {
+	return ClientTLSEnabled(cluster) || clientDualListen(cluster)
+}
+
+// peerTLSConfigured 返回成员是否配置了 peer 证书。peer 协议迁移期间成员需要以 https 访问已切换的成员
+func peerTLSConfigured(cluster *etcdv1alpha1.EtcdCluster) bool {
+	migration := TLSMigration(cluster)
+	return PeerTLSEnabled(cluster) ||
+		migration == etcdv1alpha1.EtcdTLSMigrationPeerTrust || migration == etcdv1alpha1.EtcdTLSMigrationPeerSwitch
+}
+
+// ListenClientURLs 返回成员监听的客户端地址，客户端协议迁移期间在同一端口上同时监听 http 和 https
+func ListenClientURLs(cluster *etcdv1alpha1.EtcdCluster) string {
+	if clientDualListen(cluster) {
+		return fmt.Sprintf("http://0.0.0.0:%d,https://0.0.0.0:%d", utils.EtcdClientPort, utils.EtcdClientPort)
+	}
+	return fmt.Sprintf("%s://0.0.0.0:%d", ClientScheme(cluster), utils.EtcdClientPort)
+}
+
+// ClientScheme 返回成员客户端地址的协议
+func ClientScheme(cluster *etcdv1alpha1.EtcdCluster) string {
+	if ClientTLSEnabled(cluster) {
+		return "https"
+	}
+	return "http"
+}
+
+// PeerScheme 返回成员 peer 地址的协议
+func PeerScheme(cluster *etcdv1alpha1.EtcdCluster) string {
+	if PeerTLSEnabled(cluster) {
+		return "https"
+	}
+	return "http"
+}
+
+// PeerDomain 返回 peer 服务的域名，成员主机名为 <成员名称>.<域名>
+func PeerDomain(cluster *etcdv1alpha1.EtcdCluster) string {
+	return fmt.Sprintf("%s-peer.%s.svc.cluster.local", cluster.Name, cluster.Namespace)
+}
+
+// MemberHost 返回指定序号成员在 peer 服务下的主机名
+func MemberHost(cluster *etcdv1alpha1.EtcdCluster, ordinal int32) string {
+	return MemberName(cluster, ordinal) + "." + PeerDomain(cluster)
+}
+
+// MemberClientURL 返回指定序号成员的客户端地址
+func MemberClientURL(cluster *etcdv1alpha1.EtcdCluster, ordinal int32) string {
+	return fmt.Sprintf("%s://%s:%d", ClientScheme(cluster), MemberHost(cluster, ordinal), utils.EtcdClientPort)
+}
+
+// ClientServiceHost 返回客户端服务的集群内主机名，Operator 以它校验服务端证书
+func ClientServiceHost(cluster *etcdv1alpha1.EtcdCluster) string {
+	return fmt.Sprintf("%s-client.%s.svc", cluster.Name, cluster.Namespace)
+}
+
+// CASecretName 返回保存集群 CA 的 Secret 名称
+func CASecretName(cluster *etcdv1alpha1.EtcdCluster) string {
+	return fmt.Sprintf("%s-ca", cluster.Name)
+}
+
+// NextCASecretName 返回 CA 轮换期间保存新 CA 的 Secret 名称
+func NextCASecretName(cluster *etcdv1alpha1.EtcdCluster) string {
+	return fmt.Sprintf("%s-ca-next", cluster.Name)
+}
+
+// CertificateRenewBefore 返回自动生成的证书在到期前多久重新签发
+func CertificateRenewBefore(cluster *etcdv1alpha1.EtcdCluster) time.Duration {
+	if renewBefore := cluster.Spec.Security.TLS.RenewBefore; renewBefore != nil {
+		return renewBefore.Duration
+	}
+	return utils.DefaultCertificateRenewBefore
+}
+
+// TLSSecretName 返回保存指定用途证书的 Secret 名称
+func TLSSecretName(cluster *etcdv1alpha1.EtcdCluster, usage string) string {
+	return fmt.Sprintf("%s-%s-tls", cluster.Name, usage)
+}
+
+// ExternalCASecretName 返回用户提供的 CA 所在的 Secret，未指定 CASecret 时使用证书 Secret 中的 ca.crt
+func ExternalCASecretName(cluster *etcdv1alpha1.EtcdCluster) string {
+	if cluster.Spec.Security.TLS.CASecret != "" {
+		return cluster.Spec.Security.TLS.CASecret
+	}
+	return cluster.Spec.Security.TLS.CertificateSecret
+}
+
+// ClientCertificateSecrets 返回 Operator 访问集群时使用的客户端证书 Secret 以及 CA 所在的 Secret
+func ClientCertificateSecrets(cluster *etcdv1alpha1.EtcdCluster) (string, string) {
+	if cluster.Spec.Security.TLS.AutoTLS {
+		name := TLSSecretName(cluster, CertificateClient)
+		return name, name
+	}
+	return cluster.Spec.Security.TLS.CertificateSecret, ExternalCASecretName(cluster)
+}
+
+// ExternalCertificateDNSNames 返回用户提供的证书必须覆盖的主机名：当前每个成员，
+// 启用客户端 TLS 时还包括 Operator 校验时使用的客户端服务以及探针使用的 localhost
+func ExternalCertificateDNSNames(cluster *etcdv1alpha1.EtcdCluster) []string {
+	names := make([]string, 0, cluster.Spec.Size+2)
+	for i := int32(0); i < cluster.Spec.Size; i++ {
+		names = append(names, MemberHost(cluster, i))
+	}
+	if DesiredTLSStatus(cluster).ClientTLS || ClientTLSEnabled(cluster) {
+		names = append(names, ClientServiceHost(cluster), "localhost")
+	}
+	return names
+}
+
+// PeerCertificateDNSNames 返回 peer 证书需要覆盖的主机名。
+// 覆盖集群可能扩容到的每个成员，扩容时无需重新签发
+func PeerCertificateDNSNames(cluster *etcdv1alpha1.EtcdCluster) []string {
+	names := make([]string, 0, 2*utils.MaxClusterSize)
+	for i := int32(0); i < utils.MaxClusterSize; i++ {
+		host := fmt.Sprintf("%s.%s-peer.%s.svc", MemberName(cluster, i), cluster.Name, cluster.Namespace)
+		names = append(names, host, host+".cluster.local")
+	}
+	return names
+}
+
+// ServerCertificateDNSNames 返回客户端服务证书需要覆盖的主机名：每个成员、客户端服务以及 localhost
+func ServerCertificateDNSNames(cluster *etcdv1alpha1.EtcdCluster) []string {
+	service := fmt.Sprintf("%s-client", cluster.Name)
+	names := PeerCertificateDNSNames(cluster)
+	return append(names,
+		service,
+		fmt.Sprintf("%s.%s", service, cluster.Namespace),
+		ClientServiceHost(cluster),
+		ClientServiceHost(cluster)+".cluster.local",
+		"localhost",
+	)
+}
+
+// podAnnotations 返回 Pod 模板注解。记录成员加载的证书版本，证书更新后修改模板以重启成员
+func podAnnotations(cluster *etcdv1alpha1.EtcdCluster) map[string]string {
+	annotations := utils.AnnotationsForEtcdCluster(cluster)
+	if cluster.Status.TLS != nil && cluster.Status.TLS.Revision != "" {
+		annotations[utils.AnnotationTLSRevision] = cluster.Status.TLS.Revision
+	}
+	return annotations
+}
+
+// tlsMountPath 返回指定用途证书在 etcd 容器中的挂载目录
+func tlsMountPath(usage string) string {
+	return path.Join(utils.EtcdTLSDir, usage)
+}
+
+// tlsUsages 返回成员需要挂载的证书用途
+func tlsUsages(cluster *etcdv1alpha1.EtcdCluster) []string {
+	var usages []string
+	if peerTLSConfigured(cluster) {
+		usages = append(usages, CertificatePeer)
+	}
+	if clientTLSConfigured(cluster) {
+		// 客户端证书用于探针中的 etcdctl
+		usages = append(usages, CertificateServer, CertificateClient)
+	}
+	return usages
+}
+
+// buildTLSVolumes 返回挂载证书 Secret 的卷。
+// 使用用户提供的证书时，每种用途都投影同一张证书以及 CA，目录结构与自动生成的证书一致
+func buildTLSVolumes(cluster *etcdv1alpha1.EtcdCluster) []corev1.Volume {
+	var volumes []corev1.Volume
+	for _, usage := range tlsUsages(cluster) {
+		mode := tlsSecretMode
+		source := corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName:  TLSSecretName(cluster, usage),
+				DefaultMode: &mode,
+			},
+		}
+		if !cluster.Spec.Security.TLS.AutoTLS {
+			source = corev1.VolumeSource{
+				Projected: &corev1.ProjectedVolumeSource{
+					Sources:     externalCertificateProjections(cluster),
+					DefaultMode: &mode,
+				},
+			}
+		}
+		volumes = append(volumes, corev1.Volume{
+			Name:         fmt.Sprintf("%s-tls", usage),
+			VolumeSource: source,
+		})
+	}
+	return volumes
+}
+
+// externalCertificateProjections 返回投影用户证书和 CA 的卷来源
+func externalCertificateProjections(cluster *etcdv1alpha1.EtcdCluster) []corev1.VolumeProjection {
+	return []corev1.VolumeProjection{
+		{
+			Secret: &corev1.SecretProjection{
+				LocalObjectReference: corev1.LocalObjectReference{Name: cluster.Spec.Security.TLS.CertificateSecret},
+				Items: []corev1.KeyToPath{
+					{Key: corev1.TLSCertKey, Path: corev1.TLSCertKey},
+					{Key: corev1.TLSPrivateKeyKey, Path: corev1.TLSPrivateKeyKey},
+				},
+			},
+		},
+		{
+			Secret: &corev1.SecretProjection{
+				LocalObjectReference: corev1.LocalObjectReference{Name: ExternalCASecretName(cluster)},
+				Items: []corev1.KeyToPath{
+					{Key: utils.SecretKeyCACert, Path: utils.SecretKeyCACert},
+				},
+			},
+		},
+	}
+}
+
+// buildTLSVolumeMounts 返回证书卷在 etcd 容器中的挂载点
+func buildTLSVolumeMounts(cluster *etcdv1alpha1.EtcdCluster) []corev1.VolumeMount {
+	var mounts []corev1.VolumeMount
+	for _, usage := range tlsUsages(cluster) {
+		mounts = append(mounts, corev1.VolumeMount{
+			Name:      fmt.Sprintf("%s-tls", usage),
+			MountPath: tlsMountPath(usage),
+			ReadOnly:  true,
+		})
+	}
+	return mounts
+}
+
+// buildTLSConfig 返回 etcd 配置文件中的证书配置段，未启用 TLS 时为空
+func buildTLSConfig(cluster *etcdv1alpha1.EtcdCluster) string {
+	var config string
+	if clientTLSConfigured(cluster) {
+		config += transportSecurityConfig("client-transport-security", tlsMountPath(CertificateServer))
+	}
+	if peerTLSConfigured(cluster) {
+		config += transportSecurityConfig("peer-transport-security", tlsMountPath(CertificatePeer))
+	}
+	return config
+}
+
+// transportSecurityConfig 返回一段双向认证的 transport-security 配置
+func transportSecurityConfig(section, dir string) string {
+	return fmt.Sprintf(`%s:
+  cert-file: %s
+  key-file: %s
+  trusted-ca-file: %s
+  client-cert-auth: true
+`, section, path.Join(dir, corev1.TLSCertKey), path.Join(dir, corev1.TLSPrivateKeyKey), path.Join(dir, utils.SecretKeyCACert))
+}
+
+// etcdctlCommand 返回访问本机成员的 etcdctl 命令前缀
+func etcdctlCommand(cluster *etcdv1alpha1.EtcdCluster) []string {
+	command := []string{
+		"etcdctl",
+		fmt.Sprintf("--endpoints=%s://localhost:%d", ClientScheme(cluster), utils.EtcdClientPort),
+	}
+	if ClientTLSEnabled(cluster) {
+		dir := tlsMountPath(CertificateClient)
+		command = append(command,
+			"--cacert="+path.Join(dir, utils.SecretKeyCACert),
+			"--cert="+path.Join(dir, corev1.TLSCertKey),
+			"--key="+path.Join(dir, corev1.TLSPrivateKeyKey),
+		)
+	}
+	return command
+}
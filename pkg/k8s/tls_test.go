@@ -0,0 +1,157 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8s
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+)
+
+// TestTLSStatefulSet 测试启用 TLS 的集群挂载证书并以 https 通信
+func TestTLSStatefulSet(t *testing.T) {
+	cluster := newRestoreTestCluster()
+	cluster.Spec.Security.TLS.AutoTLS = true
+	cluster.Status.TLS = &etcdv1alpha1.EtcdTLSStatus{ClientTLS: true, PeerTLS: true}
+
+	assert.Equal(t, "https://restored-1.restored-peer.prod.svc.cluster.local:2380", MemberPeerURL(cluster, 1))
+	assert.Equal(t, "https://restored-2.restored-peer.prod.svc.cluster.local:2379", MemberClientURL(cluster, 2))
+
+	pod := BuildStatefulSet(cluster, StatefulSetOptions{}).Spec.Template.Spec
+	secrets := map[string]string{}
+	for _, v := range pod.Volumes {
+		if v.Secret != nil {
+			secrets[v.Name] = v.Secret.SecretName
+		}
+	}
+	assert.Equal(t, map[string]string{
+		"peer-tls":   "restored-peer-tls",
+		"server-tls": "restored-server-tls",
+		"client-tls": "restored-client-tls",
+	}, secrets)
+
+	etcd := pod.Containers[0]
+	mounts := map[string]string{}
+	for _, m := range etcd.VolumeMounts {
+		mounts[m.Name] = m.MountPath
+	}
+	assert.Equal(t, "/etc/etcd-tls/peer", mounts["peer-tls"])
+	assert.Equal(t, "/etc/etcd-tls/server", mounts["server-tls"])
+
+	probe := etcd.LivenessProbe.Exec.Command
+	assert.Contains(t, probe, "--endpoints=https://localhost:2379")
+	assert.Contains(t, probe, "--cert=/etc/etcd-tls/client/tls.crt")
+
+	args := pod.InitContainers[0].Args
+	assert.Contains(t, args, "--client-scheme=https")
+	assert.Contains(t, args, "--peer-scheme=https")
+	assert.Contains(t, args, "--ca-file=/etc/etcd-tls/client/ca.crt")
+	assert.Contains(t, args, "--server-name=restored-client.prod.svc")
+	assert.Contains(t, args, "--endpoints=https://restored-client.prod.svc:2379,https://restored-0.restored-peer.prod.svc.cluster.local:2379")
+	config := extraConfig(pod)
+	assert.Equal(t, 1, strings.Count(config, "peer-transport-security:"))
+	assert.Equal(t, 1, strings.Count(config, "trusted-ca-file: /etc/etcd-tls/server/ca.crt"))
+}
+
+// TestPlaintextStatefulSet 测试未记录 TLS 状态的集群保持 http，已有集群升级 Operator 后不受影响
+func TestPlaintextStatefulSet(t *testing.T) {
+	cluster := newRestoreTestCluster()
+	cluster.Spec.Security.TLS = etcdv1alpha1.EtcdTLSSpec{Enabled: true, ClientTLSEnabled: true, PeerTLSEnabled: true, AutoTLS: true}
+
+	assert.Equal(t, "http://restored-0.restored-peer.prod.svc.cluster.local:2380", MemberPeerURL(cluster, 0))
+	assert.True(t, AutoTLSEnabled(cluster))
+	assert.Equal(t, &etcdv1alpha1.EtcdTLSStatus{ClientTLS: true, PeerTLS: true}, DesiredTLSStatus(cluster))
+
+	pod := BuildStatefulSet(cluster, StatefulSetOptions{}).Spec.Template.Spec
+	for _, v := range pod.Volumes {
+		assert.Nil(t, v.Secret)
+	}
+	assert.Contains(t, pod.InitContainers[0].Args, "--client-scheme=http")
+	assert.NotContains(t, extraConfig(pod), "transport-security")
+	assert.Contains(t, pod.Containers[0].LivenessProbe.Exec.Command, "--endpoints=http://localhost:2379")
+}
+
+// TestTLSMigrationStatefulSet 测试迁移期间成员挂载证书但保持原协议，客户端协议迁移期间同时监听 http 和 https
+func TestTLSMigrationStatefulSet(t *testing.T) {
+	cluster := newRestoreTestCluster()
+	cluster.Spec.Security.TLS = etcdv1alpha1.EtcdTLSSpec{Enabled: true, ClientTLSEnabled: true, PeerTLSEnabled: true, AutoTLS: true}
+	cluster.Status.TLS = &etcdv1alpha1.EtcdTLSStatus{Migration: etcdv1alpha1.EtcdTLSMigrationPeerTrust}
+
+	assert.Equal(t, "http://restored-0.restored-peer.prod.svc.cluster.local:2380", MemberPeerURL(cluster, 0))
+	assert.Equal(t, []string{CertificatePeer}, tlsUsages(cluster))
+	pod := BuildStatefulSet(cluster, StatefulSetOptions{}).Spec.Template.Spec
+	assert.Contains(t, pod.InitContainers[0].Args, "--peer-scheme=http")
+	assert.Equal(t, 1, strings.Count(extraConfig(pod), "peer-transport-security:"))
+	assert.NotContains(t, extraConfig(pod), "client-transport-security:")
+
+	cluster.Status.TLS = &etcdv1alpha1.EtcdTLSStatus{PeerTLS: true, Migration: etcdv1alpha1.EtcdTLSMigrationClientDual}
+	assert.Equal(t, "http://0.0.0.0:2379,https://0.0.0.0:2379", ListenClientURLs(cluster))
+	pod = BuildStatefulSet(cluster, StatefulSetOptions{}).Spec.Template.Spec
+	assert.Contains(t, pod.InitContainers[0].Args, "--client-scheme=http")
+	assert.Contains(t, pod.InitContainers[0].Args, "--listen-client-urls=http://0.0.0.0:2379,https://0.0.0.0:2379")
+	assert.NotContains(t, pod.InitContainers[0].Args, "--ca-file=/etc/etcd-tls/client/ca.crt")
+	assert.Equal(t, 1, strings.Count(extraConfig(pod), "client-transport-security:"))
+	assert.Contains(t, pod.Containers[0].LivenessProbe.Exec.Command, "--endpoints=http://localhost:2379")
+
+	cluster.Status.TLS = &etcdv1alpha1.EtcdTLSStatus{ClientTLS: true, PeerTLS: true}
+	assert.Equal(t, "https://0.0.0.0:2379", ListenClientURLs(cluster))
+}
+
+// TestCertificateDNSNames 测试证书 SAN 覆盖所有可能的成员和客户端服务
+func TestCertificateDNSNames(t *testing.T) {
+	cluster := newRestoreTestCluster()
+
+	peer := PeerCertificateDNSNames(cluster)
+	assert.Contains(t, peer, "restored-0.restored-peer.prod.svc")
+	assert.Contains(t, peer, "restored-8.restored-peer.prod.svc.cluster.local")
+
+	server := ServerCertificateDNSNames(cluster)
+	assert.Contains(t, server, ClientServiceHost(cluster))
+	assert.Contains(t, server, "restored-client")
+	assert.Contains(t, server, "localhost")
+}
+
+// TestExternalCertificateVolumes 测试用户提供的证书与 CA 投影到与自动生成证书相同的目录
+func TestExternalCertificateVolumes(t *testing.T) {
+	cluster := newRestoreTestCluster()
+	cluster.Spec.Security.TLS = etcdv1alpha1.EtcdTLSSpec{Enabled: true, PeerTLSEnabled: true, CertificateSecret: "corp-etcd", CASecret: "corp-ca"}
+	cluster.Status.TLS = DesiredTLSStatus(cluster)
+
+	assert.True(t, ExternalTLSEnabled(cluster))
+	assert.Equal(t, []string{
+		"restored-0.restored-peer.prod.svc.cluster.local",
+		"restored-1.restored-peer.prod.svc.cluster.local",
+		"restored-2.restored-peer.prod.svc.cluster.local",
+	}, ExternalCertificateDNSNames(cluster))
+
+	volumes := buildTLSVolumes(cluster)
+	if assert.Len(t, volumes, 1) {
+		sources := volumes[0].Projected.Sources
+		assert.Equal(t, "corp-etcd", sources[0].Secret.Name)
+		assert.Equal(t, "corp-ca", sources[1].Secret.Name)
+		assert.Equal(t, "ca.crt", sources[1].Secret.Items[0].Path)
+	}
+
+	// 未指定 CASecret 时使用证书 Secret 中的 ca.crt
+	cluster.Spec.Security.TLS.CASecret = ""
+	cert, ca := ClientCertificateSecrets(cluster)
+	assert.Equal(t, "corp-etcd", cert)
+	assert.Equal(t, "corp-etcd", ca)
+}
@@ -0,0 +1,40 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8s
+
+import (
+	"fmt"
+
+	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+)
+
+// MemberVersion 返回成员应运行的 etcd 版本，以状态中记录的版本为准。
+// spec 中的版本变化经过升级流程写入状态后才会修改 Pod 模板
+func MemberVersion(cluster *etcdv1alpha1.EtcdCluster) string {
+	if cluster.Status.Upgrade != nil {
+		return cluster.Status.Upgrade.ToVersion
+	}
+	if cluster.Status.CurrentVersion != "" {
+		return cluster.Status.CurrentVersion
+	}
+	return cluster.Spec.Version
+}
+
+// MemberImage 返回成员使用的 etcd 镜像
+func MemberImage(cluster *etcdv1alpha1.EtcdCluster) string {
+	return fmt.Sprintf("%s:%s", cluster.Spec.Repository, MemberVersion(cluster))
+}
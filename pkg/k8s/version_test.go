@@ -0,0 +1,41 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+	"github.com/your-org/etcd-k8s-operator/pkg/utils"
+)
+
+// TestMemberVersion 测试 spec 中的版本变化在升级开始前不会修改成员镜像
+func TestMemberVersion(t *testing.T) {
+	cluster := newRestoreTestCluster()
+	cluster.Spec.Version = "v3.6.0"
+	assert.Equal(t, "quay.io/coreos/etcd:v3.6.0", MemberImage(cluster))
+
+	cluster.Status.CurrentVersion = "v3.5.21"
+	sts := BuildStatefulSet(cluster, StatefulSetOptions{})
+	assert.Equal(t, "quay.io/coreos/etcd:v3.5.21", sts.Spec.Template.Spec.Containers[0].Image)
+	assert.Equal(t, "v3.5.21", sts.Spec.Template.Labels[utils.LabelAppVersion])
+
+	cluster.Status.Upgrade = &etcdv1alpha1.EtcdUpgradeStatus{FromVersion: "v3.5.21", ToVersion: "v3.6.0"}
+	assert.Equal(t, "v3.6.0", MemberVersion(cluster))
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metrics 定义 Operator 暴露的 Prometheus 指标，注册到 controller-runtime 的指标服务
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+)
+
+// CertificateExpiry 集群各证书的到期时间 (Unix 秒)
+var CertificateExpiry = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "etcd_operator_certificate_expiry_timestamp_seconds",
+		Help: "Time the certificates used by etcd cluster members expire, in seconds since the epoch",
+	},
+	[]string{"namespace", "cluster", "certificate"},
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(CertificateExpiry)
+}
+
+// SetCertificateExpiry 用集群当前的证书替换该集群的到期时间指标
+func SetCertificateExpiry(cluster *etcdv1alpha1.EtcdCluster, certificates []etcdv1alpha1.EtcdCertificateStatus) {
+	DeleteClusterMetrics(cluster)
+	for _, cert := range certificates {
+		CertificateExpiry.WithLabelValues(cluster.Namespace, cluster.Name, cert.Name).Set(float64(cert.NotAfter.Unix()))
+	}
+}
+
+// DeleteClusterMetrics 删除集群的全部指标
+func DeleteClusterMetrics(cluster *etcdv1alpha1.EtcdCluster) {
+	CertificateExpiry.DeletePartialMatch(prometheus.Labels{"namespace": cluster.Namespace, "cluster": cluster.Name})
+}
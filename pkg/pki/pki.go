@@ -0,0 +1,263 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pki
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"strings"
+	"time"
+)
+
+// clockSkew 证书生效时间向前偏移，容忍节点间的时钟误差
+const clockSkew = 5 * time.Minute
+
+// KeyPair 证书及其私钥，同时保存 PEM 编码
+type KeyPair struct {
+	Cert    *x509.Certificate
+	Key     crypto.Signer
+	CertPEM []byte
+	KeyPEM  []byte
+}
+
+// VerifyRequest 校验证书的要求
+type VerifyRequest struct {
+	// Roots 受信任的 CA
+	Roots *x509.CertPool
+	// Intermediates 证书链中的中间证书
+	Intermediates *x509.CertPool
+	// DNSNames 证书必须覆盖的主机名
+	DNSNames []string
+	// Usages 证书必须具备的全部扩展用途
+	Usages []x509.ExtKeyUsage
+	// Now 校验时间，为零值时使用当前时间
+	Now time.Time
+}
+
+// CertificateRequest 签发叶子证书的参数
+type CertificateRequest struct {
+	// CommonName 证书的 CN
+	CommonName string
+	// DNSNames 证书的 DNS SAN
+	DNSNames []string
+	// IPAddresses 证书的 IP SAN
+	IPAddresses []net.IP
+	// Usages 证书的扩展用途，例如 x509.ExtKeyUsageServerAuth
+	Usages []x509.ExtKeyUsage
+	// Validity 证书有效期
+	Validity time.Duration
+}
+
+// NewCA 生成自签名的 CA 证书
+func NewCA(commonName string, validity time.Duration) (*KeyPair, error) {
+	key, err := newPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             now.Add(-clockSkew),
+		NotAfter:              now.Add(validity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	return sign(template, template, key, key)
+}
+
+// Issue 使用 CA 签发叶子证书
+func (ca *KeyPair) Issue(req CertificateRequest) (*KeyPair, error) {
+	if !ca.Cert.IsCA {
+		return nil, fmt.Errorf("certificate %s is not a CA", ca.Cert.Subject.CommonName)
+	}
+	key, err := newPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	notAfter := now.Add(req.Validity)
+	// 叶子证书不能比签发它的 CA 更晚过期
+	if notAfter.After(ca.Cert.NotAfter) {
+		notAfter = ca.Cert.NotAfter
+	}
+	template := &x509.Certificate{
+		Subject:     pkix.Name{CommonName: req.CommonName},
+		DNSNames:    req.DNSNames,
+		IPAddresses: req.IPAddresses,
+		NotBefore:   now.Add(-clockSkew),
+		NotAfter:    notAfter,
+		KeyUsage:    x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage: req.Usages,
+	}
+	return sign(template, ca.Cert, key, ca.Key)
+}
+
+// ParseKeyPair 解析 PEM 编码的证书和私钥，并校验二者匹配
+func ParseKeyPair(certPEM, keyPEM []byte) (*KeyPair, error) {
+	cert, err := ParseCertificate(certPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, errors.New("failed to decode private key PEM")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		if parsed, err = x509.ParseECPrivateKey(block.Bytes); err != nil {
+			if parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes); err != nil {
+				return nil, fmt.Errorf("failed to parse private key: %w", err)
+			}
+		}
+	}
+	key, ok := parsed.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("private key cannot sign")
+	}
+
+	certKey, ok := cert.PublicKey.(interface{ Equal(crypto.PublicKey) bool })
+	if !ok || !certKey.Equal(key.Public()) {
+		return nil, errors.New("private key does not match certificate")
+	}
+
+	return &KeyPair{Cert: cert, Key: key, CertPEM: certPEM, KeyPEM: keyPEM}, nil
+}
+
+// ParseCertificate 解析 PEM 编码中的第一张证书
+func ParseCertificate(certPEM []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(certPEM)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.New("failed to decode certificate PEM")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse certificate: %w", err)
+	}
+	return cert, nil
+}
+
+// ParseCertificates 解析 PEM 编码中的全部证书
+func ParseCertificates(certPEM []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for block, rest := pem.Decode(certPEM); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse certificate: %w", err)
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("no certificate found in PEM")
+	}
+	return certs, nil
+}
+
+// Verify 校验证书处于有效期内、由受信任的 CA 签发，并覆盖要求的全部主机名和用途
+func Verify(cert *x509.Certificate, req VerifyRequest) error {
+	now := req.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	if now.After(cert.NotAfter) {
+		return fmt.Errorf("certificate expired at %s", cert.NotAfter.UTC().Format(time.RFC3339))
+	}
+	if now.Before(cert.NotBefore) {
+		return fmt.Errorf("certificate is not valid before %s", cert.NotBefore.UTC().Format(time.RFC3339))
+	}
+
+	// x509 只要求满足任意一个用途，逐个校验以确保全部具备
+	usages := req.Usages
+	if len(usages) == 0 {
+		usages = []x509.ExtKeyUsage{x509.ExtKeyUsageAny}
+	}
+	for _, usage := range usages {
+		if _, err := cert.Verify(x509.VerifyOptions{
+			Roots:         req.Roots,
+			Intermediates: req.Intermediates,
+			CurrentTime:   now,
+			KeyUsages:     []x509.ExtKeyUsage{usage},
+		}); err != nil {
+			return fmt.Errorf("certificate is not trusted for the required usage: %w", err)
+		}
+	}
+
+	var missing []string
+	for _, name := range req.DNSNames {
+		if cert.VerifyHostname(name) != nil {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("certificate does not cover required names: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// newPrivateKey 生成 ECDSA P-256 私钥
+func newPrivateKey() (*ecdsa.PrivateKey, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate private key: %w", err)
+	}
+	return key, nil
+}
+
+// sign 使用 parentKey 签发 template 描述的证书，并编码为 PEM
+func sign(template, parent *x509.Certificate, key, parentKey crypto.Signer) (*KeyPair, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate serial number: %w", err)
+	}
+	template.SerialNumber = serial
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, key.Public(), parentKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create certificate: %w", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode private key: %w", err)
+	}
+
+	return &KeyPair{
+		Cert:    cert,
+		Key:     key,
+		CertPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		KeyPEM:  pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}),
+	}, nil
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pki
+
+import (
+	"crypto/x509"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestIssueCertificate 测试 CA 签发的证书可以通过链校验并覆盖请求的 SAN
+func TestIssueCertificate(t *testing.T) {
+	ca, err := NewCA("test-ca", 24*time.Hour)
+	require.NoError(t, err)
+	assert.True(t, ca.Cert.IsCA)
+
+	leaf, err := ca.Issue(CertificateRequest{
+		CommonName:  "etcd-server",
+		DNSNames:    []string{"etcd-0.etcd-peer.default.svc"},
+		IPAddresses: []net.IP{net.ParseIP("127.0.0.1")},
+		Usages:      []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		Validity:    48 * time.Hour,
+	})
+	require.NoError(t, err)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.Cert)
+	_, err = leaf.Cert.Verify(x509.VerifyOptions{
+		DNSName:   "etcd-0.etcd-peer.default.svc",
+		Roots:     roots,
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+	require.NoError(t, err)
+	assert.NoError(t, leaf.Cert.VerifyHostname("127.0.0.1"))
+
+	// 叶子证书的有效期被截断到 CA 的有效期之内
+	assert.False(t, leaf.Cert.NotAfter.After(ca.Cert.NotAfter))
+
+	_, err = leaf.Issue(CertificateRequest{CommonName: "nested", Validity: time.Hour})
+	assert.Error(t, err)
+}
+
+// TestParseKeyPair 测试 PEM 往返解析以及证书与私钥不匹配时报错
+func TestParseKeyPair(t *testing.T) {
+	ca, err := NewCA("test-ca", time.Hour)
+	require.NoError(t, err)
+
+	parsed, err := ParseKeyPair(ca.CertPEM, ca.KeyPEM)
+	require.NoError(t, err)
+	assert.Equal(t, ca.Cert.SerialNumber, parsed.Cert.SerialNumber)
+
+	other, err := NewCA("other-ca", time.Hour)
+	require.NoError(t, err)
+	_, err = ParseKeyPair(ca.CertPEM, other.KeyPEM)
+	assert.Error(t, err)
+
+	_, err = ParseKeyPair([]byte("garbage"), ca.KeyPEM)
+	assert.Error(t, err)
+}
+
+// TestVerify 测试证书校验覆盖有效期、签发者、用途和主机名
+func TestVerify(t *testing.T) {
+	ca, err := NewCA("test-ca", 24*time.Hour)
+	require.NoError(t, err)
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.Cert)
+
+	leaf, err := ca.Issue(CertificateRequest{
+		CommonName: "etcd",
+		DNSNames:   []string{"*.etcd-peer.default.svc.cluster.local", "localhost"},
+		Usages:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		Validity:   time.Hour,
+	})
+	require.NoError(t, err)
+
+	req := VerifyRequest{
+		Roots:    roots,
+		DNSNames: []string{"etcd-0.etcd-peer.default.svc.cluster.local", "localhost"},
+		Usages:   []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	assert.NoError(t, Verify(leaf.Cert, req))
+
+	missing := req
+	missing.DNSNames = []string{"etcd-client.default.svc", "localhost"}
+	assert.ErrorContains(t, Verify(leaf.Cert, missing), "etcd-client.default.svc")
+
+	usages := req
+	usages.Usages = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}
+	assert.Error(t, Verify(leaf.Cert, usages))
+
+	expired := req
+	expired.Now = time.Now().Add(2 * time.Hour)
+	assert.ErrorContains(t, Verify(leaf.Cert, expired), "expired")
+
+	other, err := NewCA("other-ca", time.Hour)
+	require.NoError(t, err)
+	untrusted := req
+	untrusted.Roots = x509.NewCertPool()
+	untrusted.Roots.AddCert(other.Cert)
+	assert.Error(t, Verify(leaf.Cert, untrusted))
+}
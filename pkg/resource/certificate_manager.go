@@ -0,0 +1,448 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"net"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+	"github.com/your-org/etcd-k8s-operator/pkg/client"
+	"github.com/your-org/etcd-k8s-operator/pkg/k8s"
+	"github.com/your-org/etcd-k8s-operator/pkg/pki"
+	"github.com/your-org/etcd-k8s-operator/pkg/utils"
+)
+
+// 证书状态中 CA 与用户提供证书的名称，其余证书以用途命名
+const (
+	CertificateCA       = "ca"
+	CertificateExternal = "external"
+)
+
+// generatedUsages AutoTLS 时生成的叶子证书用途
+var generatedUsages = []string{k8s.CertificatePeer, k8s.CertificateServer, k8s.CertificateClient}
+
+// certificateManager 证书 Secret 管理器实现
+type certificateManager struct {
+	k8sClient client.KubernetesClient
+}
+
+// NewCertificateManager 创建证书管理器
+func NewCertificateManager(k8sClient client.KubernetesClient) CertificateManager {
+	return &certificateManager{
+		k8sClient: k8sClient,
+	}
+}
+
+// Ensure 确保成员证书可用，未启用 AutoTLS 时只校验用户提供的证书
+func (cm *certificateManager) Ensure(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster) error {
+	if !cluster.Spec.Security.TLS.AutoTLS {
+		return cm.validateExternal(ctx, cluster)
+	}
+	return cm.ensureGenerated(ctx, cluster)
+}
+
+// ensureGenerated 确保集群 CA 以及 peer、server、client 证书存在。
+// 已有的 CA 只会通过轮换替换：先让成员同时信任新旧 CA，再由新 CA 签发证书，最后移除旧 CA。
+// 叶子证书缺失、损坏、不是由当前签发 CA 签发或进入续期窗口时重新签发
+func (cm *certificateManager) ensureGenerated(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster) error {
+	ca, err := cm.ensureCA(ctx, cluster, k8s.CASecretName(cluster))
+	if err != nil {
+		return err
+	}
+
+	signer, trusted := ca, [][]byte{ca.CertPEM}
+	switch caRotationPhase(cluster) {
+	case etcdv1alpha1.EtcdCARotationTrustBoth, etcdv1alpha1.EtcdCARotationIssueNew:
+		next, err := cm.ensureCA(ctx, cluster, k8s.NextCASecretName(cluster))
+		if err != nil {
+			return err
+		}
+		trusted = append(trusted, next.CertPEM)
+		if caRotationPhase(cluster) == etcdv1alpha1.EtcdCARotationIssueNew {
+			signer = next
+		}
+	case etcdv1alpha1.EtcdCARotationDropOld:
+		if signer, err = cm.promoteNextCA(ctx, cluster, ca); err != nil {
+			return err
+		}
+		trusted = [][]byte{signer.CertPEM}
+	}
+	bundle := bytes.Join(trusted, nil)
+
+	requests := map[string]pki.CertificateRequest{
+		k8s.CertificatePeer: {
+			CommonName: fmt.Sprintf("%s-peer", cluster.Name),
+			DNSNames:   k8s.PeerCertificateDNSNames(cluster),
+			Usages:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+			Validity:   utils.DefaultCertificateValidity,
+		},
+		k8s.CertificateServer: {
+			CommonName:  fmt.Sprintf("%s-server", cluster.Name),
+			DNSNames:    k8s.ServerCertificateDNSNames(cluster),
+			IPAddresses: []net.IP{net.IPv4(127, 0, 0, 1)},
+			Usages:      []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+			Validity:    utils.DefaultCertificateValidity,
+		},
+		k8s.CertificateClient: {
+			CommonName: fmt.Sprintf("%s-client", cluster.Name),
+			Usages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+			Validity:   utils.DefaultCertificateValidity,
+		},
+	}
+	for _, usage := range generatedUsages {
+		if err := cm.ensureLeaf(ctx, cluster, signer, bundle, usage, requests[usage]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Delete 删除集群的全部证书 Secret
+func (cm *certificateManager) Delete(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster) error {
+	names := []string{
+		k8s.CASecretName(cluster),
+		k8s.NextCASecretName(cluster),
+		k8s.TLSSecretName(cluster, k8s.CertificatePeer),
+		k8s.TLSSecretName(cluster, k8s.CertificateServer),
+		k8s.TLSSecretName(cluster, k8s.CertificateClient),
+	}
+	for _, name := range names {
+		secret := &corev1.Secret{}
+		err := cm.k8sClient.Get(ctx, types.NamespacedName{Name: name, Namespace: cluster.Namespace}, secret)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		if err := cm.k8sClient.Delete(ctx, secret); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateExternal 校验用户提供的证书：与私钥匹配、处于有效期内、由 CA 签发、
+// 同时可用于服务端和客户端认证，并覆盖集群要求的主机名
+func (cm *certificateManager) validateExternal(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster) error {
+	certName := cluster.Spec.Security.TLS.CertificateSecret
+	if certName == "" {
+		return fmt.Errorf("%w: certificateSecret is required when autoTLS is disabled", ErrInvalidCertificate)
+	}
+	certSecret, err := cm.getExternalSecret(ctx, cluster, certName)
+	if err != nil {
+		return err
+	}
+	caName := k8s.ExternalCASecretName(cluster)
+	caSecret, err := cm.getExternalSecret(ctx, cluster, caName)
+	if err != nil {
+		return err
+	}
+
+	keyPair, err := pki.ParseKeyPair(certSecret.Data[corev1.TLSCertKey], certSecret.Data[corev1.TLSPrivateKeyKey])
+	if err != nil {
+		return fmt.Errorf("%w: secret %s: %v", ErrInvalidCertificate, certName, err)
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(caSecret.Data[utils.SecretKeyCACert]) {
+		return fmt.Errorf("%w: secret %s has no valid %s", ErrInvalidCertificate, caName, utils.SecretKeyCACert)
+	}
+	// tls.crt 可以附带中间证书
+	intermediates := x509.NewCertPool()
+	if chain, err := pki.ParseCertificates(certSecret.Data[corev1.TLSCertKey]); err == nil {
+		for _, cert := range chain[1:] {
+			intermediates.AddCert(cert)
+		}
+	}
+
+	if err := pki.Verify(keyPair.Cert, pki.VerifyRequest{
+		Roots:         roots,
+		Intermediates: intermediates,
+		DNSNames:      k8s.ExternalCertificateDNSNames(cluster),
+		Usages:        []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}); err != nil {
+		return fmt.Errorf("%w: secret %s: %v", ErrInvalidCertificate, certName, err)
+	}
+	return nil
+}
+
+// getExternalSecret 读取用户提供的 Secret，不存在时视为证书不可用
+func (cm *certificateManager) getExternalSecret(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster, name string) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	err := cm.k8sClient.Get(ctx, types.NamespacedName{Name: name, Namespace: cluster.Namespace}, secret)
+	if errors.IsNotFound(err) {
+		return nil, fmt.Errorf("%w: secret %s not found", ErrInvalidCertificate, name)
+	}
+	return secret, err
+}
+
+// ensureCA 读取指定 Secret 中的 CA，不存在时生成新的 CA
+func (cm *certificateManager) ensureCA(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster, name string) (*pki.KeyPair, error) {
+	existing := &corev1.Secret{}
+	err := cm.k8sClient.Get(ctx, types.NamespacedName{Name: name, Namespace: cluster.Namespace}, existing)
+	if err == nil {
+		// 替换 CA 会让所有成员的证书失效，损坏时交给用户处理
+		ca, err := pki.ParseKeyPair(existing.Data[corev1.TLSCertKey], existing.Data[corev1.TLSPrivateKeyKey])
+		if err != nil {
+			return nil, fmt.Errorf("invalid CA in secret %s: %w", name, err)
+		}
+		return ca, nil
+	}
+	if !errors.IsNotFound(err) {
+		return nil, err
+	}
+
+	ca, err := pki.NewCA(fmt.Sprintf("%s-ca", cluster.Name), utils.DefaultCAValidity)
+	if err != nil {
+		return nil, err
+	}
+	secret := cm.buildSecret(cluster, name, map[string][]byte{
+		corev1.TLSCertKey:       ca.CertPEM,
+		corev1.TLSPrivateKeyKey: ca.KeyPEM,
+	})
+	if err := cm.create(ctx, cluster, secret); err != nil {
+		return nil, err
+	}
+
+	log.FromContext(ctx).Info("Generated cluster CA", "secret", name)
+	return ca, nil
+}
+
+// promoteNextCA 用轮换中生成的新 CA 替换当前 CA 并删除新 CA 的 Secret，返回替换后的 CA。
+// 新 CA 的 Secret 已不存在时说明替换已经完成
+func (cm *certificateManager) promoteNextCA(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster, current *pki.KeyPair) (*pki.KeyPair, error) {
+	nextName := k8s.NextCASecretName(cluster)
+	next := &corev1.Secret{}
+	err := cm.k8sClient.Get(ctx, types.NamespacedName{Name: nextName, Namespace: cluster.Namespace}, next)
+	if errors.IsNotFound(err) {
+		return current, nil
+	} else if err != nil {
+		return nil, err
+	}
+	ca, err := pki.ParseKeyPair(next.Data[corev1.TLSCertKey], next.Data[corev1.TLSPrivateKeyKey])
+	if err != nil {
+		return nil, fmt.Errorf("invalid CA in secret %s: %w", nextName, err)
+	}
+
+	name := k8s.CASecretName(cluster)
+	secret := &corev1.Secret{}
+	if err := cm.k8sClient.Get(ctx, types.NamespacedName{Name: name, Namespace: cluster.Namespace}, secret); err != nil {
+		return nil, err
+	}
+	secret.Data = map[string][]byte{
+		corev1.TLSCertKey:       ca.CertPEM,
+		corev1.TLSPrivateKeyKey: ca.KeyPEM,
+	}
+	if err := cm.k8sClient.Update(ctx, secret); err != nil {
+		return nil, err
+	}
+	if err := cm.k8sClient.Delete(ctx, next); err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+
+	log.FromContext(ctx).Info("Replaced cluster CA", "secret", name)
+	return ca, nil
+}
+
+// ensureLeaf 确保指定用途的证书由 signer 签发且未进入续期窗口，并且 ca.crt 为成员应信任的 CA 集合
+func (cm *certificateManager) ensureLeaf(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster, signer *pki.KeyPair, bundle []byte, usage string, req pki.CertificateRequest) error {
+	name := k8s.TLSSecretName(cluster, usage)
+	existing := &corev1.Secret{}
+	err := cm.k8sClient.Get(ctx, types.NamespacedName{Name: name, Namespace: cluster.Namespace}, existing)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	found := err == nil
+	if found && cm.issuedBy(existing, signer, k8s.CertificateRenewBefore(cluster)) {
+		if bytes.Equal(existing.Data[utils.SecretKeyCACert], bundle) {
+			return nil
+		}
+		existing.Data[utils.SecretKeyCACert] = bundle
+		if err := cm.k8sClient.Update(ctx, existing); err != nil {
+			return err
+		}
+		log.FromContext(ctx).Info("Updated trusted CA bundle", "secret", name)
+		return nil
+	}
+
+	cert, err := signer.Issue(req)
+	if err != nil {
+		return fmt.Errorf("failed to issue %s certificate: %w", usage, err)
+	}
+	data := map[string][]byte{
+		corev1.TLSCertKey:       cert.CertPEM,
+		corev1.TLSPrivateKeyKey: cert.KeyPEM,
+		utils.SecretKeyCACert:   bundle,
+	}
+
+	if found {
+		existing.Data = data
+		if err := cm.k8sClient.Update(ctx, existing); err != nil {
+			return err
+		}
+		log.FromContext(ctx).Info("Reissued certificate", "secret", name, "notAfter", cert.Cert.NotAfter)
+		return nil
+	}
+	if err := cm.create(ctx, cluster, cm.buildSecret(cluster, name, data)); err != nil {
+		return err
+	}
+	log.FromContext(ctx).Info("Issued certificate", "secret", name)
+	return nil
+}
+
+// issuedBy 检查 Secret 中的证书是否可用、由 ca 签发且不需要续期。
+// 证书有效期已被 CA 的有效期截断时重新签发也无法延长，不视为需要续期
+func (cm *certificateManager) issuedBy(secret *corev1.Secret, ca *pki.KeyPair, renewBefore time.Duration) bool {
+	cert, err := pki.ParseKeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+	if err != nil {
+		return false
+	}
+	if cert.Cert.CheckSignatureFrom(ca.Cert) != nil {
+		return false
+	}
+	return time.Until(cert.Cert.NotAfter) > renewBefore || !cert.Cert.NotAfter.Before(ca.Cert.NotAfter)
+}
+
+// Inspect 读取成员所用证书的到期时间，并计算证书内容摘要
+func (cm *certificateManager) Inspect(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster) (*CertificateInfo, error) {
+	info := &CertificateInfo{}
+	if !k8s.TLSRequired(cluster) {
+		return info, nil
+	}
+	digest := sha256.New()
+
+	if !cluster.Spec.Security.TLS.AutoTLS {
+		certName := cluster.Spec.Security.TLS.CertificateSecret
+		certSecret, err := cm.getExternalSecret(ctx, cluster, certName)
+		if err != nil {
+			return nil, err
+		}
+		caSecret, err := cm.getExternalSecret(ctx, cluster, k8s.ExternalCASecretName(cluster))
+		if err != nil {
+			return nil, err
+		}
+		cert, err := pki.ParseCertificate(certSecret.Data[corev1.TLSCertKey])
+		if err != nil {
+			return nil, fmt.Errorf("%w: secret %s: %v", ErrInvalidCertificate, certName, err)
+		}
+		info.Certificates = append(info.Certificates, certificateStatus(CertificateExternal, certName, cert))
+		writeDigest(digest, certSecret.Data[corev1.TLSCertKey], caSecret.Data[utils.SecretKeyCACert])
+		info.Revision = hex.EncodeToString(digest.Sum(nil))[:16]
+		return info, nil
+	}
+
+	caName := k8s.CASecretName(cluster)
+	caSecret, err := cm.getSecret(ctx, cluster, caName)
+	if err != nil {
+		return nil, err
+	}
+	ca, err := pki.ParseCertificate(caSecret.Data[corev1.TLSCertKey])
+	if err != nil {
+		return nil, fmt.Errorf("invalid CA in secret %s: %w", caName, err)
+	}
+	info.Certificates = append(info.Certificates, certificateStatus(CertificateCA, caName, ca))
+	// 新签发的证书有效期会被 CA 截断时开始轮换 CA
+	info.CARenewalDue = caRotationPhase(cluster) == "" && time.Until(ca.NotAfter) < utils.DefaultCertificateValidity
+
+	for _, usage := range generatedUsages {
+		name := k8s.TLSSecretName(cluster, usage)
+		secret, err := cm.getSecret(ctx, cluster, name)
+		if err != nil {
+			return nil, err
+		}
+		cert, err := pki.ParseCertificate(secret.Data[corev1.TLSCertKey])
+		if err != nil {
+			return nil, fmt.Errorf("invalid certificate in secret %s: %w", name, err)
+		}
+		info.Certificates = append(info.Certificates, certificateStatus(usage, name, cert))
+		writeDigest(digest, secret.Data[corev1.TLSCertKey], secret.Data[utils.SecretKeyCACert])
+	}
+	info.Revision = hex.EncodeToString(digest.Sum(nil))[:16]
+	return info, nil
+}
+
+// getSecret 读取 Operator 生成的证书 Secret
+func (cm *certificateManager) getSecret(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster, name string) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	if err := cm.k8sClient.Get(ctx, types.NamespacedName{Name: name, Namespace: cluster.Namespace}, secret); err != nil {
+		return nil, fmt.Errorf("failed to get certificate secret %s: %w", name, err)
+	}
+	return secret, nil
+}
+
+// buildSecret 构建证书 Secret
+func (cm *certificateManager) buildSecret(cluster *etcdv1alpha1.EtcdCluster, name string, data map[string][]byte) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: cluster.Namespace,
+			Labels:    utils.LabelsForEtcdCluster(cluster),
+		},
+		Type: corev1.SecretTypeTLS,
+		Data: data,
+	}
+}
+
+// create 设置 ControllerReference 后创建 Secret
+func (cm *certificateManager) create(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster, secret *corev1.Secret) error {
+	if client := cm.k8sClient.GetClient(); client != nil {
+		if err := ctrl.SetControllerReference(cluster, secret, client.Scheme()); err != nil {
+			return err
+		}
+	}
+	return cm.k8sClient.Create(ctx, secret)
+}
+
+// caRotationPhase 返回集群当前的 CA 轮换阶段
+func caRotationPhase(cluster *etcdv1alpha1.EtcdCluster) etcdv1alpha1.EtcdCARotationPhase {
+	if cluster.Status.TLS == nil {
+		return ""
+	}
+	return cluster.Status.TLS.CARotation
+}
+
+// certificateStatus 构建证书状态
+func certificateStatus(name, secretName string, cert *x509.Certificate) etcdv1alpha1.EtcdCertificateStatus {
+	return etcdv1alpha1.EtcdCertificateStatus{
+		Name:       name,
+		SecretName: secretName,
+		NotAfter:   metav1.NewTime(cert.NotAfter),
+	}
+}
+
+// writeDigest 将证书内容写入摘要，每段以长度作为前缀避免拼接产生歧义
+func writeDigest(digest hash.Hash, parts ...[]byte) {
+	for _, part := range parts {
+		fmt.Fprintf(digest, "%d:", len(part))
+		digest.Write(part)
+	}
+}
@@ -0,0 +1,227 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+	"crypto/x509"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+	"github.com/your-org/etcd-k8s-operator/pkg/client"
+	"github.com/your-org/etcd-k8s-operator/pkg/k8s"
+	"github.com/your-org/etcd-k8s-operator/pkg/pki"
+	"github.com/your-org/etcd-k8s-operator/pkg/utils"
+)
+
+// TestCertificateManagerEnsure 测试生成的证书链可用、重复调用不会重新签发，以及 CA 变化后重新签发
+func TestCertificateManagerEnsure(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, etcdv1alpha1.AddToScheme(scheme))
+	k8sClient := client.NewKubernetesClient(fake.NewClientBuilder().WithScheme(scheme).Build(), record.NewFakeRecorder(10))
+
+	ctx := context.Background()
+	cluster := &etcdv1alpha1.EtcdCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "secure", Namespace: "prod", UID: "uid-1"},
+		Spec: etcdv1alpha1.EtcdClusterSpec{
+			Security: etcdv1alpha1.EtcdSecuritySpec{TLS: etcdv1alpha1.EtcdTLSSpec{AutoTLS: true}},
+		},
+	}
+	mgr := NewCertificateManager(k8sClient)
+	require.NoError(t, mgr.Ensure(ctx, cluster))
+
+	getSecret := func(name string) *corev1.Secret {
+		secret := &corev1.Secret{}
+		require.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Name: name, Namespace: "prod"}, secret))
+		return secret
+	}
+
+	ca := getSecret("secure-ca")
+	assert.Equal(t, corev1.SecretTypeTLS, ca.Type)
+	assert.Equal(t, "secure", ca.OwnerReferences[0].Name)
+	roots := x509.NewCertPool()
+	require.True(t, roots.AppendCertsFromPEM(ca.Data[corev1.TLSCertKey]))
+
+	server := getSecret(k8s.TLSSecretName(cluster, k8s.CertificateServer))
+	assert.Equal(t, ca.Data[corev1.TLSCertKey], server.Data[utils.SecretKeyCACert])
+	cert, err := pki.ParseKeyPair(server.Data[corev1.TLSCertKey], server.Data[corev1.TLSPrivateKeyKey])
+	require.NoError(t, err)
+	for _, host := range []string{"secure-2.secure-peer.prod.svc.cluster.local", "secure-client.prod.svc", "127.0.0.1"} {
+		_, err := cert.Cert.Verify(x509.VerifyOptions{DNSName: host, Roots: roots, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}})
+		assert.NoError(t, err, host)
+	}
+
+	clientSecret := getSecret(k8s.TLSSecretName(cluster, k8s.CertificateClient))
+	clientCert, err := pki.ParseCertificate(clientSecret.Data[corev1.TLSCertKey])
+	require.NoError(t, err)
+	_, err = clientCert.Verify(x509.VerifyOptions{Roots: roots, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}})
+	assert.NoError(t, err)
+
+	// 证书仍有效时不重新签发
+	peer := getSecret(k8s.TLSSecretName(cluster, k8s.CertificatePeer))
+	require.NoError(t, mgr.Ensure(ctx, cluster))
+	assert.Equal(t, peer.Data, getSecret(peer.Name).Data)
+
+	// 叶子证书不是由当前 CA 签发时重新签发
+	other, err := pki.NewCA("other", utils.DefaultCAValidity)
+	require.NoError(t, err)
+	peer.Data[utils.SecretKeyCACert] = other.CertPEM
+	require.NoError(t, k8sClient.Update(ctx, peer))
+	require.NoError(t, mgr.Ensure(ctx, cluster))
+	assert.Equal(t, ca.Data[corev1.TLSCertKey], getSecret(peer.Name).Data[utils.SecretKeyCACert])
+
+	require.NoError(t, mgr.Delete(ctx, cluster))
+	err = k8sClient.Get(ctx, types.NamespacedName{Name: "secure-ca", Namespace: "prod"}, &corev1.Secret{})
+	assert.True(t, errors.IsNotFound(err))
+}
+
+// TestCertificateManagerRenewal 测试进入续期窗口的证书被重新签发并改变证书版本，
+// 以及 CA 即将到期时请求轮换、被 CA 截断的证书不会反复签发
+func TestCertificateManagerRenewal(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, etcdv1alpha1.AddToScheme(scheme))
+	k8sClient := client.NewKubernetesClient(fake.NewClientBuilder().WithScheme(scheme).Build(), record.NewFakeRecorder(10))
+
+	ctx := context.Background()
+	cluster := &etcdv1alpha1.EtcdCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "secure", Namespace: "prod", UID: "uid-1"},
+		Spec: etcdv1alpha1.EtcdClusterSpec{
+			Size: 3,
+			Security: etcdv1alpha1.EtcdSecuritySpec{TLS: etcdv1alpha1.EtcdTLSSpec{
+				Enabled: true, ClientTLSEnabled: true, PeerTLSEnabled: true, AutoTLS: true,
+			}},
+		},
+	}
+	mgr := NewCertificateManager(k8sClient)
+	require.NoError(t, mgr.Ensure(ctx, cluster))
+	info, err := mgr.Inspect(ctx, cluster)
+	require.NoError(t, err)
+	require.Len(t, info.Certificates, 4)
+	assert.Equal(t, CertificateCA, info.Certificates[0].Name)
+	assert.False(t, info.CARenewalDue)
+
+	getSecret := func(name string) *corev1.Secret {
+		secret := &corev1.Secret{}
+		require.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Name: name, Namespace: "prod"}, secret))
+		return secret
+	}
+
+	// 将 peer 证书替换为 24 小时后到期的证书
+	caSecret := getSecret("secure-ca")
+	ca, err := pki.ParseKeyPair(caSecret.Data[corev1.TLSCertKey], caSecret.Data[corev1.TLSPrivateKeyKey])
+	require.NoError(t, err)
+	short, err := ca.Issue(pki.CertificateRequest{CommonName: "secure-peer", Validity: 24 * time.Hour})
+	require.NoError(t, err)
+	peer := getSecret(k8s.TLSSecretName(cluster, k8s.CertificatePeer))
+	peer.Data[corev1.TLSCertKey] = short.CertPEM
+	peer.Data[corev1.TLSPrivateKeyKey] = short.KeyPEM
+	require.NoError(t, k8sClient.Update(ctx, peer))
+	expiring, err := mgr.Inspect(ctx, cluster)
+	require.NoError(t, err)
+	assert.NotEqual(t, info.Revision, expiring.Revision)
+
+	require.NoError(t, mgr.Ensure(ctx, cluster))
+	renewed, err := mgr.Inspect(ctx, cluster)
+	require.NoError(t, err)
+	assert.NotEqual(t, expiring.Revision, renewed.Revision)
+	assert.True(t, renewed.Certificates[1].NotAfter.After(time.Now().Add(utils.DefaultCertificateRenewBefore)))
+
+	// 只剩 30 天的 CA 需要轮换，由它签发的证书已被截断，不再重复签发
+	expiringCA, err := pki.NewCA("secure-ca", 30*24*time.Hour)
+	require.NoError(t, err)
+	caSecret.Data = map[string][]byte{corev1.TLSCertKey: expiringCA.CertPEM, corev1.TLSPrivateKeyKey: expiringCA.KeyPEM}
+	require.NoError(t, k8sClient.Update(ctx, caSecret))
+	require.NoError(t, mgr.Ensure(ctx, cluster))
+	capped, err := mgr.Inspect(ctx, cluster)
+	require.NoError(t, err)
+	assert.True(t, capped.CARenewalDue)
+	require.NoError(t, mgr.Ensure(ctx, cluster))
+	again, err := mgr.Inspect(ctx, cluster)
+	require.NoError(t, err)
+	assert.Equal(t, capped.Revision, again.Revision)
+}
+
+// TestCertificateManagerCARotation 测试 CA 轮换各阶段生成的证书：先同时信任新旧 CA，
+// 再由新 CA 签发，最后新 CA 取代旧 CA
+func TestCertificateManagerCARotation(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, etcdv1alpha1.AddToScheme(scheme))
+	k8sClient := client.NewKubernetesClient(fake.NewClientBuilder().WithScheme(scheme).Build(), record.NewFakeRecorder(10))
+
+	ctx := context.Background()
+	cluster := &etcdv1alpha1.EtcdCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "secure", Namespace: "prod", UID: "uid-1"},
+		Spec: etcdv1alpha1.EtcdClusterSpec{
+			Size:     3,
+			Security: etcdv1alpha1.EtcdSecuritySpec{TLS: etcdv1alpha1.EtcdTLSSpec{AutoTLS: true}},
+		},
+		Status: etcdv1alpha1.EtcdClusterStatus{TLS: &etcdv1alpha1.EtcdTLSStatus{ClientTLS: true, PeerTLS: true}},
+	}
+	mgr := NewCertificateManager(k8sClient)
+	require.NoError(t, mgr.Ensure(ctx, cluster))
+
+	getSecret := func(name string) *corev1.Secret {
+		secret := &corev1.Secret{}
+		require.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Name: name, Namespace: "prod"}, secret))
+		return secret
+	}
+	peerName := k8s.TLSSecretName(cluster, k8s.CertificatePeer)
+	oldCA := getSecret("secure-ca").Data[corev1.TLSCertKey]
+	oldPeer := getSecret(peerName).Data[corev1.TLSCertKey]
+
+	// 同时信任新旧 CA，证书保持不变
+	cluster.Status.TLS.CARotation = etcdv1alpha1.EtcdCARotationTrustBoth
+	require.NoError(t, mgr.Ensure(ctx, cluster))
+	nextCA := getSecret("secure-ca-next").Data[corev1.TLSCertKey]
+	peer := getSecret(peerName)
+	assert.Equal(t, append(append([]byte{}, oldCA...), nextCA...), peer.Data[utils.SecretKeyCACert])
+	assert.Equal(t, oldPeer, peer.Data[corev1.TLSCertKey])
+
+	// 新 CA 签发证书
+	cluster.Status.TLS.CARotation = etcdv1alpha1.EtcdCARotationIssueNew
+	require.NoError(t, mgr.Ensure(ctx, cluster))
+	next, err := pki.ParseCertificate(nextCA)
+	require.NoError(t, err)
+	cert, err := pki.ParseCertificate(getSecret(peerName).Data[corev1.TLSCertKey])
+	require.NoError(t, err)
+	assert.NoError(t, cert.CheckSignatureFrom(next))
+	assert.Equal(t, peer.Data[utils.SecretKeyCACert], getSecret(peerName).Data[utils.SecretKeyCACert])
+
+	// 新 CA 取代旧 CA，重复调用不会再生成新的 CA
+	cluster.Status.TLS.CARotation = etcdv1alpha1.EtcdCARotationDropOld
+	require.NoError(t, mgr.Ensure(ctx, cluster))
+	require.NoError(t, mgr.Ensure(ctx, cluster))
+	assert.Equal(t, nextCA, getSecret("secure-ca").Data[corev1.TLSCertKey])
+	assert.Equal(t, nextCA, getSecret(peerName).Data[utils.SecretKeyCACert])
+	err = k8sClient.Get(ctx, types.NamespacedName{Name: "secure-ca-next", Namespace: "prod"}, &corev1.Secret{})
+	assert.True(t, errors.IsNotFound(err))
+}
@@ -18,6 +18,7 @@ package resource
 
 import (
 	"context"
+	"errors"
 
 	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
 	appsv1 "k8s.io/api/apps/v1"
@@ -36,6 +37,9 @@ type StatefulSetManager interface {
 	// 状态查询
 	GetStatus(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster) (*StatefulSetStatus, error)
 	NeedsUpdate(existing, desired *appsv1.StatefulSet) bool
+
+	// 期望状态
+	Desired(cluster *etcdv1alpha1.EtcdCluster, replicas int32) *appsv1.StatefulSet
 }
 
 // ServiceManager Service 管理器接口
@@ -71,6 +75,30 @@ type PVCManager interface {
 	CleanupAll(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster) error
 }
 
+// ErrInvalidCertificate 用户提供的证书缺失或不满足集群要求，需要用户修正而不是重试
+var ErrInvalidCertificate = errors.New("invalid certificate")
+
+// CertificateManager 证书 Secret 管理器接口
+type CertificateManager interface {
+	// 确保成员证书可用：AutoTLS 时生成 CA 及成员证书，否则校验用户提供的证书，
+	// 校验失败时返回包装了 ErrInvalidCertificate 的错误
+	Ensure(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster) error
+	Delete(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster) error
+
+	// 读取成员所用证书的到期时间与内容版本
+	Inspect(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster) (*CertificateInfo, error)
+}
+
+// CertificateInfo 成员所用证书的到期时间与内容版本
+type CertificateInfo struct {
+	// Certificates 各证书的到期时间
+	Certificates []etcdv1alpha1.EtcdCertificateStatus
+	// Revision 证书内容摘要，变化后需要重启成员加载新证书
+	Revision string
+	// CARenewalDue 自动生成的 CA 已无法覆盖一张完整有效期的证书，需要轮换
+	CARenewalDue bool
+}
+
 // ResourceManager 资源管理器聚合接口
 type ResourceManager interface {
 	// 聚合操作
@@ -82,6 +110,7 @@ type ResourceManager interface {
 	Service() ServiceManager
 	ConfigMap() ConfigMapManager
 	PVC() PVCManager
+	Certificate() CertificateManager
 }
 
 // StatefulSetStatus StatefulSet 状态
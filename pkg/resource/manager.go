@@ -22,6 +22,7 @@ import (
 
 	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
 	"github.com/your-org/etcd-k8s-operator/pkg/client"
+	"github.com/your-org/etcd-k8s-operator/pkg/k8s"
 )
 
 // resourceManager 资源管理器实现
@@ -31,21 +32,30 @@ type resourceManager struct {
 	serviceMgr     ServiceManager
 	configMapMgr   ConfigMapManager
 	pvcMgr         PVCManager
+	certificateMgr CertificateManager
 }
 
-// NewResourceManager 创建资源管理器实例
-func NewResourceManager(k8sClient client.KubernetesClient) ResourceManager {
+// NewResourceManager 创建资源管理器实例，options 为构建成员 StatefulSet 使用的 operator 配置
+func NewResourceManager(k8sClient client.KubernetesClient, options k8s.StatefulSetOptions) ResourceManager {
 	return &resourceManager{
 		k8sClient:      k8sClient,
-		statefulSetMgr: NewStatefulSetManager(k8sClient),
+		statefulSetMgr: NewStatefulSetManager(k8sClient, options),
 		serviceMgr:     NewServiceManager(k8sClient),
 		configMapMgr:   NewConfigMapManager(k8sClient),
 		pvcMgr:         NewPVCManager(k8sClient),
+		certificateMgr: NewCertificateManager(k8sClient),
 	}
 }
 
 // EnsureAllResources 确保所有资源存在
 func (rm *resourceManager) EnsureAllResources(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster) error {
+	// 0. 先确保证书可用，Pod 启动时需要挂载
+	if k8s.TLSRequired(cluster) {
+		if err := rm.certificateMgr.Ensure(ctx, cluster); err != nil {
+			return fmt.Errorf("failed to ensure certificates: %w", err)
+		}
+	}
+
 	// 1. 确保 ConfigMap
 	if err := rm.configMapMgr.Ensure(ctx, cluster); err != nil {
 		return fmt.Errorf("failed to ensure ConfigMap: %w", err)
@@ -84,7 +94,14 @@ func (rm *resourceManager) CleanupResources(ctx context.Context, cluster *etcdv1
 		return fmt.Errorf("failed to delete ConfigMap: %w", err)
 	}
 
-	// 4. 清理 PVCs (可选，根据策略决定)
+	// 4. 删除 Operator 生成的证书
+	if k8s.AutoTLSEnabled(cluster) {
+		if err := rm.certificateMgr.Delete(ctx, cluster); err != nil {
+			return fmt.Errorf("failed to delete certificates: %w", err)
+		}
+	}
+
+	// 5. 清理 PVCs (可选，根据策略决定)
 	if err := rm.pvcMgr.CleanupAll(ctx, cluster); err != nil {
 		return fmt.Errorf("failed to cleanup PVCs: %w", err)
 	}
@@ -111,3 +128,8 @@ func (rm *resourceManager) ConfigMap() ConfigMapManager {
 func (rm *resourceManager) PVC() PVCManager {
 	return rm.pvcMgr
 }
+
+// Certificate 获取证书管理器
+func (rm *resourceManager) Certificate() CertificateManager {
+	return rm.certificateMgr
+}
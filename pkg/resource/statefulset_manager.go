@@ -32,12 +32,14 @@ import (
 // statefulSetManager StatefulSet 管理器实现
 type statefulSetManager struct {
 	k8sClient client.KubernetesClient
+	options   k8s.StatefulSetOptions
 }
 
-// NewStatefulSetManager 创建 StatefulSet 管理器
-func NewStatefulSetManager(k8sClient client.KubernetesClient) StatefulSetManager {
+// NewStatefulSetManager 创建 StatefulSet 管理器，options 为构建成员 StatefulSet 使用的 operator 配置
+func NewStatefulSetManager(k8sClient client.KubernetesClient, options k8s.StatefulSetOptions) StatefulSetManager {
 	return &statefulSetManager{
 		k8sClient: k8sClient,
+		options:   options,
 	}
 }
 
@@ -49,12 +51,7 @@ func (sm *statefulSetManager) Ensure(ctx context.Context, cluster *etcdv1alpha1.
 // EnsureWithReplicas 确保 StatefulSet 存在并设置副本数
 func (sm *statefulSetManager) EnsureWithReplicas(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster, replicas int32) error {
 	// 构建期望的 StatefulSet
-	var desired *appsv1.StatefulSet
-	if replicas == cluster.Spec.Size {
-		desired = k8s.BuildStatefulSet(cluster)
-	} else {
-		desired = k8s.BuildStatefulSetWithReplicas(cluster, replicas)
-	}
+	desired := sm.Desired(cluster, replicas)
 
 	// 检查是否已存在
 	existing := &appsv1.StatefulSet{}
@@ -100,6 +97,11 @@ func (sm *statefulSetManager) EnsureWithReplicas(ctx context.Context, cluster *e
 	return nil
 }
 
+// Desired 返回指定副本数的期望 StatefulSet
+func (sm *statefulSetManager) Desired(cluster *etcdv1alpha1.EtcdCluster, replicas int32) *appsv1.StatefulSet {
+	return k8s.BuildStatefulSetWithReplicas(cluster, replicas, sm.options)
+}
+
 // Get 获取 StatefulSet
 func (sm *statefulSetManager) Get(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster) (*appsv1.StatefulSet, error) {
 	sts := &appsv1.StatefulSet{}
@@ -113,6 +115,11 @@ func (sm *statefulSetManager) Get(ctx context.Context, cluster *etcdv1alpha1.Etc
 // Update 更新 StatefulSet
 func (sm *statefulSetManager) Update(ctx context.Context, existing *appsv1.StatefulSet, desired *appsv1.StatefulSet) error {
 	// 保留一些不应该更新的字段
+	// 滚动分区由服务层在逐个重启成员时调整，更新模板时不能重置
+	if rolling := existing.Spec.UpdateStrategy.RollingUpdate; rolling != nil && rolling.Partition != nil &&
+		desired.Spec.UpdateStrategy.Type == appsv1.RollingUpdateStatefulSetStrategyType {
+		desired.Spec.UpdateStrategy.RollingUpdate = rolling.DeepCopy()
+	}
 	existing.Spec = desired.Spec
 	existing.Labels = desired.Labels
 	existing.Annotations = desired.Annotations
@@ -208,6 +215,16 @@ func (sm *statefulSetManager) NeedsUpdate(existing, desired *appsv1.StatefulSet)
 				return true
 			}
 		}
+		// 只比较环境变量的名称和取值，引用字段的 apiVersion 会被 API 服务器补全
+		if len(desiredInits[i].Env) != len(existingInits[i].Env) {
+			return true
+		}
+		for j := range desiredInits[i].Env {
+			if desiredInits[i].Env[j].Name != existingInits[i].Env[j].Name ||
+				desiredInits[i].Env[j].Value != existingInits[i].Env[j].Value {
+				return true
+			}
+		}
 	}
 
 	// 检查资源限制
@@ -0,0 +1,175 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+	"github.com/your-org/etcd-k8s-operator/pkg/backup"
+	"github.com/your-org/etcd-k8s-operator/pkg/utils"
+)
+
+// retentionPolicy 将 API 中的保留策略转换为 backup.RetentionPolicy
+func retentionPolicy(spec etcdv1alpha1.EtcdRetentionPolicy) (backup.RetentionPolicy, error) {
+	policy := backup.RetentionPolicy{MaxBackups: int(spec.MaxBackups)}
+	if spec.MaxBackups < 0 {
+		return policy, fmt.Errorf("retentionPolicy.maxBackups cannot be negative")
+	}
+	if spec.MaxAge != "" {
+		maxAge, err := backup.ParseMaxAge(spec.MaxAge)
+		if err != nil {
+			return policy, fmt.Errorf("retentionPolicy.maxAge: %w", err)
+		}
+		policy.MaxAge = maxAge
+	}
+	return policy, nil
+}
+
+// snapshotOwner 返回快照归属的 EtcdBackup 名称：定时备份的各次执行归属于父对象，其他备份归属于自身
+func snapshotOwner(b *etcdv1alpha1.EtcdBackup) string {
+	if schedule := b.Labels[utils.LabelBackupSchedule]; schedule != "" {
+		return schedule
+	}
+	return b.Name
+}
+
+// enforceRetention 按保留策略清理该备份创建的旧快照，结果写入备份状态 (由调用方持久化)。
+// runs 为定时备份的子备份。只清理属于 snapshotOwner 的快照，手动备份和其他定时备份的快照不受影响。
+// 返回被删除快照的存储路径
+func (s *backupService) enforceRetention(ctx context.Context, b *etcdv1alpha1.EtcdBackup, runs []etcdv1alpha1.EtcdBackup) []string {
+	logger := log.FromContext(ctx)
+
+	policy, err := retentionPolicy(b.Spec.RetentionPolicy)
+	if err != nil || (policy.MaxBackups == 0 && policy.MaxAge == 0) {
+		// 非法策略在 validateBackupSpec 中已拒绝
+		return nil
+	}
+
+	now := metav1.Now()
+	b.Status.LastPruneTime = &now
+	b.Status.PrunedBackups = nil
+
+	storage, prefix, err := s.backupStorage(ctx, b)
+	if err != nil {
+		s.recordPruneFailure(b, err.Error())
+		return nil
+	}
+
+	objects, err := storage.List(ctx, backup.ClusterSnapshotDir(prefix, b.Spec.ClusterName)+"/")
+	if err != nil {
+		s.recordPruneFailure(b, err.Error())
+		return nil
+	}
+	objects = ownedSnapshots(storage, objects, snapshotOwner(b), recordedSnapshots(b, runs))
+
+	var pruned, failures []string
+	for _, obj := range backup.SelectExpired(objects, policy, now.Time) {
+		if err := storage.Delete(ctx, obj.Key); err != nil {
+			logger.Error(err, "Failed to prune backup", "key", obj.Key)
+			failures = append(failures, err.Error())
+			continue
+		}
+		pruned = append(pruned, storage.URL(obj.Key))
+	}
+	b.Status.PrunedBackups = pruned
+
+	if len(pruned) > 0 {
+		logger.Info("Pruned expired backups", "count", len(pruned))
+		s.k8sClient.RecordEvent(b, corev1.EventTypeNormal, utils.EventReasonBackupPruned,
+			fmt.Sprintf("Pruned %d expired backup(s)", len(pruned)))
+	}
+
+	if len(failures) > 0 {
+		s.recordPruneFailure(b, fmt.Sprintf("failed to prune %d backup(s): %s", len(failures), strings.Join(failures, "; ")))
+		return pruned
+	}
+
+	s.setCondition(b, utils.ConditionTypeRetentionApplied, metav1.ConditionTrue, utils.ReasonPruned,
+		fmt.Sprintf("Pruned %d backup(s), %d retained", len(pruned), len(objects)-len(pruned)))
+	return pruned
+}
+
+// recordedSnapshots 返回备份及其子备份状态中记录的快照存储路径
+func recordedSnapshots(b *etcdv1alpha1.EtcdBackup, runs []etcdv1alpha1.EtcdBackup) map[string]bool {
+	recorded := map[string]bool{}
+	for _, run := range append([]etcdv1alpha1.EtcdBackup{*b}, runs...) {
+		if run.Status.StoragePath != "" {
+			recorded[run.Status.StoragePath] = true
+		}
+	}
+	return recorded
+}
+
+// ownedSnapshots 返回 objects 中属于 owner 的快照。早期版本写入的快照名中不带 owner，
+// 只有存储路径记录在 recorded 中时才属于 owner，其他备份的旧快照不会被误删
+func ownedSnapshots(storage backup.Storage, objects []backup.ObjectInfo, owner string, recorded map[string]bool) []backup.ObjectInfo {
+	var owned []backup.ObjectInfo
+	for _, obj := range objects {
+		_, keyOwner, ok := backup.ParseSnapshotKey(obj.Key)
+		if !ok {
+			continue
+		}
+		if keyOwner == owner || (keyOwner == "" && recorded[storage.URL(obj.Key)]) {
+			owned = append(owned, obj)
+		}
+	}
+	return owned
+}
+
+// recordPruneFailure 记录清理失败，失败不影响备份本身的结果，下次成功备份后会重试
+func (s *backupService) recordPruneFailure(b *etcdv1alpha1.EtcdBackup, message string) {
+	s.setCondition(b, utils.ConditionTypeRetentionApplied, metav1.ConditionFalse, utils.ReasonPruneFailed, message)
+	s.k8sClient.RecordEvent(b, corev1.EventTypeWarning, utils.EventReasonBackupPruneFailed, message)
+}
+
+// deletePrunedRuns 删除快照已被清理的子备份，使定时备份的历史记录同样受保留策略约束
+func (s *backupService) deletePrunedRuns(ctx context.Context, runs []etcdv1alpha1.EtcdBackup, pruned []string) {
+	if len(pruned) == 0 {
+		return
+	}
+
+	prunedPaths := make(map[string]bool, len(pruned))
+	for _, p := range pruned {
+		prunedPaths[p] = true
+	}
+
+	for i := range runs {
+		run := &runs[i]
+		if run.Status.Phase != etcdv1alpha1.EtcdBackupPhaseCompleted || !prunedPaths[run.Status.StoragePath] {
+			continue
+		}
+		if err := s.k8sClient.Delete(ctx, run); err != nil && !errors.IsNotFound(err) {
+			log.FromContext(ctx).Error(err, "Failed to delete pruned backup run", "run", run.Name)
+		}
+	}
+}
+
+// isNewerSuccess 判断子备份中是否出现了比已记录更新的成功备份
+func isNewerSuccess(recorded, latest *metav1.Time) bool {
+	if latest == nil {
+		return false
+	}
+	return recorded == nil || recorded.Before(latest)
+}
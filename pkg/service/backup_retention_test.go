@@ -0,0 +1,136 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+	"github.com/your-org/etcd-k8s-operator/pkg/utils"
+)
+
+// TestHandleSchedulePrunesAfterSuccess 测试新的成功备份出现后按保留策略清理快照和子备份
+func TestHandleSchedulePrunesAfterSuccess(t *testing.T) {
+	parent := newScheduledBackup(time.Now().Add(-30*time.Minute), etcdv1alpha1.EtcdBackupConcurrencyForbid)
+	parent.Spec.RetentionPolicy = etcdv1alpha1.EtcdRetentionPolicy{MaxBackups: 2}
+
+	keys := []string{
+		"default/test-cluster/20250801-100000-hourly.db",
+		"default/test-cluster/20250801-110000-hourly.db",
+		"default/test-cluster/20250801-120000-hourly.db",
+	}
+
+	oldRun := newActiveRun(t, parent)
+	completed := metav1.NewTime(time.Now().Add(-time.Minute))
+	oldRun.Status = etcdv1alpha1.EtcdBackupStatus{
+		Phase:          etcdv1alpha1.EtcdBackupPhaseCompleted,
+		CompletionTime: &completed,
+	}
+
+	svc, k8sClient := newScheduleTestService(t, parent, oldRun)
+	ctx := context.Background()
+	for _, key := range keys {
+		_, err := svc.localStorage.Put(ctx, key, strings.NewReader("snapshot"), nil)
+		require.NoError(t, err)
+	}
+	// 子备份记录的是最旧的快照，清理后应一并删除
+	oldRun.Status.StoragePath = svc.localStorage.URL(keys[0])
+	require.NoError(t, k8sClient.UpdateStatus(ctx, oldRun))
+
+	_, err := svc.HandleBackup(ctx, parent)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{svc.localStorage.URL(keys[0])}, parent.Status.PrunedBackups)
+	assert.NotNil(t, parent.Status.LastPruneTime)
+	assert.True(t, meta.IsStatusConditionTrue(parent.Status.Conditions, utils.ConditionTypeRetentionApplied))
+
+	objects, err := svc.localStorage.List(ctx, "default/test-cluster/")
+	require.NoError(t, err)
+	assert.Len(t, objects, 2)
+
+	runs := &etcdv1alpha1.EtcdBackupList{}
+	require.NoError(t, k8sClient.List(ctx, runs))
+	for _, run := range runs.Items {
+		assert.NotEqual(t, oldRun.Name, run.Name, "pruned run should be deleted")
+	}
+}
+
+// TestEnforceRetentionKeepsOtherBackups 测试保留策略只清理本备份创建的快照，手动备份和其他定时备份的快照保留。
+// 不带 owner 的旧快照只有记录在子备份状态中时才会被清理
+func TestEnforceRetentionKeepsOtherBackups(t *testing.T) {
+	parent := newScheduledBackup(time.Now().Add(-30*time.Minute), etcdv1alpha1.EtcdBackupConcurrencyForbid)
+	parent.Spec.RetentionPolicy = etcdv1alpha1.EtcdRetentionPolicy{MaxBackups: 1}
+	svc, _ := newScheduleTestService(t, parent)
+	ctx := context.Background()
+
+	keys := map[string]string{
+		"old run":          "default/test-cluster/20250801-100000-hourly.db",
+		"latest run":       "default/test-cluster/20250801-110000-hourly.db.gz",
+		"legacy run":       "default/test-cluster/20250601-100000.db",
+		"legacy unowned":   "default/test-cluster/20250601-110000.db",
+		"manual":           "default/test-cluster/20250701-100000-pre-upgrade.db",
+		"other policy":     "default/test-cluster/20250701-000000-daily.db",
+		"similar policy":   "default/test-cluster/20250701-000000-hourly-eu.db",
+		"other cluster":    "default/other-cluster/20250701-000000-hourly.db",
+		"unrelated object": "default/test-cluster/notes.txt",
+	}
+	for _, key := range keys {
+		_, err := svc.localStorage.Put(ctx, key, strings.NewReader("snapshot"), nil)
+		require.NoError(t, err)
+	}
+
+	// 定时备份的各次执行归属于父对象
+	run := newScheduledBackup(time.Now(), "")
+	run.Name = "hourly-1754046000"
+	run.Labels = map[string]string{utils.LabelBackupSchedule: "hourly"}
+	assert.Equal(t, "hourly", snapshotOwner(run))
+	assert.Equal(t, "pre-upgrade", snapshotOwner(&etcdv1alpha1.EtcdBackup{ObjectMeta: metav1.ObjectMeta{Name: "pre-upgrade"}}))
+
+	run.Status.StoragePath = svc.localStorage.URL(keys["legacy run"])
+
+	pruned := svc.enforceRetention(ctx, parent, []etcdv1alpha1.EtcdBackup{*run})
+	assert.ElementsMatch(t, []string{svc.localStorage.URL(keys["old run"]), svc.localStorage.URL(keys["legacy run"])}, pruned)
+
+	objects, err := svc.localStorage.List(ctx, "default/")
+	require.NoError(t, err)
+	var remaining []string
+	for _, obj := range objects {
+		remaining = append(remaining, obj.Key)
+	}
+	assert.ElementsMatch(t, []string{keys["latest run"], keys["legacy unowned"], keys["manual"], keys["other policy"],
+		keys["similar policy"], keys["other cluster"], keys["unrelated object"]}, remaining)
+}
+
+// TestValidateBackupSpecRejectsInvalidMaxAge 测试拒绝无法解析的 MaxAge
+func TestValidateBackupSpecRejectsInvalidMaxAge(t *testing.T) {
+	svc, _ := newScheduleTestService(t)
+
+	b := newScheduledBackup(time.Now(), "")
+	b.Spec.RetentionPolicy.MaxAge = "30 days"
+	assert.Error(t, svc.validateBackupSpec(b))
+
+	b.Spec.RetentionPolicy.MaxAge = "30d"
+	assert.NoError(t, svc.validateBackupSpec(b))
+}
@@ -0,0 +1,265 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+	"github.com/your-org/etcd-k8s-operator/pkg/utils"
+)
+
+// handleSchedule 处理定时备份策略：到达触发时间时创建一个子 EtcdBackup 执行本次备份，并在下次触发时间重新入队
+func (s *backupService) handleSchedule(ctx context.Context, b *etcdv1alpha1.EtcdBackup) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	if err := s.validateBackupSpec(b); err != nil {
+		return s.failSchedule(ctx, b, err)
+	}
+
+	sched, err := cron.ParseStandard(b.Spec.Schedule)
+	if err != nil {
+		return s.failSchedule(ctx, b, fmt.Errorf("invalid schedule %q: %w", b.Spec.Schedule, err))
+	}
+
+	runs, err := s.listScheduledRuns(ctx, b)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	active, lastSuccessful := summarizeScheduledRuns(runs)
+
+	// 每出现一次新的成功备份执行一次保留策略
+	if isNewerSuccess(b.Status.LastSuccessfulTime, lastSuccessful) {
+		pruned := s.enforceRetention(ctx, b, runs)
+		s.deletePrunedRuns(ctx, runs, pruned)
+	}
+
+	now := time.Now()
+	earliest := b.CreationTimestamp.Time
+	if b.Status.LastScheduleTime != nil {
+		earliest = b.Status.LastScheduleTime.Time
+	}
+
+	scheduledTime, missed, err := mostRecentScheduleTime(sched, earliest, now)
+	if err != nil {
+		logger.Info("Too many missed scheduled backup runs", "reason", err.Error())
+		s.k8sClient.RecordEvent(b, corev1.EventTypeWarning, utils.EventReasonTooManyMissedSchedules, err.Error())
+	}
+	if scheduledTime != nil {
+		if missed > 1 {
+			logger.Info("Missed scheduled backup runs", "count", missed-1, "policy", b.Spec.MissedRunPolicy)
+		}
+
+		switch {
+		case b.Spec.MissedRunPolicy == etcdv1alpha1.EtcdBackupMissedRunSkip && now.Sub(*scheduledTime) > utils.DefaultMissedRunGracePeriod:
+			s.k8sClient.RecordEvent(b, corev1.EventTypeWarning, utils.EventReasonBackupSkipped,
+				fmt.Sprintf("Skipped run scheduled at %s because it was missed", scheduledTime.UTC().Format(time.RFC3339)))
+
+		case len(active) > 0 && b.Spec.ConcurrencyPolicy != etcdv1alpha1.EtcdBackupConcurrencyReplace:
+			s.k8sClient.RecordEvent(b, corev1.EventTypeWarning, utils.EventReasonBackupSkipped,
+				fmt.Sprintf("Skipped run scheduled at %s because %d previous run(s) are still in progress",
+					scheduledTime.UTC().Format(time.RFC3339), len(active)))
+
+		default:
+			if len(active) > 0 {
+				if err := s.replaceActiveRuns(ctx, b, runs); err != nil {
+					return ctrl.Result{}, err
+				}
+				active = nil
+			}
+
+			run, err := s.createScheduledRun(ctx, b, *scheduledTime)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			active = append(active, run.Name)
+			logger.Info("Scheduled backup run created", "run", run.Name, "scheduledTime", scheduledTime)
+		}
+
+		b.Status.LastScheduleTime = &metav1.Time{Time: *scheduledTime}
+	}
+
+	next := sched.Next(now)
+	b.Status.Phase = etcdv1alpha1.EtcdBackupPhaseScheduled
+	b.Status.ObservedGeneration = b.Generation
+	b.Status.Active = active
+	b.Status.LastSuccessfulTime = lastSuccessful
+	b.Status.NextScheduleTime = &metav1.Time{Time: next}
+	s.setCondition(b, utils.ConditionTypeReady, metav1.ConditionTrue, utils.ReasonScheduled,
+		fmt.Sprintf("Backups are scheduled with %q", b.Spec.Schedule))
+	if err := s.k8sClient.UpdateStatus(ctx, b); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// 多等一秒，避免因时钟精度在触发时间之前被唤醒
+	return ctrl.Result{RequeueAfter: next.Sub(now) + time.Second}, nil
+}
+
+// failSchedule 将规范无效的定时备份标记为失败，修正规范后会重新进入调度。
+// 当前 Generation 已标记为失败时直接返回，避免每轮调谐都写状态和记录事件
+func (s *backupService) failSchedule(ctx context.Context, b *etcdv1alpha1.EtcdBackup, cause error) (ctrl.Result, error) {
+	if b.Status.Phase == etcdv1alpha1.EtcdBackupPhaseFailed && b.Status.ObservedGeneration == b.Generation {
+		return ctrl.Result{}, nil
+	}
+
+	b.Status.Phase = etcdv1alpha1.EtcdBackupPhaseFailed
+	b.Status.ObservedGeneration = b.Generation
+	b.Status.NextScheduleTime = nil
+	s.setCondition(b, utils.ConditionTypeReady, metav1.ConditionFalse, utils.ReasonInvalidSchedule, cause.Error())
+	if err := s.k8sClient.UpdateStatus(ctx, b); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	s.k8sClient.RecordEvent(b, corev1.EventTypeWarning, utils.EventReasonBackupFailed, cause.Error())
+	return ctrl.Result{}, nil
+}
+
+// listScheduledRuns 列出定时备份创建的子备份
+func (s *backupService) listScheduledRuns(ctx context.Context, b *etcdv1alpha1.EtcdBackup) ([]etcdv1alpha1.EtcdBackup, error) {
+	list := &etcdv1alpha1.EtcdBackupList{}
+	if err := s.k8sClient.List(ctx, list,
+		ctrlclient.InNamespace(b.Namespace),
+		ctrlclient.MatchingLabels{utils.LabelBackupSchedule: b.Name},
+	); err != nil {
+		return nil, fmt.Errorf("failed to list scheduled runs: %w", err)
+	}
+
+	runs := make([]etcdv1alpha1.EtcdBackup, 0, len(list.Items))
+	for _, run := range list.Items {
+		if metav1.IsControlledBy(&run, b) {
+			runs = append(runs, run)
+		}
+	}
+	return runs, nil
+}
+
+// createScheduledRun 为一次触发创建子备份，名称由触发时间决定以保证幂等
+func (s *backupService) createScheduledRun(ctx context.Context, b *etcdv1alpha1.EtcdBackup, scheduledTime time.Time) (*etcdv1alpha1.EtcdBackup, error) {
+	spec := b.Spec.DeepCopy()
+	spec.Schedule = ""
+	spec.ConcurrencyPolicy = ""
+	spec.MissedRunPolicy = ""
+	// 保留策略由父对象统一执行
+	spec.RetentionPolicy = etcdv1alpha1.EtcdRetentionPolicy{}
+	spec.ClusterNamespace = s.clusterNamespace(b)
+
+	run := &etcdv1alpha1.EtcdBackup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%d", b.Name, scheduledTime.Unix()),
+			Namespace: b.Namespace,
+			Labels: map[string]string{
+				utils.LabelBackupSchedule: b.Name,
+				utils.LabelEtcdCluster:    b.Spec.ClusterName,
+			},
+		},
+		Spec: *spec,
+	}
+	if err := ctrl.SetControllerReference(b, run, s.k8sClient.GetClient().Scheme()); err != nil {
+		return nil, err
+	}
+
+	if err := s.k8sClient.Create(ctx, run); err != nil {
+		if errors.IsAlreadyExists(err) {
+			return run, nil
+		}
+		return nil, fmt.Errorf("failed to create scheduled run %s: %w", run.Name, err)
+	}
+
+	s.k8sClient.RecordEvent(b, corev1.EventTypeNormal, utils.EventReasonBackupScheduled,
+		fmt.Sprintf("Created backup %s for run scheduled at %s", run.Name, scheduledTime.UTC().Format(time.RFC3339)))
+	return run, nil
+}
+
+// replaceActiveRuns 取消并删除仍在进行中的子备份
+func (s *backupService) replaceActiveRuns(ctx context.Context, b *etcdv1alpha1.EtcdBackup, runs []etcdv1alpha1.EtcdBackup) error {
+	for i := range runs {
+		run := &runs[i]
+		if !isBackupActive(run) {
+			continue
+		}
+
+		s.cancelInflight(run.UID)
+		if err := s.k8sClient.Delete(ctx, run); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete running backup %s: %w", run.Name, err)
+		}
+
+		s.k8sClient.RecordEvent(b, corev1.EventTypeNormal, utils.EventReasonBackupReplaced,
+			fmt.Sprintf("Cancelled running backup %s to start a new scheduled run", run.Name))
+	}
+	return nil
+}
+
+// summarizeScheduledRuns 返回进行中的子备份名称和最近一次成功完成的时间
+func summarizeScheduledRuns(runs []etcdv1alpha1.EtcdBackup) ([]string, *metav1.Time) {
+	var active []string
+	var lastSuccessful *metav1.Time
+	for i := range runs {
+		run := &runs[i]
+		if isBackupActive(run) {
+			active = append(active, run.Name)
+			continue
+		}
+		if run.Status.Phase == etcdv1alpha1.EtcdBackupPhaseCompleted && run.Status.CompletionTime != nil {
+			if lastSuccessful == nil || lastSuccessful.Before(run.Status.CompletionTime) {
+				lastSuccessful = run.Status.CompletionTime
+			}
+		}
+	}
+	return active, lastSuccessful
+}
+
+// isBackupActive 判断备份是否仍在进行中
+func isBackupActive(b *etcdv1alpha1.EtcdBackup) bool {
+	return b.Status.Phase == "" || b.Status.Phase == etcdv1alpha1.EtcdBackupPhaseRunning
+}
+
+// mostRecentScheduleTime 返回 (earliest, now] 区间内最近的一次触发时间以及该区间内的触发次数。
+// 与 CronJob 控制器一样，错过的触发超过 utils.MaxMissedSchedules 次时不再继续遍历，
+// 改为只在最近 utils.DefaultScheduleStartingDeadline 内查找，并返回说明原因的错误
+func mostRecentScheduleTime(sched cron.Schedule, earliest, now time.Time) (*time.Time, int, error) {
+	latest, count := walkSchedule(sched, earliest, now, utils.MaxMissedSchedules+1)
+	if count <= utils.MaxMissedSchedules {
+		return latest, count, nil
+	}
+
+	start := now.Add(-utils.DefaultScheduleStartingDeadline)
+	latest, _ = walkSchedule(sched, start, now, -1)
+	return latest, count, fmt.Errorf("missed more than %d scheduled runs, only runs scheduled in the last %s are considered",
+		utils.MaxMissedSchedules, utils.DefaultScheduleStartingDeadline)
+}
+
+// walkSchedule 遍历 (earliest, now] 区间内的触发时间，返回最近一次触发时间和遍历的次数；limit 大于等于 0 时最多遍历 limit 次
+func walkSchedule(sched cron.Schedule, earliest, now time.Time, limit int) (*time.Time, int) {
+	var latest *time.Time
+	count := 0
+	for t := sched.Next(earliest); !t.IsZero() && !t.After(now) && count != limit; t = sched.Next(t) {
+		scheduled := t
+		latest = &scheduled
+		count++
+	}
+	return latest, count
+}
@@ -0,0 +1,247 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+	"github.com/your-org/etcd-k8s-operator/pkg/client"
+	"github.com/your-org/etcd-k8s-operator/pkg/utils"
+)
+
+// newScheduleTestService 创建使用 fake client 的备份服务
+func newScheduleTestService(t *testing.T, objs ...*etcdv1alpha1.EtcdBackup) (*backupService, client.KubernetesClient) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, etcdv1alpha1.AddToScheme(scheme))
+
+	builder := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&etcdv1alpha1.EtcdBackup{})
+	for _, obj := range objs {
+		builder = builder.WithObjects(obj)
+	}
+	k8sClient := client.NewKubernetesClient(builder.Build(), record.NewFakeRecorder(10))
+
+	return NewBackupService(k8sClient, t.TempDir()).(*backupService), k8sClient
+}
+
+// newScheduledBackup 创建一个每小时整点触发的定时备份
+func newScheduledBackup(created time.Time, policy etcdv1alpha1.EtcdBackupConcurrencyPolicy) *etcdv1alpha1.EtcdBackup {
+	return &etcdv1alpha1.EtcdBackup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "hourly",
+			Namespace:         "default",
+			UID:               types.UID("hourly-uid"),
+			CreationTimestamp: metav1.NewTime(created),
+		},
+		Spec: etcdv1alpha1.EtcdBackupSpec{
+			ClusterName:       "test-cluster",
+			StorageType:       etcdv1alpha1.EtcdBackupStorageTypeLocal,
+			Schedule:          "0 * * * *",
+			ConcurrencyPolicy: policy,
+		},
+	}
+}
+
+// TestMostRecentScheduleTime 测试计算错过的触发时间
+func TestMostRecentScheduleTime(t *testing.T) {
+	sched, err := cron.ParseStandard("0 * * * *")
+	require.NoError(t, err)
+
+	earliest := time.Date(2025, 8, 1, 10, 30, 0, 0, time.UTC)
+
+	latest, count, err := mostRecentScheduleTime(sched, earliest, earliest.Add(10*time.Minute))
+	require.NoError(t, err)
+	assert.Nil(t, latest)
+	assert.Equal(t, 0, count)
+
+	latest, count, err = mostRecentScheduleTime(sched, earliest, time.Date(2025, 8, 1, 13, 5, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.NotNil(t, latest)
+	assert.Equal(t, time.Date(2025, 8, 1, 13, 0, 0, 0, time.UTC), *latest)
+	assert.Equal(t, 3, count)
+}
+
+// TestMostRecentScheduleTimeTooManyMissed 测试错过的触发过多时停止遍历，只在最近的截止时间内查找
+func TestMostRecentScheduleTimeTooManyMissed(t *testing.T) {
+	sched, err := cron.ParseStandard("* * * * *")
+	require.NoError(t, err)
+
+	now := time.Date(2025, 8, 1, 12, 0, 30, 0, time.UTC)
+	latest, count, err := mostRecentScheduleTime(sched, now.AddDate(-1, 0, 0), now)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "missed more than 100 scheduled runs")
+	assert.Equal(t, utils.MaxMissedSchedules+1, count)
+	require.NotNil(t, latest)
+	assert.Equal(t, time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC), *latest)
+
+	// 截止时间内没有触发时不补跑
+	daily, err := cron.ParseStandard("0 3 * * *")
+	require.NoError(t, err)
+	latest, _, err = mostRecentScheduleTime(daily, now.AddDate(-1, 0, 0), now)
+	require.Error(t, err)
+	assert.Nil(t, latest)
+}
+
+// TestHandleScheduleCreatesRun 测试到达触发时间时创建子备份
+func TestHandleScheduleCreatesRun(t *testing.T) {
+	parent := newScheduledBackup(time.Now().Add(-90*time.Minute), etcdv1alpha1.EtcdBackupConcurrencyForbid)
+	svc, k8sClient := newScheduleTestService(t, parent)
+	ctx := context.Background()
+
+	result, err := svc.HandleBackup(ctx, parent)
+	require.NoError(t, err)
+	assert.True(t, result.RequeueAfter > 0 && result.RequeueAfter <= time.Hour+time.Second)
+
+	runs := &etcdv1alpha1.EtcdBackupList{}
+	require.NoError(t, k8sClient.List(ctx, runs))
+	var children []etcdv1alpha1.EtcdBackup
+	for _, b := range runs.Items {
+		if b.Labels[utils.LabelBackupSchedule] == parent.Name {
+			children = append(children, b)
+		}
+	}
+	require.Len(t, children, 1)
+	child := children[0]
+	assert.Empty(t, child.Spec.Schedule)
+	assert.Equal(t, "default", child.Spec.ClusterNamespace)
+	assert.True(t, metav1.IsControlledBy(&child, parent))
+
+	assert.Equal(t, etcdv1alpha1.EtcdBackupPhaseScheduled, parent.Status.Phase)
+	assert.Equal(t, []string{child.Name}, parent.Status.Active)
+	require.NotNil(t, parent.Status.LastScheduleTime)
+	require.NotNil(t, parent.Status.NextScheduleTime)
+
+	// 同一触发时间再次调谐不应重复创建
+	_, err = svc.HandleBackup(ctx, parent)
+	require.NoError(t, err)
+	require.NoError(t, k8sClient.List(ctx, runs))
+	assert.Len(t, runs.Items, 2)
+}
+
+// newActiveRun 创建一个属于定时备份且仍在进行中的子备份
+func newActiveRun(t *testing.T, parent *etcdv1alpha1.EtcdBackup) *etcdv1alpha1.EtcdBackup {
+	run := &etcdv1alpha1.EtcdBackup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      parent.Name + "-previous",
+			Namespace: parent.Namespace,
+			Labels:    map[string]string{utils.LabelBackupSchedule: parent.Name},
+		},
+		Spec: etcdv1alpha1.EtcdBackupSpec{
+			ClusterName: parent.Spec.ClusterName,
+			StorageType: parent.Spec.StorageType,
+		},
+		Status: etcdv1alpha1.EtcdBackupStatus{Phase: etcdv1alpha1.EtcdBackupPhaseRunning},
+	}
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, etcdv1alpha1.AddToScheme(scheme))
+	require.NoError(t, controllerutil.SetControllerReference(parent, run, scheme))
+	return run
+}
+
+// TestHandleScheduleForbidSkipsWhileActive 测试 Forbid 策略在上次备份未完成时跳过本次触发
+func TestHandleScheduleForbidSkipsWhileActive(t *testing.T) {
+	parent := newScheduledBackup(time.Now().Add(-90*time.Minute), etcdv1alpha1.EtcdBackupConcurrencyForbid)
+	previous := newActiveRun(t, parent)
+	svc, k8sClient := newScheduleTestService(t, parent, previous)
+	ctx := context.Background()
+
+	_, err := svc.HandleBackup(ctx, parent)
+	require.NoError(t, err)
+	assert.Equal(t, []string{previous.Name}, parent.Status.Active)
+	assert.NotNil(t, parent.Status.LastScheduleTime)
+
+	runs := &etcdv1alpha1.EtcdBackupList{}
+	require.NoError(t, k8sClient.List(ctx, runs))
+	assert.Len(t, runs.Items, 2, "expected only the parent and the previous run")
+}
+
+// TestHandleScheduleReplaceDeletesActive 测试 Replace 策略删除进行中的备份后创建新备份
+func TestHandleScheduleReplaceDeletesActive(t *testing.T) {
+	parent := newScheduledBackup(time.Now().Add(-90*time.Minute), etcdv1alpha1.EtcdBackupConcurrencyReplace)
+	previous := newActiveRun(t, parent)
+	svc, k8sClient := newScheduleTestService(t, parent, previous)
+	ctx := context.Background()
+
+	_, err := svc.HandleBackup(ctx, parent)
+	require.NoError(t, err)
+	require.Len(t, parent.Status.Active, 1)
+	assert.NotEqual(t, previous.Name, parent.Status.Active[0])
+
+	runs := &etcdv1alpha1.EtcdBackupList{}
+	require.NoError(t, k8sClient.List(ctx, runs))
+	var names []string
+	for _, b := range runs.Items {
+		names = append(names, b.Name)
+	}
+	assert.ElementsMatch(t, []string{parent.Name, parent.Status.Active[0]}, names)
+}
+
+// TestHandleScheduleInvalidCron 测试无法解析的 Schedule 被标记为失败
+func TestHandleScheduleInvalidCron(t *testing.T) {
+	parent := newScheduledBackup(time.Now(), "")
+	parent.Spec.Schedule = "every hour"
+	svc, _ := newScheduleTestService(t, parent)
+
+	result, err := svc.HandleBackup(context.Background(), parent)
+	require.NoError(t, err)
+	assert.Zero(t, result.RequeueAfter)
+	assert.Equal(t, etcdv1alpha1.EtcdBackupPhaseFailed, parent.Status.Phase)
+}
+
+// TestHandleScheduleInvalidSpecFailsOnce 测试规范无效的定时备份在同一 Generation 内只标记一次失败，修正规范后重新进入调度
+func TestHandleScheduleInvalidSpecFailsOnce(t *testing.T) {
+	parent := newScheduledBackup(time.Now(), "")
+	parent.Generation = 1
+	parent.Spec.StorageType = etcdv1alpha1.EtcdBackupStorageTypeS3
+	svc, k8sClient := newScheduleTestService(t, parent)
+	recorder := k8sClient.GetRecorder().(*record.FakeRecorder)
+	ctx := context.Background()
+
+	_, err := svc.HandleBackup(ctx, parent)
+	require.NoError(t, err)
+	assert.Equal(t, etcdv1alpha1.EtcdBackupPhaseFailed, parent.Status.Phase)
+	assert.Equal(t, int64(1), parent.Status.ObservedGeneration)
+	assert.Nil(t, parent.Status.CompletionTime)
+	assert.Contains(t, <-recorder.Events, "s3.bucket is required")
+
+	resourceVersion := parent.ResourceVersion
+	result, err := svc.HandleBackup(ctx, parent)
+	require.NoError(t, err)
+	assert.Zero(t, result)
+	assert.Equal(t, resourceVersion, parent.ResourceVersion)
+	assert.Empty(t, recorder.Events)
+
+	parent.Generation = 2
+	parent.Spec.StorageType = etcdv1alpha1.EtcdBackupStorageTypeLocal
+	_, err = svc.HandleBackup(ctx, parent)
+	require.NoError(t, err)
+	assert.Equal(t, etcdv1alpha1.EtcdBackupPhaseScheduled, parent.Status.Phase)
+	assert.Equal(t, int64(2), parent.Status.ObservedGeneration)
+}
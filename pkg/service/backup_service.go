@@ -0,0 +1,457 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sync"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+	"github.com/your-org/etcd-k8s-operator/pkg/backup"
+	"github.com/your-org/etcd-k8s-operator/pkg/client"
+	"github.com/your-org/etcd-k8s-operator/pkg/utils"
+)
+
+// backupService 备份服务实现
+type backupService struct {
+	k8sClient    client.KubernetesClient
+	localStorage *backup.LocalStorage
+	// scratchDir 校验快照内容时存放临时文件的目录，位于备份 PVC 上以容纳大快照
+	scratchDir string
+
+	// inflight 记录正在执行的快照，用于 Replace 并发策略取消旧的备份
+	mu       sync.Mutex
+	inflight map[types.UID]context.CancelFunc
+}
+
+// NewBackupService 创建备份服务实例
+func NewBackupService(k8sClient client.KubernetesClient, backupDir string) BackupService {
+	return newBackupService(k8sClient, backupDir)
+}
+
+// newBackupService 创建备份服务，恢复服务复用其中的存储和密钥解析
+func newBackupService(k8sClient client.KubernetesClient, backupDir string) *backupService {
+	return &backupService{
+		k8sClient:    k8sClient,
+		localStorage: backup.NewLocalStorage(backupDir),
+		scratchDir:   filepath.Join(backupDir, ".scratch"),
+		inflight:     make(map[types.UID]context.CancelFunc),
+	}
+}
+
+// snapshotResult 一次快照的结果
+type snapshotResult struct {
+	Key         string
+	StoragePath string
+	SHA256      string
+	Size        int64
+	RawSize     int64
+	Codec       backup.Codec
+	KeyID       string
+	Revision    int64
+	Version     string
+}
+
+// HandleBackup 处理备份状态机
+func (s *backupService) HandleBackup(ctx context.Context, b *etcdv1alpha1.EtcdBackup) (ctrl.Result, error) {
+	// 带 Schedule 的备份是定时策略，每次触发创建一个子备份
+	if b.Spec.Schedule != "" {
+		return s.handleSchedule(ctx, b)
+	}
+
+	switch b.Status.Phase {
+	case "":
+		return s.startBackup(ctx, b)
+	case etcdv1alpha1.EtcdBackupPhaseRunning:
+		return s.runBackup(ctx, b)
+	default:
+		// Completed 和 Failed 都是终态
+		return ctrl.Result{}, nil
+	}
+}
+
+// startBackup 校验备份规范并等待目标集群就绪
+func (s *backupService) startBackup(ctx context.Context, b *etcdv1alpha1.EtcdBackup) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	if err := s.validateBackupSpec(b); err != nil {
+		return s.failBackup(ctx, b, err)
+	}
+
+	cluster, err := s.getTargetCluster(ctx, b)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return s.failBackup(ctx, b, fmt.Errorf("etcd cluster %s/%s not found", s.clusterNamespace(b), b.Spec.ClusterName))
+		}
+		return ctrl.Result{}, err
+	}
+
+	if cluster.Status.Phase != etcdv1alpha1.EtcdClusterPhaseRunning {
+		logger.Info("Target cluster is not running yet, waiting", "cluster", cluster.Name, "phase", cluster.Status.Phase)
+		s.setCondition(b, utils.ConditionTypeReady, metav1.ConditionFalse, utils.ReasonWaitingForCluster,
+			fmt.Sprintf("Waiting for etcd cluster %s to be running (phase: %s)", cluster.Name, cluster.Status.Phase))
+		if err := s.k8sClient.UpdateStatus(ctx, b); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: utils.DefaultRequeueInterval}, nil
+	}
+
+	now := metav1.Now()
+	b.Status.Phase = etcdv1alpha1.EtcdBackupPhaseRunning
+	b.Status.StartTime = &now
+	s.setCondition(b, utils.ConditionTypeReady, metav1.ConditionFalse, utils.ReasonBackupInProgress,
+		fmt.Sprintf("Taking snapshot of etcd cluster %s", cluster.Name))
+	if err := s.k8sClient.UpdateStatus(ctx, b); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	logger.Info("Backup started", "cluster", cluster.Name, "storageType", b.Spec.StorageType)
+	return ctrl.Result{Requeue: true}, nil
+}
+
+// runBackup 执行快照并记录结果
+func (s *backupService) runBackup(ctx context.Context, b *etcdv1alpha1.EtcdBackup) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	cluster, err := s.getTargetCluster(ctx, b)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return s.failBackup(ctx, b, fmt.Errorf("etcd cluster %s/%s not found", s.clusterNamespace(b), b.Spec.ClusterName))
+		}
+		return ctrl.Result{}, err
+	}
+
+	result, err := s.takeSnapshot(ctx, b, cluster)
+	if err != nil {
+		logger.Error(err, "Failed to take snapshot", "cluster", cluster.Name)
+		return s.failBackup(ctx, b, err)
+	}
+
+	b.Status.SHA256 = result.SHA256
+	b.Status.StoragePath = result.StoragePath
+	snapshotStatus, err := s.verifySnapshot(ctx, b, result)
+	if err != nil {
+		logger.Error(err, "Snapshot verification failed", "path", result.StoragePath)
+		s.setCondition(b, utils.ConditionTypeVerified, metav1.ConditionFalse, utils.ReasonVerificationFailed, err.Error())
+		s.discardSnapshot(ctx, b, result.Key)
+		return s.failBackup(ctx, b, fmt.Errorf("snapshot verification failed: %w", err))
+	}
+
+	verifiedMessage := fmt.Sprintf("SHA-256 %s of stored snapshot verified", result.SHA256)
+	if snapshotStatus != nil {
+		// 以快照内容中的修订版本为准，成员状态中的修订版本取自快照开始之前
+		result.Revision = snapshotStatus.Revision
+		verifiedMessage = fmt.Sprintf("%s; snapshot hash %08x, revision %d, %d keys",
+			verifiedMessage, snapshotStatus.Hash, snapshotStatus.Revision, snapshotStatus.TotalKey)
+	}
+	s.setCondition(b, utils.ConditionTypeVerified, metav1.ConditionTrue, utils.ReasonSnapshotVerified, verifiedMessage)
+
+	now := metav1.Now()
+	b.Status.Phase = etcdv1alpha1.EtcdBackupPhaseCompleted
+	b.Status.CompletionTime = &now
+	b.Status.BackupSize = result.Size
+	b.Status.Compression = etcdv1alpha1.EtcdBackupCompressionCodec(result.Codec)
+	b.Status.RawSize = result.RawSize
+	b.Status.CompressedSize = result.Size
+	b.Status.EncryptionKeyID = result.KeyID
+	b.Status.EtcdRevision = result.Revision
+	b.Status.EtcdVersion = result.Version
+	s.setCondition(b, utils.ConditionTypeReady, metav1.ConditionTrue, utils.ReasonBackupCompleted,
+		fmt.Sprintf("Snapshot saved to %s", result.StoragePath))
+	s.enforceRetention(ctx, b, nil)
+	if err := s.k8sClient.UpdateStatus(ctx, b); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	s.k8sClient.RecordEvent(b, corev1.EventTypeNormal, utils.EventReasonBackupCreated,
+		fmt.Sprintf("Snapshot of etcd cluster %s saved to %s (%d bytes, revision %d)",
+			cluster.Name, result.StoragePath, result.Size, result.Revision))
+
+	if err := s.recordLastBackupTime(ctx, cluster, now); err != nil {
+		// 集群状态更新失败不影响备份结果
+		logger.Error(err, "Failed to update last backup time", "cluster", cluster.Name)
+	}
+
+	logger.Info("Backup completed", "cluster", cluster.Name, "path", result.StoragePath, "size", result.Size)
+	return ctrl.Result{}, nil
+}
+
+// takeSnapshot 从一个健康成员流式读取快照并写入存储
+func (s *backupService) takeSnapshot(ctx context.Context, b *etcdv1alpha1.EtcdBackup, cluster *etcdv1alpha1.EtcdCluster) (*snapshotResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, utils.DefaultBackupTimeout)
+	defer cancel()
+	s.trackInflight(b.UID, cancel)
+	defer s.untrackInflight(b.UID)
+
+	storage, prefix, err := s.backupStorage(ctx, b)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint, status, err := s.selectSnapshotMember(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	etcdClient, err := newEtcdClient(ctx, s.k8sClient.GetClient(), cluster, []string{endpoint})
+	if err != nil {
+		return nil, err
+	}
+	defer etcdClient.Close()
+
+	rc, err := etcdClient.OpenSnapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	// 边读取边压缩，快照不会完整地落在内存或临时文件中
+	codec := backupCodec(b)
+	raw := &backup.CountingReader{R: rc}
+	compressed, err := backup.CompressReader(raw, codec)
+	if err != nil {
+		return nil, err
+	}
+	defer compressed.Close()
+
+	key := backup.SnapshotKey(prefix, cluster.Name, snapshotOwner(b), time.Now()) + codec.Extension()
+	metadata := map[string]string{
+		backup.MetadataKeyCompression: string(codec),
+	}
+
+	// 加密在压缩之后进行，确保写入任何存储后端的都是密文
+	var payload io.Reader = compressed
+	var keyID string
+	if b.Spec.Encryption != nil {
+		keys, err := s.encryptionKeys(ctx, b.Namespace, b.Spec.Encryption)
+		if err != nil {
+			return nil, err
+		}
+		var encryptionKey []byte
+		keyID, encryptionKey, err = activeEncryptionKey(keys, b.Spec.Encryption)
+		if err != nil {
+			return nil, err
+		}
+		if payload, err = backup.EncryptReader(compressed, keyID, encryptionKey); err != nil {
+			return nil, err
+		}
+		key += backup.EncryptedExtension
+		metadata[backup.MetadataKeyEncryptionKeyID] = keyID
+	}
+
+	hashed := backup.NewHashingReader(payload)
+	size, err := storage.Put(ctx, key, hashed, metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save snapshot from %s: %w", endpoint, err)
+	}
+
+	return &snapshotResult{
+		Key:         key,
+		StoragePath: storage.URL(key),
+		SHA256:      hashed.Sum(),
+		Size:        size,
+		RawSize:     raw.N,
+		Codec:       codec,
+		KeyID:       keyID,
+		Revision:    status.Header.Revision,
+		Version:     status.Version,
+	}, nil
+}
+
+// selectSnapshotMember 选择一个健康成员用于快照，优先选择 follower 以减轻 leader 压力
+func (s *backupService) selectSnapshotMember(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster) (string, *clientv3.StatusResponse, error) {
+	endpoints := clusterClientEndpoints(ctx, s.k8sClient, cluster)
+	if len(endpoints) == 0 {
+		return "", nil, fmt.Errorf("etcd cluster %s has no members", cluster.Name)
+	}
+
+	etcdClient, err := newEtcdClient(ctx, s.k8sClient.GetClient(), cluster, endpoints)
+	if err != nil {
+		return "", nil, err
+	}
+	defer etcdClient.Close()
+
+	var leaderEndpoint string
+	var leaderStatus *clientv3.StatusResponse
+	var lastErr error
+	for _, endpoint := range endpoints {
+		status, err := etcdClient.MemberStatus(ctx, endpoint)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if len(status.Errors) > 0 {
+			lastErr = fmt.Errorf("member %s reports errors: %v", endpoint, status.Errors)
+			continue
+		}
+		if status.Header.MemberId != status.Leader {
+			return endpoint, status, nil
+		}
+		leaderEndpoint, leaderStatus = endpoint, status
+	}
+
+	if leaderStatus != nil {
+		return leaderEndpoint, leaderStatus, nil
+	}
+	return "", nil, fmt.Errorf("no healthy member available for snapshot: %w", lastErr)
+}
+
+// backupCodec 返回备份使用的压缩算法，CompressionCodec 优先于 Compression 开关
+func backupCodec(b *etcdv1alpha1.EtcdBackup) backup.Codec {
+	if b.Spec.CompressionCodec != "" {
+		return backup.Codec(b.Spec.CompressionCodec)
+	}
+	if b.Spec.Compression {
+		return backup.CodecGzip
+	}
+	return backup.CodecNone
+}
+
+// failBackup 将备份标记为失败
+func (s *backupService) failBackup(ctx context.Context, b *etcdv1alpha1.EtcdBackup, cause error) (ctrl.Result, error) {
+	now := metav1.Now()
+	b.Status.Phase = etcdv1alpha1.EtcdBackupPhaseFailed
+	b.Status.CompletionTime = &now
+	s.setCondition(b, utils.ConditionTypeReady, metav1.ConditionFalse, utils.ReasonBackupFailed, cause.Error())
+
+	if err := s.k8sClient.UpdateStatus(ctx, b); err != nil {
+		if errors.IsNotFound(err) {
+			// 备份已被删除 (例如被 Replace 策略取消)，无需记录
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	s.k8sClient.RecordEvent(b, corev1.EventTypeWarning, utils.EventReasonBackupFailed, cause.Error())
+	return ctrl.Result{}, nil
+}
+
+// trackInflight 记录正在执行的快照的取消函数
+func (s *backupService) trackInflight(uid types.UID, cancel context.CancelFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.inflight[uid] = cancel
+}
+
+// untrackInflight 移除已结束的快照
+func (s *backupService) untrackInflight(uid types.UID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.inflight, uid)
+}
+
+// cancelInflight 取消正在执行的快照，快照不在本进程中执行时无操作
+func (s *backupService) cancelInflight(uid types.UID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if cancel, ok := s.inflight[uid]; ok {
+		cancel()
+	}
+}
+
+// recordLastBackupTime 更新集群的最后备份时间
+func (s *backupService) recordLastBackupTime(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster, t metav1.Time) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest := &etcdv1alpha1.EtcdCluster{}
+		if err := s.k8sClient.Get(ctx, types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, latest); err != nil {
+			return err
+		}
+		latest.Status.LastBackupTime = &t
+		return s.k8sClient.UpdateStatus(ctx, latest)
+	})
+}
+
+// validateBackupSpec 验证备份规范
+func (s *backupService) validateBackupSpec(b *etcdv1alpha1.EtcdBackup) error {
+	if b.Spec.ClusterName == "" {
+		return fmt.Errorf("clusterName cannot be empty")
+	}
+
+	if _, err := retentionPolicy(b.Spec.RetentionPolicy); err != nil {
+		return err
+	}
+
+	if _, err := backup.ParseCodec(string(b.Spec.CompressionCodec)); err != nil {
+		return err
+	}
+
+	if b.Spec.Encryption != nil && b.Spec.Encryption.KeySecret == "" {
+		return fmt.Errorf("encryption.keySecret is required when encryption is enabled")
+	}
+
+	switch b.Spec.StorageType {
+	case etcdv1alpha1.EtcdBackupStorageTypeLocal:
+		return nil
+	case etcdv1alpha1.EtcdBackupStorageTypeS3:
+		if b.Spec.S3 == nil || b.Spec.S3.Bucket == "" {
+			return fmt.Errorf("s3.bucket is required for S3 storage")
+		}
+		return nil
+	case etcdv1alpha1.EtcdBackupStorageTypeGCS:
+		if b.Spec.GCS == nil || b.Spec.GCS.Bucket == "" {
+			return fmt.Errorf("gcs.bucket is required for GCS storage")
+		}
+		return nil
+	case "":
+		return fmt.Errorf("storageType cannot be empty")
+	default:
+		return fmt.Errorf("storage type %s is not supported yet", b.Spec.StorageType)
+	}
+}
+
+// getTargetCluster 获取备份对应的 EtcdCluster
+func (s *backupService) getTargetCluster(ctx context.Context, b *etcdv1alpha1.EtcdBackup) (*etcdv1alpha1.EtcdCluster, error) {
+	cluster := &etcdv1alpha1.EtcdCluster{}
+	err := s.k8sClient.Get(ctx, types.NamespacedName{
+		Name:      b.Spec.ClusterName,
+		Namespace: s.clusterNamespace(b),
+	}, cluster)
+	return cluster, err
+}
+
+// clusterNamespace 返回目标集群的命名空间，默认与备份对象相同
+func (s *backupService) clusterNamespace(b *etcdv1alpha1.EtcdBackup) string {
+	if b.Spec.ClusterNamespace != "" {
+		return b.Spec.ClusterNamespace
+	}
+	return b.Namespace
+}
+
+// setCondition 设置备份条件
+func (s *backupService) setCondition(b *etcdv1alpha1.EtcdBackup, conditionType string, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&b.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: b.Generation,
+	})
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+	"github.com/your-org/etcd-k8s-operator/pkg/backup"
+	"github.com/your-org/etcd-k8s-operator/pkg/utils"
+)
+
+// backupStorage 根据备份的 StorageType 返回对应的存储后端和对象 key 前缀
+func (s *backupService) backupStorage(ctx context.Context, b *etcdv1alpha1.EtcdBackup) (backup.Storage, string, error) {
+	switch b.Spec.StorageType {
+	case etcdv1alpha1.EtcdBackupStorageTypeLocal:
+		return s.localStorage, s.clusterNamespace(b), nil
+	case etcdv1alpha1.EtcdBackupStorageTypeS3:
+		storage, err := s.newS3Storage(ctx, b.Namespace, b.Spec.S3)
+		if err != nil {
+			return nil, "", err
+		}
+		return storage, strings.Trim(b.Spec.S3.Path, "/"), nil
+	case etcdv1alpha1.EtcdBackupStorageTypeGCS:
+		storage, err := s.newGCSStorage(ctx, b.Namespace, b.Spec.GCS)
+		if err != nil {
+			return nil, "", err
+		}
+		return storage, strings.Trim(b.Spec.GCS.Prefix, "/"), nil
+	default:
+		return nil, "", fmt.Errorf("storage type %s is not supported yet", b.Spec.StorageType)
+	}
+}
+
+// newS3Storage 根据 S3 配置创建存储，凭据从备份所在命名空间的 Secret 中读取
+func (s *backupService) newS3Storage(ctx context.Context, namespace string, spec *etcdv1alpha1.EtcdS3BackupSpec) (backup.Storage, error) {
+	cfg := backup.S3Config{
+		Bucket:   spec.Bucket,
+		Region:   spec.Region,
+		Endpoint: spec.Endpoint,
+	}
+
+	var err error
+	if spec.AccessKeySecret != "" {
+		if cfg.AccessKey, err = s.secretValue(ctx, namespace, spec.AccessKeySecret, utils.SecretKeyS3AccessKey); err != nil {
+			return nil, err
+		}
+	}
+	if spec.SecretKeySecret != "" {
+		if cfg.SecretKey, err = s.secretValue(ctx, namespace, spec.SecretKeySecret, utils.SecretKeyS3SecretKey); err != nil {
+			return nil, err
+		}
+	}
+
+	return backup.NewS3Storage(cfg)
+}
+
+// newGCSStorage 根据 GCS 配置创建存储，未指定凭据 Secret 时使用默认凭据
+func (s *backupService) newGCSStorage(ctx context.Context, namespace string, spec *etcdv1alpha1.EtcdGCSBackupSpec) (backup.Storage, error) {
+	cfg := backup.GCSConfig{
+		Bucket: spec.Bucket,
+	}
+
+	if spec.CredentialsSecret != "" {
+		credentials, err := s.secretValue(ctx, namespace, spec.CredentialsSecret, utils.SecretKeyGCSCredentials)
+		if err != nil {
+			return nil, err
+		}
+		cfg.CredentialsJSON = []byte(credentials)
+	}
+
+	return backup.NewGCSStorage(ctx, cfg)
+}
+
+// encryptionKeys 读取加密密钥 Secret 并构造密钥环
+func (s *backupService) encryptionKeys(ctx context.Context, namespace string, spec *etcdv1alpha1.EtcdBackupEncryptionSpec) (backup.KeyRing, error) {
+	data, err := s.secretData(ctx, namespace, spec.KeySecret)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := backup.ParseKeyRing(data)
+	if err != nil {
+		return nil, fmt.Errorf("secret %s/%s: %w", namespace, spec.KeySecret, err)
+	}
+	return keys, nil
+}
+
+// activeEncryptionKey 返回用于加密新快照的密钥及其 ID
+func activeEncryptionKey(keys backup.KeyRing, spec *etcdv1alpha1.EtcdBackupEncryptionSpec) (string, []byte, error) {
+	if spec.ActiveKeyID == "" {
+		if len(keys) != 1 {
+			return "", nil, fmt.Errorf("encryption.activeKeyID is required when the key secret holds %d keys", len(keys))
+		}
+		id := keys.IDs()[0]
+		return id, keys[id], nil
+	}
+
+	key, ok := keys[spec.ActiveKeyID]
+	if !ok {
+		return "", nil, fmt.Errorf("encryption key %q not found in secret %s", spec.ActiveKeyID, spec.KeySecret)
+	}
+	return spec.ActiveKeyID, key, nil
+}
+
+// secretData 读取 Secret 的全部数据
+func (s *backupService) secretData(ctx context.Context, namespace, name string) (map[string][]byte, error) {
+	secret := &corev1.Secret{}
+	if err := s.k8sClient.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, secret); err != nil {
+		return nil, fmt.Errorf("failed to get secret %s/%s: %w", namespace, name, err)
+	}
+	return secret.Data, nil
+}
+
+// secretValue 读取 Secret 中指定 key 的值
+func (s *backupService) secretValue(ctx context.Context, namespace, name, key string) (string, error) {
+	data, err := s.secretData(ctx, namespace, name)
+	if err != nil {
+		return "", err
+	}
+
+	value, ok := data[key]
+	if !ok || len(value) == 0 {
+		return "", fmt.Errorf("secret %s/%s has no %q key", namespace, name, key)
+	}
+	return string(value), nil
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+	"github.com/your-org/etcd-k8s-operator/pkg/backup"
+)
+
+// TestActiveEncryptionKey 测试选择用于加密新快照的密钥
+func TestActiveEncryptionKey(t *testing.T) {
+	k1, k2 := make([]byte, backup.EncryptionKeySize), make([]byte, backup.EncryptionKeySize)
+	k2[0] = 1
+
+	// 只有一个密钥时可以省略 ActiveKeyID
+	id, key, err := activeEncryptionKey(backup.KeyRing{"k1": k1}, &etcdv1alpha1.EtcdBackupEncryptionSpec{KeySecret: "keys"})
+	require.NoError(t, err)
+	assert.Equal(t, "k1", id)
+	assert.Equal(t, k1, key)
+
+	rotated := backup.KeyRing{"k1": k1, "k2": k2}
+	_, _, err = activeEncryptionKey(rotated, &etcdv1alpha1.EtcdBackupEncryptionSpec{KeySecret: "keys"})
+	assert.Error(t, err)
+
+	id, key, err = activeEncryptionKey(rotated, &etcdv1alpha1.EtcdBackupEncryptionSpec{KeySecret: "keys", ActiveKeyID: "k2"})
+	require.NoError(t, err)
+	assert.Equal(t, "k2", id)
+	assert.Equal(t, k2, key)
+
+	_, _, err = activeEncryptionKey(rotated, &etcdv1alpha1.EtcdBackupEncryptionSpec{KeySecret: "keys", ActiveKeyID: "k3"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/your-org/etcd-k8s-operator/pkg/backup"
+)
+
+// storeTestSnapshot 生成一个 bbolt 快照，以 gzip 压缩后写入本地存储，返回快照结果
+func storeTestSnapshot(t *testing.T, svc *backupService, key string) *snapshotResult {
+	path := filepath.Join(t.TempDir(), "snapshot.db")
+	db, err := bolt.Open(path, 0o600, nil)
+	require.NoError(t, err)
+	require.NoError(t, db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucket([]byte("key"))
+		if err != nil {
+			return err
+		}
+		k := make([]byte, 17)
+		binary.BigEndian.PutUint64(k[:8], 7)
+		return b.Put(k, []byte("value"))
+	}))
+	require.NoError(t, db.Close())
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	compressed, err := backup.CompressReader(f, backup.CodecGzip)
+	require.NoError(t, err)
+	defer compressed.Close()
+
+	hashed := backup.NewHashingReader(compressed)
+	_, err = svc.localStorage.Put(context.Background(), key, hashed, nil)
+	require.NoError(t, err)
+	return &snapshotResult{Key: key, SHA256: hashed.Sum()}
+}
+
+// TestVerifySnapshot 测试重新读取快照校验 SHA-256 和快照内容
+func TestVerifySnapshot(t *testing.T) {
+	svc, _ := newScheduleTestService(t)
+	b := newScheduledBackup(time.Now(), "")
+	b.Spec.Schedule = ""
+	b.Spec.VerifySnapshot = true
+
+	result := storeTestSnapshot(t, svc, "default/test-cluster/20250801-120000-hourly.db.gz")
+
+	status, err := svc.verifySnapshot(context.Background(), b, result)
+	require.NoError(t, err)
+	require.NotNil(t, status)
+	assert.Equal(t, int64(7), status.Revision)
+
+	// 只校验 SHA-256 时不解析快照内容
+	b.Spec.VerifySnapshot = false
+	status, err = svc.verifySnapshot(context.Background(), b, result)
+	require.NoError(t, err)
+	assert.Nil(t, status)
+
+	// 校验和不一致时失败
+	wrong := sha256.Sum256([]byte("other"))
+	result.SHA256 = hex.EncodeToString(wrong[:])
+	_, err = svc.verifySnapshot(context.Background(), b, result)
+	assert.ErrorContains(t, err, "checksum mismatch")
+
+	// 临时文件应被清理
+	entries, err := os.ReadDir(svc.scratchDir)
+	require.NoError(t, err)
+	for _, e := range entries {
+		assert.False(t, strings.HasPrefix(e.Name(), "verify-"), e.Name())
+	}
+}
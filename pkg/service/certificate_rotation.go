@@ -0,0 +1,81 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+	"github.com/your-org/etcd-k8s-operator/pkg/resource"
+	"github.com/your-org/etcd-k8s-operator/pkg/utils"
+)
+
+// rotateCertificates 证书内容变化后逐个重启成员加载新证书，并推进 CA 轮换。
+// 返回非 nil 的 Result 表示本轮调谐由证书轮换接管
+func (s *scalingService) rotateCertificates(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster, sts *appsv1.StatefulSet, info *resource.CertificateInfo) (*ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	switch {
+	case info.Revision != cluster.Status.TLS.Revision:
+		// 1. 证书内容变化，先用分区挡住全部成员再更新 Pod 模板
+		logger.Info("Certificates changed, restarting members one at a time",
+			"from", cluster.Status.TLS.Revision, "to", info.Revision)
+		cluster.Status.TLS.Revision = info.Revision
+		s.setCondition(cluster, utils.ConditionTypeProgressing, metav1.ConditionTrue, utils.ReasonCertificateRotation, "Restarting members to load renewed certificates")
+		if err := s.startRoll(ctx, cluster, sts); err != nil {
+			return nil, err
+		}
+		return &ctrl.Result{RequeueAfter: utils.DefaultRequeueInterval}, nil
+
+	case cluster.Status.TLS.CARotation != "":
+		// 2. 当前阶段的证书已被所有成员加载，进入 CA 轮换的下一阶段
+		next := nextCARotationPhase(cluster.Status.TLS.CARotation)
+		logger.Info("CA rotation phase completed", "phase", cluster.Status.TLS.CARotation, "next", next)
+		cluster.Status.TLS.CARotation = next
+		if err := s.k8sClient.Status().Update(ctx, cluster); err != nil {
+			return nil, err
+		}
+		return &ctrl.Result{Requeue: true}, nil
+
+	case info.CARenewalDue:
+		// 3. CA 即将到期，先让成员同时信任新旧 CA
+		logger.Info("Cluster CA is due for renewal, starting CA rotation")
+		cluster.Status.TLS.CARotation = etcdv1alpha1.EtcdCARotationTrustBoth
+		if err := s.k8sClient.Status().Update(ctx, cluster); err != nil {
+			return nil, err
+		}
+		return &ctrl.Result{Requeue: true}, nil
+	}
+	return nil, nil
+}
+
+// nextCARotationPhase 返回 CA 轮换的下一阶段，最后一个阶段之后轮换结束
+func nextCARotationPhase(phase etcdv1alpha1.EtcdCARotationPhase) etcdv1alpha1.EtcdCARotationPhase {
+	switch phase {
+	case etcdv1alpha1.EtcdCARotationTrustBoth:
+		return etcdv1alpha1.EtcdCARotationIssueNew
+	case etcdv1alpha1.EtcdCARotationIssueNew:
+		return etcdv1alpha1.EtcdCARotationDropOld
+	default:
+		return ""
+	}
+}
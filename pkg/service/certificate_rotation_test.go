@@ -0,0 +1,179 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+	"github.com/your-org/etcd-k8s-operator/pkg/client"
+	"github.com/your-org/etcd-k8s-operator/pkg/k8s"
+	"github.com/your-org/etcd-k8s-operator/pkg/metrics"
+	"github.com/your-org/etcd-k8s-operator/pkg/pki"
+	resourcepkg "github.com/your-org/etcd-k8s-operator/pkg/resource"
+	"github.com/your-org/etcd-k8s-operator/pkg/utils"
+)
+
+// memberExtraConfig 返回成员 init 容器追加到配置文件的配置段
+func memberExtraConfig(sts *appsv1.StatefulSet) string {
+	for _, env := range sts.Spec.Template.Spec.InitContainers[0].Env {
+		if env.Name == "ETCD_EXTRA_CONFIG" {
+			return env.Value
+		}
+	}
+	return ""
+}
+
+// newRotationFixture 创建运行中的 AutoTLS 集群、它的 StatefulSet 以及扩缩容服务
+func newRotationFixture(t *testing.T, objects ...ctrlclient.Object) (ctrlclient.Client, *scalingService, *etcdv1alpha1.EtcdCluster) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, etcdv1alpha1.AddToScheme(scheme))
+
+	cluster := &etcdv1alpha1.EtcdCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "secure", Namespace: "default", UID: "uid-1"},
+		Spec: etcdv1alpha1.EtcdClusterSpec{
+			Size:       3,
+			Version:    "v3.5.21",
+			Repository: "quay.io/coreos/etcd",
+			Storage:    etcdv1alpha1.EtcdStorageSpec{Size: resource.MustParse("1Gi")},
+			Security: etcdv1alpha1.EtcdSecuritySpec{TLS: etcdv1alpha1.EtcdTLSSpec{
+				Enabled: true, ClientTLSEnabled: true, PeerTLSEnabled: true, AutoTLS: true,
+			}},
+		},
+		Status: etcdv1alpha1.EtcdClusterStatus{
+			Phase: etcdv1alpha1.EtcdClusterPhaseRunning,
+			TLS:   &etcdv1alpha1.EtcdTLSStatus{ClientTLS: true, PeerTLS: true},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).
+		WithStatusSubresource(&etcdv1alpha1.EtcdCluster{}).
+		WithObjects(append(objects, cluster, k8s.BuildStatefulSet(cluster, k8s.StatefulSetOptions{}))...).
+		Build()
+	k8sClient := client.NewKubernetesClient(c, record.NewFakeRecorder(10))
+	svc := NewScalingService(c, resourcepkg.NewResourceManager(k8sClient, k8s.StatefulSetOptions{}), NewHealthService(k8sClient)).(*scalingService)
+	return c, svc, cluster
+}
+
+// TestReconcileMemberRestartsStartsGatedRoll 测试证书变化后先用分区挡住全部成员再更新 Pod 模板，
+// 并记录证书到期时间和指标
+func TestReconcileMemberRestartsStartsGatedRoll(t *testing.T) {
+	c, svc, cluster := newRotationFixture(t)
+	ctx := context.Background()
+	require.NoError(t, svc.resourceManager.Certificate().Ensure(ctx, cluster))
+
+	result, err := svc.reconcileMemberRestarts(ctx, cluster, nil)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+
+	sts := &appsv1.StatefulSet{}
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Name: "secure", Namespace: "default"}, sts))
+	assert.Equal(t, int32(3), rollPartition(sts))
+	assert.True(t, rollPending(sts))
+	assert.NotEmpty(t, cluster.Status.TLS.Revision)
+	assert.Equal(t, cluster.Status.TLS.Revision, sts.Spec.Template.Annotations[utils.AnnotationTLSRevision])
+	assert.Len(t, cluster.Status.TLS.Certificates, 4)
+
+	expiry := testutil.ToFloat64(metrics.CertificateExpiry.WithLabelValues("default", "secure", k8s.CertificatePeer))
+	assert.InDelta(t, float64(time.Now().Add(utils.DefaultCertificateValidity).Unix()), expiry, 60)
+
+	// 上一个放开的成员尚未按新模板重建时不放开下一个成员
+	sts.Spec.UpdateStrategy.RollingUpdate.Partition = &[]int32{2}[0]
+	sts.Status.UpdateRevision = "secure-new"
+	sts.Status.CurrentRevision = "secure-old"
+	require.NoError(t, c.Update(ctx, sts))
+	require.NoError(t, c.Create(ctx, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name: "secure-2", Namespace: "default",
+		Labels: map[string]string{appsv1.StatefulSetRevisionLabel: "secure-old"},
+	}}))
+	done, err := svc.rollMembers(ctx, cluster, sts)
+	require.NoError(t, err)
+	assert.False(t, done)
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Name: "secure", Namespace: "default"}, sts))
+	assert.Equal(t, int32(2), rollPartition(sts))
+}
+
+// TestReconcileMemberRestartsCARotation 测试 CA 即将到期时开始轮换，每个阶段的证书被成员加载后进入下一阶段
+func TestReconcileMemberRestartsCARotation(t *testing.T) {
+	expiringCA, err := pki.NewCA("secure-ca", 30*24*time.Hour)
+	require.NoError(t, err)
+	c, svc, cluster := newRotationFixture(t, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "secure-ca", Namespace: "default"},
+		Type:       corev1.SecretTypeTLS,
+		Data:       map[string][]byte{corev1.TLSCertKey: expiringCA.CertPEM, corev1.TLSPrivateKeyKey: expiringCA.KeyPEM},
+	})
+	ctx := context.Background()
+	certificates := svc.resourceManager.Certificate()
+
+	// markRolled 模拟成员已经加载当前证书
+	markRolled := func() {
+		require.NoError(t, certificates.Ensure(ctx, cluster))
+		info, err := certificates.Inspect(ctx, cluster)
+		require.NoError(t, err)
+		cluster.Status.TLS.Revision = info.Revision
+		require.NoError(t, c.Status().Update(ctx, cluster))
+	}
+
+	markRolled()
+	_, err = svc.reconcileMemberRestarts(ctx, cluster, nil)
+	require.NoError(t, err)
+	assert.Equal(t, etcdv1alpha1.EtcdCARotationTrustBoth, cluster.Status.TLS.CARotation)
+
+	// 新阶段的证书尚未被成员加载时先滚动重启，不推进阶段
+	require.NoError(t, certificates.Ensure(ctx, cluster))
+	_, err = svc.reconcileMemberRestarts(ctx, cluster, nil)
+	require.NoError(t, err)
+	assert.Equal(t, etcdv1alpha1.EtcdCARotationTrustBoth, cluster.Status.TLS.CARotation)
+
+	for _, next := range []etcdv1alpha1.EtcdCARotationPhase{
+		etcdv1alpha1.EtcdCARotationIssueNew,
+		etcdv1alpha1.EtcdCARotationDropOld,
+		"",
+	} {
+		sts := &appsv1.StatefulSet{}
+		require.NoError(t, c.Get(ctx, types.NamespacedName{Name: "secure", Namespace: "default"}, sts))
+		sts.Spec.UpdateStrategy.RollingUpdate = nil
+		require.NoError(t, c.Update(ctx, sts))
+
+		markRolled()
+		_, err = svc.reconcileMemberRestarts(ctx, cluster, nil)
+		require.NoError(t, err)
+		assert.Equal(t, next, cluster.Status.TLS.CARotation)
+	}
+
+	// 新 CA 已取代即将到期的 CA，不再需要轮换
+	info, err := certificates.Inspect(ctx, cluster)
+	require.NoError(t, err)
+	assert.False(t, info.CARenewalDue)
+	assert.True(t, info.Certificates[0].NotAfter.After(time.Now().Add(utils.DefaultCertificateValidity)))
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+	"github.com/your-org/etcd-k8s-operator/pkg/k8s"
+	"github.com/your-org/etcd-k8s-operator/pkg/utils"
+)
+
+// applyConfig 将 spec 中的 etcd 调优配置写入状态，并逐个重启成员使新的配置生效。
+// 配置无效时通过 ConfigApplied 条件提示，成员保持当前的配置，不重启成员
+func (s *scalingService) applyConfig(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster, sts *appsv1.StatefulSet) (*ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	if cluster.Status.Config != nil && equality.Semantic.DeepEqual(*cluster.Status.Config, cluster.Spec.Config) {
+		// 撤销无效的配置后去掉之前的提示
+		if current := meta.FindStatusCondition(cluster.Status.Conditions, utils.ConditionTypeConfigApplied); current != nil &&
+			current.Reason == utils.ReasonInvalidConfig {
+			setConfigCondition(cluster, metav1.ConditionTrue, utils.ReasonConfigApplied, "Members run with the etcd configuration in the spec")
+			return nil, s.k8sClient.Status().Update(ctx, cluster)
+		}
+		return nil, nil
+	}
+
+	if err := k8s.ValidateConfig(&cluster.Spec.Config); err != nil {
+		logger.Info("Ignoring invalid etcd configuration", "reason", err.Error())
+		if !setConfigCondition(cluster, metav1.ConditionFalse, utils.ReasonInvalidConfig,
+			fmt.Sprintf("Members keep the previous etcd configuration: %v", err)) {
+			return nil, nil
+		}
+		return nil, s.k8sClient.Status().Update(ctx, cluster)
+	}
+
+	logger.Info("Restarting members with new etcd configuration")
+	cluster.Status.Config = cluster.Spec.Config.DeepCopy()
+	setConfigCondition(cluster, metav1.ConditionTrue, utils.ReasonConfigApplied, "Restarting members one at a time to apply the etcd configuration")
+	if err := s.startRoll(ctx, cluster, sts); err != nil {
+		return nil, err
+	}
+	return &ctrl.Result{Requeue: true}, nil
+}
+
+// setConfigCondition 设置 ConfigApplied 条件，返回条件是否发生变化
+func setConfigCondition(cluster *etcdv1alpha1.EtcdCluster, status metav1.ConditionStatus, reason, message string) bool {
+	return meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+		Type:               utils.ConditionTypeConfigApplied,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: cluster.Generation,
+	})
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+	"github.com/your-org/etcd-k8s-operator/pkg/utils"
+)
+
+// TestApplyConfigStartsGatedRoll 测试调优配置变化后写入状态，并用分区挡住全部成员再更新 Pod 模板
+func TestApplyConfigStartsGatedRoll(t *testing.T) {
+	c, svc, cluster := newRotationFixture(t)
+	ctx := context.Background()
+	sts := &appsv1.StatefulSet{}
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Name: "secure", Namespace: "default"}, sts))
+
+	cluster.Status.Config = &etcdv1alpha1.EtcdConfigSpec{}
+	result, err := svc.applyConfig(ctx, cluster, sts)
+	require.NoError(t, err)
+	assert.Nil(t, result)
+
+	cluster.Spec.Config = etcdv1alpha1.EtcdConfigSpec{SnapshotCount: 5000, ExtraFlags: map[string]string{"max-txn-ops": "256"}}
+	require.NoError(t, c.Update(ctx, cluster))
+	result, err = svc.applyConfig(ctx, cluster, sts)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+
+	stored := &etcdv1alpha1.EtcdCluster{}
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Name: "secure", Namespace: "default"}, stored))
+	require.NotNil(t, stored.Status.Config)
+	assert.Equal(t, int64(5000), stored.Status.Config.SnapshotCount)
+	assert.True(t, meta.IsStatusConditionTrue(stored.Status.Conditions, utils.ConditionTypeConfigApplied))
+
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Name: "secure", Namespace: "default"}, sts))
+	assert.Equal(t, int32(3), rollPartition(sts))
+	assert.Contains(t, memberExtraConfig(sts), "snapshot-count: 5000\nmax-txn-ops: 256\n")
+}
+
+// TestApplyConfigRejectsInvalidConfig 测试无效的调优配置不写入状态也不重启成员，撤销后去掉提示
+func TestApplyConfigRejectsInvalidConfig(t *testing.T) {
+	c, svc, cluster := newRotationFixture(t)
+	ctx := context.Background()
+	sts := &appsv1.StatefulSet{}
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Name: "secure", Namespace: "default"}, sts))
+
+	cluster.Spec.Config = etcdv1alpha1.EtcdConfigSpec{ExtraFlags: map[string]string{"data-dir": "/tmp"}}
+	require.NoError(t, c.Update(ctx, cluster))
+	cluster.Status.Config = &etcdv1alpha1.EtcdConfigSpec{}
+	result, err := svc.applyConfig(ctx, cluster, sts)
+	require.NoError(t, err)
+	assert.Nil(t, result)
+	assert.Equal(t, &etcdv1alpha1.EtcdConfigSpec{}, cluster.Status.Config)
+	condition := meta.FindStatusCondition(cluster.Status.Conditions, utils.ConditionTypeConfigApplied)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, utils.ReasonInvalidConfig, condition.Reason)
+
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Name: "secure", Namespace: "default"}, sts))
+	assert.Equal(t, int32(0), rollPartition(sts))
+
+	cluster.Spec.Config = etcdv1alpha1.EtcdConfigSpec{}
+	require.NoError(t, c.Update(ctx, cluster))
+	cluster.Status.Config = &etcdv1alpha1.EtcdConfigSpec{}
+	cluster.Status.Conditions = []metav1.Condition{*condition}
+	result, err = svc.applyConfig(ctx, cluster, sts)
+	require.NoError(t, err)
+	assert.Nil(t, result)
+	assert.True(t, meta.IsStatusConditionTrue(cluster.Status.Conditions, utils.ConditionTypeConfigApplied))
+}
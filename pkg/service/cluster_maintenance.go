@@ -0,0 +1,278 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+	"github.com/your-org/etcd-k8s-operator/pkg/k8s"
+	"github.com/your-org/etcd-k8s-operator/pkg/utils"
+)
+
+// memberMaintainer 维护单个成员使用的 etcd 操作，客户端只连接该成员
+type memberMaintainer interface {
+	Defragment(ctx context.Context, endpoint string) error
+	TransferLeadership(ctx context.Context, transfereeID uint64) error
+	Close() error
+}
+
+// newMemberMaintainer 创建只连接指定序号成员的 etcd 客户端，转移 leader 的请求必须发送给当前 leader
+func (s *healthService) newMemberMaintainer(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster, ordinal int32) (memberMaintainer, error) {
+	etcdClient, err := newEtcdClient(ctx, s.k8sClient.GetClient(), cluster, []string{k8s.MemberClientURL(cluster, ordinal)})
+	if err != nil {
+		return nil, err
+	}
+	return etcdClient, nil
+}
+
+// applyCompaction 将 spec 中的自动压缩设置写入状态，并逐个重启成员使新的配置生效
+func (s *scalingService) applyCompaction(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster, sts *appsv1.StatefulSet) (*ctrl.Result, error) {
+	spec := cluster.Spec.Maintenance
+	status := cluster.Status.Maintenance
+	if status != nil && status.AutoCompactionMode == spec.AutoCompactionMode && status.AutoCompactionRetention == spec.AutoCompactionRetention {
+		return nil, nil
+	}
+
+	log.FromContext(ctx).Info("Restarting members with new auto compaction settings",
+		"mode", spec.AutoCompactionMode, "retention", spec.AutoCompactionRetention)
+	if status == nil {
+		status = &etcdv1alpha1.EtcdMaintenanceStatus{}
+		cluster.Status.Maintenance = status
+	}
+	status.AutoCompactionMode = spec.AutoCompactionMode
+	status.AutoCompactionRetention = spec.AutoCompactionRetention
+	s.setCondition(cluster, utils.ConditionTypeProgressing, metav1.ConditionTrue, utils.ReasonRunning, "Restarting members to apply auto compaction settings")
+	if err := s.startRoll(ctx, cluster, sts); err != nil {
+		return nil, err
+	}
+	return &ctrl.Result{Requeue: true}, nil
+}
+
+// defragmentMembers 按 spec.maintenance.defragSchedule 整理成员数据库的碎片。每轮调谐只处理一个成员，
+// 并且只在全部成员健康时进行：follower 按序号先整理，leader 在其他成员都完成后先转移 leader 再整理。
+// 空闲空间占比低于阈值的成员跳过，没有 follower 可以接任时不整理 leader
+func (s *healthService) defragmentMembers(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster, health *HealthStatus) error {
+	logger := log.FromContext(ctx)
+	maintenance := cluster.Status.Maintenance
+	if maintenance == nil || cluster.Spec.Maintenance.DefragSchedule == "" || health.Overall != HealthOverallHealthy ||
+		cluster.Status.MemberReplacement != nil || cluster.Status.Recovery != nil {
+		return nil
+	}
+
+	if maintenance.DefragRunTime == nil {
+		scheduled := s.dueDefragRun(ctx, cluster, time.Now())
+		if scheduled == nil {
+			return nil
+		}
+		logger.Info("Starting defragmentation run", "scheduledTime", scheduled)
+		runTime := metav1.NewTime(*scheduled)
+		maintenance.DefragRunTime = &runTime
+		maintenance.LastDefragRunTime = &runTime
+		if err := s.k8sClient.UpdateStatus(ctx, cluster); err != nil {
+			return err
+		}
+	}
+
+	// 写回状态会替换 cluster.Status.Maintenance，重新取当前的整理状态
+	maintenance = cluster.Status.Maintenance
+	if handled, err := s.defragmentNextMember(ctx, cluster, health, *maintenance.DefragRunTime,
+		cluster.Spec.Maintenance.FragmentationThreshold); handled || err != nil {
+		return err
+	}
+	logger.Info("Defragmentation run completed", "scheduledTime", maintenance.DefragRunTime.Time)
+	maintenance.DefragRunTime = nil
+	return s.k8sClient.UpdateStatus(ctx, cluster)
+}
+
+// defragmentNextMember 整理下一个自 since 之后尚未整理、且空闲空间占比不低于 threshold 的成员，
+// 下一个成员是 leader 时先转移 leader。整理会阻塞 leader，没有 follower 可以接任（如单成员集群）时
+// 跳过 leader 并发出告警事件。返回是否处理了成员，没有需要整理的成员时返回 false
+func (s *healthService) defragmentNextMember(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster, health *HealthStatus, since metav1.Time, threshold int32) (bool, error) {
+	ordinal, ok := nextDefragMember(cluster, health, since, threshold)
+	if !ok {
+		return false, nil
+	}
+	member := health.Members[ordinal]
+	if member.Role == memberRoleLeader {
+		transferee, ok := defragTransferee(health)
+		if !ok {
+			log.FromContext(ctx).Info("Skipping defragmentation of the leader, no follower to transfer leadership to", "member", member.Name)
+			s.k8sClient.RecordEvent(cluster, corev1.EventTypeWarning, utils.EventReasonDefragSkipped,
+				fmt.Sprintf("Skipped defragmenting leader %s: no follower to transfer leadership to", member.Name))
+			return false, nil
+		}
+		return true, s.moveLeaderForDefrag(ctx, cluster, ordinal, health.Members[transferee])
+	}
+	return true, s.defragmentMember(ctx, cluster, ordinal, member)
+}
+
+// dueDefragRun 返回到期但尚未开始的最近一次碎片整理时间，没有到期的整理时返回 nil
+func (s *healthService) dueDefragRun(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster, now time.Time) *time.Time {
+	sched, err := cron.ParseStandard(cluster.Spec.Maintenance.DefragSchedule)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Invalid defrag schedule", "schedule", cluster.Spec.Maintenance.DefragSchedule)
+		s.k8sClient.RecordEvent(cluster, corev1.EventTypeWarning, utils.EventReasonDefragFailed,
+			fmt.Sprintf("Invalid defrag schedule %q: %v", cluster.Spec.Maintenance.DefragSchedule, err))
+		return nil
+	}
+	earliest := cluster.CreationTimestamp.Time
+	if last := cluster.Status.Maintenance.LastDefragRunTime; last != nil {
+		earliest = last.Time
+	}
+	scheduled, _, err := mostRecentScheduleTime(sched, earliest, now)
+	if err != nil {
+		log.FromContext(ctx).Info("Too many missed defragmentation runs", "reason", err.Error())
+		s.k8sClient.RecordEvent(cluster, corev1.EventTypeWarning, utils.EventReasonTooManyMissedSchedules, err.Error())
+	}
+	return scheduled
+}
+
+// nextDefragMember 返回下一个需要整理的健康成员序号：follower 按序号优先，leader 最后
+func nextDefragMember(cluster *etcdv1alpha1.EtcdCluster, health *HealthStatus, since metav1.Time, threshold int32) (int32, bool) {
+	defragged := map[string]bool{}
+	for _, m := range cluster.Status.Maintenance.Members {
+		if !m.LastDefragTime.Before(&since) {
+			defragged[m.Name] = true
+		}
+	}
+
+	leader := int32(-1)
+	for i, m := range health.Members {
+		if m.Status != MemberHealthHealthy || defragged[m.Name] || fragmentation(m) < threshold {
+			continue
+		}
+		if m.Role == memberRoleLeader {
+			leader = int32(i)
+			continue
+		}
+		return int32(i), true
+	}
+	return leader, leader >= 0
+}
+
+// fragmentation 返回成员数据库文件中空闲空间的百分比
+func fragmentation(m MemberHealth) int32 {
+	if m.DBSize <= 0 {
+		return 0
+	}
+	return int32((m.DBSize - m.DBSizeInUse) * 100 / m.DBSize)
+}
+
+// defragTransferee 返回接替 leader 的 follower 序号，单成员集群没有 follower
+func defragTransferee(health *HealthStatus) (int32, bool) {
+	for i, m := range health.Members {
+		if m.Role == memberRoleFollower {
+			return int32(i), true
+		}
+	}
+	return 0, false
+}
+
+// moveLeaderForDefrag 在整理 leader 之前把 leader 转移给 follower，下一轮调谐按 follower 整理该成员
+func (s *healthService) moveLeaderForDefrag(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster, leader int32, transferee MemberHealth) error {
+	var transfereeID uint64
+	if _, err := fmt.Sscanf(transferee.ID, "%x", &transfereeID); err != nil {
+		return fmt.Errorf("failed to parse member ID %s: %w", transferee.ID, err)
+	}
+
+	etcdClient, err := s.memberMaintenance(ctx, cluster, leader)
+	if err != nil {
+		return err
+	}
+	defer etcdClient.Close()
+
+	if err := etcdClient.TransferLeadership(ctx, transfereeID); err != nil {
+		return err
+	}
+	from, to := k8s.MemberName(cluster, leader), transferee.Name
+	log.FromContext(ctx).Info("Transferred leadership before defragmentation", "from", from, "to", to)
+	s.k8sClient.RecordEvent(cluster, corev1.EventTypeNormal, utils.EventReasonMemberDefragmented,
+		fmt.Sprintf("Transferred leadership from %s to %s before defragmenting it", from, to))
+	return nil
+}
+
+// defragmentMember 整理成员的数据库，并记录整理时间和数据库文件缩小的字节数
+func (s *healthService) defragmentMember(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster, ordinal int32, member MemberHealth) error {
+	etcdClient, err := s.memberMaintenance(ctx, cluster, ordinal)
+	if err != nil {
+		return err
+	}
+	defer etcdClient.Close()
+
+	if err := etcdClient.Defragment(ctx, k8s.MemberClientURL(cluster, ordinal)); err != nil {
+		s.k8sClient.RecordEvent(cluster, corev1.EventTypeWarning, utils.EventReasonDefragFailed,
+			fmt.Sprintf("Failed to defragment member %s: %v", member.Name, err))
+		return err
+	}
+	status, err := s.memberStatus(ctx, cluster, ordinal)
+	if err != nil {
+		return err
+	}
+	reclaimed := member.DBSize - status.DbSize
+	log.FromContext(ctx).Info("Defragmented member", "member", member.Name, "reclaimedBytes", reclaimed)
+	s.k8sClient.RecordEvent(cluster, corev1.EventTypeNormal, utils.EventReasonMemberDefragmented,
+		fmt.Sprintf("Defragmented member %s, reclaimed %d bytes", member.Name, reclaimed))
+
+	recordDefrag(cluster, etcdv1alpha1.EtcdMemberDefragStatus{
+		Name:           member.Name,
+		LastDefragTime: metav1.Now(),
+		ReclaimedBytes: reclaimed,
+	})
+	return s.k8sClient.UpdateStatus(ctx, cluster)
+}
+
+// maintenanceInProgress 返回是否正在逐个整理成员碎片或处理 NOSPACE 告警
+func maintenanceInProgress(cluster *etcdv1alpha1.EtcdCluster) bool {
+	maintenance := cluster.Status.Maintenance
+	if maintenance == nil {
+		return false
+	}
+	return maintenance.DefragRunTime != nil || (maintenance.NoSpace != nil && maintenance.NoSpace.Action != "")
+}
+
+// recordDefrag 记录成员最近一次整理的结果，并去掉已经缩容的成员的记录
+func recordDefrag(cluster *etcdv1alpha1.EtcdCluster, defrag etcdv1alpha1.EtcdMemberDefragStatus) {
+	current := map[string]bool{}
+	for i := int32(0); i < cluster.Spec.Size; i++ {
+		current[k8s.MemberName(cluster, i)] = true
+	}
+	members := []etcdv1alpha1.EtcdMemberDefragStatus{}
+	recorded := false
+	for _, m := range cluster.Status.Maintenance.Members {
+		switch {
+		case m.Name == defrag.Name:
+			members = append(members, defrag)
+			recorded = true
+		case current[m.Name]:
+			members = append(members, m)
+		}
+	}
+	if !recorded {
+		members = append(members, defrag)
+	}
+	cluster.Status.Maintenance.Members = members
+}
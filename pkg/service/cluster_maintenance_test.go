@@ -0,0 +1,213 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+	"github.com/your-org/etcd-k8s-operator/pkg/k8s"
+	"github.com/your-org/etcd-k8s-operator/pkg/utils"
+)
+
+// fakeMaintainer 记录对单个成员的维护操作，并按操作结果修改成员状态
+type fakeMaintainer struct {
+	ordinal  int32
+	statuses map[int32]*clientv3.StatusResponse
+	calls    *[]string
+}
+
+func (f *fakeMaintainer) Defragment(_ context.Context, endpoint string) error {
+	*f.calls = append(*f.calls, "defragment "+endpoint)
+	f.statuses[f.ordinal].DbSize = f.statuses[f.ordinal].DbSizeInUse
+	return nil
+}
+
+func (f *fakeMaintainer) TransferLeadership(_ context.Context, transfereeID uint64) error {
+	*f.calls = append(*f.calls, fmt.Sprintf("move leader from %d to %x", f.ordinal, transfereeID))
+	for _, status := range f.statuses {
+		status.Leader = transfereeID
+	}
+	return nil
+}
+
+func (f *fakeMaintainer) Close() error {
+	return nil
+}
+
+// TestApplyCompactionStartsGatedRoll 测试自动压缩设置变化后写入状态，并用分区挡住全部成员再更新 Pod 模板
+func TestApplyCompactionStartsGatedRoll(t *testing.T) {
+	c, svc, cluster := newRotationFixture(t)
+	ctx := context.Background()
+	sts := &appsv1.StatefulSet{}
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Name: "secure", Namespace: "default"}, sts))
+
+	cluster.Status.Maintenance = &etcdv1alpha1.EtcdMaintenanceStatus{}
+	result, err := svc.applyCompaction(ctx, cluster, sts)
+	require.NoError(t, err)
+	assert.Nil(t, result)
+
+	cluster.Spec.Maintenance = etcdv1alpha1.EtcdMaintenanceSpec{AutoCompactionMode: "periodic", AutoCompactionRetention: "1h"}
+	require.NoError(t, c.Update(ctx, cluster))
+	result, err = svc.applyCompaction(ctx, cluster, sts)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+
+	stored := &etcdv1alpha1.EtcdCluster{}
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Name: "secure", Namespace: "default"}, stored))
+	require.NotNil(t, stored.Status.Maintenance)
+	assert.Equal(t, "periodic", stored.Status.Maintenance.AutoCompactionMode)
+	assert.Equal(t, "1h", stored.Status.Maintenance.AutoCompactionRetention)
+
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Name: "secure", Namespace: "default"}, sts))
+	assert.Equal(t, int32(3), rollPartition(sts))
+	assert.Contains(t, memberExtraConfig(sts), "auto-compaction-retention: \"1h\"\n")
+}
+
+// TestDefragmentMembersLeaderLast 测试按计划逐个整理成员：跳过碎片低于阈值的成员，follower 先整理，
+// leader 先转移 leader 再整理，并记录每个成员回收的空间
+func TestDefragmentMembersLeaderLast(t *testing.T) {
+	statuses := map[int32]*clientv3.StatusResponse{
+		0: memberStatusResponse(0xa, 0xa, 100),
+		1: memberStatusResponse(0xb, 0xa, 100),
+		2: memberStatusResponse(0xc, 0xa, 100),
+	}
+	statuses[0].DbSizeInUse = 512
+	statuses[1].DbSizeInUse = 1000
+	statuses[2].DbSizeInUse = 256
+	svc, cluster := newHealthFixture(t, statuses)
+	ctx := context.Background()
+	var calls []string
+	svc.memberMaintenance = func(_ context.Context, _ *etcdv1alpha1.EtcdCluster, ordinal int32) (memberMaintainer, error) {
+		return &fakeMaintainer{ordinal: ordinal, statuses: statuses, calls: &calls}, nil
+	}
+	recorder := svc.k8sClient.GetRecorder().(*record.FakeRecorder)
+
+	cluster.Spec.Maintenance = etcdv1alpha1.EtcdMaintenanceSpec{DefragSchedule: "0 * * * *", FragmentationThreshold: 20}
+	require.NoError(t, svc.k8sClient.Update(ctx, cluster))
+	lastRun := metav1.NewTime(time.Now().Add(-2 * time.Hour).Truncate(time.Second))
+	cluster.Status.Maintenance = &etcdv1alpha1.EtcdMaintenanceStatus{LastDefragRunTime: &lastRun}
+
+	// 1. 先整理 follower，碎片低于阈值的 secure-1 跳过
+	require.NoError(t, svc.PerformHealthCheck(ctx, cluster))
+	require.NotNil(t, cluster.Status.Maintenance.DefragRunTime)
+	runTime := cluster.Status.Maintenance.DefragRunTime.Time
+	assert.WithinDuration(t, time.Now(), runTime, time.Hour)
+	assert.Zero(t, runTime.Minute())
+	assert.Equal(t, runTime, cluster.Status.Maintenance.LastDefragRunTime.Time)
+	assert.Equal(t, []string{"defragment " + k8s.MemberClientURL(cluster, 2)}, calls)
+	require.Len(t, cluster.Status.Maintenance.Members, 1)
+	assert.Equal(t, "secure-2", cluster.Status.Maintenance.Members[0].Name)
+	assert.Equal(t, int64(768), cluster.Status.Maintenance.Members[0].ReclaimedBytes)
+	assert.Contains(t, <-recorder.Events, utils.EventReasonMemberDefragmented+" Defragmented member secure-2, reclaimed 768 bytes")
+
+	// 2. 只剩 leader 时先把 leader 转移给 follower
+	require.NoError(t, svc.PerformHealthCheck(ctx, cluster))
+	assert.Equal(t, "move leader from 0 to b", calls[1])
+	assert.Contains(t, <-recorder.Events, "Transferred leadership from secure-0 to secure-1")
+
+	// 3. 原 leader 成为 follower 后整理
+	require.NoError(t, svc.PerformHealthCheck(ctx, cluster))
+	assert.Equal(t, "defragment "+k8s.MemberClientURL(cluster, 0), calls[2])
+	require.Len(t, cluster.Status.Maintenance.Members, 2)
+	assert.Equal(t, etcdv1alpha1.EtcdMemberDefragStatus{
+		Name:           "secure-0",
+		LastDefragTime: cluster.Status.Maintenance.Members[1].LastDefragTime,
+		ReclaimedBytes: 512,
+	}, cluster.Status.Maintenance.Members[1])
+	assert.Contains(t, <-recorder.Events, "Defragmented member secure-0, reclaimed 512 bytes")
+
+	// 4. 没有需要整理的成员时结束本次整理，下一次计划时间之前不再整理
+	require.NoError(t, svc.PerformHealthCheck(ctx, cluster))
+	assert.Nil(t, cluster.Status.Maintenance.DefragRunTime)
+	require.NoError(t, svc.PerformHealthCheck(ctx, cluster))
+	assert.Len(t, calls, 3)
+
+	stored := &etcdv1alpha1.EtcdCluster{}
+	require.NoError(t, svc.k8sClient.Get(ctx, types.NamespacedName{Name: "secure", Namespace: "default"}, stored))
+	assert.Len(t, stored.Status.Maintenance.Members, 2)
+	assert.Nil(t, stored.Status.Maintenance.DefragRunTime)
+}
+
+// TestDefragmentMembersRequiresHealthyCluster 测试有成员不健康时不开始整理
+func TestDefragmentMembersRequiresHealthyCluster(t *testing.T) {
+	svc, cluster := newHealthFixture(t, map[int32]*clientv3.StatusResponse{
+		0: memberStatusResponse(0xa, 0xa, 100),
+		1: memberStatusResponse(0xb, 0xa, 100),
+	})
+	ctx := context.Background()
+	svc.memberMaintenance = func(context.Context, *etcdv1alpha1.EtcdCluster, int32) (memberMaintainer, error) {
+		return nil, fmt.Errorf("unexpected maintenance")
+	}
+	cluster.Spec.Maintenance.DefragSchedule = "* * * * *"
+	require.NoError(t, svc.k8sClient.Update(ctx, cluster))
+	lastRun := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	cluster.Status.Maintenance = &etcdv1alpha1.EtcdMaintenanceStatus{LastDefragRunTime: &lastRun}
+
+	require.NoError(t, svc.PerformHealthCheck(ctx, cluster))
+	assert.Nil(t, cluster.Status.Maintenance.DefragRunTime)
+	assert.Equal(t, lastRun, *cluster.Status.Maintenance.LastDefragRunTime)
+}
+
+// TestDefragmentMembersSkipsLeaderWithoutFollower 测试没有 follower 可以接任时不整理 leader，发出告警事件并结束本次整理
+func TestDefragmentMembersSkipsLeaderWithoutFollower(t *testing.T) {
+	svc, cluster := newHealthFixture(t, nil)
+	ctx := context.Background()
+	svc.memberMaintenance = func(context.Context, *etcdv1alpha1.EtcdCluster, int32) (memberMaintainer, error) {
+		return nil, fmt.Errorf("unexpected maintenance")
+	}
+	recorder := svc.k8sClient.GetRecorder().(*record.FakeRecorder)
+	cluster.Spec.Maintenance = etcdv1alpha1.EtcdMaintenanceSpec{DefragSchedule: "0 * * * *", FragmentationThreshold: 20}
+	require.NoError(t, svc.k8sClient.Update(ctx, cluster))
+	lastRun := metav1.NewTime(time.Now().Add(-2 * time.Hour).Truncate(time.Second))
+	cluster.Status.Maintenance = &etcdv1alpha1.EtcdMaintenanceStatus{LastDefragRunTime: &lastRun}
+	health := &HealthStatus{
+		Overall: HealthOverallHealthy,
+		Members: []MemberHealth{{Name: "secure-0", Status: MemberHealthHealthy, ID: "a", Role: memberRoleLeader, DBSize: 1024, DBSizeInUse: 256}},
+	}
+
+	require.NoError(t, svc.defragmentMembers(ctx, cluster, health))
+	assert.Nil(t, cluster.Status.Maintenance.DefragRunTime)
+	assert.True(t, cluster.Status.Maintenance.LastDefragRunTime.After(lastRun.Time))
+	assert.Empty(t, cluster.Status.Maintenance.Members)
+	assert.Contains(t, <-recorder.Events, utils.EventReasonDefragSkipped+" Skipped defragmenting leader secure-0: no follower to transfer leadership to")
+}
+
+// TestDueDefragRunTooManyMissed 测试错过的整理过多时发出告警事件，并只考虑最近的触发时间
+func TestDueDefragRunTooManyMissed(t *testing.T) {
+	svc, cluster := newHealthFixture(t, map[int32]*clientv3.StatusResponse{})
+	recorder := svc.k8sClient.GetRecorder().(*record.FakeRecorder)
+	cluster.Spec.Maintenance.DefragSchedule = "*/5 * * * *"
+	lastRun := metav1.NewTime(time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC))
+	cluster.Status.Maintenance = &etcdv1alpha1.EtcdMaintenanceStatus{LastDefragRunTime: &lastRun}
+
+	scheduled := svc.dueDefragRun(context.Background(), cluster, time.Date(2025, 8, 1, 12, 7, 0, 0, time.UTC))
+	require.NotNil(t, scheduled)
+	assert.Equal(t, time.Date(2025, 8, 1, 12, 5, 0, 0, time.UTC), *scheduled)
+	assert.Contains(t, <-recorder.Events, utils.EventReasonTooManyMissedSchedules+" missed more than 100 scheduled runs")
+}
@@ -0,0 +1,404 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+	"github.com/your-org/etcd-k8s-operator/pkg/k8s"
+	"github.com/your-org/etcd-k8s-operator/pkg/utils"
+)
+
+// detectQuorumLoss 集群失去法定人数超过 DefaultQuorumLossTimeout 时将集群标记为失败，返回是否已标记
+func (s *healthService) detectQuorumLoss(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster, health *HealthStatus) (bool, error) {
+	available := meta.FindStatusCondition(cluster.Status.Conditions, utils.ConditionTypeAvailable)
+	if health.Overall != HealthOverallUnavailable || available == nil || available.Status != metav1.ConditionFalse ||
+		time.Since(available.LastTransitionTime.Time) < utils.DefaultQuorumLossTimeout {
+		return false, nil
+	}
+
+	message := fmt.Sprintf("Lost quorum at %s: %s. %s", available.LastTransitionTime.UTC().Format(time.RFC3339),
+		available.Message, recoveryGuidance(recoveryPolicy(cluster)))
+	log.FromContext(ctx).Info("Etcd cluster lost quorum, marking it failed", "since", available.LastTransitionTime, "policy", recoveryPolicy(cluster))
+
+	// 没有法定人数时成员替换无法继续，由恢复流程重建成员
+	cluster.Status.MemberReplacement = nil
+	cluster.Status.Phase = etcdv1alpha1.EtcdClusterPhaseFailed
+	setReadyCondition(cluster, metav1.ConditionFalse, utils.ReasonQuorumLost, message)
+	if err := s.k8sClient.UpdateStatus(ctx, cluster); err != nil {
+		return false, err
+	}
+	s.k8sClient.RecordEvent(cluster, corev1.EventTypeWarning, utils.EventReasonClusterFailed, message)
+	return true, nil
+}
+
+// recoverCluster 按恢复策略恢复失去法定人数的集群。恢复开始后按记录的策略继续，修改 spec 不会中途切换
+func (s *healthService) recoverCluster(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster, health *HealthStatus) (ctrl.Result, error) {
+	policy := recoveryPolicy(cluster)
+	if cluster.Status.Recovery != nil {
+		policy = cluster.Status.Recovery.Policy
+	}
+
+	switch policy {
+	case etcdv1alpha1.EtcdRecoveryPolicyRestoreLatestBackup:
+		return s.restoreLatestBackup(ctx, cluster)
+	case etcdv1alpha1.EtcdRecoveryPolicyForceNewCluster:
+		return s.forceNewCluster(ctx, cluster, health)
+	default:
+		log.FromContext(ctx).Info("Waiting for members to recover, recovery policy is manual", "health", health.Overall)
+		return ctrl.Result{RequeueAfter: utils.DefaultRequeueInterval}, nil
+	}
+}
+
+// restoreLatestBackup 创建替换集群数据的 EtcdRestore，数据来自集群最近一次完成的备份。
+// 恢复期间集群被 EtcdRestore 暂停，恢复完成后回到运行状态
+func (s *healthService) restoreLatestBackup(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	rec := cluster.Status.Recovery
+	if rec == nil {
+		// 先记录名称再创建，重复调谐不会创建多个恢复
+		rec = &etcdv1alpha1.EtcdRecoveryStatus{
+			Policy:      etcdv1alpha1.EtcdRecoveryPolicyRestoreLatestBackup,
+			RestoreName: fmt.Sprintf("%s-recovery-%d", cluster.Name, time.Now().Unix()),
+			StartTime:   metav1.Now(),
+		}
+		cluster.Status.Recovery = rec
+		if err := s.k8sClient.UpdateStatus(ctx, cluster); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	restore := &etcdv1alpha1.EtcdRestore{}
+	err := s.k8sClient.Get(ctx, types.NamespacedName{Name: rec.RestoreName, Namespace: cluster.Namespace}, restore)
+	if errors.IsNotFound(err) {
+		backup, err := s.latestBackup(ctx, cluster)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if backup == nil {
+			return s.updateRecovery(ctx, cluster, utils.ReasonRecoveryFailed,
+				fmt.Sprintf("No completed EtcdBackup of cluster %s to restore, create one or recover the cluster manually", cluster.Name))
+		}
+
+		logger.Info("Restoring latest backup to recover the cluster", "backup", backup.Name, "restore", rec.RestoreName)
+		if err := s.k8sClient.Create(ctx, newRecoveryRestore(cluster, backup, rec.RestoreName)); err != nil && !errors.IsAlreadyExists(err) {
+			return ctrl.Result{}, fmt.Errorf("failed to create recovery restore %s: %w", rec.RestoreName, err)
+		}
+		s.k8sClient.RecordEvent(cluster, corev1.EventTypeWarning, utils.EventReasonClusterRecovery,
+			fmt.Sprintf("Replacing the cluster data with backup %s through EtcdRestore %s, writes after the backup are lost", backup.Name, rec.RestoreName))
+		return s.updateRecovery(ctx, cluster, utils.ReasonRecoveryInProgress,
+			fmt.Sprintf("Restoring backup %s through EtcdRestore %s", backup.Name, rec.RestoreName))
+	} else if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if restore.Status.Phase == etcdv1alpha1.EtcdRestorePhaseFailed {
+		return s.updateRecovery(ctx, cluster, utils.ReasonRecoveryFailed,
+			fmt.Sprintf("EtcdRestore %s failed, delete it to retry with the latest backup", restore.Name))
+	}
+	return ctrl.Result{RequeueAfter: utils.DefaultRequeueInterval}, nil
+}
+
+// latestBackup 返回集群命名空间中该集群最近一次完成的备份
+func (s *healthService) latestBackup(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster) (*etcdv1alpha1.EtcdBackup, error) {
+	backups := &etcdv1alpha1.EtcdBackupList{}
+	if err := s.k8sClient.List(ctx, backups, ctrlclient.InNamespace(cluster.Namespace)); err != nil {
+		return nil, err
+	}
+
+	var latest *etcdv1alpha1.EtcdBackup
+	for i := range backups.Items {
+		b := &backups.Items[i]
+		if b.Spec.ClusterName != cluster.Name || (b.Spec.ClusterNamespace != "" && b.Spec.ClusterNamespace != cluster.Namespace) {
+			continue
+		}
+		if b.Status.Phase != etcdv1alpha1.EtcdBackupPhaseCompleted || b.Status.CompletionTime == nil {
+			continue
+		}
+		if latest == nil || latest.Status.CompletionTime.Before(b.Status.CompletionTime) {
+			latest = b
+		}
+	}
+	return latest, nil
+}
+
+// newRecoveryRestore 构造用备份替换集群数据的 EtcdRestore
+func newRecoveryRestore(cluster *etcdv1alpha1.EtcdCluster, backup *etcdv1alpha1.EtcdBackup, name string) *etcdv1alpha1.EtcdRestore {
+	return &etcdv1alpha1.EtcdRestore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: cluster.Namespace,
+			Labels:    map[string]string{utils.LabelEtcdCluster: cluster.Name},
+		},
+		Spec: etcdv1alpha1.EtcdRestoreSpec{
+			BackupName:  backup.Name,
+			ClusterName: cluster.Name,
+			RestoreType: etcdv1alpha1.EtcdRestoreTypeReplace,
+		},
+	}
+}
+
+// forceNewCluster 以 --force-new-cluster 重启 raft 索引最高的存活成员，使它成为单成员集群，
+// 再逐个将其他成员以 learner 的身份加入并删除它们的旧数据，learner 追上 leader 之后提升为投票成员。
+// learner 不计入法定人数，尚未同步数据的成员不会让恢复中的集群失去法定人数
+func (s *healthService) forceNewCluster(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster, health *HealthStatus) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	rec := cluster.Status.Recovery
+	if rec == nil {
+		ordinal, ok := survivingMember(health)
+		if !ok {
+			return s.updateRecovery(ctx, cluster, utils.ReasonRecoveryFailed,
+				"No member is reachable to restart with --force-new-cluster, waiting for a member to come back")
+		}
+		name := k8s.MemberName(cluster, ordinal)
+		logger.Info("Recovering cluster from surviving member", "member", name, "raftIndex", health.Members[ordinal].RaftIndex)
+		cluster.Status.Recovery = &etcdv1alpha1.EtcdRecoveryStatus{
+			Policy:    etcdv1alpha1.EtcdRecoveryPolicyForceNewCluster,
+			Member:    name,
+			StartTime: metav1.Now(),
+		}
+		s.k8sClient.RecordEvent(cluster, corev1.EventTypeWarning, utils.EventReasonClusterRecovery,
+			fmt.Sprintf("Restarting member %s (raft index %d) with --force-new-cluster, writes it has not applied are lost",
+				name, health.Members[ordinal].RaftIndex))
+		return s.updateRecovery(ctx, cluster, utils.ReasonRecoveryInProgress,
+			fmt.Sprintf("Restarting member %s with --force-new-cluster", name))
+	}
+
+	survivor, err := memberOrdinal(cluster, rec.Member)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// 1. 以 --force-new-cluster 重启存活成员，等待它成为单成员集群的 leader
+	if int(survivor) >= len(health.Members) || health.Members[survivor].Status != MemberHealthHealthy {
+		if err := s.setForceNewCluster(ctx, cluster, rec.Member); err != nil {
+			return ctrl.Result{}, err
+		}
+		if err := s.restartMember(ctx, cluster, survivor, rec.StartTime); err != nil {
+			return ctrl.Result{}, err
+		}
+		logger.Info("Waiting for member to start as a new cluster", "member", rec.Member)
+		return ctrl.Result{RequeueAfter: utils.DefaultRequeueInterval}, nil
+	}
+	// 之后的重启不能再次重置成员列表
+	if err := s.setForceNewCluster(ctx, cluster, ""); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// 2. 逐个以 learner 加入其他成员，上一个成员提升并就绪后再加入下一个
+	etcdClient, err := s.memberClient(ctx, cluster)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	defer etcdClient.Close()
+
+	members, err := etcdClient.GetClusterMembers(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	for ordinal := int32(0); ordinal < cluster.Spec.Size; ordinal++ {
+		if ordinal == survivor {
+			continue
+		}
+		name := k8s.MemberName(cluster, ordinal)
+		peerURL := k8s.MemberPeerURL(cluster, ordinal)
+		var member *etcdv1alpha1.EtcdMember
+		for i := range members {
+			if members[i].PeerURL == peerURL {
+				member = &members[i]
+				break
+			}
+		}
+		if member == nil {
+			if _, err := etcdClient.AddLearner(ctx, peerURL); err != nil {
+				return ctrl.Result{}, err
+			}
+			logger.Info("Added member back to the recovered cluster as a learner", "member", name)
+			s.k8sClient.RecordEvent(cluster, corev1.EventTypeNormal, utils.EventReasonClusterRecovery,
+				fmt.Sprintf("Added member %s back to the recovered cluster as a learner", name))
+			return s.updateRecovery(ctx, cluster, utils.ReasonRecoveryInProgress,
+				fmt.Sprintf("Adding member %s back to the cluster recovered from %s", name, rec.Member))
+		}
+
+		recreated, err := s.resetMemberData(ctx, cluster, ordinal, rec.StartTime)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if recreated && member.Role == memberRoleLearner {
+			promoted, _, err := promoteCaughtUpLearner(ctx, etcdClient, member, k8s.MemberClientURL(cluster, ordinal), members)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			if !promoted {
+				logger.Info("Waiting for learner to catch up with the recovered cluster", "member", name)
+				return ctrl.Result{RequeueAfter: utils.DefaultRequeueInterval}, nil
+			}
+			logger.Info("Promoted member of the recovered cluster to a voting member", "member", name)
+			s.k8sClient.RecordEvent(cluster, corev1.EventTypeNormal, utils.EventReasonClusterRecovery,
+				fmt.Sprintf("Member %s caught up with the recovered cluster and was promoted to a voting member", name))
+			return ctrl.Result{RequeueAfter: utils.DefaultRequeueInterval}, nil
+		}
+		if !recreated || health.Members[ordinal].Status != MemberHealthHealthy {
+			logger.Info("Waiting for member to rejoin the recovered cluster", "member", name)
+			return ctrl.Result{RequeueAfter: utils.DefaultRequeueInterval}, nil
+		}
+	}
+
+	return s.finishRecovery(ctx, cluster)
+}
+
+// setForceNewCluster 在集群 ConfigMap 中记录以 --force-new-cluster 启动的成员，member 为空时移除记录。
+// ConfigMap 管理器下次同步时也会移除该记录
+func (s *healthService) setForceNewCluster(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster, member string) error {
+	configMap := &corev1.ConfigMap{}
+	if err := s.k8sClient.Get(ctx, types.NamespacedName{Name: k8s.ConfigMapName(cluster), Namespace: cluster.Namespace}, configMap); err != nil {
+		if errors.IsNotFound(err) && member == "" {
+			return nil
+		}
+		return err
+	}
+	if configMap.Data[utils.ConfigKeyForceNewCluster] == member {
+		return nil
+	}
+	if member == "" {
+		delete(configMap.Data, utils.ConfigKeyForceNewCluster)
+	} else {
+		if configMap.Data == nil {
+			configMap.Data = map[string]string{}
+		}
+		configMap.Data[utils.ConfigKeyForceNewCluster] = member
+	}
+	return s.k8sClient.Update(ctx, configMap)
+}
+
+// restartMember 删除恢复开始之前创建的成员 Pod，由 StatefulSet 重建后读取新的启动配置
+func (s *healthService) restartMember(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster, ordinal int32, start metav1.Time) error {
+	pod := &corev1.Pod{}
+	err := s.k8sClient.Get(ctx, types.NamespacedName{Name: k8s.MemberName(cluster, ordinal), Namespace: cluster.Namespace}, pod)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if pod.DeletionTimestamp != nil || !pod.CreationTimestamp.Before(&start) {
+		return nil
+	}
+	if err := s.k8sClient.Delete(ctx, pod); err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete pod %s: %w", pod.Name, err)
+	}
+	return nil
+}
+
+// finishRecovery 集群恢复后回到运行状态
+func (s *healthService) finishRecovery(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster) (ctrl.Result, error) {
+	log.FromContext(ctx).Info("Failed cluster is healthy again, returning to running phase")
+	if cluster.Status.Recovery != nil {
+		s.k8sClient.RecordEvent(cluster, corev1.EventTypeNormal, utils.EventReasonClusterRecovery,
+			fmt.Sprintf("Cluster recovered with policy %s", cluster.Status.Recovery.Policy))
+		cluster.Status.Recovery = nil
+	}
+	cluster.Status.Phase = etcdv1alpha1.EtcdClusterPhaseRunning
+	setReadyCondition(cluster, metav1.ConditionTrue, utils.ReasonRunning, "Cluster recovered")
+	if err := s.k8sClient.UpdateStatus(ctx, cluster); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{Requeue: true}, nil
+}
+
+// updateRecovery 在 Ready 条件中记录恢复进度并稍后重新调和
+func (s *healthService) updateRecovery(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster, reason, message string) (ctrl.Result, error) {
+	setReadyCondition(cluster, metav1.ConditionFalse, reason, message)
+	if err := s.k8sClient.UpdateStatus(ctx, cluster); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: utils.DefaultRequeueInterval}, nil
+}
+
+// survivingMember 返回 raft 索引最高的可达成员，它保留了最多已提交的数据
+func survivingMember(health *HealthStatus) (int32, bool) {
+	survivor := -1
+	for i, m := range health.Members {
+		if m.Status == MemberHealthUnreachable {
+			continue
+		}
+		if survivor < 0 || m.RaftIndex > health.Members[survivor].RaftIndex {
+			survivor = i
+		}
+	}
+	return int32(survivor), survivor >= 0
+}
+
+// recoveryPolicy 返回集群的恢复策略，未设置时为手动恢复
+func recoveryPolicy(cluster *etcdv1alpha1.EtcdCluster) etcdv1alpha1.EtcdRecoveryPolicy {
+	if cluster.Spec.Recovery.Policy == "" {
+		return etcdv1alpha1.EtcdRecoveryPolicyManual
+	}
+	return cluster.Spec.Recovery.Policy
+}
+
+// recoveryGuidance 返回失去法定人数后按恢复策略给出的说明
+func recoveryGuidance(policy etcdv1alpha1.EtcdRecoveryPolicy) string {
+	switch policy {
+	case etcdv1alpha1.EtcdRecoveryPolicyRestoreLatestBackup:
+		return "Recovering by restoring the latest completed backup"
+	case etcdv1alpha1.EtcdRecoveryPolicyForceNewCluster:
+		return "Recovering by restarting the most up-to-date surviving member with --force-new-cluster"
+	default:
+		return "Bring the members back, or set spec.recovery.policy to RestoreLatestBackup or ForceNewCluster to recover automatically"
+	}
+}
+
+// quorumLost 返回集群是否因为失去法定人数而失败，其他原因失败的集群不会自动恢复
+func quorumLost(cluster *etcdv1alpha1.EtcdCluster) bool {
+	if cluster.Status.Recovery != nil {
+		return true
+	}
+	ready := meta.FindStatusCondition(cluster.Status.Conditions, utils.ConditionTypeReady)
+	if ready == nil {
+		return false
+	}
+	switch ready.Reason {
+	case utils.ReasonQuorumLost, utils.ReasonRecoveryInProgress, utils.ReasonRecoveryFailed:
+		return true
+	default:
+		return false
+	}
+}
+
+// setReadyCondition 设置集群的 Ready 条件
+func setReadyCondition(cluster *etcdv1alpha1.EtcdCluster, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+		Type:               utils.ConditionTypeReady,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: cluster.Generation,
+	})
+}
@@ -0,0 +1,250 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+	"github.com/your-org/etcd-k8s-operator/pkg/k8s"
+	"github.com/your-org/etcd-k8s-operator/pkg/utils"
+)
+
+// failQuorum 将集群标记为因失去法定人数而失败，并设置恢复策略
+func failQuorum(t *testing.T, svc *healthService, cluster *etcdv1alpha1.EtcdCluster, policy etcdv1alpha1.EtcdRecoveryPolicy) {
+	ctx := context.Background()
+	cluster.Spec.Recovery.Policy = policy
+	require.NoError(t, svc.k8sClient.Update(ctx, cluster))
+	cluster.Status.Phase = etcdv1alpha1.EtcdClusterPhaseFailed
+	setReadyCondition(cluster, metav1.ConditionFalse, utils.ReasonQuorumLost, "Lost quorum")
+	require.NoError(t, svc.k8sClient.UpdateStatus(ctx, cluster))
+}
+
+// TestDetectQuorumLoss 测试集群失去法定人数超过阈值后被标记为失败，并在 Ready 条件中说明恢复方式
+func TestDetectQuorumLoss(t *testing.T) {
+	svc, cluster := newHealthFixture(t, map[int32]*clientv3.StatusResponse{
+		0: memberStatusResponse(0xa, 0, 100),
+	})
+	ctx := context.Background()
+	recorder := svc.k8sClient.GetRecorder().(*record.FakeRecorder)
+
+	require.NoError(t, svc.PerformHealthCheck(ctx, cluster))
+	assert.Equal(t, etcdv1alpha1.EtcdClusterPhaseRunning, cluster.Status.Phase)
+
+	available := meta.FindStatusCondition(cluster.Status.Conditions, utils.ConditionTypeAvailable)
+	require.NotNil(t, available)
+	available.LastTransitionTime = metav1.NewTime(time.Now().Add(-utils.DefaultQuorumLossTimeout - time.Minute))
+	require.NoError(t, svc.PerformHealthCheck(ctx, cluster))
+
+	stored := &etcdv1alpha1.EtcdCluster{}
+	require.NoError(t, svc.k8sClient.Get(ctx, types.NamespacedName{Name: "secure", Namespace: "default"}, stored))
+	assert.Equal(t, etcdv1alpha1.EtcdClusterPhaseFailed, stored.Status.Phase)
+	ready := meta.FindStatusCondition(stored.Status.Conditions, utils.ConditionTypeReady)
+	require.NotNil(t, ready)
+	assert.Equal(t, utils.ReasonQuorumLost, ready.Reason)
+	assert.Contains(t, ready.Message, "0 of 3 members are healthy")
+	assert.Contains(t, ready.Message, "spec.recovery.policy")
+	assert.Contains(t, <-recorder.Events, utils.EventReasonClusterFailed)
+
+	// 手动恢复策略下只等待成员恢复
+	result, err := svc.HandleFailed(ctx, cluster)
+	require.NoError(t, err)
+	assert.Equal(t, utils.DefaultRequeueInterval, result.RequeueAfter)
+	assert.Nil(t, cluster.Status.Recovery)
+}
+
+// TestHandleFailedRestoresLatestBackup 测试从集群最近一次完成的备份创建替换恢复，恢复失败时给出重试说明
+func TestHandleFailedRestoresLatestBackup(t *testing.T) {
+	svc, cluster := newHealthFixture(t, nil)
+	ctx := context.Background()
+	failQuorum(t, svc, cluster, etcdv1alpha1.EtcdRecoveryPolicyRestoreLatestBackup)
+
+	// 没有可用的备份
+	_, err := svc.HandleFailed(ctx, cluster)
+	require.NoError(t, err)
+	require.NotNil(t, cluster.Status.Recovery)
+	ready := meta.FindStatusCondition(cluster.Status.Conditions, utils.ConditionTypeReady)
+	assert.Equal(t, utils.ReasonRecoveryFailed, ready.Reason)
+
+	now := time.Now()
+	for _, b := range []struct {
+		name      string
+		cluster   string
+		phase     etcdv1alpha1.EtcdBackupPhase
+		completed time.Time
+	}{
+		{"older", "secure", etcdv1alpha1.EtcdBackupPhaseCompleted, now.Add(-2 * time.Hour)},
+		{"latest", "secure", etcdv1alpha1.EtcdBackupPhaseCompleted, now.Add(-time.Hour)},
+		{"failed", "secure", etcdv1alpha1.EtcdBackupPhaseFailed, now},
+		{"other", "other", etcdv1alpha1.EtcdBackupPhaseCompleted, now},
+	} {
+		completed := metav1.NewTime(b.completed)
+		require.NoError(t, svc.k8sClient.Create(ctx, &etcdv1alpha1.EtcdBackup{
+			ObjectMeta: metav1.ObjectMeta{Name: b.name, Namespace: "default"},
+			Spec:       etcdv1alpha1.EtcdBackupSpec{ClusterName: b.cluster},
+			Status:     etcdv1alpha1.EtcdBackupStatus{Phase: b.phase, CompletionTime: &completed},
+		}))
+	}
+
+	_, err = svc.HandleFailed(ctx, cluster)
+	require.NoError(t, err)
+	restore := &etcdv1alpha1.EtcdRestore{}
+	require.NoError(t, svc.k8sClient.Get(ctx, types.NamespacedName{Name: cluster.Status.Recovery.RestoreName, Namespace: "default"}, restore))
+	assert.Equal(t, "latest", restore.Spec.BackupName)
+	assert.Equal(t, "secure", restore.Spec.ClusterName)
+	assert.Equal(t, etcdv1alpha1.EtcdRestoreTypeReplace, restore.Spec.RestoreType)
+	ready = meta.FindStatusCondition(cluster.Status.Conditions, utils.ConditionTypeReady)
+	assert.Equal(t, utils.ReasonRecoveryInProgress, ready.Reason)
+
+	restore.Status.Phase = etcdv1alpha1.EtcdRestorePhaseFailed
+	require.NoError(t, svc.k8sClient.Update(ctx, restore))
+	_, err = svc.HandleFailed(ctx, cluster)
+	require.NoError(t, err)
+	ready = meta.FindStatusCondition(cluster.Status.Conditions, utils.ConditionTypeReady)
+	assert.Equal(t, utils.ReasonRecoveryFailed, ready.Reason)
+	assert.Contains(t, ready.Message, "delete it to retry")
+}
+
+// TestHandleFailedForcesNewCluster 测试以 --force-new-cluster 重启 raft 索引最高的存活成员，
+// 再逐个以 learner 的身份加入其他成员，追上 leader 后提升，全部就绪后回到运行状态
+func TestHandleFailedForcesNewCluster(t *testing.T) {
+	old := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	fresh := metav1.NewTime(time.Now().Add(time.Minute))
+	c, scaling, cluster := newRotationFixture(t,
+		k8s.BuildConfigMap(&etcdv1alpha1.EtcdCluster{ObjectMeta: metav1.ObjectMeta{Name: "secure", Namespace: "default"}}),
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "secure-0", Namespace: "default", CreationTimestamp: old}},
+		&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "data-secure-0", Namespace: "default", CreationTimestamp: old}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "secure-2", Namespace: "default", CreationTimestamp: old}},
+	)
+	ctx := context.Background()
+	svc := scaling.healthService.(*healthService)
+	statuses := map[int32]*clientv3.StatusResponse{
+		1: memberStatusResponse(0xb, 0, 90),
+		2: memberStatusResponse(0xc, 0, 120),
+	}
+	svc.memberStatus = func(_ context.Context, _ *etcdv1alpha1.EtcdCluster, ordinal int32) (*clientv3.StatusResponse, error) {
+		if status, ok := statuses[ordinal]; ok {
+			return status, nil
+		}
+		return nil, fmt.Errorf("connection refused")
+	}
+	etcdMembers := &fakeMemberManager{nextID: 0xd, members: []etcdv1alpha1.EtcdMember{
+		{Name: "secure-2", ID: "c", PeerURL: k8s.MemberPeerURL(cluster, 2), ClientURL: k8s.MemberClientURL(cluster, 2)},
+	}, statuses: map[string]*clientv3.StatusResponse{}}
+	svc.memberClient = func(context.Context, *etcdv1alpha1.EtcdCluster) (memberManager, error) {
+		return etcdMembers, nil
+	}
+	recorder := svc.k8sClient.GetRecorder().(*record.FakeRecorder)
+	failQuorum(t, svc, cluster, etcdv1alpha1.EtcdRecoveryPolicyForceNewCluster)
+	configKey := types.NamespacedName{Name: "secure-config", Namespace: "default"}
+	configMap := &corev1.ConfigMap{}
+
+	// 选择 raft 索引最高的存活成员
+	_, err := svc.HandleFailed(ctx, cluster)
+	require.NoError(t, err)
+	require.NotNil(t, cluster.Status.Recovery)
+	assert.Equal(t, "secure-2", cluster.Status.Recovery.Member)
+	assert.Contains(t, <-recorder.Events, "Restarting member secure-2 (raft index 120) with --force-new-cluster")
+
+	// 记录启动参数并重启存活成员
+	_, err = svc.HandleFailed(ctx, cluster)
+	require.NoError(t, err)
+	require.NoError(t, c.Get(ctx, configKey, configMap))
+	assert.Equal(t, "secure-2", configMap.Data[utils.ConfigKeyForceNewCluster])
+	err = c.Get(ctx, types.NamespacedName{Name: "secure-2", Namespace: "default"}, &corev1.Pod{})
+	assert.True(t, errors.IsNotFound(err))
+
+	// 存活成员成为单成员集群的 leader 后移除启动参数，加入第一个成员并删除它的旧数据
+	require.NoError(t, c.Create(ctx, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "secure-2", Namespace: "default", CreationTimestamp: fresh}}))
+	statuses[2] = memberStatusResponse(0xc, 0xc, 121)
+	_, err = svc.HandleFailed(ctx, cluster)
+	require.NoError(t, err)
+	require.NoError(t, c.Get(ctx, configKey, configMap))
+	assert.NotContains(t, configMap.Data, utils.ConfigKeyForceNewCluster)
+	require.Len(t, etcdMembers.members, 2)
+	assert.Equal(t, k8s.MemberPeerURL(cluster, 0), etcdMembers.members[1].PeerURL)
+	assert.Contains(t, <-recorder.Events, "Added member secure-0 back to the recovered cluster")
+
+	_, err = svc.HandleFailed(ctx, cluster)
+	require.NoError(t, err)
+	err = c.Get(ctx, types.NamespacedName{Name: "data-secure-0", Namespace: "default"}, &corev1.PersistentVolumeClaim{})
+	assert.True(t, errors.IsNotFound(err))
+	require.Len(t, etcdMembers.members, 2)
+	assert.Contains(t, <-recorder.Events, "Deleted volume data-secure-0")
+
+	// 成员以 learner 加入，追上 leader 之后提升为投票成员，就绪后才加入下一个成员
+	etcdMembers.statuses[k8s.MemberClientURL(cluster, 2)] = memberStatusResponse(0xc, 0xc, 100000)
+	for _, ordinal := range []int32{0, 1} {
+		require.NoError(t, c.Create(ctx, &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: k8s.MemberPVCName(cluster, ordinal), Namespace: "default", CreationTimestamp: fresh}}))
+		require.NoError(t, c.Create(ctx, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: k8s.MemberName(cluster, ordinal), Namespace: "default", CreationTimestamp: fresh}}))
+		memberID := uint64(0xd + ordinal)
+		learnerURL := k8s.MemberClientURL(cluster, ordinal)
+		etcdMembers.statuses[learnerURL] = memberStatusResponse(memberID, 0xc, 100)
+		_, err = svc.HandleFailed(ctx, cluster)
+		require.NoError(t, err)
+		assert.Len(t, etcdMembers.promoted, int(ordinal), "learner must not be promoted before it catches up")
+		assert.Equal(t, memberRoleLearner, etcdMembers.members[len(etcdMembers.members)-1].Role)
+
+		etcdMembers.statuses[learnerURL] = memberStatusResponse(memberID, 0xc, 100000)
+		_, err = svc.HandleFailed(ctx, cluster)
+		require.NoError(t, err)
+		assert.Equal(t, memberID, etcdMembers.promoted[ordinal])
+		assert.Contains(t, <-recorder.Events, fmt.Sprintf("Member %s caught up with the recovered cluster", k8s.MemberName(cluster, ordinal)))
+
+		statuses[ordinal] = memberStatusResponse(memberID, 0xc, 121)
+		_, err = svc.HandleFailed(ctx, cluster)
+		require.NoError(t, err)
+		if ordinal == 0 {
+			require.Len(t, etcdMembers.members, 3)
+			assert.Contains(t, <-recorder.Events, "Added member secure-1 back to the recovered cluster")
+			_, err = svc.HandleFailed(ctx, cluster)
+			require.NoError(t, err)
+		}
+	}
+
+	assert.Equal(t, etcdv1alpha1.EtcdClusterPhaseRunning, cluster.Status.Phase)
+	assert.Nil(t, cluster.Status.Recovery)
+	assert.True(t, meta.IsStatusConditionTrue(cluster.Status.Conditions, utils.ConditionTypeReady))
+	assert.Contains(t, <-recorder.Events, "Cluster recovered with policy ForceNewCluster")
+}
+
+// TestSurvivingMember 测试选择 raft 索引最高的可达成员
+func TestSurvivingMember(t *testing.T) {
+	_, ok := survivingMember(&HealthStatus{Members: []MemberHealth{{Status: MemberHealthUnreachable}}})
+	assert.False(t, ok)
+
+	ordinal, ok := survivingMember(&HealthStatus{Members: []MemberHealth{
+		{Status: MemberHealthUnhealthy, RaftIndex: 100},
+		{Status: MemberHealthUnreachable, RaftIndex: 300},
+		{Status: MemberHealthUnhealthy, RaftIndex: 200},
+	}})
+	assert.True(t, ok)
+	assert.Equal(t, int32(2), ordinal)
+}
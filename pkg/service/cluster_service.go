@@ -34,6 +34,8 @@ import (
 	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
 	"github.com/your-org/etcd-k8s-operator/pkg/client"
 	etcdclient "github.com/your-org/etcd-k8s-operator/pkg/etcd"
+	"github.com/your-org/etcd-k8s-operator/pkg/k8s"
+	"github.com/your-org/etcd-k8s-operator/pkg/metrics"
 	resourcepkg "github.com/your-org/etcd-k8s-operator/pkg/resource"
 	"github.com/your-org/etcd-k8s-operator/pkg/utils"
 )
@@ -95,6 +97,17 @@ func (s *clusterService) ValidateClusterSpec(cluster *etcdv1alpha1.EtcdCluster)
 		return fmt.Errorf("storage size cannot be zero")
 	}
 
+	// 续期窗口不短于证书有效期时，新签发的证书会立即再次进入续期窗口
+	if renewBefore := cluster.Spec.Security.TLS.RenewBefore; renewBefore != nil &&
+		(renewBefore.Duration <= 0 || renewBefore.Duration >= utils.DefaultCertificateValidity) {
+		return fmt.Errorf("tls renewBefore must be positive and shorter than the certificate validity %s", utils.DefaultCertificateValidity)
+	}
+
+	// 验证 etcd 调优配置
+	if err := k8s.ValidateConfig(&cluster.Spec.Config); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -110,6 +123,25 @@ func (s *clusterService) InitializeCluster(ctx context.Context, cluster *etcdv1a
 
 	// 设置初始状态
 	cluster.Status.Phase = etcdv1alpha1.EtcdClusterPhaseCreating
+	// 记录创建时的传输安全配置，之后成员地址的协议以状态为准
+	if cluster.Status.TLS == nil {
+		cluster.Status.TLS = k8s.DesiredTLSStatus(cluster)
+	}
+	// 之后 spec 中的版本变化通过升级流程生效
+	if cluster.Status.CurrentVersion == "" {
+		cluster.Status.CurrentVersion = cluster.Spec.Version
+	}
+	// 之后 spec 中的自动压缩设置变化通过逐个重启成员生效
+	if cluster.Status.Maintenance == nil {
+		cluster.Status.Maintenance = &etcdv1alpha1.EtcdMaintenanceStatus{
+			AutoCompactionMode:      cluster.Spec.Maintenance.AutoCompactionMode,
+			AutoCompactionRetention: cluster.Spec.Maintenance.AutoCompactionRetention,
+		}
+	}
+	// 之后 spec 中的调优配置变化通过逐个重启成员生效
+	if cluster.Status.Config == nil {
+		cluster.Status.Config = cluster.Spec.Config.DeepCopy()
+	}
 	s.setCondition(cluster, utils.ConditionTypeProgressing, metav1.ConditionTrue, utils.ReasonCreating, "Starting cluster creation")
 
 	if err := s.UpdateClusterStatus(ctx, cluster); err != nil {
@@ -124,18 +156,37 @@ func (s *clusterService) InitializeCluster(ctx context.Context, cluster *etcdv1a
 func (s *clusterService) CreateCluster(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster) (ctrl.Result, error) {
 	logger := log.FromContext(ctx)
 
-	// 1. 创建必要的 Kubernetes 资源
+	// 1. 首次创建时先准备证书并记录证书版本，StatefulSet 创建时即带上该版本，避免集群运行后立即重启成员
+	recorded, err := s.recordInitialCertificates(ctx, cluster)
+	if err != nil {
+		if isInvalidCertificate(err) {
+			return s.waitForCertificates(ctx, cluster, err)
+		}
+		logger.Error(err, "Failed to prepare certificates")
+		return s.updateStatusWithError(ctx, cluster, etcdv1alpha1.EtcdClusterPhaseFailed, err)
+	}
+
+	// 2. 创建必要的 Kubernetes 资源
 	if err := s.resourceManager.EnsureAllResources(ctx, cluster); err != nil {
+		if isInvalidCertificate(err) {
+			return s.waitForCertificates(ctx, cluster, err)
+		}
 		logger.Error(err, "Failed to ensure resources")
 		return s.updateStatusWithError(ctx, cluster, etcdv1alpha1.EtcdClusterPhaseFailed, err)
 	}
+	if setTLSCondition(cluster, nil) || recorded {
+		if err := s.UpdateClusterStatus(ctx, cluster); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
 
-	// 2. 对于多节点集群，使用渐进式启动策略
-	if cluster.Spec.Size > 1 {
+	// 3. 对于多节点集群，使用渐进式启动策略
+	// 从快照恢复的集群每个成员的数据目录中已包含完整的成员列表，所有成员同时启动
+	if cluster.Spec.Size > 1 && !isRestoredCluster(cluster) {
 		return s.handleMultiNodeClusterCreation(ctx, cluster)
 	}
 
-	// 3. 单节点集群的处理逻辑
+	// 4. 单节点集群和恢复集群的处理逻辑
 	ready, err := s.IsClusterReady(ctx, cluster)
 	if err != nil {
 		logger.Error(err, "Failed to check cluster readiness")
@@ -152,7 +203,7 @@ func (s *clusterService) CreateCluster(ctx context.Context, cluster *etcdv1alpha
 			return ctrl.Result{}, err
 		}
 
-		logger.Info("Single-node cluster is ready, transitioning to running phase")
+		logger.Info("Cluster is ready, transitioning to running phase", "size", cluster.Spec.Size)
 		return ctrl.Result{}, nil
 	}
 
@@ -210,6 +261,7 @@ func (s *clusterService) DeleteCluster(ctx context.Context, cluster *etcdv1alpha
 		logger.Error(err, "Failed to cleanup resources")
 		return ctrl.Result{}, err
 	}
+	metrics.DeleteClusterMetrics(cluster)
 
 	logger.Info("Cluster deletion completed")
 	return ctrl.Result{}, nil
@@ -338,7 +390,7 @@ func (s *clusterService) addEtcdMember(ctx context.Context, cluster *etcdv1alpha
 
 	// 创建 etcd 客户端
 	logger.Info("Creating etcd client for member addition")
-	etcdClient, err := s.createEtcdClient(cluster)
+	etcdClient, err := s.createEtcdClient(ctx, cluster)
 	if err != nil {
 		logger.Error(err, "Failed to create etcd client")
 		return fmt.Errorf("failed to create etcd client: %w", err)
@@ -347,8 +399,7 @@ func (s *clusterService) addEtcdMember(ctx context.Context, cluster *etcdv1alpha
 
 	// 构建新成员的信息
 	memberName := fmt.Sprintf("%s-%d", cluster.Name, memberIndex)
-	peerURL := fmt.Sprintf("http://%s.%s-peer.%s.svc.cluster.local:%d",
-		memberName, cluster.Name, cluster.Namespace, utils.EtcdPeerPort)
+	peerURL := k8s.MemberPeerURL(cluster, memberIndex)
 
 	logger.Info("Checking if etcd member already exists", "name", memberName, "peerURL", peerURL)
 
@@ -381,7 +432,7 @@ func (s *clusterService) addEtcdMember(ctx context.Context, cluster *etcdv1alpha
 }
 
 // createEtcdClient creates an etcd client for the cluster
-func (s *clusterService) createEtcdClient(cluster *etcdv1alpha1.EtcdCluster) (*etcdclient.Client, error) {
+func (s *clusterService) createEtcdClient(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster) (*etcdclient.Client, error) {
 	var endpoints []string
 
 	// 检查是否运行在集群内部
@@ -392,10 +443,7 @@ func (s *clusterService) createEtcdClient(cluster *etcdv1alpha1.EtcdCluster) (*e
 		// 运行在集群内部，使用集群内端点
 		endpoints = cluster.Status.ClientEndpoints
 		if len(endpoints) == 0 {
-			endpoints = []string{
-				fmt.Sprintf("http://%s-0.%s-peer.%s.svc.cluster.local:%d",
-					cluster.Name, cluster.Name, cluster.Namespace, utils.EtcdClientPort),
-			}
+			endpoints = []string{k8s.MemberClientURL(cluster, 0)}
 		}
 	} else {
 		// 运行在集群外部，使用 NodePort 服务连接到第一个节点
@@ -405,11 +453,11 @@ func (s *clusterService) createEtcdClient(cluster *etcdv1alpha1.EtcdCluster) (*e
 			log.Log.Error(err, "Failed to get Kind node IP, falling back to localhost")
 			nodeIP = "localhost"
 		}
-		endpoints = []string{fmt.Sprintf("http://%s:30379", nodeIP)}
+		endpoints = []string{fmt.Sprintf("%s://%s:30379", k8s.ClientScheme(cluster), nodeIP)}
 	}
 
 	log.Log.Info("Creating etcd client", "endpoints", endpoints)
-	return etcdclient.NewClient(endpoints)
+	return newEtcdClient(ctx, s.k8sClient.GetClient(), cluster, endpoints)
 }
 
 // isRunningInCluster checks if the operator is running inside the cluster
@@ -456,6 +504,42 @@ func (s *clusterService) updateStatusWithError(ctx context.Context, cluster *etc
 	return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
 }
 
+// recordInitialCertificates 集群需要证书且尚未记录证书版本时准备证书，并将到期时间和版本写入状态。
+// 返回状态是否发生变化
+func (s *clusterService) recordInitialCertificates(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster) (bool, error) {
+	if !k8s.TLSRequired(cluster) || cluster.Status.TLS == nil || cluster.Status.TLS.Revision != "" {
+		return false, nil
+	}
+	if err := s.resourceManager.Certificate().Ensure(ctx, cluster); err != nil {
+		return false, err
+	}
+	info, err := s.resourceManager.Certificate().Inspect(ctx, cluster)
+	if err != nil {
+		return false, err
+	}
+	recordCertificates(cluster, info)
+	cluster.Status.TLS.Revision = info.Revision
+	return true, nil
+}
+
+// waitForCertificates 用户提供的证书不可用时设置 TLSReady=False 并等待修正。
+// 不创建成员也不进入失败状态，证书 Secret 更新后会重新调谐
+func (s *clusterService) waitForCertificates(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster, certErr error) (ctrl.Result, error) {
+	log.FromContext(ctx).Info("Cluster certificates are not usable, waiting for them to be fixed", "reason", certErr.Error())
+	if setTLSCondition(cluster, certErr) {
+		s.k8sClient.RecordEvent(cluster, corev1.EventTypeWarning, utils.EventReasonInvalidCertificate, certErr.Error())
+		if err := s.UpdateClusterStatus(ctx, cluster); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	return ctrl.Result{RequeueAfter: utils.DefaultRequeueInterval}, nil
+}
+
+// isRestoredCluster 判断集群是否由 EtcdRestore 从快照恢复创建
+func isRestoredCluster(cluster *etcdv1alpha1.EtcdCluster) bool {
+	return cluster.Annotations[utils.AnnotationRestoredFrom] != ""
+}
+
 // setCondition 设置条件
 func (s *clusterService) setCondition(cluster *etcdv1alpha1.EtcdCluster, conditionType string, status metav1.ConditionStatus, reason, message string) {
 	// TODO: 实现条件设置逻辑
@@ -0,0 +1,94 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+	"github.com/your-org/etcd-k8s-operator/pkg/k8s"
+	"github.com/your-org/etcd-k8s-operator/pkg/metrics"
+	"github.com/your-org/etcd-k8s-operator/pkg/resource"
+	"github.com/your-org/etcd-k8s-operator/pkg/utils"
+)
+
+// isInvalidCertificate 判断错误是否由用户提供的证书不可用引起
+func isInvalidCertificate(err error) bool {
+	return errors.Is(err, resource.ErrInvalidCertificate)
+}
+
+// setTLSCondition 根据证书检查结果设置 TLSReady 条件，返回条件是否发生变化。
+// 集群不需要证书时移除该条件
+func setTLSCondition(cluster *etcdv1alpha1.EtcdCluster, certErr error) bool {
+	if !k8s.TLSRequired(cluster) {
+		return meta.RemoveStatusCondition(&cluster.Status.Conditions, utils.ConditionTypeTLSReady)
+	}
+
+	condition := metav1.Condition{
+		Type:               utils.ConditionTypeTLSReady,
+		Status:             metav1.ConditionTrue,
+		Reason:             utils.ReasonCertificatesReady,
+		Message:            "Cluster certificates are valid",
+		ObservedGeneration: cluster.Generation,
+	}
+	if certErr != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = utils.ReasonInvalidCertificate
+		condition.Message = certErr.Error()
+	}
+	return meta.SetStatusCondition(&cluster.Status.Conditions, condition)
+}
+
+// recordCertificates 将证书到期时间写入状态并更新到期时间指标，返回状态是否发生变化
+func recordCertificates(cluster *etcdv1alpha1.EtcdCluster, info *resource.CertificateInfo) bool {
+	metrics.SetCertificateExpiry(cluster, info.Certificates)
+	if cluster.Status.TLS == nil || equality.Semantic.DeepEqual(cluster.Status.TLS.Certificates, info.Certificates) {
+		return false
+	}
+	cluster.Status.TLS.Certificates = info.Certificates
+	return true
+}
+
+// setTLSMigrationCondition 根据传输安全迁移阶段设置 TLSMigration 条件，返回条件是否发生变化
+func setTLSMigrationCondition(cluster *etcdv1alpha1.EtcdCluster) bool {
+	condition := metav1.Condition{
+		Type:               utils.ConditionTypeTLSMigration,
+		Status:             metav1.ConditionTrue,
+		Reason:             string(k8s.TLSMigration(cluster)),
+		ObservedGeneration: cluster.Generation,
+	}
+	switch k8s.TLSMigration(cluster) {
+	case etcdv1alpha1.EtcdTLSMigrationPeerTrust:
+		condition.Message = "Restarting members with peer certificates mounted"
+	case etcdv1alpha1.EtcdTLSMigrationPeerSwitch:
+		condition.Message = fmt.Sprintf("Restarting members to switch peer traffic to %s", k8s.PeerScheme(cluster))
+	case etcdv1alpha1.EtcdTLSMigrationClientDual:
+		condition.Message = "Restarting members to serve clients over both http and https"
+	case etcdv1alpha1.EtcdTLSMigrationClientSwitch:
+		condition.Message = fmt.Sprintf("Restarting members to advertise %s client URLs", k8s.ClientScheme(cluster))
+	default:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = utils.ReasonTLSMigrationCompleted
+		condition.Message = "Members serve with the transport security the spec requests"
+	}
+	return meta.SetStatusCondition(&cluster.Status.Conditions, condition)
+}
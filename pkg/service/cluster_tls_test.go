@@ -0,0 +1,132 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"crypto/x509"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+	"github.com/your-org/etcd-k8s-operator/pkg/client"
+	"github.com/your-org/etcd-k8s-operator/pkg/k8s"
+	"github.com/your-org/etcd-k8s-operator/pkg/pki"
+	resourcepkg "github.com/your-org/etcd-k8s-operator/pkg/resource"
+	"github.com/your-org/etcd-k8s-operator/pkg/utils"
+)
+
+// newCertificateSecret 使用 ca 签发覆盖 hosts 的证书，并保存为 cert-manager 格式的 Secret
+func newCertificateSecret(t *testing.T, ca *pki.KeyPair, name string, hosts ...string) *corev1.Secret {
+	cert, err := ca.Issue(pki.CertificateRequest{
+		CommonName: name,
+		DNSNames:   hosts,
+		Usages:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		Validity:   time.Hour,
+	})
+	require.NoError(t, err)
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Type:       corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       cert.CertPEM,
+			corev1.TLSPrivateKeyKey: cert.KeyPEM,
+			utils.SecretKeyCACert:   ca.CertPEM,
+		},
+	}
+}
+
+// TestCreateClusterWithExternalCertificates 测试用户证书不满足要求时集群等待修正而不是创建成员，修正后继续创建
+func TestCreateClusterWithExternalCertificates(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, etcdv1alpha1.AddToScheme(scheme))
+	c := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&etcdv1alpha1.EtcdCluster{}).Build()
+	k8sClient := client.NewKubernetesClient(c, record.NewFakeRecorder(10))
+	svc := NewClusterService(k8sClient, resourcepkg.NewResourceManager(k8sClient, k8s.StatefulSetOptions{}))
+	ctx := context.Background()
+
+	ca, err := pki.NewCA("corp-ca", 24*time.Hour)
+	require.NoError(t, err)
+	// 证书缺少第三个成员
+	secret := newCertificateSecret(t, ca, "corp-etcd",
+		"secure-0.secure-peer.default.svc.cluster.local", "secure-1.secure-peer.default.svc.cluster.local")
+	require.NoError(t, c.Create(ctx, secret))
+
+	cluster := &etcdv1alpha1.EtcdCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "secure", Namespace: "default"},
+		Spec: etcdv1alpha1.EtcdClusterSpec{
+			Size:       3,
+			Version:    "v3.5.21",
+			Repository: "quay.io/coreos/etcd",
+			Storage:    etcdv1alpha1.EtcdStorageSpec{Size: resource.MustParse("1Gi")},
+			Security: etcdv1alpha1.EtcdSecuritySpec{TLS: etcdv1alpha1.EtcdTLSSpec{
+				Enabled: true, PeerTLSEnabled: true, CertificateSecret: "corp-etcd",
+			}},
+		},
+	}
+	require.NoError(t, c.Create(ctx, cluster))
+	_, err = svc.InitializeCluster(ctx, cluster)
+	require.NoError(t, err)
+
+	result, err := svc.CreateCluster(ctx, cluster)
+	require.NoError(t, err)
+	assert.NotZero(t, result.RequeueAfter)
+	assert.Equal(t, etcdv1alpha1.EtcdClusterPhaseCreating, cluster.Status.Phase)
+	condition := meta.FindStatusCondition(cluster.Status.Conditions, utils.ConditionTypeTLSReady)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, utils.ReasonInvalidCertificate, condition.Reason)
+	assert.Contains(t, condition.Message, "secure-2.secure-peer.default.svc.cluster.local")
+
+	err = c.Get(ctx, types.NamespacedName{Name: "secure", Namespace: "default"}, &appsv1.StatefulSet{})
+	assert.True(t, errors.IsNotFound(err), "members must not start with unusable certificates")
+
+	// cert-manager 重新签发后继续创建
+	fixed := newCertificateSecret(t, ca, "corp-etcd", "*.secure-peer.default.svc.cluster.local")
+	secret.Data = fixed.Data
+	require.NoError(t, c.Update(ctx, secret))
+
+	_, err = svc.CreateCluster(ctx, cluster)
+	require.NoError(t, err)
+	condition = meta.FindStatusCondition(cluster.Status.Conditions, utils.ConditionTypeTLSReady)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+
+	sts := &appsv1.StatefulSet{}
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Name: "secure", Namespace: "default"}, sts))
+	var projected []string
+	for _, v := range sts.Spec.Template.Spec.Volumes {
+		if v.Projected != nil {
+			projected = append(projected, v.Name+"="+v.Projected.Sources[0].Secret.Name)
+		}
+	}
+	assert.Equal(t, []string{"peer-tls=corp-etcd"}, projected)
+}
@@ -0,0 +1,554 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilversion "k8s.io/apimachinery/pkg/util/version"
+	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+	etcdclient "github.com/your-org/etcd-k8s-operator/pkg/etcd"
+	"github.com/your-org/etcd-k8s-operator/pkg/k8s"
+	"github.com/your-org/etcd-k8s-operator/pkg/utils"
+)
+
+// checkUpgrade 在 spec 中的版本与成员运行的版本不同时校验版本变化，并进入升级阶段。
+// 返回非 nil 的 Result 表示本轮调谐由升级接管
+func (s *scalingService) checkUpgrade(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster) (*ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	if cluster.Status.CurrentVersion == "" {
+		// 早期创建的集群没有记录版本，成员按当时 spec 中的版本运行
+		cluster.Status.CurrentVersion = cluster.Spec.Version
+		if err := s.k8sClient.Status().Update(ctx, cluster); err != nil {
+			return nil, err
+		}
+	}
+	from, to := cluster.Status.CurrentVersion, cluster.Spec.Version
+	if sameVersion(from, to) {
+		// 撤销无法执行的版本变化后去掉之前的提示
+		if current := meta.FindStatusCondition(cluster.Status.Conditions, utils.ConditionTypeVersionUpgrade); current != nil &&
+			current.Status == metav1.ConditionFalse &&
+			current.Reason != utils.ReasonUpgradeCompleted && current.Reason != utils.ReasonDowngradeCompleted {
+			meta.RemoveStatusCondition(&cluster.Status.Conditions, utils.ConditionTypeVersionUpgrade)
+			return nil, s.k8sClient.Status().Update(ctx, cluster)
+		}
+		return nil, nil
+	}
+
+	downgrade, err := validateUpgrade(from, to)
+	if err != nil {
+		logger.Info("Ignoring unsupported version change", "from", from, "to", to, "reason", err.Error())
+		return nil, s.updateUpgradeCondition(ctx, cluster, metav1.ConditionFalse, utils.ReasonUnsupportedUpgrade, err.Error())
+	}
+	if !cluster.Spec.Upgrade.SkipBackup {
+		template, err := s.upgradeBackupTemplate(ctx, cluster)
+		if err != nil {
+			return nil, err
+		}
+		if template == nil {
+			return nil, s.updateUpgradeCondition(ctx, cluster, metav1.ConditionFalse, utils.ReasonMissingBackupTemplate,
+				"No EtcdBackup to take the pre-upgrade backup with, set spec.upgrade.backupTemplate or spec.upgrade.skipBackup")
+		}
+	}
+
+	logger.Info("Starting etcd version change", "from", from, "to", to, "downgrade", downgrade)
+	cluster.Status.Phase = etcdv1alpha1.EtcdClusterPhaseUpgrading
+	cluster.Status.Upgrade = &etcdv1alpha1.EtcdUpgradeStatus{FromVersion: from, ToVersion: to, Downgrade: downgrade}
+	reason, message := upgradeProgress(cluster.Status.Upgrade)
+	setUpgradeCondition(cluster, metav1.ConditionTrue, reason, message)
+	if err := s.k8sClient.Status().Update(ctx, cluster); err != nil {
+		return nil, err
+	}
+	return &ctrl.Result{Requeue: true}, nil
+}
+
+// HandleUpgrading 处理升级状态的集群：先完成升级前备份，再逐个重启成员，follower 先升级、leader 最后升级。
+// 每个成员重启后确认它以新版本重新加入集群且集群健康，才放开下一个成员。
+// 降级时在重启成员之前通过 etcd 的降级接口降低集群版本，并等待成员的存储版本随之降低
+func (s *scalingService) HandleUpgrading(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	up := cluster.Status.Upgrade
+	if up == nil {
+		logger.Info("No upgrade recorded, returning to running phase")
+		cluster.Status.Phase = etcdv1alpha1.EtcdClusterPhaseRunning
+		if err := s.k8sClient.Status().Update(ctx, cluster); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	// 1. 升级前备份
+	if !cluster.Spec.Upgrade.SkipBackup {
+		done, err := s.ensureUpgradeBackup(ctx, cluster)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !done {
+			return ctrl.Result{RequeueAfter: utils.DefaultRequeueInterval}, nil
+		}
+	}
+
+	sts := &appsv1.StatefulSet{}
+	if err := s.k8sClient.Get(ctx, types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, sts); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// 2. 用分区挡住全部成员后切换到新版本的镜像
+	if !rollPending(sts) {
+		if s.resourceManager.StatefulSet().NeedsUpdate(sts, s.resourceManager.StatefulSet().Desired(cluster, *sts.Spec.Replicas)) {
+			if up.Downgrade {
+				ready, err := s.prepareDowngrade(ctx, cluster)
+				if err != nil {
+					return ctrl.Result{}, err
+				}
+				if !ready {
+					return ctrl.Result{RequeueAfter: utils.DefaultRequeueInterval}, nil
+				}
+			}
+			logger.Info("Switching members to the new version", "version", up.ToVersion)
+			if err := s.startRoll(ctx, cluster, sts); err != nil {
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{RequeueAfter: utils.DefaultRequeueInterval}, nil
+		}
+		return s.completeUpgrade(ctx, cluster)
+	}
+
+	// 3. 逐个升级成员
+	done, err := s.upgradeMembers(ctx, cluster, sts)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !done {
+		return ctrl.Result{RequeueAfter: utils.DefaultRequeueInterval}, nil
+	}
+	return s.completeUpgrade(ctx, cluster)
+}
+
+// completeUpgrade 记录成员运行的新版本并回到运行状态。
+// 降级时全部成员运行目标版本后 etcd 自动结束降级，不需要额外调用
+func (s *scalingService) completeUpgrade(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster) (ctrl.Result, error) {
+	up := cluster.Status.Upgrade
+	log.FromContext(ctx).Info("etcd version change completed", "from", up.FromVersion, "to", up.ToVersion, "downgrade", up.Downgrade)
+
+	cluster.Status.CurrentVersion = up.ToVersion
+	cluster.Status.Upgrade = nil
+	cluster.Status.Phase = etcdv1alpha1.EtcdClusterPhaseRunning
+	if up.Downgrade {
+		setUpgradeCondition(cluster, metav1.ConditionFalse, utils.ReasonDowngradeCompleted,
+			fmt.Sprintf("Downgraded from %s to %s", up.FromVersion, up.ToVersion))
+	} else {
+		setUpgradeCondition(cluster, metav1.ConditionFalse, utils.ReasonUpgradeCompleted,
+			fmt.Sprintf("Upgraded from %s to %s", up.FromVersion, up.ToVersion))
+	}
+	setUpgradeDegraded(cluster, nil)
+	if err := s.k8sClient.Status().Update(ctx, cluster); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{Requeue: true}, nil
+}
+
+// prepareDowngrade 通过 etcd 的降级接口把集群版本降到目标次版本，并等待各成员的存储版本降到目标次版本。
+// 返回是否可以开始重启成员；etcd 拒绝降级时暂停降级并设置 VersionUpgrade 条件
+func (s *scalingService) prepareDowngrade(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster) (bool, error) {
+	logger := log.FromContext(ctx)
+	up := cluster.Status.Upgrade
+	target, err := minorVersion(up.ToVersion)
+	if err != nil {
+		return false, err
+	}
+
+	etcdClient, err := s.createEtcdClient(ctx, cluster)
+	if err != nil {
+		return false, err
+	}
+	defer etcdClient.Close()
+
+	// 集群外运行时只能访问 NodePort 后的成员
+	endpoints := etcdClient.Endpoints()
+	if s.isRunningInCluster() {
+		endpoints = make([]string, 0, cluster.Spec.Size)
+		for i := int32(0); i < cluster.Spec.Size; i++ {
+			endpoints = append(endpoints, k8s.MemberClientURL(cluster, i))
+		}
+	}
+	versions := make([]*etcdclient.Versions, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		v, err := etcdClient.GetVersions(ctx, endpoint)
+		if err != nil {
+			logger.Info("Waiting for member versions before downgrading", "reason", err.Error())
+			return false, nil
+		}
+		versions = append(versions, v)
+	}
+
+	enabled, ready := downgradeProgress(target, versions)
+	if !enabled {
+		if err := etcdClient.DowngradeValidate(ctx, target); err != nil {
+			logger.Info("etcd rejected the downgrade", "version", target, "reason", err.Error())
+			return false, s.updateUpgradeCondition(ctx, cluster, metav1.ConditionTrue, utils.ReasonDowngradeRejected,
+				fmt.Sprintf("Downgrade to %s rejected by etcd: %v", up.ToVersion, err))
+		}
+		if err := etcdClient.DowngradeEnable(ctx, target); err != nil {
+			return false, err
+		}
+		logger.Info("Enabled etcd downgrade", "version", target)
+		reason, message := upgradeProgress(up)
+		return false, s.updateUpgradeCondition(ctx, cluster, metav1.ConditionTrue, reason, message)
+	}
+	if !ready {
+		logger.Info("Waiting for the storage version to drop before downgrading members", "version", target)
+		return false, nil
+	}
+	return true, nil
+}
+
+// downgradeProgress 根据成员报告的版本判断集群版本是否已经降到目标次版本（降级已开启），
+// 以及成员的存储版本是否都已降到目标次版本。3.6 之前的成员不报告存储版本，只看集群版本
+func downgradeProgress(target string, versions []*etcdclient.Versions) (enabled, ready bool) {
+	enabled, ready = true, true
+	for _, v := range versions {
+		if minor, err := minorVersion(v.Cluster); err != nil || minor != target {
+			enabled = false
+		}
+		if v.Storage == "" {
+			continue
+		}
+		if minor, err := minorVersion(v.Storage); err != nil || minor != target {
+			ready = false
+		}
+	}
+	return enabled, enabled && ready
+}
+
+// upgradeMembers 逐个放开成员按新版本重启。上一个成员以新版本就绪、集群健康后，
+// 先把它持有的 leadership 转移出去再放开下一个成员。返回全部成员是否已经升级
+func (s *scalingService) upgradeMembers(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster, sts *appsv1.StatefulSet) (bool, error) {
+	logger := log.FromContext(ctx)
+	if sts.Status.ObservedGeneration < sts.Generation {
+		logger.Info("Waiting for StatefulSet controller to observe the new version")
+		return false, nil
+	}
+
+	partition := rollPartition(sts)
+	if partition < *sts.Spec.Replicas {
+		updated, err := s.memberUpdated(ctx, cluster, sts, partition)
+		if err != nil {
+			return false, err
+		}
+		if !updated {
+			return false, s.upgradeStalled(ctx, cluster,
+				fmt.Errorf("member %s is not ready on the new version", k8s.MemberName(cluster, partition)))
+		}
+		if err := s.checkMemberVersion(ctx, cluster, partition); err != nil {
+			return false, s.upgradeStalled(ctx, cluster, err)
+		}
+	}
+	if err := s.checkMembersHealthy(ctx, cluster); err != nil {
+		return false, s.upgradeStalled(ctx, cluster, err)
+	}
+
+	if partition == 0 {
+		// 最后一个成员已经恢复，等待 StatefulSet 控制器记录新的当前版本
+		return sts.Status.CurrentRevision == sts.Status.UpdateRevision, nil
+	}
+
+	next := partition - 1
+	if err := s.transferLeadership(ctx, cluster, next); err != nil {
+		return false, err
+	}
+	now := metav1.Now()
+	cluster.Status.Upgrade.MemberRestartTime = &now
+	setUpgradeDegraded(cluster, nil)
+	if err := s.k8sClient.Status().Update(ctx, cluster); err != nil {
+		return false, err
+	}
+	logger.Info("Upgrading member", "member", k8s.MemberName(cluster, next), "version", cluster.Status.Upgrade.ToVersion)
+	return false, s.setPartition(ctx, sts, next)
+}
+
+// upgradeStalled 记录升级等待的原因。放开重启的成员超过 DefaultUpgradeMemberTimeout 仍未以新版本重新加入集群时，
+// 设置 Degraded 条件；在该成员恢复之前不会放开后续成员
+func (s *scalingService) upgradeStalled(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster, cause error) error {
+	log.FromContext(ctx).Info("Waiting for upgraded member to rejoin the cluster", "reason", cause.Error())
+
+	restarted := cluster.Status.Upgrade.MemberRestartTime
+	if restarted == nil || time.Since(restarted.Time) < utils.DefaultUpgradeMemberTimeout {
+		return nil
+	}
+	if !setUpgradeDegraded(cluster, cause) {
+		return nil
+	}
+	return s.k8sClient.Status().Update(ctx, cluster)
+}
+
+// checkMemberVersion 确认成员报告的版本与升级的目标版本一致。集群外运行时无法直连各成员，跳过检查
+func (s *scalingService) checkMemberVersion(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster, ordinal int32) error {
+	if !s.isRunningInCluster() {
+		return nil
+	}
+	endpoint := k8s.MemberClientURL(cluster, ordinal)
+	etcdClient, err := newEtcdClient(ctx, s.k8sClient, cluster, []string{endpoint})
+	if err != nil {
+		return err
+	}
+	defer etcdClient.Close()
+
+	resp, err := etcdClient.MemberStatus(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+	if want := cluster.Status.Upgrade.ToVersion; !sameVersion(resp.Version, want) {
+		return fmt.Errorf("member %s runs etcd %s, expected %s", k8s.MemberName(cluster, ordinal), resp.Version, want)
+	}
+	return nil
+}
+
+// transferLeadership 在放开成员重启前把它持有的 leadership 转移出去：序号大于 0 的成员交给最后升级的成员 0，
+// 成员 0 交给已经升级的成员 1，从而 follower 先升级、leader 最后升级。集群外运行时无法直连各成员，跳过转移
+func (s *scalingService) transferLeadership(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster, ordinal int32) error {
+	if !s.isRunningInCluster() || cluster.Spec.Size < 2 {
+		return nil
+	}
+	endpoint := k8s.MemberClientURL(cluster, ordinal)
+	etcdClient, err := newEtcdClient(ctx, s.k8sClient, cluster, []string{endpoint})
+	if err != nil {
+		return err
+	}
+	defer etcdClient.Close()
+
+	resp, err := etcdClient.MemberStatus(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+	if resp.Header.MemberId != resp.Leader {
+		return nil
+	}
+
+	transferee := k8s.MemberName(cluster, 0)
+	if ordinal == 0 {
+		transferee = k8s.MemberName(cluster, 1)
+	}
+	members, err := etcdClient.GetClusterMembers(ctx)
+	if err != nil {
+		return err
+	}
+	for _, member := range members {
+		if member.Name != transferee {
+			continue
+		}
+		var memberID uint64
+		if _, err := fmt.Sscanf(member.ID, "%x", &memberID); err != nil {
+			return fmt.Errorf("failed to parse member ID %s: %w", member.ID, err)
+		}
+		log.FromContext(ctx).Info("Transferring leadership before upgrading member",
+			"from", k8s.MemberName(cluster, ordinal), "to", transferee)
+		return etcdClient.TransferLeadership(ctx, memberID)
+	}
+	return fmt.Errorf("member %s not found in the cluster", transferee)
+}
+
+// ensureUpgradeBackup 创建升级前备份，返回备份是否已经完成。
+// 备份失败时暂停升级，删除失败的 EtcdBackup 后按模板重新创建
+func (s *scalingService) ensureUpgradeBackup(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster) (bool, error) {
+	up := cluster.Status.Upgrade
+	if up.BackupName == "" {
+		// 先记录名称再创建，重复调谐不会创建多个备份
+		up.BackupName = fmt.Sprintf("%s-pre-upgrade-%d", cluster.Name, time.Now().Unix())
+		return false, s.k8sClient.Status().Update(ctx, cluster)
+	}
+
+	backup := &etcdv1alpha1.EtcdBackup{}
+	err := s.k8sClient.Get(ctx, types.NamespacedName{Name: up.BackupName, Namespace: cluster.Namespace}, backup)
+	if errors.IsNotFound(err) {
+		template, err := s.upgradeBackupTemplate(ctx, cluster)
+		if err != nil {
+			return false, err
+		}
+		if template == nil {
+			return false, s.updateUpgradeCondition(ctx, cluster, metav1.ConditionTrue, utils.ReasonMissingBackupTemplate,
+				"No EtcdBackup to take the pre-upgrade backup with, set spec.upgrade.backupTemplate or spec.upgrade.skipBackup")
+		}
+		log.FromContext(ctx).Info("Taking backup before upgrading", "backup", up.BackupName, "template", template.Name)
+		if err := s.k8sClient.Create(ctx, newUpgradeBackup(cluster, template, up.BackupName)); err != nil && !errors.IsAlreadyExists(err) {
+			return false, fmt.Errorf("failed to create pre-upgrade backup %s: %w", up.BackupName, err)
+		}
+		return false, s.updateUpgradeCondition(ctx, cluster, metav1.ConditionTrue, utils.ReasonBackupInProgress,
+			fmt.Sprintf("Taking backup %s before upgrading from %s to %s", up.BackupName, up.FromVersion, up.ToVersion))
+	} else if err != nil {
+		return false, err
+	}
+
+	switch backup.Status.Phase {
+	case etcdv1alpha1.EtcdBackupPhaseCompleted:
+		reason, message := upgradeProgress(up)
+		return true, s.updateUpgradeCondition(ctx, cluster, metav1.ConditionTrue, reason, message)
+	case etcdv1alpha1.EtcdBackupPhaseFailed:
+		return false, s.updateUpgradeCondition(ctx, cluster, metav1.ConditionTrue, utils.ReasonBackupFailed,
+			fmt.Sprintf("Pre-upgrade backup %s failed, delete it to retry", up.BackupName))
+	default:
+		return false, nil
+	}
+}
+
+// upgradeBackupTemplate 返回升级前备份使用的 EtcdBackup：spec 指定的备份，或者集群命名空间中备份该集群的定时备份
+func (s *scalingService) upgradeBackupTemplate(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster) (*etcdv1alpha1.EtcdBackup, error) {
+	if name := cluster.Spec.Upgrade.BackupTemplate; name != "" {
+		template := &etcdv1alpha1.EtcdBackup{}
+		err := s.k8sClient.Get(ctx, types.NamespacedName{Name: name, Namespace: cluster.Namespace}, template)
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return template, err
+	}
+
+	list := &etcdv1alpha1.EtcdBackupList{}
+	if err := s.k8sClient.List(ctx, list, ctrlclient.InNamespace(cluster.Namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list backups: %w", err)
+	}
+	for i := range list.Items {
+		b := &list.Items[i]
+		if b.Spec.Schedule != "" && b.Spec.ClusterName == cluster.Name &&
+			(b.Spec.ClusterNamespace == "" || b.Spec.ClusterNamespace == cluster.Namespace) {
+			return b, nil
+		}
+	}
+	return nil, nil
+}
+
+// newUpgradeBackup 按模板的存储配置创建一次性备份。备份不归集群所有，集群删除后仍可用于恢复
+func newUpgradeBackup(cluster *etcdv1alpha1.EtcdCluster, template *etcdv1alpha1.EtcdBackup, name string) *etcdv1alpha1.EtcdBackup {
+	spec := template.Spec.DeepCopy()
+	spec.ClusterName = cluster.Name
+	spec.ClusterNamespace = cluster.Namespace
+	spec.Schedule = ""
+	spec.ConcurrencyPolicy = ""
+	spec.MissedRunPolicy = ""
+	spec.RetentionPolicy = etcdv1alpha1.EtcdRetentionPolicy{}
+
+	return &etcdv1alpha1.EtcdBackup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: cluster.Namespace,
+			Labels:    map[string]string{utils.LabelEtcdCluster: cluster.Name},
+		},
+		Spec: *spec,
+	}
+}
+
+// updateUpgradeCondition 设置 VersionUpgrade 条件，条件变化时更新集群状态
+func (s *scalingService) updateUpgradeCondition(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster, status metav1.ConditionStatus, reason, message string) error {
+	if !setUpgradeCondition(cluster, status, reason, message) {
+		return nil
+	}
+	return s.k8sClient.Status().Update(ctx, cluster)
+}
+
+// upgradeProgress 返回版本变化进行中 VersionUpgrade 条件的原因和说明
+func upgradeProgress(up *etcdv1alpha1.EtcdUpgradeStatus) (string, string) {
+	if up.Downgrade {
+		return utils.ReasonDowngrading, fmt.Sprintf("Downgrading from %s to %s", up.FromVersion, up.ToVersion)
+	}
+	return utils.ReasonUpgrading, fmt.Sprintf("Upgrading from %s to %s", up.FromVersion, up.ToVersion)
+}
+
+// setUpgradeCondition 设置 VersionUpgrade 条件，返回条件是否发生变化
+func setUpgradeCondition(cluster *etcdv1alpha1.EtcdCluster, status metav1.ConditionStatus, reason, message string) bool {
+	return meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+		Type:               utils.ConditionTypeVersionUpgrade,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: cluster.Generation,
+	})
+}
+
+// setUpgradeDegraded 成员未能以新版本重新加入集群时设置 Degraded 条件，cause 为 nil 时清除由升级设置的该条件。
+// 返回条件是否发生变化
+func setUpgradeDegraded(cluster *etcdv1alpha1.EtcdCluster, cause error) bool {
+	if cause == nil {
+		current := meta.FindStatusCondition(cluster.Status.Conditions, utils.ConditionTypeDegraded)
+		if current == nil || current.Reason != utils.ReasonUpgradeHalted || current.Status != metav1.ConditionTrue {
+			return false
+		}
+		return meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+			Type:               utils.ConditionTypeDegraded,
+			Status:             metav1.ConditionFalse,
+			Reason:             utils.ReasonHealthy,
+			Message:            "Upgraded members rejoined the cluster",
+			ObservedGeneration: cluster.Generation,
+		})
+	}
+	return meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+		Type:               utils.ConditionTypeDegraded,
+		Status:             metav1.ConditionTrue,
+		Reason:             utils.ReasonUpgradeHalted,
+		Message:            fmt.Sprintf("Upgrade to %s halted: %v", cluster.Status.Upgrade.ToVersion, cause),
+		ObservedGeneration: cluster.Generation,
+	})
+}
+
+// validateUpgrade 检查版本变化是否受支持：主版本不变，次版本每次最多增加或降低一个。
+// 返回是否需要通过 etcd 的降级接口降低次版本；同一次版本内降低补丁版本按升级的方式重启成员
+func validateUpgrade(from, to string) (bool, error) {
+	fromVersion, err := utilversion.ParseGeneric(from)
+	if err != nil {
+		return false, fmt.Errorf("invalid current version %q: %w", from, err)
+	}
+	toVersion, err := utilversion.ParseGeneric(to)
+	if err != nil {
+		return false, fmt.Errorf("invalid version %q: %w", to, err)
+	}
+
+	switch {
+	case toVersion.Major() != fromVersion.Major():
+		return false, fmt.Errorf("changing major version from %s to %s is not supported", from, to)
+	case toVersion.Minor() > fromVersion.Minor()+1:
+		return false, fmt.Errorf("upgrading from %s to %s skips a minor version, upgrade one minor version at a time", from, to)
+	case toVersion.Minor()+1 < fromVersion.Minor():
+		return false, fmt.Errorf("downgrading from %s to %s skips a minor version, downgrade one minor version at a time", from, to)
+	}
+	return toVersion.Minor() < fromVersion.Minor(), nil
+}
+
+// minorVersion 返回版本的 major.minor 部分，etcd 的集群版本和降级接口只使用这两部分
+func minorVersion(v string) (string, error) {
+	parsed, err := utilversion.ParseGeneric(v)
+	if err != nil {
+		return "", fmt.Errorf("invalid version %q: %w", v, err)
+	}
+	return fmt.Sprintf("%d.%d", parsed.Major(), parsed.Minor()), nil
+}
+
+// sameVersion 判断两个版本是否相同，忽略可选的 v 前缀
+func sameVersion(a, b string) bool {
+	return strings.TrimPrefix(a, "v") == strings.TrimPrefix(b, "v")
+}
@@ -0,0 +1,238 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+	etcdclient "github.com/your-org/etcd-k8s-operator/pkg/etcd"
+	"github.com/your-org/etcd-k8s-operator/pkg/utils"
+)
+
+// TestValidateUpgrade 测试只允许主版本不变、次版本最多增加或降低一个的版本变化
+func TestValidateUpgrade(t *testing.T) {
+	for _, tc := range []struct {
+		from, to  string
+		downgrade bool
+		err       string
+	}{
+		{from: "v3.5.21", to: "v3.5.22"},
+		{from: "3.5.21", to: "v3.6.0"},
+		{from: "v3.5.22", to: "v3.5.21"},
+		{from: "v3.6.0", to: "v3.5.21", downgrade: true},
+		{from: "v3.4.30", to: "v3.6.0", err: "one minor version at a time"},
+		{from: "v3.6.0", to: "v3.4.30", err: "downgrade one minor version at a time"},
+		{from: "v3.6.0", to: "v4.0.0", err: "major version"},
+	} {
+		downgrade, err := validateUpgrade(tc.from, tc.to)
+		if tc.err != "" {
+			assert.ErrorContains(t, err, tc.err, "%s -> %s", tc.from, tc.to)
+			continue
+		}
+		require.NoError(t, err, "%s -> %s", tc.from, tc.to)
+		assert.Equal(t, tc.downgrade, downgrade, "%s -> %s", tc.from, tc.to)
+	}
+	assert.True(t, sameVersion("3.5.21", "v3.5.21"))
+}
+
+// TestDowngradeProgress 测试降级开启后等待各成员的存储版本降到目标次版本
+func TestDowngradeProgress(t *testing.T) {
+	before := []*etcdclient.Versions{
+		{Server: "3.6.0", Cluster: "3.6.0", Storage: "3.6.0"},
+		{Server: "3.6.0", Cluster: "3.6.0", Storage: "3.6.0"},
+	}
+	enabled, ready := downgradeProgress("3.5", before)
+	assert.False(t, enabled)
+	assert.False(t, ready)
+
+	migrating := []*etcdclient.Versions{
+		{Server: "3.6.0", Cluster: "3.5.0", Storage: "3.5.0"},
+		{Server: "3.6.0", Cluster: "3.5.0", Storage: "3.6.0"},
+	}
+	enabled, ready = downgradeProgress("3.5", migrating)
+	assert.True(t, enabled)
+	assert.False(t, ready)
+
+	migrating[1].Storage = "3.5.0"
+	enabled, ready = downgradeProgress("3.5", migrating)
+	assert.True(t, enabled)
+	assert.True(t, ready)
+
+	// 3.6 之前的成员不报告存储版本
+	enabled, ready = downgradeProgress("3.4", []*etcdclient.Versions{{Server: "3.5.21", Cluster: "3.4.0"}})
+	assert.True(t, enabled)
+	assert.True(t, ready)
+}
+
+// TestCheckUpgradeStartsDowngrade 测试降低一个次版本时记录降级并设置 Downgrading 条件
+func TestCheckUpgradeStartsDowngrade(t *testing.T) {
+	c, svc, cluster := newRotationFixture(t)
+	ctx := context.Background()
+	cluster.Status.CurrentVersion = "v3.6.0"
+	require.NoError(t, c.Status().Update(ctx, cluster))
+	cluster.Spec.Version = "v3.5.21"
+	cluster.Spec.Upgrade.SkipBackup = true
+	require.NoError(t, c.Update(ctx, cluster))
+
+	result, err := svc.checkUpgrade(ctx, cluster)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, etcdv1alpha1.EtcdClusterPhaseUpgrading, cluster.Status.Phase)
+	assert.Equal(t, &etcdv1alpha1.EtcdUpgradeStatus{FromVersion: "v3.6.0", ToVersion: "v3.5.21", Downgrade: true}, cluster.Status.Upgrade)
+	condition := meta.FindStatusCondition(cluster.Status.Conditions, utils.ConditionTypeVersionUpgrade)
+	require.NotNil(t, condition)
+	assert.Equal(t, utils.ReasonDowngrading, condition.Reason)
+
+	// 完成后报告降级完成
+	_, err = svc.completeUpgrade(ctx, cluster)
+	require.NoError(t, err)
+	assert.Equal(t, "v3.5.21", cluster.Status.CurrentVersion)
+	condition = meta.FindStatusCondition(cluster.Status.Conditions, utils.ConditionTypeVersionUpgrade)
+	require.NotNil(t, condition)
+	assert.Equal(t, utils.ReasonDowngradeCompleted, condition.Reason)
+	assert.Equal(t, "Downgraded from v3.6.0 to v3.5.21", condition.Message)
+}
+
+// TestCheckUpgradeRejectsMultiMinorDowngrade 测试跨多个次版本的降级不会开始
+func TestCheckUpgradeRejectsMultiMinorDowngrade(t *testing.T) {
+	c, svc, cluster := newRotationFixture(t)
+	ctx := context.Background()
+	cluster.Status.CurrentVersion = "v3.6.0"
+	require.NoError(t, c.Status().Update(ctx, cluster))
+	cluster.Spec.Version = "v3.4.30"
+	require.NoError(t, c.Update(ctx, cluster))
+
+	result, err := svc.checkUpgrade(ctx, cluster)
+	require.NoError(t, err)
+	assert.Nil(t, result)
+	assert.Equal(t, etcdv1alpha1.EtcdClusterPhaseRunning, cluster.Status.Phase)
+	condition := meta.FindStatusCondition(cluster.Status.Conditions, utils.ConditionTypeVersionUpgrade)
+	require.NotNil(t, condition)
+	assert.Equal(t, utils.ReasonUnsupportedUpgrade, condition.Reason)
+}
+
+// TestUpgradeTakesBackupBeforeRestartingMembers 测试升级先按定时备份的存储配置完成备份，再切换镜像并用分区挡住全部成员
+func TestUpgradeTakesBackupBeforeRestartingMembers(t *testing.T) {
+	c, svc, cluster := newRotationFixture(t, &etcdv1alpha1.EtcdBackup{
+		ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: "default"},
+		Spec: etcdv1alpha1.EtcdBackupSpec{
+			ClusterName: "secure",
+			StorageType: etcdv1alpha1.EtcdBackupStorageTypeLocal,
+			Schedule:    "0 3 * * *",
+			Compression: true,
+		},
+	})
+	ctx := context.Background()
+	cluster.Status.CurrentVersion = "v3.5.21"
+	require.NoError(t, c.Status().Update(ctx, cluster))
+	cluster.Spec.Version = "v3.6.0"
+	require.NoError(t, c.Update(ctx, cluster))
+
+	result, err := svc.checkUpgrade(ctx, cluster)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, etcdv1alpha1.EtcdClusterPhaseUpgrading, cluster.Status.Phase)
+	assert.Equal(t, &etcdv1alpha1.EtcdUpgradeStatus{FromVersion: "v3.5.21", ToVersion: "v3.6.0"}, cluster.Status.Upgrade)
+
+	// 备份完成前不修改成员的镜像
+	for i := 0; i < 2; i++ {
+		_, err = svc.HandleUpgrading(ctx, cluster)
+		require.NoError(t, err)
+	}
+	backup := &etcdv1alpha1.EtcdBackup{}
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Name: cluster.Status.Upgrade.BackupName, Namespace: "default"}, backup))
+	assert.Empty(t, backup.Spec.Schedule)
+	assert.True(t, backup.Spec.Compression)
+	assert.Equal(t, "default", backup.Spec.ClusterNamespace)
+
+	sts := &appsv1.StatefulSet{}
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Name: "secure", Namespace: "default"}, sts))
+	assert.Equal(t, "quay.io/coreos/etcd:v3.5.21", sts.Spec.Template.Spec.Containers[0].Image)
+
+	backup.Status.Phase = etcdv1alpha1.EtcdBackupPhaseCompleted
+	require.NoError(t, c.Update(ctx, backup))
+	_, err = svc.HandleUpgrading(ctx, cluster)
+	require.NoError(t, err)
+
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Name: "secure", Namespace: "default"}, sts))
+	assert.Equal(t, "quay.io/coreos/etcd:v3.6.0", sts.Spec.Template.Spec.Containers[0].Image)
+	assert.Equal(t, int32(3), rollPartition(sts))
+}
+
+// TestUpgradeRequiresBackupTemplate 测试没有可用的备份配置时不开始升级
+func TestUpgradeRequiresBackupTemplate(t *testing.T) {
+	c, svc, cluster := newRotationFixture(t)
+	ctx := context.Background()
+	cluster.Status.CurrentVersion = "v3.5.21"
+	require.NoError(t, c.Status().Update(ctx, cluster))
+	cluster.Spec.Version = "v3.6.0"
+	require.NoError(t, c.Update(ctx, cluster))
+
+	result, err := svc.checkUpgrade(ctx, cluster)
+	require.NoError(t, err)
+	assert.Nil(t, result)
+	assert.Equal(t, etcdv1alpha1.EtcdClusterPhaseRunning, cluster.Status.Phase)
+	condition := meta.FindStatusCondition(cluster.Status.Conditions, utils.ConditionTypeVersionUpgrade)
+	require.NotNil(t, condition)
+	assert.Equal(t, utils.ReasonMissingBackupTemplate, condition.Reason)
+}
+
+// TestUpgradeHaltsWhenMemberDoesNotRejoin 测试升级后的成员超时未恢复时设置 Degraded 条件且不放开下一个成员
+func TestUpgradeHaltsWhenMemberDoesNotRejoin(t *testing.T) {
+	c, svc, cluster := newRotationFixture(t, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name: "secure-2", Namespace: "default",
+		Labels: map[string]string{appsv1.StatefulSetRevisionLabel: "secure-old"},
+	}})
+	ctx := context.Background()
+	restarted := metav1.NewTime(time.Now().Add(-utils.DefaultUpgradeMemberTimeout - time.Minute))
+	cluster.Status.Phase = etcdv1alpha1.EtcdClusterPhaseUpgrading
+	cluster.Status.Upgrade = &etcdv1alpha1.EtcdUpgradeStatus{FromVersion: "v3.5.21", ToVersion: "v3.6.0", MemberRestartTime: &restarted}
+	require.NoError(t, c.Status().Update(ctx, cluster))
+
+	sts := &appsv1.StatefulSet{}
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Name: "secure", Namespace: "default"}, sts))
+	require.NoError(t, svc.setPartition(ctx, sts, 2))
+	sts.Status.UpdateRevision = "secure-new"
+	sts.Status.CurrentRevision = "secure-old"
+
+	done, err := svc.upgradeMembers(ctx, cluster, sts)
+	require.NoError(t, err)
+	assert.False(t, done)
+	assert.Equal(t, int32(2), rollPartition(sts))
+	condition := meta.FindStatusCondition(cluster.Status.Conditions, utils.ConditionTypeDegraded)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, utils.ReasonUpgradeHalted, condition.Reason)
+	assert.Contains(t, condition.Message, "secure-2")
+
+	// 成员恢复后清除升级设置的 Degraded 条件
+	assert.True(t, setUpgradeDegraded(cluster, nil))
+	assert.False(t, setUpgradeDegraded(cluster, nil))
+	assert.True(t, setUpgradeDegraded(cluster, errors.New("member secure-1 is not ready")))
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+	"github.com/your-org/etcd-k8s-operator/pkg/client"
+	etcdclient "github.com/your-org/etcd-k8s-operator/pkg/etcd"
+	"github.com/your-org/etcd-k8s-operator/pkg/k8s"
+	"github.com/your-org/etcd-k8s-operator/pkg/utils"
+)
+
+// serviceAccountTokenPath 用于判断 operator 是否运行在集群内部
+const serviceAccountTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+// memberClientURL 返回指定成员的集群内客户端地址
+func memberClientURL(cluster *etcdv1alpha1.EtcdCluster, memberIndex int32) string {
+	return k8s.MemberClientURL(cluster, memberIndex)
+}
+
+// clusterClientEndpoints 返回 operator 可以访问的客户端端点
+// 集群内运行时返回每个成员的地址，集群外运行时（本地调试）回退到 NodePort 服务
+func clusterClientEndpoints(ctx context.Context, k8sClient client.KubernetesClient, cluster *etcdv1alpha1.EtcdCluster) []string {
+	if _, err := os.Stat(serviceAccountTokenPath); err != nil {
+		nodeIP, err := nodeInternalIP(ctx, k8sClient)
+		if err != nil {
+			log.FromContext(ctx).Error(err, "Failed to get Kind node IP, falling back to localhost")
+			nodeIP = "localhost"
+		}
+		return []string{fmt.Sprintf("%s://%s:30379", k8s.ClientScheme(cluster), nodeIP)}
+	}
+
+	endpoints := make([]string, 0, cluster.Spec.Size)
+	for i := int32(0); i < cluster.Spec.Size; i++ {
+		endpoints = append(endpoints, memberClientURL(cluster, i))
+	}
+	return endpoints
+}
+
+// newEtcdClient 创建连接到指定端点的 etcd 客户端
+// 快照等维护操作需要固定在某个成员上执行，此时只传入一个端点。
+// 集群启用客户端 TLS 时使用集群的客户端证书
+func newEtcdClient(ctx context.Context, reader ctrlclient.Reader, cluster *etcdv1alpha1.EtcdCluster, endpoints []string) (*etcdclient.Client, error) {
+	tlsConfig, err := clientTLSConfig(ctx, reader, cluster)
+	if err != nil {
+		return nil, err
+	}
+	return etcdclient.NewClientWithTLS(endpoints, tlsConfig)
+}
+
+// clientTLSConfig 从客户端证书及 CA 所在的 Secret 构建 TLS 配置，集群未启用客户端 TLS 时返回 nil
+func clientTLSConfig(ctx context.Context, reader ctrlclient.Reader, cluster *etcdv1alpha1.EtcdCluster) (*tls.Config, error) {
+	if !k8s.ClientTLSEnabled(cluster) {
+		return nil, nil
+	}
+
+	certName, caName := k8s.ClientCertificateSecrets(cluster)
+	secret := &corev1.Secret{}
+	if err := reader.Get(ctx, types.NamespacedName{Name: certName, Namespace: cluster.Namespace}, secret); err != nil {
+		return nil, fmt.Errorf("failed to get client certificate secret %s: %w", certName, err)
+	}
+	cert, err := tls.X509KeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+	if err != nil {
+		return nil, fmt.Errorf("invalid client certificate in secret %s: %w", certName, err)
+	}
+
+	if caName != certName {
+		secret = &corev1.Secret{}
+		if err := reader.Get(ctx, types.NamespacedName{Name: caName, Namespace: cluster.Namespace}, secret); err != nil {
+			return nil, fmt.Errorf("failed to get CA secret %s: %w", caName, err)
+		}
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(secret.Data[utils.SecretKeyCACert]) {
+		return nil, fmt.Errorf("secret %s has no valid %s", caName, utils.SecretKeyCACert)
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      roots,
+		// 服务端证书覆盖客户端服务的主机名，通过 NodePort 访问时同样以它校验
+		ServerName: k8s.ClientServiceHost(cluster),
+		MinVersion: tls.VersionTLS12,
+	}, nil
+}
+
+// nodeInternalIP 返回第一个节点的内部 IP（用于集群外访问 NodePort）
+func nodeInternalIP(ctx context.Context, k8sClient client.KubernetesClient) (string, error) {
+	nodes := &corev1.NodeList{}
+	if err := k8sClient.List(ctx, nodes); err != nil {
+		return "", fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	if len(nodes.Items) == 0 {
+		return "", fmt.Errorf("no nodes found in cluster")
+	}
+
+	node := nodes.Items[0]
+	for _, addr := range node.Status.Addresses {
+		if addr.Type == corev1.NodeInternalIP {
+			return addr.Address, nil
+		}
+	}
+
+	return "", fmt.Errorf("no internal IP found for node %s", node.Name)
+}
@@ -0,0 +1,465 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+	"github.com/your-org/etcd-k8s-operator/pkg/client"
+	"github.com/your-org/etcd-k8s-operator/pkg/k8s"
+	"github.com/your-org/etcd-k8s-operator/pkg/utils"
+)
+
+// 集群整体健康状态
+const (
+	HealthOverallHealthy     = "Healthy"
+	HealthOverallDegraded    = "Degraded"
+	HealthOverallUnavailable = "Unavailable"
+)
+
+// 成员健康状态
+const (
+	MemberHealthHealthy     = "Healthy"
+	MemberHealthUnhealthy   = "Unhealthy"
+	MemberHealthUnreachable = "Unreachable"
+)
+
+// 健康问题类型及严重程度
+const (
+	HealthIssueMemberUnreachable = "MemberUnreachable"
+	HealthIssueMemberErrors      = "MemberErrors"
+	HealthIssueNoLeader          = "NoLeader"
+	HealthIssueLeaderMismatch    = "LeaderMismatch"
+	HealthIssueRaftIndexLag      = "RaftIndexLag"
+	HealthIssueDatabaseSize      = "DatabaseSize"
+	HealthIssueNoSpace           = "NoSpace"
+
+	HealthSeverityWarning  = "Warning"
+	HealthSeverityCritical = "Critical"
+)
+
+// 成员角色，写入 EtcdMember.Role
+const (
+	memberRoleLeader   = "leader"
+	memberRoleFollower = "follower"
+	memberRoleLearner  = "learner"
+)
+
+// databaseSizeWarningRatio 数据库大小超过后端配额的该比例时报告问题
+const databaseSizeWarningRatio = 0.8
+
+// healthService 健康检查服务实现
+type healthService struct {
+	k8sClient client.KubernetesClient
+	// memberStatus 读取指定序号成员的状态，测试中可替换
+	memberStatus func(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster, ordinal int32) (*clientv3.StatusResponse, error)
+	// memberClient 创建替换成员使用的 etcd 客户端，测试中可替换
+	memberClient func(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster) (memberManager, error)
+	// memberMaintenance 创建只连接指定序号成员的维护客户端，测试中可替换
+	memberMaintenance func(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster, ordinal int32) (memberMaintainer, error)
+	// clusterMaintenance 创建处理 NOSPACE 告警使用的 etcd 客户端，测试中可替换
+	clusterMaintenance func(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster) (clusterMaintainer, error)
+}
+
+// NewHealthService 创建健康检查服务
+func NewHealthService(k8sClient client.KubernetesClient) HealthService {
+	s := &healthService{k8sClient: k8sClient}
+	s.memberStatus = s.readMemberStatus
+	s.memberClient = s.newMemberClient
+	s.memberMaintenance = s.newMemberMaintainer
+	s.clusterMaintenance = s.newClusterMaintainer
+	return s
+}
+
+// CheckClusterHealth 逐个查询成员的 Status 接口，汇总 leader、raft 索引、数据库大小和成员报告的错误
+func (s *healthService) CheckClusterHealth(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster) (*HealthStatus, error) {
+	now := time.Now().Unix()
+	health := &HealthStatus{LastCheck: now}
+
+	for i := int32(0); i < cluster.Spec.Size; i++ {
+		member := MemberHealth{Name: k8s.MemberName(cluster, i)}
+		status, err := s.memberStatus(ctx, cluster, i)
+		if err != nil {
+			member.Status = MemberHealthUnreachable
+			member.Errors = []string{err.Error()}
+			health.Members = append(health.Members, member)
+			continue
+		}
+
+		member.LastSeen = now
+		member.ID = fmt.Sprintf("%x", status.Header.MemberId)
+		if status.Leader != 0 {
+			member.LeaderID = fmt.Sprintf("%x", status.Leader)
+		}
+		member.Role = memberRole(status)
+		member.RaftIndex = status.RaftIndex
+		member.DBSize = status.DbSize
+		member.DBSizeInUse = status.DbSizeInUse
+		member.Errors = status.Errors
+		member.Status = MemberHealthHealthy
+		if len(memberErrors(status.Errors)) > 0 || status.Leader == 0 {
+			member.Status = MemberHealthUnhealthy
+		}
+		health.Members = append(health.Members, member)
+	}
+
+	health.Issues = healthIssues(health.Members, k8s.QuotaBackendBytes(cluster))
+	health.Overall = overallHealth(cluster.Spec.Size, health.Members, health.Issues)
+	return health, nil
+}
+
+// PerformHealthCheck 检查集群健康，将成员的就绪状态和角色写入 Status.Members，并设置 Available 和 Degraded 条件。
+// 法定人数完好时替换长时间不可达的成员，失去法定人数超过阈值时将集群标记为失败；
+// 按策略处理 NOSPACE 告警，全部成员健康时按计划整理碎片
+func (s *healthService) PerformHealthCheck(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster) error {
+	health, err := s.refreshHealth(ctx, cluster)
+	if err != nil {
+		return err
+	}
+	if failed, err := s.detectQuorumLoss(ctx, cluster, health); err != nil || failed {
+		return err
+	}
+	// 从备份恢复完成后由 EtcdRestore 直接回到运行状态
+	if cluster.Status.Recovery != nil && health.Overall != HealthOverallUnavailable {
+		if _, err := s.finishRecovery(ctx, cluster); err != nil {
+			return err
+		}
+	}
+	if err := s.replaceFailedMember(ctx, cluster, health); err != nil {
+		return err
+	}
+	if err := s.remediateNoSpace(ctx, cluster, health); err != nil {
+		return err
+	}
+	return s.defragmentMembers(ctx, cluster, health)
+}
+
+// HandleFailed 处理失败状态的集群：成员重新全部健康后回到运行状态。
+// 因失去法定人数而失败的集群按 spec.recovery.policy 恢复
+func (s *healthService) HandleFailed(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster) (ctrl.Result, error) {
+	health, err := s.refreshHealth(ctx, cluster)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if health.Overall == HealthOverallHealthy {
+		return s.finishRecovery(ctx, cluster)
+	}
+	if quorumLost(cluster) && (cluster.Status.Recovery != nil || health.Overall == HealthOverallUnavailable) {
+		return s.recoverCluster(ctx, cluster, health)
+	}
+
+	log.FromContext(ctx).Info("Waiting for failed cluster to become healthy", "health", health.Overall, "issues", len(health.Issues))
+	return ctrl.Result{RequeueAfter: utils.DefaultRequeueInterval}, nil
+}
+
+// refreshHealth 检查集群健康并在状态变化时更新集群状态
+func (s *healthService) refreshHealth(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster) (*HealthStatus, error) {
+	health, err := s.CheckClusterHealth(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+	log.FromContext(ctx).Info("Checked cluster health", "health", health.Overall, "issues", len(health.Issues))
+
+	if applyHealth(cluster, health) {
+		if err := s.k8sClient.UpdateStatus(ctx, cluster); err != nil {
+			return nil, err
+		}
+	}
+	return health, nil
+}
+
+// readMemberStatus 直连成员的客户端地址读取它的状态
+func (s *healthService) readMemberStatus(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster, ordinal int32) (*clientv3.StatusResponse, error) {
+	endpoint := k8s.MemberClientURL(cluster, ordinal)
+	etcdClient, err := newEtcdClient(ctx, s.k8sClient.GetClient(), cluster, []string{endpoint})
+	if err != nil {
+		return nil, err
+	}
+	defer etcdClient.Close()
+
+	return etcdClient.MemberStatus(ctx, endpoint)
+}
+
+// memberRole 根据成员的状态判断它的角色
+func memberRole(status *clientv3.StatusResponse) string {
+	switch {
+	case status.IsLearner:
+		return memberRoleLearner
+	case status.Leader != 0 && status.Header.MemberId == status.Leader:
+		return memberRoleLeader
+	default:
+		return memberRoleFollower
+	}
+}
+
+// healthIssues 根据成员的健康状态汇总集群的健康问题，quota 为成员使用的后端配额
+func healthIssues(members []MemberHealth, quota int64) []HealthIssue {
+	var issues []HealthIssue
+	leaders := map[string]bool{}
+	var maxIndex uint64
+	reachable := 0
+	noSpace := false
+	for _, m := range members {
+		if m.Status == MemberHealthUnreachable {
+			issues = append(issues, HealthIssue{
+				Type:        HealthIssueMemberUnreachable,
+				Member:      m.Name,
+				Description: fmt.Sprintf("member %s is unreachable: %s", m.Name, strings.Join(m.Errors, "; ")),
+				Severity:    HealthSeverityWarning,
+			})
+			continue
+		}
+		reachable++
+		if errs := memberErrors(m.Errors); len(errs) > 0 {
+			issues = append(issues, HealthIssue{
+				Type:        HealthIssueMemberErrors,
+				Member:      m.Name,
+				Description: fmt.Sprintf("member %s reports errors: %s", m.Name, strings.Join(errs, "; ")),
+				Severity:    HealthSeverityWarning,
+			})
+		}
+		if len(memberErrors(m.Errors)) < len(m.Errors) {
+			noSpace = true
+		}
+		if m.LeaderID != "" {
+			leaders[m.LeaderID] = true
+		} else {
+			issues = append(issues, HealthIssue{
+				Type:        HealthIssueNoLeader,
+				Member:      m.Name,
+				Description: fmt.Sprintf("member %s does not see a leader", m.Name),
+				Severity:    HealthSeverityWarning,
+			})
+		}
+		if m.RaftIndex > maxIndex {
+			maxIndex = m.RaftIndex
+		}
+		if float64(m.DBSize) >= databaseSizeWarningRatio*float64(quota) {
+			issues = append(issues, HealthIssue{
+				Type:        HealthIssueDatabaseSize,
+				Member:      m.Name,
+				Description: fmt.Sprintf("member %s database is %d bytes, close to the %d byte quota", m.Name, m.DBSize, quota),
+				Severity:    HealthSeverityWarning,
+			})
+		}
+	}
+
+	for _, m := range members {
+		if m.Status != MemberHealthUnreachable && maxIndex-m.RaftIndex > utils.DefaultMaxRaftIndexLag {
+			issues = append(issues, HealthIssue{
+				Type:        HealthIssueRaftIndexLag,
+				Member:      m.Name,
+				Description: fmt.Sprintf("member %s raft index %d trails %d", m.Name, m.RaftIndex, maxIndex),
+				Severity:    HealthSeverityWarning,
+			})
+		}
+	}
+
+	if noSpace {
+		issues = append(issues, HealthIssue{
+			Type:        HealthIssueNoSpace,
+			Description: fmt.Sprintf("NOSPACE alarm raised: the database exceeded the %d byte quota and the cluster only accepts reads and deletes", quota),
+			Severity:    HealthSeverityWarning,
+		})
+	}
+
+	switch {
+	case reachable > 0 && len(leaders) == 0:
+		issues = append(issues, HealthIssue{
+			Type:        HealthIssueNoLeader,
+			Description: "no member reports a leader",
+			Severity:    HealthSeverityCritical,
+		})
+	case len(leaders) > 1:
+		issues = append(issues, HealthIssue{
+			Type:        HealthIssueLeaderMismatch,
+			Description: fmt.Sprintf("members disagree on the leader: %d different leaders reported", len(leaders)),
+			Severity:    HealthSeverityWarning,
+		})
+	}
+	return issues
+}
+
+// overallHealth 判断集群整体健康状态：健康成员不足法定人数或没有 leader 时不可用，
+// 全部成员健康且没有问题时健康，其余情况为降级
+func overallHealth(size int32, members []MemberHealth, issues []HealthIssue) string {
+	healthy := int32(0)
+	for _, m := range members {
+		if m.Status == MemberHealthHealthy {
+			healthy++
+		}
+	}
+	for _, issue := range issues {
+		if issue.Severity == HealthSeverityCritical {
+			return HealthOverallUnavailable
+		}
+	}
+
+	switch {
+	case healthy < size/2+1:
+		return HealthOverallUnavailable
+	case healthy == size && len(issues) == 0:
+		return HealthOverallHealthy
+	default:
+		return HealthOverallDegraded
+	}
+}
+
+// applyHealth 将健康检查结果写入成员列表、LeaderID 以及 Available 和 Degraded 条件，返回状态是否发生变化。
+// 成员保留第一次被发现不健康的时间，用于判断是否需要替换。Degraded 条件只记录问题类型和涉及的成员，
+// 数据库大小、raft index 等每次检查都会变化的数值不写入状态，避免每轮健康检查都更新状态并触发调谐
+func applyHealth(cluster *etcdv1alpha1.EtcdCluster, health *HealthStatus) bool {
+	unhealthySince := map[string]*metav1.Time{}
+	for _, m := range cluster.Status.Members {
+		unhealthySince[m.Name] = m.UnhealthySince
+	}
+	checked := metav1.Unix(health.LastCheck, 0)
+
+	members := make([]etcdv1alpha1.EtcdMember, 0, len(health.Members))
+	leaderID := ""
+	healthy := 0
+	for i, m := range health.Members {
+		ordinal := int32(i)
+		member := etcdv1alpha1.EtcdMember{
+			Name:      m.Name,
+			ID:        m.ID,
+			PeerURL:   k8s.MemberPeerURL(cluster, ordinal),
+			ClientURL: k8s.MemberClientURL(cluster, ordinal),
+			Ready:     m.Status == MemberHealthHealthy,
+			Role:      m.Role,
+		}
+		if !member.Ready {
+			member.UnhealthySince = unhealthySince[m.Name]
+			if member.UnhealthySince == nil {
+				member.UnhealthySince = &checked
+			}
+		}
+		members = append(members, member)
+		if m.Role == memberRoleLeader {
+			leaderID = m.ID
+		}
+		if m.Status == MemberHealthHealthy {
+			healthy++
+		}
+	}
+	changed := !equality.Semantic.DeepEqual(cluster.Status.Members, members) || cluster.Status.LeaderID != leaderID
+	cluster.Status.Members = members
+	cluster.Status.LeaderID = leaderID
+
+	available := metav1.Condition{
+		Type:               utils.ConditionTypeAvailable,
+		Status:             metav1.ConditionTrue,
+		Reason:             utils.ReasonQuorumAvailable,
+		Message:            fmt.Sprintf("%d of %d members are healthy", healthy, len(health.Members)),
+		ObservedGeneration: cluster.Generation,
+	}
+	if health.Overall == HealthOverallUnavailable {
+		available.Status = metav1.ConditionFalse
+		available.Reason = utils.ReasonQuorumLost
+	}
+	if meta.SetStatusCondition(&cluster.Status.Conditions, available) {
+		changed = true
+	}
+
+	degraded := metav1.Condition{
+		Type:               utils.ConditionTypeDegraded,
+		Status:             metav1.ConditionFalse,
+		Reason:             utils.ReasonHealthy,
+		Message:            "All members are healthy",
+		ObservedGeneration: cluster.Generation,
+	}
+	if health.Overall != HealthOverallHealthy {
+		degraded.Status = metav1.ConditionTrue
+		degraded.Reason = utils.ReasonUnhealthy
+		degraded.Message = degradedMessage(health.Issues)
+		if hasHealthIssue(health, HealthIssueNoSpace) {
+			degraded.Reason = utils.ReasonNoSpace
+		}
+	}
+	if meta.SetStatusCondition(&cluster.Status.Conditions, degraded) {
+		changed = true
+	}
+	return changed
+}
+
+// degradedMessage 按问题类型汇总涉及的成员，例如 "DatabaseSize: secure-1, secure-2; NoLeader"。
+// 类型按名称排序，结果只随问题类型和涉及的成员变化
+func degradedMessage(issues []HealthIssue) string {
+	members := map[string][]string{}
+	seen := map[HealthIssue]bool{}
+	for _, issue := range issues {
+		if _, ok := members[issue.Type]; !ok {
+			members[issue.Type] = nil
+		}
+		key := HealthIssue{Type: issue.Type, Member: issue.Member}
+		if issue.Member != "" && !seen[key] {
+			seen[key] = true
+			members[issue.Type] = append(members[issue.Type], issue.Member)
+		}
+	}
+
+	types := make([]string, 0, len(members))
+	for issueType := range members {
+		types = append(types, issueType)
+	}
+	sort.Strings(types)
+
+	parts := make([]string, 0, len(types))
+	for _, issueType := range types {
+		if len(members[issueType]) == 0 {
+			parts = append(parts, issueType)
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s: %s", issueType, strings.Join(members[issueType], ", ")))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// hasHealthIssue 返回健康检查结果中是否有指定类型的问题
+func hasHealthIssue(health *HealthStatus, issueType string) bool {
+	for _, issue := range health.Issues {
+		if issue.Type == issueType {
+			return true
+		}
+	}
+	return false
+}
+
+// memberErrors 返回成员报告的错误中 NOSPACE 告警以外的错误。告警在集群内同步，每个成员都会报告；
+// NOSPACE 告警只让集群拒绝写入，不影响成员参与仲裁
+func memberErrors(errs []string) []string {
+	var filtered []string
+	for _, err := range errs {
+		if !strings.Contains(err, pb.AlarmType_NOSPACE.String()) {
+			filtered = append(filtered, err)
+		}
+	}
+	return filtered
+}
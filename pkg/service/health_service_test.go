@@ -0,0 +1,203 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+	"github.com/your-org/etcd-k8s-operator/pkg/utils"
+)
+
+// newHealthFixture 返回使用给定成员状态的健康检查服务，statuses 中缺少的序号视为不可达
+func newHealthFixture(t *testing.T, statuses map[int32]*clientv3.StatusResponse) (*healthService, *etcdv1alpha1.EtcdCluster) {
+	_, scaling, cluster := newRotationFixture(t)
+	svc := scaling.healthService.(*healthService)
+	svc.memberStatus = func(_ context.Context, _ *etcdv1alpha1.EtcdCluster, ordinal int32) (*clientv3.StatusResponse, error) {
+		if status, ok := statuses[ordinal]; ok {
+			return status, nil
+		}
+		return nil, errors.New("connection refused")
+	}
+	return svc, cluster
+}
+
+// memberStatusResponse 构造成员的 Status 响应
+func memberStatusResponse(memberID, leader, raftIndex uint64, errs ...string) *clientv3.StatusResponse {
+	return &clientv3.StatusResponse{
+		Header:    &pb.ResponseHeader{MemberId: memberID},
+		Leader:    leader,
+		RaftIndex: raftIndex,
+		DbSize:    1024,
+		Errors:    errs,
+	}
+}
+
+// TestPerformHealthCheckHealthy 测试全部成员健康时写入成员角色和 leader，并设置 Available 和 Degraded 条件
+func TestPerformHealthCheckHealthy(t *testing.T) {
+	svc, cluster := newHealthFixture(t, map[int32]*clientv3.StatusResponse{
+		0: memberStatusResponse(0xa, 0xb, 100),
+		1: memberStatusResponse(0xb, 0xb, 100),
+		2: memberStatusResponse(0xc, 0xb, 99),
+	})
+	ctx := context.Background()
+
+	require.NoError(t, svc.PerformHealthCheck(ctx, cluster))
+
+	stored := &etcdv1alpha1.EtcdCluster{}
+	require.NoError(t, svc.k8sClient.Get(ctx, types.NamespacedName{Name: "secure", Namespace: "default"}, stored))
+	require.Len(t, stored.Status.Members, 3)
+	assert.Equal(t, etcdv1alpha1.EtcdMember{
+		Name:      "secure-1",
+		ID:        "b",
+		PeerURL:   "https://secure-1.secure-peer.default.svc.cluster.local:2380",
+		ClientURL: "https://secure-1.secure-peer.default.svc.cluster.local:2379",
+		Ready:     true,
+		Role:      "leader",
+	}, stored.Status.Members[1])
+	assert.Equal(t, "follower", stored.Status.Members[0].Role)
+	assert.Equal(t, "b", stored.Status.LeaderID)
+
+	available := meta.FindStatusCondition(stored.Status.Conditions, utils.ConditionTypeAvailable)
+	require.NotNil(t, available)
+	assert.Equal(t, metav1.ConditionTrue, available.Status)
+	degraded := meta.FindStatusCondition(stored.Status.Conditions, utils.ConditionTypeDegraded)
+	require.NotNil(t, degraded)
+	assert.Equal(t, metav1.ConditionFalse, degraded.Status)
+}
+
+// TestCheckClusterHealthDegraded 测试健康成员不足法定人数时集群不可用，少数成员落后时集群降级但仍可用
+func TestCheckClusterHealthDegraded(t *testing.T) {
+	svc, cluster := newHealthFixture(t, map[int32]*clientv3.StatusResponse{
+		0: memberStatusResponse(0xa, 0xb, 100),
+		1: memberStatusResponse(0xb, 0xb, 100, "memberID:11 alarm:CORRUPT"),
+	})
+	ctx := context.Background()
+
+	health, err := svc.CheckClusterHealth(ctx, cluster)
+	require.NoError(t, err)
+	assert.Equal(t, HealthOverallUnavailable, health.Overall)
+	assert.Equal(t, MemberHealthUnhealthy, health.Members[1].Status)
+	assert.Equal(t, MemberHealthUnreachable, health.Members[2].Status)
+
+	svc, cluster = newHealthFixture(t, map[int32]*clientv3.StatusResponse{
+		0: memberStatusResponse(0xa, 0xb, 100),
+		1: memberStatusResponse(0xb, 0xb, 100),
+		2: memberStatusResponse(0xc, 0xb, 100+utils.DefaultMaxRaftIndexLag+1),
+	})
+	health, err = svc.CheckClusterHealth(ctx, cluster)
+	require.NoError(t, err)
+	assert.Equal(t, HealthOverallDegraded, health.Overall)
+	require.Len(t, health.Issues, 2)
+	assert.Equal(t, HealthIssueRaftIndexLag, health.Issues[0].Type)
+
+	require.NoError(t, svc.PerformHealthCheck(ctx, cluster))
+	degraded := meta.FindStatusCondition(cluster.Status.Conditions, utils.ConditionTypeDegraded)
+	require.NotNil(t, degraded)
+	assert.Equal(t, metav1.ConditionTrue, degraded.Status)
+	assert.Equal(t, "RaftIndexLag: secure-0, secure-1", degraded.Message)
+	available := meta.FindStatusCondition(cluster.Status.Conditions, utils.ConditionTypeAvailable)
+	require.NotNil(t, available)
+	assert.Equal(t, metav1.ConditionTrue, available.Status)
+}
+
+// TestApplyHealthIgnoresChangingNumbers 测试问题涉及的成员不变时，raft index 和数据库大小的变化不会更新状态，
+// 涉及的成员变化时才更新 Degraded 条件
+func TestApplyHealthIgnoresChangingNumbers(t *testing.T) {
+	statuses := map[int32]*clientv3.StatusResponse{
+		0: memberStatusResponse(0xa, 0xb, 100),
+		1: memberStatusResponse(0xb, 0xb, 100+utils.DefaultMaxRaftIndexLag+1),
+		2: memberStatusResponse(0xc, 0xb, 100+utils.DefaultMaxRaftIndexLag+1),
+	}
+	svc, cluster := newHealthFixture(t, statuses)
+	ctx := context.Background()
+
+	health, err := svc.CheckClusterHealth(ctx, cluster)
+	require.NoError(t, err)
+	assert.True(t, applyHealth(cluster, health))
+
+	statuses[0].RaftIndex = 200
+	statuses[1].RaftIndex = 300 + utils.DefaultMaxRaftIndexLag
+	statuses[1].DbSize = 4096
+	statuses[2].DbSize = 8192
+	health, err = svc.CheckClusterHealth(ctx, cluster)
+	require.NoError(t, err)
+	require.Len(t, health.Issues, 1)
+	assert.Contains(t, health.Issues[0].Description, "raft index 200")
+	assert.False(t, applyHealth(cluster, health))
+
+	statuses[2].RaftIndex = 20000
+	health, err = svc.CheckClusterHealth(ctx, cluster)
+	require.NoError(t, err)
+	assert.True(t, applyHealth(cluster, health))
+	degraded := meta.FindStatusCondition(cluster.Status.Conditions, utils.ConditionTypeDegraded)
+	require.NotNil(t, degraded)
+	assert.Equal(t, "RaftIndexLag: secure-0, secure-1", degraded.Message)
+}
+
+// TestCheckClusterHealthNoLeader 测试成员都没有 leader 时集群不可用
+func TestCheckClusterHealthNoLeader(t *testing.T) {
+	svc, cluster := newHealthFixture(t, map[int32]*clientv3.StatusResponse{
+		0: memberStatusResponse(0xa, 0, 100),
+		1: memberStatusResponse(0xb, 0, 100),
+		2: memberStatusResponse(0xc, 0, 100),
+	})
+
+	health, err := svc.CheckClusterHealth(context.Background(), cluster)
+	require.NoError(t, err)
+	assert.Equal(t, HealthOverallUnavailable, health.Overall)
+	assert.Equal(t, HealthSeverityCritical, health.Issues[len(health.Issues)-1].Severity)
+
+	assert.True(t, applyHealth(cluster, health))
+	available := meta.FindStatusCondition(cluster.Status.Conditions, utils.ConditionTypeAvailable)
+	require.NotNil(t, available)
+	assert.Equal(t, metav1.ConditionFalse, available.Status)
+	assert.Equal(t, utils.ReasonQuorumLost, available.Reason)
+	assert.Empty(t, cluster.Status.LeaderID)
+}
+
+// TestHandleFailedReturnsToRunning 测试失败的集群全部成员恢复健康后回到运行状态
+func TestHandleFailedReturnsToRunning(t *testing.T) {
+	svc, cluster := newHealthFixture(t, map[int32]*clientv3.StatusResponse{
+		0: memberStatusResponse(0xa, 0xa, 100),
+		1: memberStatusResponse(0xb, 0xa, 100),
+	})
+	ctx := context.Background()
+	cluster.Status.Phase = etcdv1alpha1.EtcdClusterPhaseFailed
+
+	result, err := svc.HandleFailed(ctx, cluster)
+	require.NoError(t, err)
+	assert.Equal(t, utils.DefaultRequeueInterval, result.RequeueAfter)
+	assert.Equal(t, etcdv1alpha1.EtcdClusterPhaseFailed, cluster.Status.Phase)
+
+	svc.memberStatus = func(_ context.Context, _ *etcdv1alpha1.EtcdCluster, ordinal int32) (*clientv3.StatusResponse, error) {
+		return memberStatusResponse(uint64(0xa+ordinal), 0xa, 100), nil
+	}
+	_, err = svc.HandleFailed(ctx, cluster)
+	require.NoError(t, err)
+	assert.Equal(t, etcdv1alpha1.EtcdClusterPhaseRunning, cluster.Status.Phase)
+}
@@ -46,6 +46,7 @@ type ScalingService interface {
 	HandleRunning(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster) (ctrl.Result, error)
 	HandleScaling(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster) (ctrl.Result, error)
 	HandleStopped(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster) (ctrl.Result, error)
+	HandleUpgrading(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster) (ctrl.Result, error)
 
 	// 扩缩容检查
 	NeedsScaling(cluster *etcdv1alpha1.EtcdCluster) bool
@@ -64,6 +65,18 @@ type HealthService interface {
 	HandleFailed(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster) (ctrl.Result, error)
 }
 
+// BackupService 备份服务接口
+type BackupService interface {
+	// 备份生命周期管理
+	HandleBackup(ctx context.Context, backup *etcdv1alpha1.EtcdBackup) (ctrl.Result, error)
+}
+
+// RestoreService 恢复服务接口
+type RestoreService interface {
+	// 恢复生命周期管理
+	HandleRestore(ctx context.Context, restore *etcdv1alpha1.EtcdRestore) (ctrl.Result, error)
+}
+
 // ClusterStatus 集群状态信息
 type ClusterStatus struct {
 	Phase           string
@@ -99,11 +112,20 @@ type MemberHealth struct {
 	Status   string
 	LastSeen int64
 	Errors   []string
+
+	// 成员 Status 接口报告的信息，成员不可达时为零值
+	ID          string
+	Role        string
+	LeaderID    string
+	RaftIndex   uint64
+	DBSize      int64
+	DBSizeInUse int64
 }
 
 // HealthIssue 健康问题
 type HealthIssue struct {
 	Type        string
+	Member      string
 	Description string
 	Severity    string
 }
@@ -0,0 +1,267 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+	"github.com/your-org/etcd-k8s-operator/pkg/k8s"
+	"github.com/your-org/etcd-k8s-operator/pkg/utils"
+)
+
+// memberManager 扩容、替换成员和法定人数恢复使用的 etcd 成员操作
+type memberManager interface {
+	GetClusterMembers(ctx context.Context) ([]etcdv1alpha1.EtcdMember, error)
+	RemoveMember(ctx context.Context, memberID uint64) error
+	AddLearner(ctx context.Context, peerURL string) (*clientv3.MemberAddResponse, error)
+	PromoteMember(ctx context.Context, memberID uint64) error
+	MemberStatus(ctx context.Context, endpoint string) (*clientv3.StatusResponse, error)
+	Close() error
+}
+
+// newMemberClient 创建连接到 operator 可以访问的客户端端点的 etcd 客户端
+func (s *healthService) newMemberClient(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster) (memberManager, error) {
+	etcdClient, err := newEtcdClient(ctx, s.k8sClient.GetClient(), cluster, clusterClientEndpoints(ctx, s.k8sClient, cluster))
+	if err != nil {
+		return nil, err
+	}
+	return etcdClient, nil
+}
+
+// replaceFailedMember 替换长时间不可达的成员：按 ID 从集群中移除旧成员，以 learner 的身份重新加入，
+// 再删除它的数据卷和 Pod，由 StatefulSet 重建后从其他成员同步数据，追上 leader 之后提升为投票成员。
+// learner 不计入法定人数，同步期间空的新成员不影响集群的可用性。每次只替换一个成员
+func (s *healthService) replaceFailedMember(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster, health *HealthStatus) error {
+	if cluster.Status.MemberReplacement != nil {
+		return s.continueMemberReplacement(ctx, cluster, health)
+	}
+	ordinal, ok := failedMember(cluster, health, time.Now())
+	if !ok {
+		return nil
+	}
+
+	etcdClient, err := s.memberClient(ctx, cluster)
+	if err != nil {
+		return err
+	}
+	defer etcdClient.Close()
+
+	members, err := etcdClient.GetClusterMembers(ctx)
+	if err != nil {
+		return err
+	}
+	// 从未启动过的成员没有名称，只能按 peer 地址查找
+	name := k8s.MemberName(cluster, ordinal)
+	memberID := ""
+	for _, m := range members {
+		if m.Name == name || m.PeerURL == k8s.MemberPeerURL(cluster, ordinal) {
+			memberID = m.ID
+			break
+		}
+	}
+
+	log.FromContext(ctx).Info("Replacing failed member", "member", name, "memberID", memberID)
+	cluster.Status.MemberReplacement = &etcdv1alpha1.EtcdMemberReplacementStatus{
+		Member:    name,
+		MemberID:  memberID,
+		StartTime: metav1.Now(),
+	}
+	return s.k8sClient.UpdateStatus(ctx, cluster)
+}
+
+// continueMemberReplacement 推进记录中的成员替换。每一步都可以重复执行，调谐中断后从未完成的步骤继续
+func (s *healthService) continueMemberReplacement(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster, health *HealthStatus) error {
+	logger := log.FromContext(ctx)
+	replacement := cluster.Status.MemberReplacement
+	ordinal, err := memberOrdinal(cluster, replacement.Member)
+	if err != nil {
+		return err
+	}
+	peerURL := k8s.MemberPeerURL(cluster, ordinal)
+
+	etcdClient, err := s.memberClient(ctx, cluster)
+	if err != nil {
+		return err
+	}
+	defer etcdClient.Close()
+
+	members, err := etcdClient.GetClusterMembers(ctx)
+	if err != nil {
+		return err
+	}
+
+	// 1. 按 ID 移除旧成员
+	if replacement.MemberID != "" {
+		for i, m := range members {
+			if m.ID != replacement.MemberID {
+				continue
+			}
+			var memberID uint64
+			if _, err := fmt.Sscanf(m.ID, "%x", &memberID); err != nil {
+				return fmt.Errorf("failed to parse member ID %s: %w", m.ID, err)
+			}
+			if err := etcdClient.RemoveMember(ctx, memberID); err != nil {
+				return err
+			}
+			logger.Info("Removed failed member", "member", replacement.Member, "memberID", m.ID)
+			s.k8sClient.RecordEvent(cluster, corev1.EventTypeWarning, utils.EventReasonMemberReplaced,
+				fmt.Sprintf("Removed failed member %s (%s) from the cluster", replacement.Member, m.ID))
+			members = append(members[:i], members[i+1:]...)
+			break
+		}
+	}
+
+	// 2. 以 learner 的身份重新加入，Pod 重建后以 existing 状态加入集群
+	var member *etcdv1alpha1.EtcdMember
+	for i := range members {
+		if members[i].PeerURL == peerURL {
+			member = &members[i]
+			break
+		}
+	}
+	if member == nil {
+		resp, err := etcdClient.AddLearner(ctx, peerURL)
+		if err != nil {
+			return err
+		}
+		logger.Info("Added replacement member as a learner", "member", replacement.Member, "memberID", fmt.Sprintf("%x", resp.Member.ID))
+		s.k8sClient.RecordEvent(cluster, corev1.EventTypeNormal, utils.EventReasonMemberReplaced,
+			fmt.Sprintf("Added member %s back to the cluster as a learner", replacement.Member))
+		member = &etcdv1alpha1.EtcdMember{ID: fmt.Sprintf("%x", resp.Member.ID), PeerURL: peerURL, Role: memberRoleLearner}
+	}
+
+	// 3. 删除旧的数据卷和 Pod
+	recreated, err := s.resetMemberData(ctx, cluster, ordinal, replacement.StartTime)
+	if err != nil || !recreated {
+		return err
+	}
+
+	// 4. learner 追上 leader 之后提升为投票成员
+	if member.Role == memberRoleLearner {
+		promoted, _, err := promoteCaughtUpLearner(ctx, etcdClient, member, k8s.MemberClientURL(cluster, ordinal), members)
+		if err != nil {
+			return err
+		}
+		if !promoted {
+			logger.Info("Waiting for replacement member to catch up with the leader", "member", replacement.Member)
+			return nil
+		}
+		logger.Info("Promoted replacement member to a voting member", "member", replacement.Member)
+		s.k8sClient.RecordEvent(cluster, corev1.EventTypeNormal, utils.EventReasonMemberReplaced,
+			fmt.Sprintf("Member %s caught up with the leader and was promoted to a voting member", replacement.Member))
+	}
+
+	// 5. 等待新成员就绪
+	if int(ordinal) >= len(health.Members) || health.Members[ordinal].Status != MemberHealthHealthy {
+		logger.Info("Waiting for replacement member to become healthy", "member", replacement.Member)
+		return nil
+	}
+	logger.Info("Member replaced", "member", replacement.Member)
+	s.k8sClient.RecordEvent(cluster, corev1.EventTypeNormal, utils.EventReasonMemberReplaced,
+		fmt.Sprintf("Member %s was replaced and rejoined the cluster", replacement.Member))
+	cluster.Status.MemberReplacement = nil
+	return s.k8sClient.UpdateStatus(ctx, cluster)
+}
+
+// resetMemberData 删除成员在替换开始之前创建的数据卷和 Pod，返回成员是否已经使用新的数据卷重建
+func (s *healthService) resetMemberData(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster, ordinal int32, start metav1.Time) (bool, error) {
+	return resetMemberData(ctx, s.k8sClient.GetClient(), cluster, ordinal, start, func(pvcName string) {
+		s.k8sClient.RecordEvent(cluster, corev1.EventTypeNormal, utils.EventReasonMemberReplaced,
+			fmt.Sprintf("Deleted volume %s of member %s, the member resyncs from the other members", pvcName, k8s.MemberName(cluster, ordinal)))
+	})
+}
+
+// resetMemberData 删除成员在 start 之前创建的数据卷和 Pod，返回成员是否已经使用新的数据卷重建，删除数据卷后调用 volumeDeleted。
+// 数据卷在使用它的 Pod 删除之前不会被删除；Pod 引用的数据卷不存在或者仍是旧数据卷时再次删除 Pod，
+// 由 StatefulSet 一起重建
+func resetMemberData(ctx context.Context, c ctrlclient.Client, cluster *etcdv1alpha1.EtcdCluster, ordinal int32, start metav1.Time,
+	volumeDeleted func(pvcName string)) (bool, error) {
+	name := k8s.MemberName(cluster, ordinal)
+
+	pvc := &corev1.PersistentVolumeClaim{}
+	err := c.Get(ctx, types.NamespacedName{Name: k8s.MemberPVCName(cluster, ordinal), Namespace: cluster.Namespace}, pvc)
+	if err != nil && !errors.IsNotFound(err) {
+		return false, err
+	}
+	freshPVC := err == nil && !pvc.CreationTimestamp.Before(&start)
+	if err == nil && !freshPVC && pvc.DeletionTimestamp == nil {
+		if err := c.Delete(ctx, pvc); err != nil && !errors.IsNotFound(err) {
+			return false, fmt.Errorf("failed to delete volume %s: %w", pvc.Name, err)
+		}
+		volumeDeleted(pvc.Name)
+	}
+
+	pod := &corev1.Pod{}
+	err = c.Get(ctx, types.NamespacedName{Name: name, Namespace: cluster.Namespace}, pod)
+	if errors.IsNotFound(err) {
+		// 等待 StatefulSet 重建 Pod
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if pod.DeletionTimestamp != nil {
+		return false, nil
+	}
+	if !freshPVC || pod.CreationTimestamp.Before(&start) {
+		if err := c.Delete(ctx, pod); err != nil && !errors.IsNotFound(err) {
+			return false, fmt.Errorf("failed to delete pod %s: %w", name, err)
+		}
+		return false, nil
+	}
+	return true, nil
+}
+
+// failedMember 返回需要替换的成员序号：成员不可达超过 DefaultMemberReplaceTimeout，
+// 并且其余健康成员在移除和重新加入该成员期间仍满足法定人数
+func failedMember(cluster *etcdv1alpha1.EtcdCluster, health *HealthStatus, now time.Time) (int32, bool) {
+	if health.Overall != HealthOverallDegraded {
+		return 0, false
+	}
+	for i, m := range health.Members {
+		if m.Status != MemberHealthUnreachable || i >= len(cluster.Status.Members) {
+			continue
+		}
+		since := cluster.Status.Members[i].UnhealthySince
+		if since != nil && now.Sub(since.Time) >= utils.DefaultMemberReplaceTimeout {
+			return int32(i), true
+		}
+	}
+	return 0, false
+}
+
+// memberOrdinal 从成员名称中解析成员序号
+func memberOrdinal(cluster *etcdv1alpha1.EtcdCluster, name string) (int32, error) {
+	ordinal, err := strconv.ParseInt(strings.TrimPrefix(name, cluster.Name+"-"), 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid member name %s: %w", name, err)
+	}
+	return int32(ordinal), nil
+}
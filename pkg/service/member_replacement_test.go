@@ -0,0 +1,203 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+	"github.com/your-org/etcd-k8s-operator/pkg/k8s"
+	"github.com/your-org/etcd-k8s-operator/pkg/utils"
+)
+
+// fakeMemberManager 记录扩容、成员替换和法定人数恢复对 etcd 成员列表的修改，并按端点返回成员状态
+type fakeMemberManager struct {
+	members    []etcdv1alpha1.EtcdMember
+	statuses   map[string]*clientv3.StatusResponse
+	promoteErr error
+	// nextID 下一个加入的成员的 ID
+	nextID   uint64
+	added    []string
+	promoted []uint64
+	removed  []uint64
+}
+
+func (f *fakeMemberManager) GetClusterMembers(context.Context) ([]etcdv1alpha1.EtcdMember, error) {
+	return append([]etcdv1alpha1.EtcdMember(nil), f.members...), nil
+}
+
+func (f *fakeMemberManager) RemoveMember(_ context.Context, memberID uint64) error {
+	f.removed = append(f.removed, memberID)
+	for i, m := range f.members {
+		if m.ID == fmt.Sprintf("%x", memberID) {
+			f.members = append(f.members[:i], f.members[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (f *fakeMemberManager) AddLearner(_ context.Context, peerURL string) (*clientv3.MemberAddResponse, error) {
+	id := f.nextID
+	f.nextID++
+	f.added = append(f.added, peerURL)
+	f.members = append(f.members, etcdv1alpha1.EtcdMember{ID: fmt.Sprintf("%x", id), PeerURL: peerURL, Role: memberRoleLearner})
+	return &clientv3.MemberAddResponse{Member: &pb.Member{ID: id, PeerURLs: []string{peerURL}, IsLearner: true}}, nil
+}
+
+func (f *fakeMemberManager) PromoteMember(_ context.Context, memberID uint64) error {
+	if f.promoteErr != nil {
+		return fmt.Errorf("failed to promote member %x: %w", memberID, f.promoteErr)
+	}
+	f.promoted = append(f.promoted, memberID)
+	for i := range f.members {
+		if f.members[i].ID == fmt.Sprintf("%x", memberID) {
+			f.members[i].Role = ""
+		}
+	}
+	return nil
+}
+
+func (f *fakeMemberManager) MemberStatus(_ context.Context, endpoint string) (*clientv3.StatusResponse, error) {
+	if status, ok := f.statuses[endpoint]; ok {
+		return status, nil
+	}
+	return nil, fmt.Errorf("connection refused")
+}
+
+func (f *fakeMemberManager) Close() error {
+	return nil
+}
+
+// TestReplaceFailedMember 测试成员不可达超过阈值后按 ID 移除、以 learner 重新加入、删除旧数据卷，
+// 并在 learner 追上 leader 被提升且就绪后结束替换
+func TestReplaceFailedMember(t *testing.T) {
+	old := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	c, scaling, cluster := newRotationFixture(t,
+		&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "data-secure-2", Namespace: "default", CreationTimestamp: old}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "secure-2", Namespace: "default", CreationTimestamp: old}},
+	)
+	ctx := context.Background()
+	svc := scaling.healthService.(*healthService)
+	statuses := map[int32]*clientv3.StatusResponse{
+		0: memberStatusResponse(0xa, 0xa, 100),
+		1: memberStatusResponse(0xb, 0xa, 100),
+	}
+	svc.memberStatus = func(_ context.Context, _ *etcdv1alpha1.EtcdCluster, ordinal int32) (*clientv3.StatusResponse, error) {
+		if status, ok := statuses[ordinal]; ok {
+			return status, nil
+		}
+		return nil, fmt.Errorf("connection refused")
+	}
+	etcdMembers := &fakeMemberManager{nextID: 0xd}
+	for i := int32(0); i < 3; i++ {
+		etcdMembers.members = append(etcdMembers.members, etcdv1alpha1.EtcdMember{
+			Name: k8s.MemberName(cluster, i), ID: fmt.Sprintf("%x", 0xa+i), PeerURL: k8s.MemberPeerURL(cluster, i),
+		})
+	}
+	svc.memberClient = func(context.Context, *etcdv1alpha1.EtcdCluster) (memberManager, error) {
+		return etcdMembers, nil
+	}
+	recorder := svc.k8sClient.GetRecorder().(*record.FakeRecorder)
+
+	// 不健康时间未超过阈值时只记录时间
+	require.NoError(t, svc.PerformHealthCheck(ctx, cluster))
+	assert.Nil(t, cluster.Status.MemberReplacement)
+	require.NotNil(t, cluster.Status.Members[2].UnhealthySince)
+
+	cluster.Status.Members[2].UnhealthySince = &old
+	require.NoError(t, svc.PerformHealthCheck(ctx, cluster))
+	require.NotNil(t, cluster.Status.MemberReplacement)
+	assert.Equal(t, "secure-2", cluster.Status.MemberReplacement.Member)
+	assert.Equal(t, "c", cluster.Status.MemberReplacement.MemberID)
+	assert.Equal(t, old, *cluster.Status.Members[2].UnhealthySince)
+
+	require.NoError(t, svc.PerformHealthCheck(ctx, cluster))
+	assert.Equal(t, []uint64{0xc}, etcdMembers.removed)
+	assert.Equal(t, etcdv1alpha1.EtcdMember{ID: "d", PeerURL: k8s.MemberPeerURL(cluster, 2), Role: memberRoleLearner}, etcdMembers.members[2])
+	err := c.Get(ctx, types.NamespacedName{Name: "data-secure-2", Namespace: "default"}, &corev1.PersistentVolumeClaim{})
+	assert.True(t, errors.IsNotFound(err))
+	err = c.Get(ctx, types.NamespacedName{Name: "secure-2", Namespace: "default"}, &corev1.Pod{})
+	assert.True(t, errors.IsNotFound(err))
+	for _, want := range []string{"Removed failed member secure-2 (c)", "Added member secure-2 back to the cluster as a learner", "Deleted volume data-secure-2"} {
+		assert.Contains(t, <-recorder.Events, want)
+	}
+
+	// StatefulSet 重建 Pod 和数据卷，learner 落后 leader 时不提升
+	created := metav1.NewTime(time.Now().Add(time.Minute))
+	require.NoError(t, c.Create(ctx, &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "data-secure-2", Namespace: "default", CreationTimestamp: created}}))
+	require.NoError(t, c.Create(ctx, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "secure-2", Namespace: "default", CreationTimestamp: created}}))
+	etcdMembers.members[0].ClientURL = k8s.MemberClientURL(cluster, 0)
+	etcdMembers.statuses = map[string]*clientv3.StatusResponse{
+		k8s.MemberClientURL(cluster, 0): memberStatusResponse(0xa, 0xa, 100000),
+		k8s.MemberClientURL(cluster, 2): memberStatusResponse(0xd, 0xa, 100),
+	}
+	require.NoError(t, svc.PerformHealthCheck(ctx, cluster))
+	require.NotNil(t, cluster.Status.MemberReplacement)
+	assert.Empty(t, etcdMembers.promoted)
+
+	// learner 追上 leader 后提升为投票成员，就绪后结束替换
+	etcdMembers.statuses[k8s.MemberClientURL(cluster, 2)] = memberStatusResponse(0xd, 0xa, 100000)
+	statuses[2] = memberStatusResponse(0xd, 0xa, 100000)
+	require.NoError(t, svc.PerformHealthCheck(ctx, cluster))
+	assert.Equal(t, []uint64{0xd}, etcdMembers.promoted)
+	assert.Nil(t, cluster.Status.MemberReplacement)
+	assert.Nil(t, cluster.Status.Members[2].UnhealthySince)
+	assert.Len(t, etcdMembers.members, 3)
+	assert.Contains(t, <-recorder.Events, "Member secure-2 caught up with the leader and was promoted")
+	assert.Contains(t, <-recorder.Events, utils.EventReasonMemberReplaced+" Member secure-2 was replaced")
+}
+
+// TestFailedMemberRequiresQuorum 测试健康成员不足法定人数时不替换成员
+func TestFailedMemberRequiresQuorum(t *testing.T) {
+	old := metav1.NewTime(time.Now().Add(-utils.DefaultMemberReplaceTimeout - time.Minute))
+	cluster := &etcdv1alpha1.EtcdCluster{Status: etcdv1alpha1.EtcdClusterStatus{Members: []etcdv1alpha1.EtcdMember{
+		{Name: "secure-0", Ready: true},
+		{Name: "secure-1", UnhealthySince: &old},
+		{Name: "secure-2", UnhealthySince: &old},
+	}}}
+	health := &HealthStatus{
+		Overall: HealthOverallUnavailable,
+		Members: []MemberHealth{
+			{Name: "secure-0", Status: MemberHealthHealthy},
+			{Name: "secure-1", Status: MemberHealthUnreachable},
+			{Name: "secure-2", Status: MemberHealthUnreachable},
+		},
+	}
+	_, ok := failedMember(cluster, health, time.Now())
+	assert.False(t, ok)
+
+	health.Overall = HealthOverallDegraded
+	health.Members[1].Status = MemberHealthHealthy
+	cluster.Status.Members[1] = etcdv1alpha1.EtcdMember{Name: "secure-1", Ready: true}
+	ordinal, ok := failedMember(cluster, health, time.Now())
+	assert.True(t, ok)
+	assert.Equal(t, int32(2), ordinal)
+}
@@ -0,0 +1,275 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+	"github.com/your-org/etcd-k8s-operator/pkg/k8s"
+	"github.com/your-org/etcd-k8s-operator/pkg/resource"
+	"github.com/your-org/etcd-k8s-operator/pkg/utils"
+)
+
+// reconcileMemberRestarts 驱动需要逐个重启成员的变更：先完成未结束的滚动重启，再处理证书续期和 CA 轮换，
+// 然后推进传输安全迁移，最后应用自动压缩设置、调优配置和后端配额的变化。certErr 为本轮证书检查的结果。返回非 nil 的 Result 表示本轮调谐由成员重启接管
+func (s *scalingService) reconcileMemberRestarts(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster, certErr error) (*ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	var info *resource.CertificateInfo
+	changed := false
+	if k8s.TLSRequired(cluster) && cluster.Status.TLS != nil {
+		var err error
+		info, err = s.resourceManager.Certificate().Inspect(ctx, cluster)
+		// 用户证书不可用已经反映在 TLSReady 条件中
+		if err != nil && !isInvalidCertificate(err) {
+			return nil, err
+		}
+		if info != nil {
+			changed = recordCertificates(cluster, info)
+		}
+	}
+
+	sts := &appsv1.StatefulSet{}
+	if err := s.k8sClient.Get(ctx, types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, sts); err != nil {
+		return nil, err
+	}
+
+	// 1. 继续未完成的滚动重启
+	if rollPending(sts) {
+		if changed {
+			if err := s.k8sClient.Status().Update(ctx, cluster); err != nil {
+				return nil, err
+			}
+		}
+		done, err := s.rollMembers(ctx, cluster, sts)
+		if err != nil {
+			return nil, err
+		}
+		if !done {
+			return &ctrl.Result{RequeueAfter: utils.DefaultRequeueInterval}, nil
+		}
+		logger.Info("All members restarted with the current pod template")
+		s.setCondition(cluster, utils.ConditionTypeProgressing, metav1.ConditionFalse, utils.ReasonRunning, "Members restarted")
+		return &ctrl.Result{Requeue: true}, nil
+	}
+
+	// 2. 证书续期和 CA 轮换。成员尚未使用证书时只记录证书版本，由传输安全迁移重启成员
+	if info != nil {
+		if membersUseTLS(cluster) {
+			if result, err := s.rotateCertificates(ctx, cluster, sts, info); result != nil || err != nil {
+				return result, err
+			}
+		} else if cluster.Status.TLS.Revision != info.Revision {
+			cluster.Status.TLS.Revision = info.Revision
+			changed = true
+		}
+	}
+
+	// 3. 证书可用时推进 http 与 https 之间的迁移
+	if certErr == nil {
+		if result, err := s.migrateTLS(ctx, cluster, sts); result != nil || err != nil {
+			return result, err
+		}
+	}
+
+	// 4. 自动压缩设置、调优配置或后端配额变化后逐个重启成员
+	if result, err := s.applyCompaction(ctx, cluster, sts); result != nil || err != nil {
+		return result, err
+	}
+	if result, err := s.applyConfig(ctx, cluster, sts); result != nil || err != nil {
+		return result, err
+	}
+	if result, err := s.applyQuota(ctx, cluster, sts); result != nil || err != nil {
+		return result, err
+	}
+
+	if changed {
+		if err := s.k8sClient.Status().Update(ctx, cluster); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+// startRoll 保存集群状态并按状态更新 Pod 模板。模板发生变化时先用分区挡住全部成员，之后由 rollMembers 逐个放开
+func (s *scalingService) startRoll(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster, sts *appsv1.StatefulSet) error {
+	replicas := *sts.Spec.Replicas
+	if s.resourceManager.StatefulSet().NeedsUpdate(sts, s.resourceManager.StatefulSet().Desired(cluster, replicas)) {
+		if err := s.setPartition(ctx, sts, replicas); err != nil {
+			return err
+		}
+	}
+	if err := s.k8sClient.Status().Update(ctx, cluster); err != nil {
+		return err
+	}
+	return s.resourceManager.StatefulSet().EnsureWithReplicas(ctx, cluster, replicas)
+}
+
+// rollPartition 返回 StatefulSet 的滚动分区，序号不小于分区的成员才会按新模板重启
+func rollPartition(sts *appsv1.StatefulSet) int32 {
+	if rolling := sts.Spec.UpdateStrategy.RollingUpdate; rolling != nil && rolling.Partition != nil {
+		return *rolling.Partition
+	}
+	return 0
+}
+
+// rollPending 返回是否还有成员没有按当前 Pod 模板重启
+func rollPending(sts *appsv1.StatefulSet) bool {
+	if rollPartition(sts) > 0 {
+		return true
+	}
+	return sts.Status.UpdateRevision != "" && sts.Status.CurrentRevision != sts.Status.UpdateRevision
+}
+
+// rollMembers 逐个重启成员：上一个重启的成员按新模板恢复就绪、且整个集群健康后，
+// 才把分区减一放开下一个成员，重启期间其余成员始终保持仲裁。返回滚动是否已经完成
+func (s *scalingService) rollMembers(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster, sts *appsv1.StatefulSet) (bool, error) {
+	logger := log.FromContext(ctx)
+	if sts.Status.ObservedGeneration < sts.Generation {
+		logger.Info("Waiting for StatefulSet controller to observe the new template")
+		return false, nil
+	}
+
+	partition := rollPartition(sts)
+	if partition < *sts.Spec.Replicas {
+		updated, err := s.memberUpdated(ctx, cluster, sts, partition)
+		if err != nil {
+			return false, err
+		}
+		if !updated {
+			logger.Info("Waiting for restarted member to become ready", "member", k8s.MemberName(cluster, partition))
+			return false, nil
+		}
+	}
+
+	if err := s.checkMembersHealthy(ctx, cluster); err != nil {
+		logger.Info("Waiting for cluster to become healthy before restarting the next member", "reason", err.Error())
+		return false, nil
+	}
+
+	if partition == 0 {
+		// 最后一个成员已经恢复，等待 StatefulSet 控制器记录新的当前版本
+		return sts.Status.CurrentRevision == sts.Status.UpdateRevision, nil
+	}
+
+	next := partition - 1
+	if err := s.updateMemberPeerURL(ctx, cluster, next); err != nil {
+		return false, err
+	}
+	logger.Info("Restarting member", "member", k8s.MemberName(cluster, next))
+	return false, s.setPartition(ctx, sts, next)
+}
+
+// memberUpdated 返回指定序号的成员是否已按最新模板重建并就绪
+func (s *scalingService) memberUpdated(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster, sts *appsv1.StatefulSet, ordinal int32) (bool, error) {
+	pod := &corev1.Pod{}
+	err := s.k8sClient.Get(ctx, types.NamespacedName{Name: k8s.MemberName(cluster, ordinal), Namespace: cluster.Namespace}, pod)
+	if errors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return pod.Labels[appsv1.StatefulSetRevisionLabel] == sts.Status.UpdateRevision && s.isPodReady(pod), nil
+}
+
+// checkMembersHealthy 确认集群可以读取、成员数量完整，并且每个成员都能正常响应
+func (s *scalingService) checkMembersHealthy(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster) error {
+	etcdClient, err := s.createEtcdClient(ctx, cluster)
+	if err != nil {
+		return err
+	}
+	defer etcdClient.Close()
+
+	if err := etcdClient.HealthCheck(ctx); err != nil {
+		return err
+	}
+	members, err := etcdClient.GetClusterMembers(ctx)
+	if err != nil {
+		return err
+	}
+	if int32(len(members)) < cluster.Spec.Size {
+		return fmt.Errorf("cluster has %d members, expected %d", len(members), cluster.Spec.Size)
+	}
+	// 集群外运行时只能通过 NodePort 访问单个成员
+	if !s.isRunningInCluster() {
+		return nil
+	}
+	for _, member := range members {
+		if member.ClientURL == "" {
+			return fmt.Errorf("member %s has not started", member.ID)
+		}
+		resp, err := etcdClient.MemberStatus(ctx, member.ClientURL)
+		if err != nil {
+			return err
+		}
+		// 调大配额时成员在 NOSPACE 告警期间重启
+		if errs := memberErrors(resp.Errors); len(errs) > 0 {
+			return fmt.Errorf("member %s reports errors: %s", member.Name, strings.Join(errs, "; "))
+		}
+	}
+	return nil
+}
+
+// updateMemberPeerURL 在放开成员重启前更新它公布的 peer 地址。
+// peer 协议切换后成员以新协议监听，其余成员需要按新地址访问它
+func (s *scalingService) updateMemberPeerURL(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster, ordinal int32) error {
+	logger := log.FromContext(ctx)
+
+	etcdClient, err := s.createEtcdClient(ctx, cluster)
+	if err != nil {
+		return err
+	}
+	defer etcdClient.Close()
+
+	members, err := etcdClient.GetClusterMembers(ctx)
+	if err != nil {
+		return err
+	}
+
+	name := k8s.MemberName(cluster, ordinal)
+	peerURL := k8s.MemberPeerURL(cluster, ordinal)
+	for _, member := range members {
+		if member.Name != name || member.PeerURL == peerURL {
+			continue
+		}
+		var memberID uint64
+		if _, err := fmt.Sscanf(member.ID, "%x", &memberID); err != nil {
+			return fmt.Errorf("failed to parse member ID %s: %w", member.ID, err)
+		}
+		logger.Info("Updating member peer URL", "member", name, "from", member.PeerURL, "to", peerURL)
+		return etcdClient.UpdateMember(ctx, memberID, []string{peerURL})
+	}
+	return nil
+}
+
+// setPartition 设置 StatefulSet 的滚动分区
+func (s *scalingService) setPartition(ctx context.Context, sts *appsv1.StatefulSet, partition int32) error {
+	sts.Spec.UpdateStrategy.Type = appsv1.RollingUpdateStatefulSetStrategyType
+	sts.Spec.UpdateStrategy.RollingUpdate = &appsv1.RollingUpdateStatefulSetStrategy{Partition: &partition}
+	return s.k8sClient.Update(ctx, sts)
+}
@@ -0,0 +1,206 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"fmt"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+	"github.com/your-org/etcd-k8s-operator/pkg/k8s"
+	"github.com/your-org/etcd-k8s-operator/pkg/utils"
+)
+
+// clusterMaintainer 处理 NOSPACE 告警使用的集群级 etcd 操作
+type clusterMaintainer interface {
+	CompactToCurrentRevision(ctx context.Context) (int64, error)
+	ListAlarms(ctx context.Context) ([]*pb.AlarmMember, error)
+	DisarmAlarm(ctx context.Context, alarm *pb.AlarmMember) error
+	Close() error
+}
+
+// newClusterMaintainer 创建连接到 operator 可以访问的客户端端点的 etcd 客户端
+func (s *healthService) newClusterMaintainer(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster) (clusterMaintainer, error) {
+	etcdClient, err := newEtcdClient(ctx, s.k8sClient.GetClient(), cluster, clusterClientEndpoints(ctx, s.k8sClient, cluster))
+	if err != nil {
+		return nil, err
+	}
+	return etcdClient, nil
+}
+
+// remediateNoSpace 处理 NOSPACE 告警：告警出现时记录并发出事件，再按 spec.maintenance.noSpacePolicy
+// 压缩历史版本、逐个整理成员碎片后解除告警，或者调大后端配额、逐个重启成员后解除告警。每轮调谐推进一步
+func (s *healthService) remediateNoSpace(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster, health *HealthStatus) error {
+	logger := log.FromContext(ctx)
+	maintenance := cluster.Status.Maintenance
+	if maintenance == nil {
+		return nil
+	}
+	alarmed := hasHealthIssue(health, HealthIssueNoSpace)
+	noSpace := maintenance.NoSpace
+
+	switch {
+	case !alarmed && noSpace == nil:
+		return nil
+	case !alarmed:
+		logger.Info("NOSPACE alarm cleared")
+		s.k8sClient.RecordEvent(cluster, corev1.EventTypeNormal, utils.EventReasonNoSpaceAlarm, "NOSPACE alarm cleared, the cluster accepts writes again")
+		maintenance.NoSpace = nil
+		return s.k8sClient.UpdateStatus(ctx, cluster)
+	case noSpace == nil:
+		return s.recordNoSpace(ctx, cluster)
+	}
+	if cluster.Status.MemberReplacement != nil || cluster.Status.Recovery != nil {
+		return nil
+	}
+
+	switch noSpace.Action {
+	case etcdv1alpha1.EtcdNoSpacePolicyDefragment:
+		return s.defragmentForNoSpace(ctx, cluster, health)
+	case etcdv1alpha1.EtcdNoSpacePolicyGrowQuota:
+		if maintenance.QuotaBackendBytes != noSpace.QuotaBackendBytes {
+			logger.Info("Waiting for members to restart with the grown backend quota", "quotaBackendBytes", noSpace.QuotaBackendBytes)
+			return nil
+		}
+		return s.disarmNoSpace(ctx, cluster)
+	}
+	return nil
+}
+
+// recordNoSpace 记录新出现的 NOSPACE 告警，并按策略选择处理方式
+func (s *healthService) recordNoSpace(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster) error {
+	quota := k8s.QuotaBackendBytes(cluster)
+	noSpace := &etcdv1alpha1.EtcdNoSpaceStatus{StartTime: metav1.Now()}
+	message := fmt.Sprintf("NOSPACE alarm raised: the database exceeded the %d byte quota and the cluster only accepts reads and deletes. ", quota)
+
+	switch policy := cluster.Spec.Maintenance.NoSpacePolicy; {
+	case policy == etcdv1alpha1.EtcdNoSpacePolicyGrowQuota && grownQuota(cluster) > quota:
+		noSpace.Action = etcdv1alpha1.EtcdNoSpacePolicyGrowQuota
+		noSpace.QuotaBackendBytes = grownQuota(cluster)
+		message += fmt.Sprintf("Growing the backend quota to %d bytes and restarting the members one at a time", noSpace.QuotaBackendBytes)
+	case policy == etcdv1alpha1.EtcdNoSpacePolicyGrowQuota || policy == etcdv1alpha1.EtcdNoSpacePolicyDefragment:
+		noSpace.Action = etcdv1alpha1.EtcdNoSpacePolicyDefragment
+		message += "Compacting the key space history and defragmenting the members one at a time"
+	default:
+		message += "Free space and disarm the alarm, or set spec.maintenance.noSpacePolicy"
+	}
+
+	log.FromContext(ctx).Info("NOSPACE alarm raised", "quotaBackendBytes", quota, "action", noSpace.Action)
+	s.k8sClient.RecordEvent(cluster, corev1.EventTypeWarning, utils.EventReasonNoSpaceAlarm, message)
+	cluster.Status.Maintenance.NoSpace = noSpace
+	return s.k8sClient.UpdateStatus(ctx, cluster)
+}
+
+// defragmentForNoSpace 先把历史版本压缩到当前版本，再逐个整理全部成员的碎片，数据库回到配额以内后解除告警
+func (s *healthService) defragmentForNoSpace(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster, health *HealthStatus) error {
+	noSpace := cluster.Status.Maintenance.NoSpace
+
+	// 1. 压缩历史版本，碎片整理才能回收这部分空间
+	if noSpace.CompactedRevision == 0 {
+		etcdClient, err := s.clusterMaintenance(ctx, cluster)
+		if err != nil {
+			return err
+		}
+		defer etcdClient.Close()
+
+		revision, err := etcdClient.CompactToCurrentRevision(ctx)
+		if err != nil {
+			return err
+		}
+		log.FromContext(ctx).Info("Compacted key space history for NOSPACE alarm", "revision", revision)
+		s.k8sClient.RecordEvent(cluster, corev1.EventTypeNormal, utils.EventReasonNoSpaceAlarm,
+			fmt.Sprintf("Compacted the key space history to revision %d", revision))
+		noSpace.CompactedRevision = revision
+		return s.k8sClient.UpdateStatus(ctx, cluster)
+	}
+
+	// 2. 逐个整理告警出现之后还没有整理过的成员
+	if handled, err := s.defragmentNextMember(ctx, cluster, health, noSpace.StartTime, 0); handled || err != nil {
+		return err
+	}
+
+	// 3. 整理后数据库仍然超出配额时解除告警也会再次触发，交给用户处理
+	quota := k8s.QuotaBackendBytes(cluster)
+	for _, m := range health.Members {
+		if m.DBSize >= quota {
+			s.k8sClient.RecordEvent(cluster, corev1.EventTypeWarning, utils.EventReasonNoSpaceAlarm,
+				fmt.Sprintf("Member %s database is still %d bytes after defragmentation, not below the %d byte quota. "+
+					"Delete keys or grow the volume, then disarm the alarm", m.Name, m.DBSize, quota))
+			noSpace.Action = ""
+			return s.k8sClient.UpdateStatus(ctx, cluster)
+		}
+	}
+	return s.disarmNoSpace(ctx, cluster)
+}
+
+// disarmNoSpace 解除集群中的 NOSPACE 告警，下一次健康检查确认告警消失后清除记录
+func (s *healthService) disarmNoSpace(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster) error {
+	etcdClient, err := s.clusterMaintenance(ctx, cluster)
+	if err != nil {
+		return err
+	}
+	defer etcdClient.Close()
+
+	alarms, err := etcdClient.ListAlarms(ctx)
+	if err != nil {
+		return err
+	}
+	for _, alarm := range alarms {
+		if alarm.Alarm != pb.AlarmType_NOSPACE {
+			continue
+		}
+		if err := etcdClient.DisarmAlarm(ctx, alarm); err != nil {
+			return err
+		}
+		log.FromContext(ctx).Info("Disarmed NOSPACE alarm", "memberID", fmt.Sprintf("%x", alarm.MemberID))
+		s.k8sClient.RecordEvent(cluster, corev1.EventTypeNormal, utils.EventReasonNoSpaceAlarm,
+			fmt.Sprintf("Disarmed the NOSPACE alarm of member %x", alarm.MemberID))
+	}
+	return nil
+}
+
+// grownQuota 返回 NOSPACE 告警后调大的后端配额：当前配额的两倍，不超过数据卷的一半和 etcd 建议的最大配额。
+// 数据卷还需要容纳 WAL、快照以及碎片整理时重建的数据库文件
+func grownQuota(cluster *etcdv1alpha1.EtcdCluster) int64 {
+	limit := min(cluster.Spec.Storage.Size.Value()/2, utils.MaxQuotaBackendBytes)
+	return min(k8s.QuotaBackendBytes(cluster)*2, limit)
+}
+
+// applyQuota 将 NOSPACE 告警处理中调大的后端配额写入状态，并逐个重启成员使新的配额生效
+func (s *scalingService) applyQuota(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster, sts *appsv1.StatefulSet) (*ctrl.Result, error) {
+	maintenance := cluster.Status.Maintenance
+	if maintenance == nil || maintenance.NoSpace == nil || maintenance.NoSpace.Action != etcdv1alpha1.EtcdNoSpacePolicyGrowQuota ||
+		maintenance.QuotaBackendBytes == maintenance.NoSpace.QuotaBackendBytes {
+		return nil, nil
+	}
+
+	log.FromContext(ctx).Info("Restarting members with a grown backend quota",
+		"from", k8s.QuotaBackendBytes(cluster), "to", maintenance.NoSpace.QuotaBackendBytes)
+	maintenance.QuotaBackendBytes = maintenance.NoSpace.QuotaBackendBytes
+	s.setCondition(cluster, utils.ConditionTypeProgressing, metav1.ConditionTrue, utils.ReasonRunning, "Restarting members to apply the grown backend quota")
+	if err := s.startRoll(ctx, cluster, sts); err != nil {
+		return nil, err
+	}
+	return &ctrl.Result{Requeue: true}, nil
+}
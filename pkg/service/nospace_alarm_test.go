@@ -0,0 +1,200 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+	"github.com/your-org/etcd-k8s-operator/pkg/k8s"
+	"github.com/your-org/etcd-k8s-operator/pkg/utils"
+)
+
+// noSpaceError 是成员 Status 接口报告的 NOSPACE 告警
+const noSpaceError = "memberID:10 alarm:NOSPACE "
+
+// fakeClusterMaintainer 记录压缩和解除告警的操作，解除告警后成员不再报告告警
+type fakeClusterMaintainer struct {
+	statuses map[int32]*clientv3.StatusResponse
+	calls    []string
+}
+
+func (f *fakeClusterMaintainer) CompactToCurrentRevision(context.Context) (int64, error) {
+	f.calls = append(f.calls, "compact")
+	return 42, nil
+}
+
+func (f *fakeClusterMaintainer) ListAlarms(context.Context) ([]*pb.AlarmMember, error) {
+	return []*pb.AlarmMember{{MemberID: 0xa, Alarm: pb.AlarmType_NOSPACE}}, nil
+}
+
+func (f *fakeClusterMaintainer) DisarmAlarm(_ context.Context, alarm *pb.AlarmMember) error {
+	f.calls = append(f.calls, fmt.Sprintf("disarm %x", alarm.MemberID))
+	for _, status := range f.statuses {
+		status.Errors = nil
+	}
+	return nil
+}
+
+func (f *fakeClusterMaintainer) Close() error {
+	return nil
+}
+
+// noSpaceStatuses 返回全部成员都报告 NOSPACE 告警的成员状态，成员 0 是 leader
+func noSpaceStatuses() map[int32]*clientv3.StatusResponse {
+	statuses := map[int32]*clientv3.StatusResponse{}
+	for i := int32(0); i < 3; i++ {
+		statuses[i] = memberStatusResponse(uint64(0xa+i), 0xa, 100, noSpaceError)
+		statuses[i].DbSizeInUse = 256
+	}
+	return statuses
+}
+
+// TestNoSpaceAlarmDefragmentsAndDisarms 测试 NOSPACE 告警不影响仲裁，按 Defragment 策略压缩历史版本、
+// 逐个整理成员碎片后解除告警，告警消失后清除记录
+func TestNoSpaceAlarmDefragmentsAndDisarms(t *testing.T) {
+	statuses := noSpaceStatuses()
+	svc, cluster := newHealthFixture(t, statuses)
+	ctx := context.Background()
+	var memberCalls []string
+	svc.memberMaintenance = func(_ context.Context, _ *etcdv1alpha1.EtcdCluster, ordinal int32) (memberMaintainer, error) {
+		return &fakeMaintainer{ordinal: ordinal, statuses: statuses, calls: &memberCalls}, nil
+	}
+	alarms := &fakeClusterMaintainer{statuses: statuses}
+	svc.clusterMaintenance = func(context.Context, *etcdv1alpha1.EtcdCluster) (clusterMaintainer, error) {
+		return alarms, nil
+	}
+	recorder := svc.k8sClient.GetRecorder().(*record.FakeRecorder)
+	cluster.Spec.Maintenance.NoSpacePolicy = etcdv1alpha1.EtcdNoSpacePolicyDefragment
+	require.NoError(t, svc.k8sClient.Update(ctx, cluster))
+	cluster.Status.Maintenance = &etcdv1alpha1.EtcdMaintenanceStatus{}
+
+	// 1. 告警反映在 Degraded 条件中，集群仍然可用
+	require.NoError(t, svc.PerformHealthCheck(ctx, cluster))
+	assert.True(t, meta.IsStatusConditionTrue(cluster.Status.Conditions, utils.ConditionTypeAvailable))
+	degraded := meta.FindStatusCondition(cluster.Status.Conditions, utils.ConditionTypeDegraded)
+	require.NotNil(t, degraded)
+	assert.Equal(t, metav1.ConditionTrue, degraded.Status)
+	assert.Equal(t, utils.ReasonNoSpace, degraded.Reason)
+	require.NotNil(t, cluster.Status.Maintenance.NoSpace)
+	assert.Equal(t, etcdv1alpha1.EtcdNoSpacePolicyDefragment, cluster.Status.Maintenance.NoSpace.Action)
+	assert.Contains(t, <-recorder.Events, utils.EventReasonNoSpaceAlarm+" NOSPACE alarm raised")
+
+	// 2. 先压缩历史版本
+	require.NoError(t, svc.PerformHealthCheck(ctx, cluster))
+	assert.Equal(t, int64(42), cluster.Status.Maintenance.NoSpace.CompactedRevision)
+	assert.Contains(t, <-recorder.Events, "Compacted the key space history to revision 42")
+
+	// 3. 逐个整理成员碎片，leader 最后
+	for i := 0; i < 4; i++ {
+		require.NoError(t, svc.PerformHealthCheck(ctx, cluster))
+	}
+	assert.Equal(t, []string{
+		"defragment " + k8s.MemberClientURL(cluster, 1),
+		"defragment " + k8s.MemberClientURL(cluster, 2),
+		"move leader from 0 to b",
+		"defragment " + k8s.MemberClientURL(cluster, 0),
+	}, memberCalls)
+	assert.Len(t, cluster.Status.Maintenance.Members, 3)
+	for _, want := range []string{"Defragmented member secure-1", "Defragmented member secure-2", "Transferred leadership", "Defragmented member secure-0"} {
+		assert.Contains(t, <-recorder.Events, want)
+	}
+
+	// 4. 数据库回到配额以内后解除告警
+	require.NoError(t, svc.PerformHealthCheck(ctx, cluster))
+	assert.Equal(t, []string{"compact", "disarm a"}, alarms.calls)
+	assert.Contains(t, <-recorder.Events, "Disarmed the NOSPACE alarm of member a")
+
+	require.NoError(t, svc.PerformHealthCheck(ctx, cluster))
+	assert.Nil(t, cluster.Status.Maintenance.NoSpace)
+	assert.False(t, meta.IsStatusConditionTrue(cluster.Status.Conditions, utils.ConditionTypeDegraded))
+	assert.Contains(t, <-recorder.Events, "NOSPACE alarm cleared")
+}
+
+// TestNoSpaceAlarmGrowsQuota 测试按 GrowQuota 策略调大后端配额，成员按新配额重启后解除告警
+func TestNoSpaceAlarmGrowsQuota(t *testing.T) {
+	c, scaling, cluster := newRotationFixture(t)
+	ctx := context.Background()
+	statuses := noSpaceStatuses()
+	svc := scaling.healthService.(*healthService)
+	svc.memberStatus = func(_ context.Context, _ *etcdv1alpha1.EtcdCluster, ordinal int32) (*clientv3.StatusResponse, error) {
+		return statuses[ordinal], nil
+	}
+	alarms := &fakeClusterMaintainer{statuses: statuses}
+	svc.clusterMaintenance = func(context.Context, *etcdv1alpha1.EtcdCluster) (clusterMaintainer, error) {
+		return alarms, nil
+	}
+	recorder := svc.k8sClient.GetRecorder().(*record.FakeRecorder)
+	cluster.Spec.Storage.Size = resource.MustParse("16Gi")
+	cluster.Spec.Maintenance.NoSpacePolicy = etcdv1alpha1.EtcdNoSpacePolicyGrowQuota
+	require.NoError(t, c.Update(ctx, cluster))
+	cluster.Status.Maintenance = &etcdv1alpha1.EtcdMaintenanceStatus{}
+
+	require.NoError(t, svc.PerformHealthCheck(ctx, cluster))
+	require.NotNil(t, cluster.Status.Maintenance.NoSpace)
+	assert.Equal(t, etcdv1alpha1.EtcdNoSpacePolicyGrowQuota, cluster.Status.Maintenance.NoSpace.Action)
+	assert.Equal(t, int64(4*1024*1024*1024), cluster.Status.Maintenance.NoSpace.QuotaBackendBytes)
+	assert.Contains(t, <-recorder.Events, "Growing the backend quota to 4294967296 bytes")
+
+	// 成员按新配额重启之前不解除告警
+	require.NoError(t, svc.PerformHealthCheck(ctx, cluster))
+	assert.Empty(t, alarms.calls)
+
+	sts := &appsv1.StatefulSet{}
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Name: "secure", Namespace: "default"}, sts))
+	result, err := scaling.applyQuota(ctx, cluster, sts)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, int64(4*1024*1024*1024), cluster.Status.Maintenance.QuotaBackendBytes)
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Name: "secure", Namespace: "default"}, sts))
+	assert.Equal(t, int32(3), rollPartition(sts))
+	assert.Contains(t, memberExtraConfig(sts), "quota-backend-bytes: 4294967296\n")
+
+	require.NoError(t, svc.PerformHealthCheck(ctx, cluster))
+	assert.Equal(t, []string{"disarm a"}, alarms.calls)
+}
+
+// TestGrownQuota 测试调大的配额不超过数据卷的一半和 etcd 建议的最大配额
+func TestGrownQuota(t *testing.T) {
+	cluster := &etcdv1alpha1.EtcdCluster{}
+	for _, tc := range []struct {
+		volume  string
+		current int64
+		want    int64
+	}{
+		{volume: "1Gi", want: 512 * 1024 * 1024},
+		{volume: "16Gi", want: 4 * 1024 * 1024 * 1024},
+		{volume: "100Gi", current: 6 * 1024 * 1024 * 1024, want: utils.MaxQuotaBackendBytes},
+	} {
+		cluster.Spec.Storage.Size = resource.MustParse(tc.volume)
+		cluster.Status.Maintenance = &etcdv1alpha1.EtcdMaintenanceStatus{QuotaBackendBytes: tc.current}
+		assert.Equal(t, tc.want, grownQuota(cluster), tc.volume)
+	}
+}
@@ -0,0 +1,250 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+	"github.com/your-org/etcd-k8s-operator/pkg/utils"
+)
+
+// runReplaceRestore 原地替换已有集群的数据：
+// 暂停集群控制器 -> 停止所有成员 -> 用新的集群令牌重新初始化每个成员的 PVC -> 重启成员 -> 更新集群 ID 并交还控制权
+func (s *restoreService) runReplaceRestore(ctx context.Context, r *etcdv1alpha1.EtcdRestore) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	cluster := &etcdv1alpha1.EtcdCluster{}
+	if err := s.k8sClient.Get(ctx, types.NamespacedName{Name: r.Spec.ClusterName, Namespace: r.Namespace}, cluster); err != nil {
+		if errors.IsNotFound(err) {
+			return s.failRestore(ctx, r, fmt.Errorf("etcd cluster %s/%s not found", r.Namespace, r.Spec.ClusterName))
+		}
+		return ctrl.Result{}, err
+	}
+
+	// 1. 暂停集群控制器，避免其在数据替换期间扩容或修复成员
+	switch pausedBy := cluster.Annotations[utils.AnnotationPaused]; pausedBy {
+	case r.Name:
+	case "":
+		if cluster.Annotations == nil {
+			cluster.Annotations = map[string]string{}
+		}
+		cluster.Annotations[utils.AnnotationPaused] = r.Name
+		if err := s.k8sClient.Update(ctx, cluster); err != nil {
+			return ctrl.Result{}, err
+		}
+		logger.Info("Paused etcd cluster reconciliation", "cluster", cluster.Name)
+	default:
+		return s.failRestore(ctx, r, fmt.Errorf("etcd cluster %s is paused by %s", cluster.Name, pausedBy))
+	}
+
+	if staged, result, err := s.prepareSnapshot(ctx, r); staged == nil {
+		return result, err
+	}
+
+	seeded, err := s.countSeededMembers(ctx, r)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// 2. 停止所有成员后再用快照覆盖数据目录
+	if seeded < cluster.Spec.Size {
+		stopped, err := s.scaleStatefulSet(ctx, cluster, 0)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !stopped {
+			return s.waitReplace(ctx, r, utils.ReasonClusterStopping,
+				fmt.Sprintf("Waiting for members of etcd cluster %s to stop", cluster.Name))
+		}
+
+		// 新的集群令牌确保旧成员残留的连接不会混入恢复后的集群
+		seeded, failure, err := s.seedMembers(ctx, r, cluster, replaceClusterToken(r, cluster), true)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if failure != nil {
+			return s.failRestore(ctx, r, failure)
+		}
+		if seeded < cluster.Spec.Size {
+			return s.waitReplace(ctx, r, utils.ReasonRestoreInProgress,
+				fmt.Sprintf("Seeded %d/%d members of etcd cluster %s", seeded, cluster.Spec.Size, cluster.Name))
+		}
+	}
+
+	// 3. 在恢复后的数据上重启所有成员
+	ready, err := s.scaleStatefulSet(ctx, cluster, cluster.Spec.Size)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !ready {
+		return s.waitReplace(ctx, r, utils.ReasonClusterRestarting,
+			fmt.Sprintf("Waiting for members of etcd cluster %s to become ready", cluster.Name))
+	}
+
+	clusterID, err := s.clusterID(ctx, cluster)
+	if err != nil {
+		logger.Info("Restored cluster is not serving yet", "cluster", cluster.Name, "error", err.Error())
+		return s.waitReplace(ctx, r, utils.ReasonClusterRestarting,
+			fmt.Sprintf("Waiting for etcd cluster %s to serve requests", cluster.Name))
+	}
+
+	// 4. 更新集群 ID 并把控制权交还给集群控制器
+	if err := s.resumeCluster(ctx, r, cluster, clusterID); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	now := metav1.Now()
+	r.Status.Phase = etcdv1alpha1.EtcdRestorePhaseCompleted
+	r.Status.CompletionTime = &now
+	s.setCondition(r, utils.ConditionTypeReady, metav1.ConditionTrue, utils.ReasonRestoreCompleted,
+		fmt.Sprintf("Etcd cluster %s replaced with %s (cluster ID %s)", cluster.Name, restoreSourceDescription(r), clusterID))
+	if err := s.k8sClient.UpdateStatus(ctx, r); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	s.cleanup(ctx, r)
+	s.k8sClient.RecordEvent(r, corev1.EventTypeNormal, utils.EventReasonClusterRestored,
+		fmt.Sprintf("Etcd cluster %s data replaced from %s", cluster.Name, restoreSourceDescription(r)))
+	s.k8sClient.RecordEvent(cluster, corev1.EventTypeNormal, utils.EventReasonClusterRestored,
+		fmt.Sprintf("Cluster data replaced by restore %s, new cluster ID %s", r.Name, clusterID))
+	logger.Info("Replace restore completed", "cluster", cluster.Name, "clusterID", clusterID)
+	return ctrl.Result{}, nil
+}
+
+// waitReplace 记录替换进度并稍后重新调和
+func (s *restoreService) waitReplace(ctx context.Context, r *etcdv1alpha1.EtcdRestore, reason, message string) (ctrl.Result, error) {
+	s.setCondition(r, utils.ConditionTypeReady, metav1.ConditionFalse, reason, message)
+	if err := s.k8sClient.UpdateStatus(ctx, r); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: utils.DefaultRequeueInterval}, nil
+}
+
+// countSeededMembers 返回已完成的初始化任务数
+func (s *restoreService) countSeededMembers(ctx context.Context, r *etcdv1alpha1.EtcdRestore) (int32, error) {
+	jobs := &batchv1.JobList{}
+	if err := s.k8sClient.List(ctx, jobs, ctrlclient.InNamespace(r.Namespace),
+		ctrlclient.MatchingLabels{utils.LabelEtcdRestore: r.Name}); err != nil {
+		return 0, err
+	}
+
+	var seeded int32
+	for i := range jobs.Items {
+		if jobHasCondition(&jobs.Items[i], batchv1.JobComplete) {
+			seeded++
+		}
+	}
+	return seeded, nil
+}
+
+// scaleStatefulSet 将集群的 StatefulSet 调整到指定副本数，返回是否已收敛。
+// 缩容到 0 时需要等待所有 Pod 退出，以确保 PVC 不再被 etcd 使用
+func (s *restoreService) scaleStatefulSet(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster, replicas int32) (bool, error) {
+	sts := &appsv1.StatefulSet{}
+	if err := s.k8sClient.Get(ctx, types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, sts); err != nil {
+		if errors.IsNotFound(err) && replicas == 0 {
+			return true, nil
+		}
+		return false, err
+	}
+
+	if sts.Spec.Replicas == nil || *sts.Spec.Replicas != replicas {
+		sts.Spec.Replicas = &replicas
+		if err := s.k8sClient.Update(ctx, sts); err != nil {
+			return false, err
+		}
+		log.FromContext(ctx).Info("Scaled etcd cluster statefulset", "cluster", cluster.Name, "replicas", replicas)
+		return false, nil
+	}
+
+	if replicas > 0 {
+		return sts.Status.ReadyReplicas == replicas, nil
+	}
+
+	pods := &corev1.PodList{}
+	if err := s.k8sClient.List(ctx, pods, ctrlclient.InNamespace(cluster.Namespace),
+		ctrlclient.MatchingLabels(utils.SelectorLabelsForEtcdCluster(cluster))); err != nil {
+		return false, err
+	}
+	return sts.Status.Replicas == 0 && len(pods.Items) == 0, nil
+}
+
+// resumeCluster 记录恢复后的集群 ID 并移除暂停注解
+func (s *restoreService) resumeCluster(ctx context.Context, r *etcdv1alpha1.EtcdRestore, cluster *etcdv1alpha1.EtcdCluster, clusterID string) error {
+	key := types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}
+	if err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := s.k8sClient.Get(ctx, key, cluster); err != nil {
+			return err
+		}
+		cluster.Status.ClusterID = clusterID
+		cluster.Status.ReadyReplicas = cluster.Spec.Size
+		cluster.Status.Phase = etcdv1alpha1.EtcdClusterPhaseRunning
+		return s.k8sClient.UpdateStatus(ctx, cluster)
+	}); err != nil {
+		return err
+	}
+
+	return s.unpauseCluster(ctx, r, key)
+}
+
+// unpauseCluster 移除当前恢复设置的暂停注解，其他对象设置的暂停保持不变
+func (s *restoreService) unpauseCluster(ctx context.Context, r *etcdv1alpha1.EtcdRestore, key types.NamespacedName) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cluster := &etcdv1alpha1.EtcdCluster{}
+		if err := s.k8sClient.Get(ctx, key, cluster); err != nil {
+			return ctrlclient.IgnoreNotFound(err)
+		}
+		if cluster.Annotations[utils.AnnotationPaused] != r.Name {
+			return nil
+		}
+		delete(cluster.Annotations, utils.AnnotationPaused)
+		return s.k8sClient.Update(ctx, cluster)
+	})
+}
+
+// readClusterID 通过客户端端点读取运行中集群的 ID
+func (s *restoreService) readClusterID(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster) (string, error) {
+	etcdClient, err := newEtcdClient(ctx, s.k8sClient.GetClient(), cluster, clusterClientEndpoints(ctx, s.k8sClient, cluster))
+	if err != nil {
+		return "", err
+	}
+	defer etcdClient.Close()
+
+	return etcdClient.GetClusterID(ctx)
+}
+
+// replaceClusterToken 返回替换恢复使用的 initial-cluster-token，每次恢复唯一
+func replaceClusterToken(r *etcdv1alpha1.EtcdRestore, cluster *etcdv1alpha1.EtcdCluster) string {
+	uid := string(r.UID)
+	if len(uid) > 8 {
+		uid = uid[:8]
+	}
+	return fmt.Sprintf("%s-%s", cluster.Name, uid)
+}
@@ -0,0 +1,209 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+	"github.com/your-org/etcd-k8s-operator/pkg/client"
+	"github.com/your-org/etcd-k8s-operator/pkg/utils"
+)
+
+// newRunningCluster 创建一个运行中的 3 节点集群及其 StatefulSet 和成员 Pod
+func newRunningCluster(t *testing.T, k8sClient client.KubernetesClient) *etcdv1alpha1.EtcdCluster {
+	ctx := context.Background()
+	cluster := &etcdv1alpha1.EtcdCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "restored", Namespace: "default"},
+		Spec: etcdv1alpha1.EtcdClusterSpec{
+			Size:    3,
+			Storage: etcdv1alpha1.EtcdStorageSpec{Size: resource.MustParse("1Gi")},
+		},
+	}
+	require.NoError(t, k8sClient.Create(ctx, cluster))
+	cluster.Status.Phase = etcdv1alpha1.EtcdClusterPhaseRunning
+	cluster.Status.ClusterID = "old"
+	require.NoError(t, k8sClient.UpdateStatus(ctx, cluster))
+
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "restored", Namespace: "default"},
+		Spec:       appsv1.StatefulSetSpec{Replicas: &cluster.Spec.Size},
+	}
+	require.NoError(t, k8sClient.Create(ctx, sts))
+	sts.Status = appsv1.StatefulSetStatus{Replicas: 3, ReadyReplicas: 3}
+	require.NoError(t, k8sClient.UpdateStatus(ctx, sts))
+	require.NoError(t, k8sClient.Create(ctx, &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "restored-0",
+			Namespace: "default",
+			Labels:    utils.SelectorLabelsForEtcdCluster(cluster),
+		},
+	}))
+	// 成员 0 的 PVC 已存在且没有恢复注解，替换恢复需要复用它
+	require.NoError(t, k8sClient.Create(ctx, &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "data-restored-0", Namespace: "default"},
+	}))
+	return cluster
+}
+
+// newReplaceRestore 创建替换 restored 集群数据的恢复对象
+func newReplaceRestore(t *testing.T, k8sClient client.KubernetesClient) *etcdv1alpha1.EtcdRestore {
+	r := &etcdv1alpha1.EtcdRestore{
+		ObjectMeta: metav1.ObjectMeta{Name: "restore", Namespace: "default", UID: types.UID("restore-uid")},
+		Spec: etcdv1alpha1.EtcdRestoreSpec{
+			BackupName:  "nightly",
+			ClusterName: "restored",
+			RestoreType: etcdv1alpha1.EtcdRestoreTypeReplace,
+		},
+	}
+	require.NoError(t, k8sClient.Create(context.Background(), r))
+	return r
+}
+
+// updateStatefulSet 修改集群 StatefulSet 的状态后写回，mutate 为 nil 时只读取
+func updateStatefulSet(t *testing.T, k8sClient client.KubernetesClient, mutate func(sts *appsv1.StatefulSet)) *appsv1.StatefulSet {
+	ctx := context.Background()
+	sts := &appsv1.StatefulSet{}
+	require.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Name: "restored", Namespace: "default"}, sts))
+	if mutate != nil {
+		mutate(sts)
+		require.NoError(t, k8sClient.UpdateStatus(ctx, sts))
+	}
+	return sts
+}
+
+// TestHandleRestoreReplace 测试原地替换已有集群数据的完整流程
+func TestHandleRestoreReplace(t *testing.T) {
+	ctx := context.Background()
+	svc, k8sClient := newRestoreTestService(t)
+	svc.clusterID = func(context.Context, *etcdv1alpha1.EtcdCluster) (string, error) {
+		return "c0ffee", nil
+	}
+	newCompletedBackup(t, svc, k8sClient)
+	newRunningCluster(t, k8sClient)
+	newReplaceRestore(t, k8sClient)
+
+	r := handleRestore(t, svc, k8sClient)
+	require.Equal(t, etcdv1alpha1.EtcdRestorePhaseRunning, r.Status.Phase)
+
+	// 暂停集群控制器并停止所有成员，成员停止前不创建初始化任务
+	r = handleRestore(t, svc, k8sClient)
+	assert.Equal(t, utils.ReasonClusterStopping, r.Status.Conditions[0].Reason)
+	cluster := &etcdv1alpha1.EtcdCluster{}
+	require.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Name: "restored", Namespace: "default"}, cluster))
+	assert.Equal(t, "restore", cluster.Annotations[utils.AnnotationPaused])
+	assert.Equal(t, int32(0), *updateStatefulSet(t, k8sClient, nil).Spec.Replicas)
+
+	jobs := &batchv1.JobList{}
+	require.NoError(t, k8sClient.List(ctx, jobs))
+	assert.Empty(t, jobs.Items)
+
+	// Pod 仍在运行时继续等待
+	r = handleRestore(t, svc, k8sClient)
+	assert.Equal(t, utils.ReasonClusterStopping, r.Status.Conditions[0].Reason)
+
+	updateStatefulSet(t, k8sClient, func(sts *appsv1.StatefulSet) {
+		sts.Status.Replicas = 0
+		sts.Status.ReadyReplicas = 0
+	})
+	require.NoError(t, k8sClient.Delete(ctx, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "restored-0", Namespace: "default"}}))
+
+	// 成员全部停止后使用新的集群令牌重新初始化每个成员
+	r = handleRestore(t, svc, k8sClient)
+	assert.Equal(t, utils.ReasonRestoreInProgress, r.Status.Conditions[0].Reason)
+	require.NoError(t, k8sClient.List(ctx, jobs))
+	require.Len(t, jobs.Items, 3)
+	for i := range jobs.Items {
+		job := &jobs.Items[i]
+		assert.Contains(t, job.Spec.Template.Spec.InitContainers[1].Command, "restored-restore-")
+		job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+		require.NoError(t, k8sClient.UpdateStatus(ctx, job))
+	}
+
+	// 初始化完成后重新拉起成员
+	r = handleRestore(t, svc, k8sClient)
+	assert.Equal(t, utils.ReasonClusterRestarting, r.Status.Conditions[0].Reason)
+	assert.Equal(t, int32(3), *updateStatefulSet(t, k8sClient, nil).Spec.Replicas)
+
+	updateStatefulSet(t, k8sClient, func(sts *appsv1.StatefulSet) {
+		sts.Status.Replicas = 3
+		sts.Status.ReadyReplicas = 3
+	})
+
+	// 成员就绪后更新集群 ID 并交还控制权
+	r = handleRestore(t, svc, k8sClient)
+	assert.Equal(t, etcdv1alpha1.EtcdRestorePhaseCompleted, r.Status.Phase)
+	require.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Name: "restored", Namespace: "default"}, cluster))
+	assert.NotContains(t, cluster.Annotations, utils.AnnotationPaused)
+	assert.Equal(t, "c0ffee", cluster.Status.ClusterID)
+	assert.Equal(t, etcdv1alpha1.EtcdClusterPhaseRunning, cluster.Status.Phase)
+}
+
+// TestHandleRestoreReplaceFailureResumesCluster 测试替换恢复失败时解除集群暂停
+func TestHandleRestoreReplaceFailureResumesCluster(t *testing.T) {
+	ctx := context.Background()
+	svc, k8sClient := newRestoreTestService(t)
+	newRunningCluster(t, k8sClient)
+	newReplaceRestore(t, k8sClient)
+
+	// 源备份不存在，暂停集群后恢复失败
+	handleRestore(t, svc, k8sClient)
+	r := handleRestore(t, svc, k8sClient)
+	assert.Equal(t, etcdv1alpha1.EtcdRestorePhaseFailed, r.Status.Phase)
+
+	cluster := &etcdv1alpha1.EtcdCluster{}
+	require.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Name: "restored", Namespace: "default"}, cluster))
+	assert.NotContains(t, cluster.Annotations, utils.AnnotationPaused)
+}
+
+// TestHandleRestoreReplaceRequiresCluster 测试替换恢复的目标集群必须存在且未被他人暂停
+func TestHandleRestoreReplaceRequiresCluster(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("missing cluster", func(t *testing.T) {
+		svc, k8sClient := newRestoreTestService(t)
+		newReplaceRestore(t, k8sClient)
+
+		r := handleRestore(t, svc, k8sClient)
+		assert.Equal(t, etcdv1alpha1.EtcdRestorePhaseFailed, r.Status.Phase)
+	})
+
+	t.Run("paused by another restore", func(t *testing.T) {
+		svc, k8sClient := newRestoreTestService(t)
+		require.NoError(t, k8sClient.Create(ctx, &etcdv1alpha1.EtcdCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "restored",
+				Namespace:   "default",
+				Annotations: map[string]string{utils.AnnotationPaused: "other"},
+			},
+		}))
+		newReplaceRestore(t, k8sClient)
+
+		r := handleRestore(t, svc, k8sClient)
+		assert.Equal(t, etcdv1alpha1.EtcdRestorePhaseFailed, r.Status.Phase)
+	})
+}
@@ -0,0 +1,565 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+	"github.com/your-org/etcd-k8s-operator/pkg/backup"
+	"github.com/your-org/etcd-k8s-operator/pkg/client"
+	"github.com/your-org/etcd-k8s-operator/pkg/k8s"
+	"github.com/your-org/etcd-k8s-operator/pkg/utils"
+)
+
+// restoreService 恢复服务实现
+type restoreService struct {
+	k8sClient      client.KubernetesClient
+	clusterService ClusterService
+	// backups 复用备份服务的存储后端和加密密钥解析
+	backups *backupService
+	// stager 暂存解密、解压后的快照，供成员 PVC 的初始化任务下载
+	stager *backup.SnapshotStager
+	// snapshotServerURL 初始化任务访问 Operator 快照下载服务的地址
+	snapshotServerURL string
+	// clusterID 读取恢复后集群的 ID，测试中可替换
+	clusterID func(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster) (string, error)
+}
+
+// NewRestoreService 创建恢复服务实例
+func NewRestoreService(
+	k8sClient client.KubernetesClient,
+	clusterService ClusterService,
+	backupDir string,
+	snapshotServerURL string,
+) RestoreService {
+	s := &restoreService{
+		k8sClient:         k8sClient,
+		clusterService:    clusterService,
+		backups:           newBackupService(k8sClient, backupDir),
+		stager:            NewRestoreStager(backupDir),
+		snapshotServerURL: strings.TrimRight(snapshotServerURL, "/"),
+	}
+	s.clusterID = s.readClusterID
+	return s
+}
+
+// NewRestoreStager 返回恢复快照的暂存器，快照下载服务和恢复服务需使用同一目录
+func NewRestoreStager(backupDir string) *backup.SnapshotStager {
+	return backup.NewSnapshotStager(filepath.Join(backupDir, ".restore"))
+}
+
+// HandleRestore 处理恢复状态机
+func (s *restoreService) HandleRestore(ctx context.Context, r *etcdv1alpha1.EtcdRestore) (ctrl.Result, error) {
+	switch r.Status.Phase {
+	case "":
+		return s.startRestore(ctx, r)
+	case etcdv1alpha1.EtcdRestorePhaseRunning:
+		if r.Spec.RestoreType == etcdv1alpha1.EtcdRestoreTypeReplace {
+			return s.runReplaceRestore(ctx, r)
+		}
+		return s.runRestore(ctx, r)
+	default:
+		// Completed 和 Failed 都是终态
+		return ctrl.Result{}, nil
+	}
+}
+
+// startRestore 校验恢复规范并检查目标集群：New 要求集群名称可用，Replace 要求集群已存在
+func (s *restoreService) startRestore(ctx context.Context, r *etcdv1alpha1.EtcdRestore) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	if err := s.validateRestoreSpec(r); err != nil {
+		return s.failRestore(ctx, r, err)
+	}
+
+	cluster := s.desiredCluster(r)
+	existing := &etcdv1alpha1.EtcdCluster{}
+	err := s.k8sClient.Get(ctx, types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, existing)
+	if err != nil && !errors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+
+	if r.Spec.RestoreType == etcdv1alpha1.EtcdRestoreTypeReplace {
+		if errors.IsNotFound(err) {
+			return s.failRestore(ctx, r, fmt.Errorf("etcd cluster %s/%s not found", cluster.Namespace, cluster.Name))
+		}
+		if pausedBy := existing.Annotations[utils.AnnotationPaused]; pausedBy != "" && pausedBy != r.Name {
+			return s.failRestore(ctx, r, fmt.Errorf("etcd cluster %s/%s is paused by %s", cluster.Namespace, cluster.Name, pausedBy))
+		}
+	} else {
+		if err := s.clusterService.ValidateClusterSpec(cluster); err != nil {
+			return s.failRestore(ctx, r, fmt.Errorf("invalid clusterTemplate: %w", err))
+		}
+		if err == nil && existing.Annotations[utils.AnnotationRestoredFrom] != r.Name {
+			return s.failRestore(ctx, r, fmt.Errorf("etcd cluster %s/%s already exists", cluster.Namespace, cluster.Name))
+		}
+	}
+
+	now := metav1.Now()
+	r.Status.Phase = etcdv1alpha1.EtcdRestorePhaseRunning
+	r.Status.StartTime = &now
+	r.Status.RestoredCluster = cluster.Name
+	s.setCondition(r, utils.ConditionTypeReady, metav1.ConditionFalse, utils.ReasonRestoreInProgress,
+		fmt.Sprintf("Restoring etcd cluster %s from %s", cluster.Name, restoreSourceDescription(r)))
+	if err := s.k8sClient.UpdateStatus(ctx, r); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	logger.Info("Restore started", "cluster", cluster.Name, "source", restoreSourceDescription(r))
+	return ctrl.Result{Requeue: true}, nil
+}
+
+// runRestore 依次暂存快照、初始化成员 PVC、创建集群并等待其就绪。
+// 每一步都是幂等的，调谐中断后可以从任意一步继续
+func (s *restoreService) runRestore(ctx context.Context, r *etcdv1alpha1.EtcdRestore) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	cluster := s.desiredCluster(r)
+
+	// 集群已创建说明数据已经就绪，只需等待集群运行
+	existing := &etcdv1alpha1.EtcdCluster{}
+	err := s.k8sClient.Get(ctx, types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, existing)
+	if err == nil {
+		if existing.Annotations[utils.AnnotationRestoredFrom] != r.Name {
+			return s.failRestore(ctx, r, fmt.Errorf("etcd cluster %s/%s was created outside of this restore", cluster.Namespace, cluster.Name))
+		}
+		return s.waitForRestoredCluster(ctx, r, existing)
+	}
+	if !errors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+
+	if staged, result, err := s.prepareSnapshot(ctx, r); staged == nil {
+		return result, err
+	}
+
+	// 新集群沿用集群控制器的 initial-cluster-token
+	seeded, failure, err := s.seedMembers(ctx, r, cluster, cluster.Name, false)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if failure != nil {
+		return s.failRestore(ctx, r, failure)
+	}
+	if seeded < cluster.Spec.Size {
+		s.setCondition(r, utils.ConditionTypeReady, metav1.ConditionFalse, utils.ReasonRestoreInProgress,
+			fmt.Sprintf("Seeding member data: %d/%d members restored", seeded, cluster.Spec.Size))
+		if err := s.k8sClient.UpdateStatus(ctx, r); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: utils.DefaultRequeueInterval}, nil
+	}
+
+	// 所有成员的数据都已就绪，创建集群后成员会同时启动
+	if cluster.Annotations == nil {
+		cluster.Annotations = map[string]string{}
+	}
+	cluster.Annotations[utils.AnnotationRestoredFrom] = r.Name
+	if err := s.k8sClient.Create(ctx, cluster); err != nil && !errors.IsAlreadyExists(err) {
+		return ctrl.Result{}, err
+	}
+
+	s.setCondition(r, utils.ConditionTypeReady, metav1.ConditionFalse, utils.ReasonWaitingForCluster,
+		fmt.Sprintf("Member data restored, waiting for etcd cluster %s to be running", cluster.Name))
+	if err := s.k8sClient.UpdateStatus(ctx, r); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	logger.Info("Restored cluster created", "cluster", cluster.Name, "size", cluster.Spec.Size)
+	return ctrl.Result{RequeueAfter: utils.DefaultRequeueInterval}, nil
+}
+
+// waitForRestoredCluster 等待恢复出的集群进入 Running 阶段
+func (s *restoreService) waitForRestoredCluster(ctx context.Context, r *etcdv1alpha1.EtcdRestore, cluster *etcdv1alpha1.EtcdCluster) (ctrl.Result, error) {
+	switch cluster.Status.Phase {
+	case etcdv1alpha1.EtcdClusterPhaseRunning:
+	case etcdv1alpha1.EtcdClusterPhaseFailed:
+		return s.failRestore(ctx, r, fmt.Errorf("restored etcd cluster %s has failed", cluster.Name))
+	default:
+		return ctrl.Result{RequeueAfter: utils.DefaultRequeueInterval}, nil
+	}
+
+	now := metav1.Now()
+	r.Status.Phase = etcdv1alpha1.EtcdRestorePhaseCompleted
+	r.Status.CompletionTime = &now
+	s.setCondition(r, utils.ConditionTypeReady, metav1.ConditionTrue, utils.ReasonRestoreCompleted,
+		fmt.Sprintf("Etcd cluster %s restored from %s", cluster.Name, restoreSourceDescription(r)))
+	if err := s.k8sClient.UpdateStatus(ctx, r); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	s.cleanup(ctx, r)
+	s.k8sClient.RecordEvent(r, corev1.EventTypeNormal, utils.EventReasonClusterRestored,
+		fmt.Sprintf("Etcd cluster %s restored with %d members", cluster.Name, cluster.Spec.Size))
+	log.FromContext(ctx).Info("Restore completed", "cluster", cluster.Name)
+	return ctrl.Result{}, nil
+}
+
+// prepareSnapshot 确保源快照已暂存并且下载令牌可供初始化任务读取。
+// 返回的快照为 nil 时，调用方应直接返回 result 和 err (等待快照来源或恢复已失败)
+func (s *restoreService) prepareSnapshot(ctx context.Context, r *etcdv1alpha1.EtcdRestore) (*backup.StagedSnapshot, ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	staged, err := s.stager.Lookup(string(r.UID))
+	if os.IsNotExist(err) {
+		src, result, err := s.resolveSnapshotSource(ctx, r)
+		if src == nil {
+			return nil, result, err
+		}
+
+		if staged, err = s.stageSnapshot(ctx, r, src); err != nil {
+			result, err := s.failRestore(ctx, r, fmt.Errorf("failed to stage snapshot: %w", err))
+			return nil, result, err
+		}
+		logger.Info("Snapshot staged", "source", restoreSourceDescription(r), "size", staged.Size)
+
+		// 快照已暂存，尽早释放源 PVC
+		if err := s.deleteSourcePod(ctx, r); err != nil {
+			logger.Error(err, "Failed to delete snapshot source pod")
+		}
+	} else if err != nil {
+		return nil, ctrl.Result{}, err
+	}
+	r.Status.RestoredSize = staged.Size
+
+	if err := s.ensureTokenSecret(ctx, r, staged.Token); err != nil {
+		return nil, ctrl.Result{}, err
+	}
+	return staged, ctrl.Result{}, nil
+}
+
+// seedMembers 确保每个成员的数据 PVC 和初始化任务存在，返回已完成初始化的成员数。
+// failure 表示恢复无法继续 (PVC 冲突或任务失败)，err 为可重试的错误
+func (s *restoreService) seedMembers(ctx context.Context, r *etcdv1alpha1.EtcdRestore, cluster *etcdv1alpha1.EtcdCluster, clusterToken string, allowExisting bool) (int32, error, error) {
+	for i := int32(0); i < cluster.Spec.Size; i++ {
+		if failure, err := s.ensureMemberPVC(ctx, r, cluster, i, allowExisting); failure != nil || err != nil {
+			return 0, failure, err
+		}
+	}
+	return s.ensureSeedJobs(ctx, r, cluster, clusterToken)
+}
+
+// stageSnapshot 读取快照来源，依次完成 SHA-256 校验、解密和解压后写入暂存目录
+func (s *restoreService) stageSnapshot(ctx context.Context, r *etcdv1alpha1.EtcdRestore, src *snapshotSource) (*backup.StagedSnapshot, error) {
+	ctx, cancel := context.WithTimeout(ctx, utils.DefaultBackupTimeout)
+	defer cancel()
+
+	rc, err := src.storage.Get(ctx, src.key)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	var raw io.Reader = rc
+	if !r.Spec.SkipHashCheck && src.sha256 != "" {
+		raw = backup.ChecksumReader(rc, src.sha256)
+	}
+
+	var keys backup.KeyRing
+	if src.encryption != nil {
+		if keys, err = s.backups.encryptionKeys(ctx, src.namespace, src.encryption); err != nil {
+			return nil, err
+		}
+	}
+
+	snapshot, _, err := backup.OpenSnapshotReader(raw, keys)
+	if err != nil {
+		return nil, err
+	}
+	defer snapshot.Close()
+
+	staged, err := s.stager.Stage(ctx, string(r.UID), &drainingReader{r: snapshot, rest: raw})
+	if err != nil {
+		return nil, err
+	}
+
+	// 提前检查快照内容，避免损坏的快照到初始化任务中才失败
+	if !r.Spec.SkipHashCheck {
+		if _, err := backup.VerifySnapshotFile(staged.Path); err != nil {
+			if rmErr := s.stager.Remove(ctx, staged.ID); rmErr != nil {
+				log.FromContext(ctx).Error(rmErr, "Failed to remove staged snapshot")
+			}
+			return nil, err
+		}
+	}
+	return staged, nil
+}
+
+// ensureTokenSecret 确保初始化任务读取下载令牌的 Secret 存在
+func (s *restoreService) ensureTokenSecret(ctx context.Context, r *etcdv1alpha1.EtcdRestore, token string) error {
+	secret := &corev1.Secret{}
+	err := s.k8sClient.Get(ctx, types.NamespacedName{Name: restoreTokenSecretName(r), Namespace: r.Namespace}, secret)
+	if err == nil {
+		if string(secret.Data[utils.SecretKeyRestoreToken]) == token {
+			return nil
+		}
+		if secret.Data == nil {
+			secret.Data = map[string][]byte{}
+		}
+		secret.Data[utils.SecretKeyRestoreToken] = []byte(token)
+		return s.k8sClient.Update(ctx, secret)
+	}
+	if !errors.IsNotFound(err) {
+		return err
+	}
+
+	secret = newRestoreTokenSecret(r)
+	secret.Data[utils.SecretKeyRestoreToken] = []byte(token)
+	if err := s.setOwner(r, secret); err != nil {
+		return err
+	}
+	return s.k8sClient.Create(ctx, secret)
+}
+
+// ensureMemberPVC 预先创建成员的数据 PVC。PVC 不归恢复对象所有，恢复完成后由 StatefulSet 接管。
+// allowExisting 为 false 时不覆盖其他集群或其他恢复留下的数据
+func (s *restoreService) ensureMemberPVC(ctx context.Context, r *etcdv1alpha1.EtcdRestore, cluster *etcdv1alpha1.EtcdCluster, ordinal int32, allowExisting bool) (error, error) {
+	desired := k8s.BuildMemberPVC(cluster, ordinal)
+
+	existing := &corev1.PersistentVolumeClaim{}
+	err := s.k8sClient.Get(ctx, types.NamespacedName{Name: desired.Name, Namespace: desired.Namespace}, existing)
+	if err == nil {
+		if !allowExisting && existing.Annotations[utils.AnnotationRestoredFrom] != r.Name {
+			return fmt.Errorf("persistent volume claim %s already exists", desired.Name), nil
+		}
+		return nil, nil
+	}
+	if !errors.IsNotFound(err) {
+		return nil, err
+	}
+
+	desired.Annotations = map[string]string{utils.AnnotationRestoredFrom: r.Name}
+	return nil, s.k8sClient.Create(ctx, desired)
+}
+
+// ensureSeedJobs 为每个成员创建初始化任务，返回已完成的任务数，任一任务失败时返回 failure
+func (s *restoreService) ensureSeedJobs(ctx context.Context, r *etcdv1alpha1.EtcdRestore, cluster *etcdv1alpha1.EtcdCluster, clusterToken string) (int32, error, error) {
+	opts := k8s.RestoreSeedJobOptions{
+		SnapshotURL:   s.snapshotServerURL + backup.SnapshotServerPathPrefix + string(r.UID),
+		TokenSecret:   restoreTokenSecretName(r),
+		ClusterToken:  clusterToken,
+		SkipHashCheck: r.Spec.SkipHashCheck,
+	}
+
+	var succeeded int32
+	for i := int32(0); i < cluster.Spec.Size; i++ {
+		desired := k8s.BuildRestoreSeedJob(r, cluster, i, opts)
+
+		job := &batchv1.Job{}
+		err := s.k8sClient.Get(ctx, types.NamespacedName{Name: desired.Name, Namespace: desired.Namespace}, job)
+		if errors.IsNotFound(err) {
+			if err := s.setOwner(r, desired); err != nil {
+				return 0, nil, err
+			}
+			if err := s.k8sClient.Create(ctx, desired); err != nil && !errors.IsAlreadyExists(err) {
+				return 0, nil, err
+			}
+			continue
+		}
+		if err != nil {
+			return 0, nil, err
+		}
+
+		switch {
+		case jobHasCondition(job, batchv1.JobComplete):
+			succeeded++
+		case jobHasCondition(job, batchv1.JobFailed):
+			return 0, fmt.Errorf("seed job %s for member %s failed", job.Name, k8s.MemberName(cluster, i)), nil
+		}
+	}
+	return succeeded, nil, nil
+}
+
+// failRestore 将恢复标记为失败并清理暂存的快照
+func (s *restoreService) failRestore(ctx context.Context, r *etcdv1alpha1.EtcdRestore, cause error) (ctrl.Result, error) {
+	now := metav1.Now()
+	r.Status.Phase = etcdv1alpha1.EtcdRestorePhaseFailed
+	r.Status.CompletionTime = &now
+	s.setCondition(r, utils.ConditionTypeReady, metav1.ConditionFalse, utils.ReasonRestoreFailed, cause.Error())
+
+	if err := s.k8sClient.UpdateStatus(ctx, r); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	s.cleanup(ctx, r)
+	if r.Spec.RestoreType == etcdv1alpha1.EtcdRestoreTypeReplace {
+		// 交还控制权，集群控制器会按原副本数重新拉起成员
+		key := types.NamespacedName{Name: r.Spec.ClusterName, Namespace: r.Namespace}
+		if err := s.unpauseCluster(ctx, r, key); err != nil {
+			log.FromContext(ctx).Error(err, "Failed to resume etcd cluster reconciliation", "cluster", r.Spec.ClusterName)
+		}
+	}
+	s.k8sClient.RecordEvent(r, corev1.EventTypeWarning, utils.EventReasonRestoreFailed, cause.Error())
+	return ctrl.Result{}, nil
+}
+
+// cleanup 删除暂存的快照和下载令牌，初始化任务保留以便排查问题
+func (s *restoreService) cleanup(ctx context.Context, r *etcdv1alpha1.EtcdRestore) {
+	logger := log.FromContext(ctx)
+	if err := s.stager.Remove(ctx, string(r.UID)); err != nil {
+		logger.Error(err, "Failed to remove staged snapshot")
+	}
+	if err := s.deleteSourcePod(ctx, r); err != nil {
+		logger.Error(err, "Failed to delete snapshot source pod")
+	}
+
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: restoreTokenSecretName(r), Namespace: r.Namespace}}
+	if err := s.k8sClient.Delete(ctx, secret); err != nil && !errors.IsNotFound(err) {
+		logger.Error(err, "Failed to delete snapshot token secret")
+	}
+}
+
+// validateRestoreSpec 验证恢复规范
+func (s *restoreService) validateRestoreSpec(r *etcdv1alpha1.EtcdRestore) error {
+	if r.Spec.ClusterName == "" {
+		return fmt.Errorf("clusterName cannot be empty")
+	}
+	switch {
+	case r.Spec.BackupName == "" && r.Spec.Source == nil:
+		return fmt.Errorf("one of backupName and source must be set")
+	case r.Spec.BackupName != "" && r.Spec.Source != nil:
+		return fmt.Errorf("backupName and source are mutually exclusive")
+	case r.Spec.Source != nil:
+		if err := validateRestoreSource(r.Spec.Source); err != nil {
+			return err
+		}
+	}
+
+	switch r.Spec.RestoreType {
+	case etcdv1alpha1.EtcdRestoreTypeNew, "":
+		if r.Spec.ClusterTemplate == nil {
+			return fmt.Errorf("clusterTemplate is required for restore type New")
+		}
+		return nil
+	case etcdv1alpha1.EtcdRestoreTypeReplace:
+		return nil
+	default:
+		return fmt.Errorf("restore type %s is not supported yet", r.Spec.RestoreType)
+	}
+}
+
+// desiredCluster 根据 ClusterTemplate 构建待恢复的集群，并填充与集群控制器相同的默认值
+func (s *restoreService) desiredCluster(r *etcdv1alpha1.EtcdRestore) *etcdv1alpha1.EtcdCluster {
+	cluster := &etcdv1alpha1.EtcdCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      r.Spec.ClusterName,
+			Namespace: r.Namespace,
+		},
+	}
+	if r.Spec.ClusterTemplate != nil {
+		cluster.Spec = *r.Spec.ClusterTemplate.DeepCopy()
+	}
+	s.clusterService.SetDefaults(cluster)
+	// 与集群控制器初始化时记录的传输安全配置一致，种子成员的 peer 地址才能匹配
+	cluster.Status.TLS = k8s.DesiredTLSStatus(cluster)
+	return cluster
+}
+
+// getSourceBackup 获取恢复使用的 EtcdBackup
+func (s *restoreService) getSourceBackup(ctx context.Context, r *etcdv1alpha1.EtcdRestore) (*etcdv1alpha1.EtcdBackup, error) {
+	b := &etcdv1alpha1.EtcdBackup{}
+	err := s.k8sClient.Get(ctx, types.NamespacedName{Name: r.Spec.BackupName, Namespace: s.backupNamespace(r)}, b)
+	return b, err
+}
+
+// backupNamespace 返回备份所在的命名空间，默认与恢复对象相同
+func (s *restoreService) backupNamespace(r *etcdv1alpha1.EtcdRestore) string {
+	if r.Spec.BackupNamespace != "" {
+		return r.Spec.BackupNamespace
+	}
+	return r.Namespace
+}
+
+// setOwner 设置恢复对象为 owner，恢复对象删除时一并回收
+func (s *restoreService) setOwner(r *etcdv1alpha1.EtcdRestore, obj metav1.Object) error {
+	if c := s.k8sClient.GetClient(); c != nil {
+		return ctrl.SetControllerReference(r, obj, c.Scheme())
+	}
+	return nil
+}
+
+// setCondition 设置恢复条件
+func (s *restoreService) setCondition(r *etcdv1alpha1.EtcdRestore, conditionType string, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&r.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: r.Generation,
+	})
+}
+
+// newRestoreTokenSecret 返回空的恢复令牌 Secret
+func newRestoreTokenSecret(r *etcdv1alpha1.EtcdRestore) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      restoreTokenSecretName(r),
+			Namespace: r.Namespace,
+			Labels:    map[string]string{utils.LabelEtcdRestore: r.Name},
+		},
+		Data: map[string][]byte{},
+	}
+}
+
+// restoreTokenSecretName 返回保存快照下载令牌的 Secret 名称
+func restoreTokenSecretName(r *etcdv1alpha1.EtcdRestore) string {
+	return fmt.Sprintf("%s-snapshot-token", r.Name)
+}
+
+// jobHasCondition 判断任务是否处于指定的终态
+func jobHasCondition(job *batchv1.Job, conditionType batchv1.JobConditionType) bool {
+	for _, c := range job.Status.Conditions {
+		if c.Type == conditionType && c.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// drainingReader 在快照明文读完时继续读完存储中的原始数据。
+// 解码器可能在对象末尾之前停止读取，这样校验和的结果能在暂存完成之前返回
+type drainingReader struct {
+	r    io.Reader
+	rest io.Reader
+}
+
+func (d *drainingReader) Read(p []byte) (int, error) {
+	n, err := d.r.Read(p)
+	if err == io.EOF {
+		if _, drainErr := io.Copy(io.Discard, d.rest); drainErr != nil {
+			return n, drainErr
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,229 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+	"github.com/your-org/etcd-k8s-operator/pkg/client"
+	"github.com/your-org/etcd-k8s-operator/pkg/k8s"
+	resourcepkg "github.com/your-org/etcd-k8s-operator/pkg/resource"
+	"github.com/your-org/etcd-k8s-operator/pkg/utils"
+)
+
+// newRestoreTestService 创建使用 fake client 的恢复服务
+func newRestoreTestService(t *testing.T) (*restoreService, client.KubernetesClient) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, etcdv1alpha1.AddToScheme(scheme))
+
+	c := fake.NewClientBuilder().WithScheme(scheme).
+		WithStatusSubresource(&etcdv1alpha1.EtcdRestore{}, &etcdv1alpha1.EtcdCluster{}, &etcdv1alpha1.EtcdBackup{}, &batchv1.Job{}).
+		Build()
+	k8sClient := client.NewKubernetesClient(c, record.NewFakeRecorder(10))
+	clusterService := NewClusterService(k8sClient, resourcepkg.NewResourceManager(k8sClient, k8s.StatefulSetOptions{}))
+
+	svc := NewRestoreService(k8sClient, clusterService, t.TempDir(), "http://operator:8082/").(*restoreService)
+	return svc, k8sClient
+}
+
+// newCompletedBackup 在本地存储中写入快照并创建对应的已完成备份
+func newCompletedBackup(t *testing.T, svc *restoreService, k8sClient client.KubernetesClient) *etcdv1alpha1.EtcdBackup {
+	key := "default/source/20250801-120000.db.gz"
+	result := storeTestSnapshot(t, svc.backups, key)
+
+	b := &etcdv1alpha1.EtcdBackup{
+		ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: "default"},
+		Spec: etcdv1alpha1.EtcdBackupSpec{
+			ClusterName: "source",
+			StorageType: etcdv1alpha1.EtcdBackupStorageTypeLocal,
+		},
+	}
+	ctx := context.Background()
+	require.NoError(t, k8sClient.Create(ctx, b))
+	b.Status.Phase = etcdv1alpha1.EtcdBackupPhaseCompleted
+	b.Status.StoragePath = svc.backups.localStorage.URL(key)
+	b.Status.SHA256 = result.SHA256
+	require.NoError(t, k8sClient.UpdateStatus(ctx, b))
+	return b
+}
+
+// newTestRestore 创建从 nightly 备份恢复出 3 节点新集群的恢复对象
+func newTestRestore(t *testing.T, k8sClient client.KubernetesClient) *etcdv1alpha1.EtcdRestore {
+	r := &etcdv1alpha1.EtcdRestore{
+		ObjectMeta: metav1.ObjectMeta{Name: "restore", Namespace: "default", UID: types.UID("restore-uid")},
+		Spec: etcdv1alpha1.EtcdRestoreSpec{
+			BackupName:  "nightly",
+			ClusterName: "restored",
+			RestoreType: etcdv1alpha1.EtcdRestoreTypeNew,
+			ClusterTemplate: &etcdv1alpha1.EtcdClusterSpec{
+				Size:    3,
+				Storage: etcdv1alpha1.EtcdStorageSpec{Size: resource.MustParse("1Gi")},
+			},
+		},
+	}
+	require.NoError(t, k8sClient.Create(context.Background(), r))
+	return r
+}
+
+// handleRestore 重新获取恢复对象后调谐一次
+func handleRestore(t *testing.T, svc *restoreService, k8sClient client.KubernetesClient) *etcdv1alpha1.EtcdRestore {
+	ctx := context.Background()
+	r := &etcdv1alpha1.EtcdRestore{}
+	require.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Name: "restore", Namespace: "default"}, r))
+	_, err := svc.HandleRestore(ctx, r)
+	require.NoError(t, err)
+	require.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Name: "restore", Namespace: "default"}, r))
+	return r
+}
+
+// TestHandleRestoreNewCluster 测试从备份恢复出新集群的完整流程
+func TestHandleRestoreNewCluster(t *testing.T) {
+	ctx := context.Background()
+	svc, k8sClient := newRestoreTestService(t)
+	newCompletedBackup(t, svc, k8sClient)
+	newTestRestore(t, k8sClient)
+
+	r := handleRestore(t, svc, k8sClient)
+	require.Equal(t, etcdv1alpha1.EtcdRestorePhaseRunning, r.Status.Phase)
+	assert.Equal(t, "restored", r.Status.RestoredCluster)
+
+	// 暂存快照、预建 PVC 并为每个成员创建初始化任务
+	r = handleRestore(t, svc, k8sClient)
+	require.Equal(t, etcdv1alpha1.EtcdRestorePhaseRunning, r.Status.Phase)
+	staged, err := svc.stager.Lookup("restore-uid")
+	require.NoError(t, err)
+	assert.Equal(t, staged.Size, r.Status.RestoredSize)
+
+	secret := &corev1.Secret{}
+	require.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Name: "restore-snapshot-token", Namespace: "default"}, secret))
+	assert.Equal(t, staged.Token, string(secret.Data[utils.SecretKeyRestoreToken]))
+
+	for _, name := range []string{"data-restored-0", "data-restored-1", "data-restored-2"} {
+		pvc := &corev1.PersistentVolumeClaim{}
+		require.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Name: name, Namespace: "default"}, pvc))
+		assert.Equal(t, "restore", pvc.Annotations[utils.AnnotationRestoredFrom])
+	}
+
+	cluster := &etcdv1alpha1.EtcdCluster{}
+	err = k8sClient.Get(ctx, types.NamespacedName{Name: "restored", Namespace: "default"}, cluster)
+	assert.True(t, errors.IsNotFound(err), "cluster must not start before its members are seeded")
+
+	// 初始化任务全部完成后创建集群
+	jobs := &batchv1.JobList{}
+	require.NoError(t, k8sClient.List(ctx, jobs))
+	require.Len(t, jobs.Items, 3)
+	for i := range jobs.Items {
+		job := &jobs.Items[i]
+		assert.Equal(t, "http://operator:8082/snapshots/restore-uid", job.Spec.Template.Spec.InitContainers[0].Env[0].Value)
+		job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+		require.NoError(t, k8sClient.UpdateStatus(ctx, job))
+	}
+
+	handleRestore(t, svc, k8sClient)
+	require.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Name: "restored", Namespace: "default"}, cluster))
+	assert.Equal(t, "restore", cluster.Annotations[utils.AnnotationRestoredFrom])
+	assert.Equal(t, int32(3), cluster.Spec.Size)
+
+	// 集群运行后恢复完成并清理暂存的快照
+	cluster.Status.Phase = etcdv1alpha1.EtcdClusterPhaseRunning
+	require.NoError(t, k8sClient.UpdateStatus(ctx, cluster))
+
+	r = handleRestore(t, svc, k8sClient)
+	assert.Equal(t, etcdv1alpha1.EtcdRestorePhaseCompleted, r.Status.Phase)
+	assert.NotNil(t, r.Status.CompletionTime)
+	_, err = svc.stager.Lookup("restore-uid")
+	assert.True(t, os.IsNotExist(err))
+	err = k8sClient.Get(ctx, types.NamespacedName{Name: "restore-snapshot-token", Namespace: "default"}, secret)
+	assert.True(t, errors.IsNotFound(err))
+}
+
+// TestHandleRestoreChecksumMismatch 测试快照 SHA-256 与备份记录不一致时恢复失败
+func TestHandleRestoreChecksumMismatch(t *testing.T) {
+	ctx := context.Background()
+	svc, k8sClient := newRestoreTestService(t)
+	b := newCompletedBackup(t, svc, k8sClient)
+	b.Status.SHA256 = "0000"
+	require.NoError(t, k8sClient.UpdateStatus(ctx, b))
+	newTestRestore(t, k8sClient)
+
+	handleRestore(t, svc, k8sClient)
+	r := handleRestore(t, svc, k8sClient)
+	assert.Equal(t, etcdv1alpha1.EtcdRestorePhaseFailed, r.Status.Phase)
+	_, err := svc.stager.Lookup("restore-uid")
+	assert.True(t, os.IsNotExist(err))
+}
+
+// TestHandleRestoreRefusesExistingData 测试不覆盖已存在的成员 PVC 和集群
+func TestHandleRestoreRefusesExistingData(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("existing pvc", func(t *testing.T) {
+		svc, k8sClient := newRestoreTestService(t)
+		newCompletedBackup(t, svc, k8sClient)
+		newTestRestore(t, k8sClient)
+		require.NoError(t, k8sClient.Create(ctx, &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "data-restored-1", Namespace: "default"},
+		}))
+
+		handleRestore(t, svc, k8sClient)
+		r := handleRestore(t, svc, k8sClient)
+		assert.Equal(t, etcdv1alpha1.EtcdRestorePhaseFailed, r.Status.Phase)
+	})
+
+	t.Run("existing cluster", func(t *testing.T) {
+		svc, k8sClient := newRestoreTestService(t)
+		newTestRestore(t, k8sClient)
+		require.NoError(t, k8sClient.Create(ctx, &etcdv1alpha1.EtcdCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "restored", Namespace: "default"},
+		}))
+
+		r := handleRestore(t, svc, k8sClient)
+		assert.Equal(t, etcdv1alpha1.EtcdRestorePhaseFailed, r.Status.Phase)
+	})
+}
+
+// TestHandleRestoreWaitsForBackup 测试源备份未完成时等待
+func TestHandleRestoreWaitsForBackup(t *testing.T) {
+	ctx := context.Background()
+	svc, k8sClient := newRestoreTestService(t)
+	require.NoError(t, k8sClient.Create(ctx, &etcdv1alpha1.EtcdBackup{
+		ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: "default"},
+	}))
+	newTestRestore(t, k8sClient)
+
+	handleRestore(t, svc, k8sClient)
+	r := handleRestore(t, svc, k8sClient)
+	assert.Equal(t, etcdv1alpha1.EtcdRestorePhaseRunning, r.Status.Phase)
+	assert.Equal(t, utils.ReasonWaitingForBackup, r.Status.Conditions[0].Reason)
+}
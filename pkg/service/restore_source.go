@@ -0,0 +1,349 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+	"github.com/your-org/etcd-k8s-operator/pkg/backup"
+	"github.com/your-org/etcd-k8s-operator/pkg/k8s"
+	"github.com/your-org/etcd-k8s-operator/pkg/utils"
+)
+
+// snapshotSource 待恢复快照在存储中的位置及校验、解密信息
+type snapshotSource struct {
+	storage backup.Storage
+	key     string
+	// sha256 存储对象的期望 SHA-256，为空时不校验
+	sha256 string
+	// encryption 解密密钥所在的 Secret，为空时快照不应被加密
+	encryption *etcdv1alpha1.EtcdBackupEncryptionSpec
+	// namespace 凭据和密钥 Secret 所在的命名空间
+	namespace string
+}
+
+// resolveSnapshotSource 返回恢复对象引用的快照位置。
+// 返回 nil 时调用方应直接返回 result 和 err (等待快照来源就绪或恢复已失败)
+func (s *restoreService) resolveSnapshotSource(ctx context.Context, r *etcdv1alpha1.EtcdRestore) (*snapshotSource, ctrl.Result, error) {
+	if r.Spec.Source != nil {
+		return s.specSnapshotSource(ctx, r)
+	}
+	return s.backupSnapshotSource(ctx, r)
+}
+
+// backupSnapshotSource 返回 EtcdBackup 记录的快照位置，备份未完成时等待
+func (s *restoreService) backupSnapshotSource(ctx context.Context, r *etcdv1alpha1.EtcdRestore) (*snapshotSource, ctrl.Result, error) {
+	b, err := s.getSourceBackup(ctx, r)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			result, err := s.failRestore(ctx, r, fmt.Errorf("etcd backup %s/%s not found", s.backupNamespace(r), r.Spec.BackupName))
+			return nil, result, err
+		}
+		return nil, ctrl.Result{}, err
+	}
+
+	switch b.Status.Phase {
+	case etcdv1alpha1.EtcdBackupPhaseCompleted:
+	case etcdv1alpha1.EtcdBackupPhaseFailed:
+		result, err := s.failRestore(ctx, r, fmt.Errorf("etcd backup %s has failed", b.Name))
+		return nil, result, err
+	default:
+		log.FromContext(ctx).Info("Source backup has not completed yet, waiting", "backup", b.Name, "phase", b.Status.Phase)
+		result, err := s.waitForSource(ctx, r, utils.ReasonWaitingForBackup,
+			fmt.Sprintf("Waiting for etcd backup %s to complete (phase: %s)", b.Name, b.Status.Phase))
+		return nil, result, err
+	}
+
+	storage, _, err := s.backups.backupStorage(ctx, b)
+	if err != nil {
+		result, err := s.failRestore(ctx, r, fmt.Errorf("failed to open backup storage: %w", err))
+		return nil, result, err
+	}
+	key, err := storage.Key(b.Status.StoragePath)
+	if err != nil {
+		result, err := s.failRestore(ctx, r, err)
+		return nil, result, err
+	}
+
+	return &snapshotSource{
+		storage:    storage,
+		key:        key,
+		sha256:     b.Status.SHA256,
+		encryption: b.Spec.Encryption,
+		namespace:  b.Namespace,
+	}, ctrl.Result{}, nil
+}
+
+// specSnapshotSource 根据 spec.source 打开对应的存储
+func (s *restoreService) specSnapshotSource(ctx context.Context, r *etcdv1alpha1.EtcdRestore) (*snapshotSource, ctrl.Result, error) {
+	source := r.Spec.Source
+	src := &snapshotSource{
+		sha256:     source.SHA256,
+		encryption: source.Encryption,
+		namespace:  r.Namespace,
+	}
+
+	var err error
+	switch {
+	case source.S3 != nil:
+		src.storage, err = s.backups.newS3Storage(ctx, r.Namespace, &etcdv1alpha1.EtcdS3BackupSpec{
+			Bucket:          objectURLBucket(source.S3.URL),
+			Region:          source.S3.Region,
+			Endpoint:        source.S3.Endpoint,
+			AccessKeySecret: source.S3.AccessKeySecret,
+			SecretKeySecret: source.S3.SecretKeySecret,
+		})
+		if err == nil {
+			src.key, err = src.storage.Key(source.S3.URL)
+		}
+	case source.GCS != nil:
+		src.storage, err = s.backups.newGCSStorage(ctx, r.Namespace, &etcdv1alpha1.EtcdGCSBackupSpec{
+			Bucket:            objectURLBucket(source.GCS.URL),
+			CredentialsSecret: source.GCS.CredentialsSecret,
+		})
+		if err == nil {
+			src.key, err = src.storage.Key(source.GCS.URL)
+		}
+	case source.HTTP != nil:
+		cfg := backup.HTTPConfig{Header: http.Header{}}
+		if source.HTTP.HeadersSecret != "" {
+			var data map[string][]byte
+			if data, err = s.backups.secretData(ctx, r.Namespace, source.HTTP.HeadersSecret); err == nil {
+				for name, value := range data {
+					cfg.Header.Set(name, string(value))
+				}
+			}
+		}
+		if err == nil {
+			src.storage = backup.NewHTTPStorage(cfg)
+			src.key, err = src.storage.Key(source.HTTP.URL)
+		}
+	case source.PVC != nil:
+		return s.pvcSnapshotSource(ctx, r, src)
+	}
+	if err != nil {
+		result, err := s.failRestore(ctx, r, fmt.Errorf("failed to open snapshot source: %w", err))
+		return nil, result, err
+	}
+	return src, ctrl.Result{}, nil
+}
+
+// pvcSnapshotSource 启动挂载源 PVC 的 Pod，就绪后经由它的 HTTP 服务读取快照
+func (s *restoreService) pvcSnapshotSource(ctx context.Context, r *etcdv1alpha1.EtcdRestore, src *snapshotSource) (*snapshotSource, ctrl.Result, error) {
+	token, err := s.ensureSourceToken(ctx, r)
+	if err != nil {
+		return nil, ctrl.Result{}, err
+	}
+
+	pod := &corev1.Pod{}
+	err = s.k8sClient.Get(ctx, types.NamespacedName{Name: k8s.RestoreSourcePodName(r), Namespace: r.Namespace}, pod)
+	if errors.IsNotFound(err) {
+		pod = k8s.BuildRestoreSourcePod(r, r.Spec.Source.PVC, restoreTokenSecretName(r))
+		if err := s.setOwner(r, pod); err != nil {
+			return nil, ctrl.Result{}, err
+		}
+		if err := s.k8sClient.Create(ctx, pod); err != nil && !errors.IsAlreadyExists(err) {
+			return nil, ctrl.Result{}, err
+		}
+		log.FromContext(ctx).Info("Created snapshot source pod", "pod", pod.Name, "claim", r.Spec.Source.PVC.ClaimName)
+	} else if err != nil {
+		return nil, ctrl.Result{}, err
+	}
+
+	if pod.Status.Phase == corev1.PodFailed || pod.Status.Phase == corev1.PodSucceeded {
+		result, err := s.failRestore(ctx, r, fmt.Errorf("snapshot source pod %s exited (phase: %s)", pod.Name, pod.Status.Phase))
+		return nil, result, err
+	}
+	if !podReady(pod) || pod.Status.PodIP == "" {
+		result, err := s.waitForSource(ctx, r, utils.ReasonWaitingForSource,
+			fmt.Sprintf("Waiting for pod %s to mount persistent volume claim %s", pod.Name, r.Spec.Source.PVC.ClaimName))
+		return nil, result, err
+	}
+
+	credentials := base64.StdEncoding.EncodeToString([]byte(k8s.RestoreSourceUser + ":" + token))
+	src.storage = backup.NewHTTPStorage(backup.HTTPConfig{
+		Header: http.Header{"Authorization": {"Basic " + credentials}},
+	})
+	src.key = (&url.URL{
+		Scheme: "http",
+		Host:   net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(k8s.RestoreSourcePort)),
+		Path:   "/" + strings.TrimPrefix(path.Clean("/"+r.Spec.Source.PVC.Path), "/"),
+	}).String()
+	return src, ctrl.Result{}, nil
+}
+
+// waitForSource 记录等待原因并稍后重新调和
+func (s *restoreService) waitForSource(ctx context.Context, r *etcdv1alpha1.EtcdRestore, reason, message string) (ctrl.Result, error) {
+	s.setCondition(r, utils.ConditionTypeReady, metav1.ConditionFalse, reason, message)
+	if err := s.k8sClient.UpdateStatus(ctx, r); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: utils.DefaultRequeueInterval}, nil
+}
+
+// ensureSourceToken 返回快照源 Pod 的访问密码，首次调用时生成并保存到恢复令牌 Secret
+func (s *restoreService) ensureSourceToken(ctx context.Context, r *etcdv1alpha1.EtcdRestore) (string, error) {
+	secret := &corev1.Secret{}
+	err := s.k8sClient.Get(ctx, types.NamespacedName{Name: restoreTokenSecretName(r), Namespace: r.Namespace}, secret)
+	if err != nil && !errors.IsNotFound(err) {
+		return "", err
+	}
+	if token := secret.Data[utils.SecretKeyRestoreSourceToken]; len(token) > 0 {
+		return string(token), nil
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(buf)
+
+	if errors.IsNotFound(err) {
+		secret = newRestoreTokenSecret(r)
+		secret.Data[utils.SecretKeyRestoreSourceToken] = []byte(token)
+		if err := s.setOwner(r, secret); err != nil {
+			return "", err
+		}
+		return token, s.k8sClient.Create(ctx, secret)
+	}
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data[utils.SecretKeyRestoreSourceToken] = []byte(token)
+	return token, s.k8sClient.Update(ctx, secret)
+}
+
+// deleteSourcePod 删除快照源 Pod 以释放源 PVC
+func (s *restoreService) deleteSourcePod(ctx context.Context, r *etcdv1alpha1.EtcdRestore) error {
+	if r.Spec.Source == nil || r.Spec.Source.PVC == nil {
+		return nil
+	}
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: k8s.RestoreSourcePodName(r), Namespace: r.Namespace}}
+	if err := s.k8sClient.Delete(ctx, pod); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// validateRestoreSource 校验 spec.source 恰好指定了一种来源且地址合法
+func validateRestoreSource(source *etcdv1alpha1.EtcdRestoreSource) error {
+	count := 0
+	for _, set := range []bool{source.S3 != nil, source.GCS != nil, source.PVC != nil, source.HTTP != nil} {
+		if set {
+			count++
+		}
+	}
+	if count != 1 {
+		return fmt.Errorf("source must specify exactly one of s3, gcs, pvc and http")
+	}
+
+	switch {
+	case source.S3 != nil:
+		return validateObjectURL(source.S3.URL, "s3")
+	case source.GCS != nil:
+		return validateObjectURL(source.GCS.URL, "gs")
+	case source.HTTP != nil:
+		_, err := backup.NewHTTPStorage(backup.HTTPConfig{}).Key(source.HTTP.URL)
+		return err
+	default:
+		if source.PVC.ClaimName == "" {
+			return fmt.Errorf("source.pvc.claimName is required")
+		}
+		if source.PVC.Path == "" || strings.HasSuffix(source.PVC.Path, "/") {
+			return fmt.Errorf("source.pvc.path must name a file")
+		}
+		// 路径相对于卷根目录，不允许通过 .. 逃出挂载点
+		for _, elem := range strings.Split(source.PVC.Path, "/") {
+			if elem == ".." {
+				return fmt.Errorf("source.pvc.path must not contain '..'")
+			}
+		}
+		return nil
+	}
+}
+
+// validateObjectURL 校验 <scheme>://bucket/key 形式的对象地址
+func validateObjectURL(rawURL, scheme string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid snapshot url: %w", err)
+	}
+	if u.Scheme != scheme || u.Host == "" || strings.Trim(u.Path, "/") == "" {
+		return fmt.Errorf("snapshot url %q must have the form %s://bucket/key", rawURL, scheme)
+	}
+	return nil
+}
+
+// objectURLBucket 返回对象地址中的存储桶名称，地址已由 validateObjectURL 校验
+func objectURLBucket(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return u.Host
+}
+
+// restoreSourceDescription 返回快照来源的可读描述，用于状态和事件
+func restoreSourceDescription(r *etcdv1alpha1.EtcdRestore) string {
+	source := r.Spec.Source
+	switch {
+	case source == nil:
+		return fmt.Sprintf("backup %s", r.Spec.BackupName)
+	case source.S3 != nil:
+		return source.S3.URL
+	case source.GCS != nil:
+		return source.GCS.URL
+	case source.PVC != nil:
+		return fmt.Sprintf("pvc %s:%s", source.PVC.ClaimName, source.PVC.Path)
+	case source.HTTP != nil:
+		// 预签名地址的查询参数包含凭据
+		if u, err := url.Parse(source.HTTP.URL); err == nil {
+			u.User = nil
+			u.RawQuery = ""
+			return u.String()
+		}
+	}
+	return "snapshot source"
+}
+
+// podReady 判断 Pod 是否处于 Ready 状态
+func podReady(pod *corev1.Pod) bool {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
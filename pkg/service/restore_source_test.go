@@ -0,0 +1,168 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+	"github.com/your-org/etcd-k8s-operator/pkg/client"
+	"github.com/your-org/etcd-k8s-operator/pkg/utils"
+)
+
+// newSourceRestore 创建直接从 source 恢复出 3 节点新集群的恢复对象
+func newSourceRestore(t *testing.T, k8sClient client.KubernetesClient, source *etcdv1alpha1.EtcdRestoreSource) *etcdv1alpha1.EtcdRestore {
+	r := &etcdv1alpha1.EtcdRestore{
+		ObjectMeta: metav1.ObjectMeta{Name: "restore", Namespace: "default", UID: types.UID("restore-uid")},
+		Spec: etcdv1alpha1.EtcdRestoreSpec{
+			Source:      source,
+			ClusterName: "restored",
+			RestoreType: etcdv1alpha1.EtcdRestoreTypeNew,
+			ClusterTemplate: &etcdv1alpha1.EtcdClusterSpec{
+				Size:    3,
+				Storage: etcdv1alpha1.EtcdStorageSpec{Size: resource.MustParse("1Gi")},
+			},
+		},
+	}
+	require.NoError(t, k8sClient.Create(context.Background(), r))
+	return r
+}
+
+// TestHandleRestoreHTTPSource 测试从 HTTP 地址下载、校验并暂存快照
+func TestHandleRestoreHTTPSource(t *testing.T) {
+	ctx := context.Background()
+	svc, k8sClient := newRestoreTestService(t)
+
+	key := "external/etcd.db.gz"
+	result := storeTestSnapshot(t, svc.backups, key)
+	rc, err := svc.backups.localStorage.Get(ctx, key)
+	require.NoError(t, err)
+	snapshot, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	require.NoError(t, rc.Close())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write(snapshot)
+	}))
+	defer server.Close()
+
+	require.NoError(t, k8sClient.Create(ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "snapshot-headers", Namespace: "default"},
+		Data:       map[string][]byte{"Authorization": []byte("Bearer secret")},
+	}))
+	newSourceRestore(t, k8sClient, &etcdv1alpha1.EtcdRestoreSource{
+		HTTP:   &etcdv1alpha1.EtcdHTTPRestoreSource{URL: server.URL + "/etcd.db.gz", HeadersSecret: "snapshot-headers"},
+		SHA256: result.SHA256,
+	})
+
+	handleRestore(t, svc, k8sClient)
+	r := handleRestore(t, svc, k8sClient)
+	require.Equal(t, etcdv1alpha1.EtcdRestorePhaseRunning, r.Status.Phase)
+	staged, err := svc.stager.Lookup("restore-uid")
+	require.NoError(t, err)
+	assert.Equal(t, staged.Size, r.Status.RestoredSize)
+}
+
+// TestHandleRestoreHTTPSourceChecksumMismatch 测试下载内容与 source.sha256 不一致时恢复失败
+func TestHandleRestoreHTTPSourceChecksumMismatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "not a snapshot")
+	}))
+	defer server.Close()
+
+	svc, k8sClient := newRestoreTestService(t)
+	newSourceRestore(t, k8sClient, &etcdv1alpha1.EtcdRestoreSource{
+		HTTP:   &etcdv1alpha1.EtcdHTTPRestoreSource{URL: server.URL + "/etcd.db"},
+		SHA256: "0000000000000000000000000000000000000000000000000000000000000000",
+	})
+
+	handleRestore(t, svc, k8sClient)
+	r := handleRestore(t, svc, k8sClient)
+	assert.Equal(t, etcdv1alpha1.EtcdRestorePhaseFailed, r.Status.Phase)
+}
+
+// TestHandleRestorePVCSource 测试 PVC 来源先启动快照源 Pod 并等待其就绪
+func TestHandleRestorePVCSource(t *testing.T) {
+	ctx := context.Background()
+	svc, k8sClient := newRestoreTestService(t)
+	newSourceRestore(t, k8sClient, &etcdv1alpha1.EtcdRestoreSource{
+		PVC: &etcdv1alpha1.EtcdPVCRestoreSource{ClaimName: "legacy-backups", Path: "snapshots/etcd.db"},
+	})
+
+	handleRestore(t, svc, k8sClient)
+	r := handleRestore(t, svc, k8sClient)
+	require.Equal(t, etcdv1alpha1.EtcdRestorePhaseRunning, r.Status.Phase)
+	assert.Equal(t, utils.ReasonWaitingForSource, r.Status.Conditions[0].Reason)
+
+	pod := &corev1.Pod{}
+	require.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Name: "restore-source", Namespace: "default"}, pod))
+	assert.Equal(t, "legacy-backups", pod.Spec.Volumes[0].PersistentVolumeClaim.ClaimName)
+	assert.True(t, pod.Spec.Volumes[0].PersistentVolumeClaim.ReadOnly)
+
+	secret := &corev1.Secret{}
+	require.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Name: "restore-snapshot-token", Namespace: "default"}, secret))
+	assert.Len(t, secret.Data[utils.SecretKeyRestoreSourceToken], 64)
+
+	// 快照源 Pod 退出后恢复失败并删除该 Pod
+	pod.Status.Phase = corev1.PodFailed
+	require.NoError(t, k8sClient.UpdateStatus(ctx, pod))
+	r = handleRestore(t, svc, k8sClient)
+	assert.Equal(t, etcdv1alpha1.EtcdRestorePhaseFailed, r.Status.Phase)
+}
+
+// TestValidateRestoreSource 测试 spec.source 的校验
+func TestValidateRestoreSource(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  etcdv1alpha1.EtcdRestoreSource
+		wantErr bool
+	}{
+		{"s3", etcdv1alpha1.EtcdRestoreSource{S3: &etcdv1alpha1.EtcdS3RestoreSource{URL: "s3://bucket/etcd.db"}}, false},
+		{"s3 without key", etcdv1alpha1.EtcdRestoreSource{S3: &etcdv1alpha1.EtcdS3RestoreSource{URL: "s3://bucket/"}}, true},
+		{"gcs", etcdv1alpha1.EtcdRestoreSource{GCS: &etcdv1alpha1.EtcdGCSRestoreSource{URL: "gs://bucket/etcd.db"}}, false},
+		{"gcs with s3 scheme", etcdv1alpha1.EtcdRestoreSource{GCS: &etcdv1alpha1.EtcdGCSRestoreSource{URL: "s3://bucket/etcd.db"}}, true},
+		{"http", etcdv1alpha1.EtcdRestoreSource{HTTP: &etcdv1alpha1.EtcdHTTPRestoreSource{URL: "https://example.com/etcd.db"}}, false},
+		{"pvc", etcdv1alpha1.EtcdRestoreSource{PVC: &etcdv1alpha1.EtcdPVCRestoreSource{ClaimName: "backups", Path: "etcd.db"}}, false},
+		{"pvc escaping volume", etcdv1alpha1.EtcdRestoreSource{PVC: &etcdv1alpha1.EtcdPVCRestoreSource{ClaimName: "backups", Path: "../etc/passwd"}}, true},
+		{"none", etcdv1alpha1.EtcdRestoreSource{}, true},
+		{"two sources", etcdv1alpha1.EtcdRestoreSource{
+			S3:   &etcdv1alpha1.EtcdS3RestoreSource{URL: "s3://bucket/etcd.db"},
+			HTTP: &etcdv1alpha1.EtcdHTTPRestoreSource{URL: "https://example.com/etcd.db"},
+		}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateRestoreSource(&tt.source)
+			assert.Equal(t, tt.wantErr, err != nil, err)
+		})
+	}
+}
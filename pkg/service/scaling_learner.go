@@ -0,0 +1,319 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+	"github.com/your-org/etcd-k8s-operator/pkg/k8s"
+	"github.com/your-org/etcd-k8s-operator/pkg/utils"
+)
+
+// newLearnerClient 创建连接到 operator 可以访问的客户端端点的 etcd 客户端
+func (s *scalingService) newLearnerClient(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster) (memberManager, error) {
+	etcdClient, err := s.createEtcdClient(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+	return etcdClient, nil
+}
+
+// learnerTimeout 返回 learner 追上 leader 的最长时间
+func learnerTimeout(cluster *etcdv1alpha1.EtcdCluster) time.Duration {
+	if timeout := cluster.Spec.Scaling.LearnerTimeout; timeout != nil {
+		return timeout.Duration
+	}
+	return utils.DefaultLearnerTimeout
+}
+
+// recordLearner 在扩容状态中记录刚加入集群的 learner，learnerTimeout 从此时开始计算
+func (s *scalingService) recordLearner(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster, name, id string) error {
+	if cluster.Status.Scaling == nil {
+		cluster.Status.Scaling = &etcdv1alpha1.EtcdScalingStatus{}
+	}
+	now := metav1.Now()
+	scaling := cluster.Status.Scaling
+	scaling.Learner = name
+	scaling.LearnerID = id
+	scaling.StartTime = &now
+	scaling.RaftIndex = 0
+	scaling.LeaderRaftIndex = 0
+	return s.k8sClient.Status().Update(ctx, cluster)
+}
+
+// promoteLearner 推进扩容中加入的 learner：raft 索引追上 leader 之后提升为投票成员，
+// 超过 spec.scaling.learnerTimeout 仍未追上时按 learnerTimeoutPolicy 处理。提升之前不添加其他成员
+func (s *scalingService) promoteLearner(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	scaling := cluster.Status.Scaling
+	ordinal, err := memberOrdinal(cluster, scaling.Learner)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Retry 策略移除了超时的 learner，成员使用新的数据卷重建之后重新添加
+	if scaling.LearnerID == "" {
+		if ordinal >= cluster.Spec.Size {
+			return s.clearLearner(ctx, cluster)
+		}
+		recreated, err := resetMemberData(ctx, s.k8sClient, cluster, ordinal, *scaling.StartTime, func(pvcName string) {
+			logger.Info("Deleted volume of timed out learner", "member", scaling.Learner, "volume", pvcName)
+		})
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !recreated {
+			logger.Info("Waiting for learner to be recreated with fresh data", "member", scaling.Learner)
+			return ctrl.Result{RequeueAfter: utils.DefaultRequeueInterval}, nil
+		}
+		if err := s.addEtcdMember(ctx, cluster, ordinal); err != nil {
+			logger.Error(err, "Failed to add learner again", "member", scaling.Learner)
+		}
+		return ctrl.Result{RequeueAfter: utils.DefaultRequeueInterval}, nil
+	}
+
+	etcdClient, err := s.learnerClient(ctx, cluster)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	defer etcdClient.Close()
+
+	members, err := etcdClient.GetClusterMembers(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	var learner *etcdv1alpha1.EtcdMember
+	for i := range members {
+		if members[i].ID == scaling.LearnerID {
+			learner = &members[i]
+			break
+		}
+	}
+	var learnerID uint64
+	if learner != nil {
+		if _, err := fmt.Sscanf(learner.ID, "%x", &learnerID); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to parse member ID %s: %w", learner.ID, err)
+		}
+	}
+
+	switch {
+	case learner == nil:
+		// learner 已经不在集群中，由扩容流程重新添加
+		logger.Info("Learner is no longer a cluster member", "member", scaling.Learner, "memberID", scaling.LearnerID)
+		return s.clearLearner(ctx, cluster)
+	case learner.Role != memberRoleLearner:
+		// 上一轮调谐已经提升，但没有来得及更新状态
+		return s.learnerPromoted(ctx, cluster)
+	case ordinal >= cluster.Spec.Size:
+		// 扩容途中调小了 spec.size，learner 不参与投票，直接移除
+		if err := etcdClient.RemoveMember(ctx, learnerID); err != nil {
+			return ctrl.Result{}, err
+		}
+		logger.Info("Removed learner after the cluster size was reduced", "member", scaling.Learner)
+		return s.clearLearner(ctx, cluster)
+	}
+
+	promoted, progress, err := promoteCaughtUpLearner(ctx, etcdClient, learner, k8s.MemberClientURL(cluster, ordinal), members)
+	recorded := recordLearnerProgress(scaling, progress, time.Now())
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if promoted {
+		logger.Info("Promoted learner to a voting member", "member", scaling.Learner,
+			"raftIndex", scaling.RaftIndex, "leaderRaftIndex", scaling.LeaderRaftIndex)
+		return s.learnerPromoted(ctx, cluster)
+	}
+
+	if time.Since(scaling.StartTime.Time) < learnerTimeout(cluster) {
+		logger.Info("Waiting for learner to catch up with the leader", "member", scaling.Learner,
+			"raftIndex", scaling.RaftIndex, "leaderRaftIndex", scaling.LeaderRaftIndex)
+		if recorded {
+			if err := s.k8sClient.Status().Update(ctx, cluster); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{RequeueAfter: utils.DefaultRequeueInterval}, nil
+	}
+	return s.learnerTimedOut(ctx, cluster, etcdClient, learnerID, recorded)
+}
+
+// recordLearnerProgress 将读取到的 raft 索引记录到扩容状态中，返回是否记录。raft 索引每次读取都不同，
+// 而状态更新会触发新一轮调谐，因此距离上次记录不足 utils.DefaultRequeueInterval 时不记录
+func recordLearnerProgress(scaling *etcdv1alpha1.EtcdScalingStatus, progress *learnerProgress, now time.Time) bool {
+	if progress == nil || (scaling.ProgressTime != nil && now.Sub(scaling.ProgressTime.Time) < utils.DefaultRequeueInterval) {
+		return false
+	}
+	scaling.RaftIndex = int64(progress.raftIndex)
+	scaling.LeaderRaftIndex = int64(progress.leaderRaftIndex)
+	scaling.ProgressTime = &metav1.Time{Time: now}
+	return true
+}
+
+// learnerProgress learner 和 leader 的 raft 索引
+type learnerProgress struct {
+	raftIndex       uint64
+	leaderRaftIndex uint64
+}
+
+// promoteCaughtUpLearner 读取 learner 和 leader 的 raft 索引，learner 追上 leader 之后将它提升为投票成员，
+// 返回是否已经提升和读取到的 raft 索引。扩容、成员替换和法定人数恢复都通过它逐个提升重新加入的成员。
+// 无法读取成员状态时（例如 operator 运行在集群外）直接尝试提升，etcd 拒绝提升尚未追上的 learner，此时返回未提升
+func promoteCaughtUpLearner(ctx context.Context, etcdClient memberManager, learner *etcdv1alpha1.EtcdMember,
+	learnerURL string, members []etcdv1alpha1.EtcdMember) (bool, *learnerProgress, error) {
+	var learnerID uint64
+	if _, err := fmt.Sscanf(learner.ID, "%x", &learnerID); err != nil {
+		return false, nil, fmt.Errorf("failed to parse member ID %s: %w", learner.ID, err)
+	}
+
+	caughtUp, progress := learnerCaughtUp(ctx, etcdClient, learnerURL, members)
+	if !caughtUp {
+		return false, progress, nil
+	}
+	if err := etcdClient.PromoteMember(ctx, learnerID); err != nil {
+		if errors.Is(err, rpctypes.ErrMemberLearnerNotReady) {
+			return false, progress, nil
+		}
+		return false, progress, err
+	}
+	return true, progress, nil
+}
+
+// learnerCaughtUp 返回 learner 的 raft 索引是否已经追上 leader，以及读取到的 raft 索引，无法读取时返回 true 和 nil
+func learnerCaughtUp(ctx context.Context, etcdClient memberManager, learnerURL string, members []etcdv1alpha1.EtcdMember) (bool, *learnerProgress) {
+	logger := log.FromContext(ctx)
+	learnerStatus, err := etcdClient.MemberStatus(ctx, learnerURL)
+	if err != nil {
+		logger.Info("Cannot read learner status, trying to promote it", "error", err.Error())
+		return true, nil
+	}
+	if learnerStatus.Leader == 0 {
+		// learner 还没有联系上 leader
+		return false, nil
+	}
+
+	leaderURL := ""
+	for _, m := range members {
+		if m.ID == fmt.Sprintf("%x", learnerStatus.Leader) {
+			leaderURL = m.ClientURL
+			break
+		}
+	}
+	if leaderURL == "" {
+		return true, nil
+	}
+	leaderStatus, err := etcdClient.MemberStatus(ctx, leaderURL)
+	if err != nil {
+		logger.Info("Cannot read leader status, trying to promote the learner", "error", err.Error())
+		return true, nil
+	}
+
+	progress := &learnerProgress{raftIndex: learnerStatus.RaftIndex, leaderRaftIndex: leaderStatus.RaftIndex}
+	return progress.leaderRaftIndex <= progress.raftIndex+utils.DefaultMaxRaftIndexLag, progress
+}
+
+// learnerTimedOut 处理超时仍未追上 leader 的 learner。Wait 策略保留 learner 继续等待；
+// Retry 策略移除 learner，删除它的数据卷和 Pod 之后重新添加。两种策略都设置 Degraded 条件，
+// 条件中不包含每次读取都会变化的 raft 索引。recorded 表示本轮记录了新的 raft 索引，需要写入状态
+func (s *scalingService) learnerTimedOut(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster, etcdClient memberManager, learnerID uint64, recorded bool) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	scaling := cluster.Status.Scaling
+	cause := fmt.Sprintf("learner %s did not catch up with the leader within %s", scaling.Learner, learnerTimeout(cluster))
+
+	if cluster.Spec.Scaling.LearnerTimeoutPolicy != etcdv1alpha1.EtcdLearnerTimeoutPolicyRetry {
+		logger.Info("Learner timed out, waiting for it to catch up", "member", scaling.Learner,
+			"raftIndex", scaling.RaftIndex, "leaderRaftIndex", scaling.LeaderRaftIndex)
+		if setLearnerDegraded(cluster, cause) || recorded {
+			if err := s.k8sClient.Status().Update(ctx, cluster); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{RequeueAfter: utils.DefaultRequeueInterval}, nil
+	}
+
+	if err := etcdClient.RemoveMember(ctx, learnerID); err != nil {
+		return ctrl.Result{}, err
+	}
+	logger.Info("Removed timed out learner, adding it again with fresh data", "member", scaling.Learner, "retries", scaling.Retries+1)
+	setLearnerDegraded(cluster, cause+", adding it again with fresh data")
+	now := metav1.Now()
+	scaling.LearnerID = ""
+	scaling.StartTime = &now
+	scaling.RaftIndex = 0
+	scaling.LeaderRaftIndex = 0
+	scaling.ProgressTime = nil
+	scaling.Retries++
+	if err := s.k8sClient.Status().Update(ctx, cluster); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{Requeue: true}, nil
+}
+
+// learnerPromoted 清除已经提升的 learner 的记录，继续添加下一个成员
+func (s *scalingService) learnerPromoted(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster) (ctrl.Result, error) {
+	setLearnerDegraded(cluster, "")
+	return s.clearLearner(ctx, cluster)
+}
+
+// clearLearner 清除扩容状态中的 learner，重试次数保留到扩容结束
+func (s *scalingService) clearLearner(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster) (ctrl.Result, error) {
+	scaling := cluster.Status.Scaling
+	scaling.Learner = ""
+	scaling.LearnerID = ""
+	scaling.StartTime = nil
+	scaling.RaftIndex = 0
+	scaling.LeaderRaftIndex = 0
+	scaling.ProgressTime = nil
+	if err := s.k8sClient.Status().Update(ctx, cluster); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{Requeue: true}, nil
+}
+
+// setLearnerDegraded 在 learner 超时后设置 Degraded 条件，cause 为空时清除由 learner 超时设置的 Degraded 条件。
+// 返回条件是否变化
+func setLearnerDegraded(cluster *etcdv1alpha1.EtcdCluster, cause string) bool {
+	if cause == "" {
+		current := meta.FindStatusCondition(cluster.Status.Conditions, utils.ConditionTypeDegraded)
+		if current == nil || current.Reason != utils.ReasonLearnerStalled || current.Status != metav1.ConditionTrue {
+			return false
+		}
+		return meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+			Type:               utils.ConditionTypeDegraded,
+			Status:             metav1.ConditionFalse,
+			Reason:             utils.ReasonHealthy,
+			Message:            "Learner caught up with the leader and was promoted",
+			ObservedGeneration: cluster.Generation,
+		})
+	}
+	return meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+		Type:               utils.ConditionTypeDegraded,
+		Status:             metav1.ConditionTrue,
+		Reason:             utils.ReasonLearnerStalled,
+		Message:            fmt.Sprintf("Scale up stalled: %s", cause),
+		ObservedGeneration: cluster.Generation,
+	})
+}
@@ -0,0 +1,229 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+	"github.com/your-org/etcd-k8s-operator/pkg/k8s"
+	"github.com/your-org/etcd-k8s-operator/pkg/utils"
+)
+
+// newLearnerFixture 创建成员 0 和 1 已经加入、正在扩容到 3 个成员的集群，成员 0 是 leader
+func newLearnerFixture(t *testing.T, objects ...ctrlclient.Object) (*scalingService, *etcdv1alpha1.EtcdCluster, *fakeMemberManager) {
+	_, svc, cluster := newRotationFixture(t, objects...)
+	cluster.Status.Phase = etcdv1alpha1.EtcdClusterPhaseScaling
+	etcdMembers := &fakeMemberManager{nextID: 0xc, statuses: map[string]*clientv3.StatusResponse{
+		k8s.MemberClientURL(cluster, 0): memberStatusResponse(0xa, 0xa, 100000),
+	}}
+	for i := int32(0); i < 2; i++ {
+		etcdMembers.members = append(etcdMembers.members, etcdv1alpha1.EtcdMember{
+			Name: k8s.MemberName(cluster, i), ID: fmt.Sprintf("%x", 0xa+i),
+			PeerURL: k8s.MemberPeerURL(cluster, i), ClientURL: k8s.MemberClientURL(cluster, i),
+		})
+	}
+	svc.learnerClient = func(context.Context, *etcdv1alpha1.EtcdCluster) (memberManager, error) {
+		return etcdMembers, nil
+	}
+	return svc, cluster, etcdMembers
+}
+
+// TestAddEtcdMemberAddsLearner 测试新成员以 learner 身份加入并记录到扩容状态中，重复调谐不会再次添加
+func TestAddEtcdMemberAddsLearner(t *testing.T) {
+	svc, cluster, etcdMembers := newLearnerFixture(t,
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "secure-2", Namespace: "default"}})
+	ctx := context.Background()
+
+	require.NoError(t, svc.addEtcdMember(ctx, cluster, 2))
+	assert.Equal(t, []string{k8s.MemberPeerURL(cluster, 2)}, etcdMembers.added)
+	stored := &etcdv1alpha1.EtcdCluster{}
+	require.NoError(t, svc.k8sClient.Get(ctx, types.NamespacedName{Name: "secure", Namespace: "default"}, stored))
+	require.NotNil(t, stored.Status.Scaling)
+	assert.Equal(t, "secure-2", stored.Status.Scaling.Learner)
+	assert.Equal(t, "c", stored.Status.Scaling.LearnerID)
+	assert.NotNil(t, stored.Status.Scaling.StartTime)
+
+	// 状态丢失后再次添加时只记录已经加入的 learner
+	cluster.Status.Scaling = nil
+	require.NoError(t, svc.addEtcdMember(ctx, cluster, 2))
+	assert.Len(t, etcdMembers.added, 1)
+	assert.Equal(t, "c", cluster.Status.Scaling.LearnerID)
+}
+
+// TestPromoteLearnerAfterCatchingUp 测试 learner 的 raft 索引落后 leader 时记录进度并等待，追上后提升为投票成员
+func TestPromoteLearnerAfterCatchingUp(t *testing.T) {
+	svc, cluster, etcdMembers := newLearnerFixture(t)
+	ctx := context.Background()
+	_, err := etcdMembers.AddLearner(ctx, k8s.MemberPeerURL(cluster, 2))
+	require.NoError(t, err)
+	require.NoError(t, svc.recordLearner(ctx, cluster, "secure-2", "c"))
+	learnerURL := k8s.MemberClientURL(cluster, 2)
+
+	// learner 还在同步数据
+	etcdMembers.statuses[learnerURL] = memberStatusResponse(0xc, 0xa, 100)
+	result, err := svc.HandleScaling(ctx, cluster)
+	require.NoError(t, err)
+	assert.Equal(t, utils.DefaultRequeueInterval, result.RequeueAfter)
+	assert.Empty(t, etcdMembers.promoted)
+	assert.Equal(t, int64(100), cluster.Status.Scaling.RaftIndex)
+	assert.Equal(t, int64(100000), cluster.Status.Scaling.LeaderRaftIndex)
+	require.NotNil(t, cluster.Status.Scaling.ProgressTime)
+
+	// 距离上次记录不足一个重新入队间隔时不写状态
+	resourceVersion := cluster.ResourceVersion
+	etcdMembers.statuses[learnerURL] = memberStatusResponse(0xc, 0xa, 200)
+	result, err = svc.HandleScaling(ctx, cluster)
+	require.NoError(t, err)
+	assert.Equal(t, utils.DefaultRequeueInterval, result.RequeueAfter)
+	assert.Equal(t, int64(100), cluster.Status.Scaling.RaftIndex)
+	assert.Equal(t, resourceVersion, cluster.ResourceVersion)
+
+	recordedAt := metav1.NewTime(time.Now().Add(-utils.DefaultRequeueInterval))
+	cluster.Status.Scaling.ProgressTime = &recordedAt
+	_, err = svc.HandleScaling(ctx, cluster)
+	require.NoError(t, err)
+	assert.Equal(t, int64(200), cluster.Status.Scaling.RaftIndex)
+	assert.NotEqual(t, resourceVersion, cluster.ResourceVersion)
+
+	// 索引接近 leader，但 etcd 认为 learner 尚未同步完成
+	etcdMembers.statuses[learnerURL] = memberStatusResponse(0xc, 0xa, 99990)
+	etcdMembers.promoteErr = rpctypes.ErrMemberLearnerNotReady
+	_, err = svc.promoteLearner(ctx, cluster)
+	require.NoError(t, err)
+	assert.Equal(t, "secure-2", cluster.Status.Scaling.Learner)
+
+	etcdMembers.promoteErr = nil
+	result, err = svc.promoteLearner(ctx, cluster)
+	require.NoError(t, err)
+	assert.True(t, result.Requeue)
+	assert.Equal(t, []uint64{0xc}, etcdMembers.promoted)
+	assert.Empty(t, cluster.Status.Scaling.Learner)
+	assert.Nil(t, cluster.Status.Scaling.StartTime)
+}
+
+// TestPromoteLearnerTimeoutWait 测试 Wait 策略下超时的 learner 保留在集群中，设置 Degraded 条件，提升后清除
+func TestPromoteLearnerTimeoutWait(t *testing.T) {
+	svc, cluster, etcdMembers := newLearnerFixture(t)
+	ctx := context.Background()
+	_, err := etcdMembers.AddLearner(ctx, k8s.MemberPeerURL(cluster, 2))
+	require.NoError(t, err)
+	require.NoError(t, svc.recordLearner(ctx, cluster, "secure-2", "c"))
+	started := metav1.NewTime(time.Now().Add(-utils.DefaultLearnerTimeout - time.Minute))
+	cluster.Status.Scaling.StartTime = &started
+	etcdMembers.statuses[k8s.MemberClientURL(cluster, 2)] = memberStatusResponse(0xc, 0xa, 100)
+
+	_, err = svc.promoteLearner(ctx, cluster)
+	require.NoError(t, err)
+	assert.Empty(t, etcdMembers.removed)
+	assert.Equal(t, "c", cluster.Status.Scaling.LearnerID)
+	condition := meta.FindStatusCondition(cluster.Status.Conditions, utils.ConditionTypeDegraded)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, utils.ReasonLearnerStalled, condition.Reason)
+	assert.NotContains(t, condition.Message, "raft index")
+
+	// 条件不变且不需要记录进度时不再写状态
+	resourceVersion := cluster.ResourceVersion
+	etcdMembers.statuses[k8s.MemberClientURL(cluster, 2)] = memberStatusResponse(0xc, 0xa, 200)
+	_, err = svc.promoteLearner(ctx, cluster)
+	require.NoError(t, err)
+	assert.Equal(t, resourceVersion, cluster.ResourceVersion)
+
+	etcdMembers.statuses[k8s.MemberClientURL(cluster, 2)] = memberStatusResponse(0xc, 0xa, 100000)
+	_, err = svc.promoteLearner(ctx, cluster)
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{0xc}, etcdMembers.promoted)
+	assert.False(t, meta.IsStatusConditionTrue(cluster.Status.Conditions, utils.ConditionTypeDegraded))
+}
+
+// TestPromoteLearnerTimeoutRetry 测试 Retry 策略下移除超时的 learner，删除它的数据卷和 Pod，重建后以新的 learner 身份加入
+func TestPromoteLearnerTimeoutRetry(t *testing.T) {
+	old := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	svc, cluster, etcdMembers := newLearnerFixture(t,
+		&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "data-secure-2", Namespace: "default", CreationTimestamp: old}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "secure-2", Namespace: "default", CreationTimestamp: old}},
+	)
+	ctx := context.Background()
+	c := svc.k8sClient
+	cluster.Spec.Scaling.LearnerTimeoutPolicy = etcdv1alpha1.EtcdLearnerTimeoutPolicyRetry
+	cluster.Spec.Scaling.LearnerTimeout = &metav1.Duration{Duration: 5 * time.Minute}
+	require.NoError(t, c.Update(ctx, cluster))
+	_, err := etcdMembers.AddLearner(ctx, k8s.MemberPeerURL(cluster, 2))
+	require.NoError(t, err)
+	require.NoError(t, svc.recordLearner(ctx, cluster, "secure-2", "c"))
+	started := metav1.NewTime(time.Now().Add(-6 * time.Minute))
+	cluster.Status.Scaling.StartTime = &started
+	etcdMembers.statuses[k8s.MemberClientURL(cluster, 2)] = memberStatusResponse(0xc, 0xa, 100)
+
+	_, err = svc.promoteLearner(ctx, cluster)
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{0xc}, etcdMembers.removed)
+	assert.Equal(t, "secure-2", cluster.Status.Scaling.Learner)
+	assert.Empty(t, cluster.Status.Scaling.LearnerID)
+	assert.Equal(t, int32(1), cluster.Status.Scaling.Retries)
+	assert.True(t, meta.IsStatusConditionTrue(cluster.Status.Conditions, utils.ConditionTypeDegraded))
+
+	// 删除旧的数据卷和 Pod，等待 StatefulSet 重建
+	_, err = svc.promoteLearner(ctx, cluster)
+	require.NoError(t, err)
+	err = c.Get(ctx, types.NamespacedName{Name: "data-secure-2", Namespace: "default"}, &corev1.PersistentVolumeClaim{})
+	assert.True(t, errors.IsNotFound(err))
+	err = c.Get(ctx, types.NamespacedName{Name: "secure-2", Namespace: "default"}, &corev1.Pod{})
+	assert.True(t, errors.IsNotFound(err))
+	assert.Len(t, etcdMembers.added, 1)
+
+	created := metav1.NewTime(time.Now().Add(time.Minute))
+	require.NoError(t, c.Create(ctx, &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "data-secure-2", Namespace: "default", CreationTimestamp: created}}))
+	require.NoError(t, c.Create(ctx, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "secure-2", Namespace: "default", CreationTimestamp: created}}))
+	_, err = svc.promoteLearner(ctx, cluster)
+	require.NoError(t, err)
+	assert.Equal(t, []string{k8s.MemberPeerURL(cluster, 2), k8s.MemberPeerURL(cluster, 2)}, etcdMembers.added)
+	assert.Equal(t, "d", cluster.Status.Scaling.LearnerID)
+	assert.Equal(t, int32(1), cluster.Status.Scaling.Retries)
+}
+
+// TestPromoteLearnerAfterSizeReduced 测试扩容途中调小 spec.size 时直接移除尚未提升的 learner
+func TestPromoteLearnerAfterSizeReduced(t *testing.T) {
+	svc, cluster, etcdMembers := newLearnerFixture(t)
+	ctx := context.Background()
+	_, err := etcdMembers.AddLearner(ctx, k8s.MemberPeerURL(cluster, 2))
+	require.NoError(t, err)
+	require.NoError(t, svc.recordLearner(ctx, cluster, "secure-2", "c"))
+
+	cluster.Spec.Size = 1
+	result, err := svc.promoteLearner(ctx, cluster)
+	require.NoError(t, err)
+	assert.True(t, result.Requeue)
+	assert.Equal(t, []uint64{0xc}, etcdMembers.removed)
+	assert.Empty(t, etcdMembers.promoted)
+	assert.Empty(t, cluster.Status.Scaling.Learner)
+}
@@ -24,10 +24,12 @@ import (
 
 	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
 	etcdclient "github.com/your-org/etcd-k8s-operator/pkg/etcd"
+	"github.com/your-org/etcd-k8s-operator/pkg/k8s"
 	"github.com/your-org/etcd-k8s-operator/pkg/resource"
 	"github.com/your-org/etcd-k8s-operator/pkg/utils"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -39,14 +41,20 @@ import (
 type scalingService struct {
 	k8sClient       client.Client
 	resourceManager resource.ResourceManager
+	healthService   HealthService
+	// learnerClient 创建扩容时添加和提升 learner 使用的 etcd 客户端，测试中可替换
+	learnerClient func(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster) (memberManager, error)
 }
 
-// NewScalingService 创建扩缩容服务
-func NewScalingService(k8sClient client.Client, resourceManager resource.ResourceManager) ScalingService {
-	return &scalingService{
+// NewScalingService 创建扩缩容服务，运行状态下使用 healthService 检查成员健康
+func NewScalingService(k8sClient client.Client, resourceManager resource.ResourceManager, healthService HealthService) ScalingService {
+	s := &scalingService{
 		k8sClient:       k8sClient,
 		resourceManager: resourceManager,
+		healthService:   healthService,
 	}
+	s.learnerClient = s.newLearnerClient
+	return s
 }
 
 // HandleRunning 处理运行状态的集群
@@ -61,9 +69,41 @@ func (s *scalingService) HandleRunning(ctx context.Context, cluster *etcdv1alpha
 
 	cluster.Status.ReadyReplicas = status.ReadyReplicas
 
-	// 2. 检查是否需要扩缩容
+	// 2. 重新检查证书，证书 Secret 变化后及时反映到 TLSReady 条件
+	var certErr error
+	if k8s.TLSRequired(cluster) {
+		certErr = s.resourceManager.Certificate().Ensure(ctx, cluster)
+		if certErr != nil && !isInvalidCertificate(certErr) {
+			return ctrl.Result{}, certErr
+		}
+	}
+	if setTLSCondition(cluster, certErr) {
+		logger.Info("TLS condition changed", "error", certErr)
+		if err := s.k8sClient.Status().Update(ctx, cluster); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// 3. 证书轮换或传输安全迁移期间逐个重启成员，重启中的成员不能当作需要扩缩容
+	if result, err := s.reconcileMemberRestarts(ctx, cluster, certErr); result != nil || err != nil {
+		if result == nil {
+			result = &ctrl.Result{}
+		}
+		return *result, err
+	}
+
+	// 4. spec 中的版本变化后先完成升级
+	if result, err := s.checkUpgrade(ctx, cluster); result != nil || err != nil {
+		if result == nil {
+			result = &ctrl.Result{}
+		}
+		return *result, err
+	}
+
+	// 5. 检查是否需要扩缩容。StatefulSet 已经运行期望数量的 Pod 时，未就绪的成员由健康检查处理；
+	// 替换故障成员期间 Pod 会被重建，同样不进入扩缩容
 	logger.Info("Checking scaling needs", "currentReadyReplicas", cluster.Status.ReadyReplicas, "desiredSize", cluster.Spec.Size)
-	if s.NeedsScaling(cluster) {
+	if cluster.Status.MemberReplacement == nil && s.NeedsScaling(cluster) && status.Replicas != cluster.Spec.Size {
 		logger.Info("Cluster needs scaling", "current", cluster.Status.ReadyReplicas, "desired", cluster.Spec.Size)
 		cluster.Status.Phase = etcdv1alpha1.EtcdClusterPhaseScaling
 		s.setCondition(cluster, utils.ConditionTypeProgressing, metav1.ConditionTrue, utils.ReasonScaling, "Scaling etcd cluster")
@@ -74,8 +114,24 @@ func (s *scalingService) HandleRunning(ctx context.Context, cluster *etcdv1alpha
 		return ctrl.Result{Requeue: true}, nil
 	}
 
-	// 3. 执行健康检查
+	// 6. 执行健康检查，更新成员状态以及 Available 和 Degraded 条件，并替换长时间不可达的成员
 	logger.Info("Performing health check")
+	if s.healthService == nil {
+		return ctrl.Result{RequeueAfter: utils.DefaultHealthCheckInterval}, nil
+	}
+	if err := s.healthService.PerformHealthCheck(ctx, cluster); err != nil {
+		return ctrl.Result{}, err
+	}
+	if cluster.Status.Phase == etcdv1alpha1.EtcdClusterPhaseFailed {
+		// 失去法定人数，由 HandleFailed 恢复
+		return ctrl.Result{Requeue: true}, nil
+	}
+	if cluster.Status.MemberReplacement != nil || cluster.Status.ReadyReplicas < cluster.Spec.Size ||
+		!meta.IsStatusConditionTrue(cluster.Status.Conditions, utils.ConditionTypeAvailable) ||
+		maintenanceInProgress(cluster) {
+		// 尽快确认故障成员的恢复或替换进度、法定人数丢失的持续时间，以及逐个成员的碎片整理和告警处理
+		return ctrl.Result{RequeueAfter: utils.DefaultRequeueInterval}, nil
+	}
 	return ctrl.Result{RequeueAfter: utils.DefaultHealthCheckInterval}, nil
 }
 
@@ -86,6 +142,12 @@ func (s *scalingService) HandleScaling(ctx context.Context, cluster *etcdv1alpha
 	// 1. 首先确保基础资源存在（修复：在扩缩容前确保Service等资源存在）
 	if err := s.resourceManager.EnsureAllResources(ctx, cluster); err != nil {
 		logger.Error(err, "Failed to ensure resources during scaling")
+		// 新成员需要的证书不可用时暂停扩缩容，并通过 TLSReady 条件提示用户
+		if isInvalidCertificate(err) && setTLSCondition(cluster, err) {
+			if err := s.k8sClient.Status().Update(ctx, cluster); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
 		return ctrl.Result{RequeueAfter: utils.DefaultRequeueInterval}, nil
 	}
 
@@ -116,6 +178,11 @@ func (s *scalingService) HandleScaling(ctx context.Context, cluster *etcdv1alpha
 		return s.handleScaleToZero(ctx, cluster)
 	}
 
+	// 新成员以 learner 身份加入，提升为投票成员之前不添加或移除其他成员
+	if cluster.Status.Scaling != nil && cluster.Status.Scaling.Learner != "" {
+		return s.promoteLearner(ctx, cluster)
+	}
+
 	// 检查是否需要继续扩缩容
 	if currentReplicas < desiredSize {
 		// 扩容前检查：如果有未就绪的Pod，等待它们就绪后再继续扩容
@@ -164,6 +231,7 @@ func (s *scalingService) HandleScaling(ctx context.Context, cluster *etcdv1alpha
 	// 扩缩容完成，回到运行状态
 	logger.Info("Scaling completed", "finalSize", readyReplicas)
 	cluster.Status.Phase = etcdv1alpha1.EtcdClusterPhaseRunning
+	cluster.Status.Scaling = nil
 	s.setCondition(cluster, utils.ConditionTypeProgressing, metav1.ConditionFalse, utils.ReasonRunning, "Scaling completed")
 
 	if err := s.k8sClient.Status().Update(ctx, cluster); err != nil {
@@ -368,13 +436,14 @@ func (s *scalingService) handleScaleToZero(ctx context.Context, cluster *etcdv1a
 	return ctrl.Result{RequeueAfter: utils.DefaultHealthCheckInterval * 2}, nil
 }
 
-// addEtcdMember adds a new member to the etcd cluster
+// addEtcdMember 以 learner 身份把成员加入 etcd 集群并记录到扩容状态中。learner 不参与投票，
+// 新成员同步数据期间不扩大法定人数；追上 leader 之后由 promoteLearner 提升为投票成员
 func (s *scalingService) addEtcdMember(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster, memberIndex int32) error {
 	logger := log.FromContext(ctx)
 
 	// 创建 etcd 客户端
 	logger.Info("Creating etcd client for member addition")
-	etcdClient, err := s.createEtcdClient(cluster)
+	etcdClient, err := s.learnerClient(ctx, cluster)
 	if err != nil {
 		logger.Error(err, "Failed to create etcd client")
 		return fmt.Errorf("failed to create etcd client: %w", err)
@@ -382,9 +451,8 @@ func (s *scalingService) addEtcdMember(ctx context.Context, cluster *etcdv1alpha
 	defer etcdClient.Close()
 
 	// 构建新成员的信息
-	memberName := fmt.Sprintf("%s-%d", cluster.Name, memberIndex)
-	peerURL := fmt.Sprintf("http://%s.%s-peer.%s.svc.cluster.local:%d",
-		memberName, cluster.Name, cluster.Namespace, utils.EtcdPeerPort)
+	memberName := k8s.MemberName(cluster, memberIndex)
+	peerURL := k8s.MemberPeerURL(cluster, memberIndex)
 
 	logger.Info("Checking if etcd member already exists", "name", memberName, "peerURL", peerURL)
 
@@ -396,16 +464,18 @@ func (s *scalingService) addEtcdMember(ctx context.Context, cluster *etcdv1alpha
 	}
 
 	// 检查成员是否已经存在，并处理unstarted成员
-	expectedPeerURL := fmt.Sprintf("http://%s.%s-peer.%s.svc.cluster.local:%d",
-		memberName, cluster.Name, cluster.Namespace, utils.EtcdPeerPort)
-
 	for _, member := range members {
+		// 上一轮调谐添加的 learner 还没有记录到状态中
+		if member.Role == memberRoleLearner && (member.Name == memberName || member.PeerURL == peerURL) {
+			logger.Info("Etcd member already added as a learner", "name", memberName, "memberID", member.ID)
+			return s.recordLearner(ctx, cluster, memberName, member.ID)
+		}
 		if member.Name == memberName {
 			logger.Info("Etcd member already exists, skipping addition", "name", memberName)
 			return nil
 		}
-		// 检查是否有unstarted成员（没有名称但有匹配的peer URL）
-		if member.Name == "" && member.PeerURL == expectedPeerURL {
+		// 检查是否有unstarted成员（没有名称但有匹配的peer URL），以投票成员身份加入的需要先移除
+		if member.Name == "" && member.PeerURL == peerURL {
 			logger.Info("Found unstarted member with matching peer URL, removing it first",
 				"memberID", member.ID, "peerURL", member.PeerURL)
 
@@ -435,17 +505,18 @@ func (s *scalingService) addEtcdMember(ctx context.Context, cluster *etcdv1alpha
 		return fmt.Errorf("service %s is not yet resolvable", serviceName)
 	}
 
-	logger.Info("Service is resolvable, proceeding to add etcd member", "name", memberName, "peerURL", peerURL)
+	logger.Info("Service is resolvable, proceeding to add etcd member as a learner", "name", memberName, "peerURL", peerURL)
 
-	// 添加成员到 etcd 集群
-	resp, err := etcdClient.AddMember(ctx, peerURL)
+	// 以 learner 身份添加成员到 etcd 集群
+	resp, err := etcdClient.AddLearner(ctx, peerURL)
 	if err != nil {
 		logger.Error(err, "Failed to add etcd member", "name", memberName, "peerURL", peerURL)
 		return fmt.Errorf("failed to add member %s: %w", memberName, err)
 	}
 
-	logger.Info("Successfully added etcd member", "name", memberName, "memberID", resp.Member.ID)
-	return nil
+	memberID := fmt.Sprintf("%x", resp.Member.ID)
+	logger.Info("Successfully added etcd member as a learner", "name", memberName, "memberID", memberID)
+	return s.recordLearner(ctx, cluster, memberName, memberID)
 }
 
 // removeEtcdMember removes a member from the etcd cluster
@@ -453,7 +524,7 @@ func (s *scalingService) removeEtcdMember(ctx context.Context, cluster *etcdv1al
 	logger := log.FromContext(ctx)
 
 	// 创建 etcd 客户端
-	etcdClient, err := s.createEtcdClient(cluster)
+	etcdClient, err := s.createEtcdClient(ctx, cluster)
 	if err != nil {
 		return fmt.Errorf("failed to create etcd client: %w", err)
 	}
@@ -546,7 +617,7 @@ func (s *scalingService) isServiceResolvable(serviceName string) bool {
 }
 
 // createEtcdClient creates an etcd client for the cluster
-func (s *scalingService) createEtcdClient(cluster *etcdv1alpha1.EtcdCluster) (*etcdclient.Client, error) {
+func (s *scalingService) createEtcdClient(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster) (*etcdclient.Client, error) {
 	var endpoints []string
 
 	// 检查是否运行在集群内部
@@ -557,10 +628,7 @@ func (s *scalingService) createEtcdClient(cluster *etcdv1alpha1.EtcdCluster) (*e
 		// 运行在集群内部，使用集群内端点
 		endpoints = cluster.Status.ClientEndpoints
 		if len(endpoints) == 0 {
-			endpoints = []string{
-				fmt.Sprintf("http://%s-0.%s-peer.%s.svc.cluster.local:%d",
-					cluster.Name, cluster.Name, cluster.Namespace, utils.EtcdClientPort),
-			}
+			endpoints = []string{k8s.MemberClientURL(cluster, 0)}
 		}
 	} else {
 		// 运行在集群外部，使用 NodePort 服务连接到第一个节点
@@ -570,11 +638,11 @@ func (s *scalingService) createEtcdClient(cluster *etcdv1alpha1.EtcdCluster) (*e
 			log.Log.Error(err, "Failed to get Kind node IP, falling back to localhost")
 			nodeIP = "localhost"
 		}
-		endpoints = []string{fmt.Sprintf("http://%s:30379", nodeIP)}
+		endpoints = []string{fmt.Sprintf("%s://%s:30379", k8s.ClientScheme(cluster), nodeIP)}
 	}
 
 	log.Log.Info("Creating etcd client", "endpoints", endpoints)
-	return etcdclient.NewClient(endpoints)
+	return newEtcdClient(ctx, s.k8sClient, cluster, endpoints)
 }
 
 // isRunningInCluster checks if the operator is running inside the cluster
@@ -0,0 +1,98 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+	"github.com/your-org/etcd-k8s-operator/pkg/k8s"
+	"github.com/your-org/etcd-k8s-operator/pkg/metrics"
+	"github.com/your-org/etcd-k8s-operator/pkg/utils"
+)
+
+// membersUseTLS 返回成员是否已经配置了证书
+func membersUseTLS(cluster *etcdv1alpha1.EtcdCluster) bool {
+	return k8s.ClientTLSEnabled(cluster) || k8s.PeerTLSEnabled(cluster) || k8s.TLSMigration(cluster) != ""
+}
+
+// migrateTLS 在 spec 要求的传输安全与成员当前的配置不一致时，分阶段迁移运行中的集群。
+// 每个阶段修改 Pod 模板后逐个重启成员，所有成员按新模板恢复后才进入下一阶段：
+//
+//	peer:   PeerTrust（挂载证书，仍按原协议通信）-> PeerSwitch（逐个更新 peer 地址并切换协议）
+//	client: ClientDual（同一端口同时提供 http 和 https）-> ClientSwitch（公布新协议的地址）
+//
+// 迁移结束后去掉不再需要的监听和证书。返回非 nil 的 Result 表示本轮调谐由迁移接管
+func (s *scalingService) migrateTLS(ctx context.Context, cluster *etcdv1alpha1.EtcdCluster, sts *appsv1.StatefulSet) (*ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	desired := k8s.DesiredTLSStatus(cluster)
+	if cluster.Status.TLS == nil {
+		if !desired.ClientTLS && !desired.PeerTLS {
+			return nil, nil
+		}
+		cluster.Status.TLS = &etcdv1alpha1.EtcdTLSStatus{}
+	}
+	current := cluster.Status.TLS
+
+	phase := current.Migration
+	switch phase {
+	case "":
+		if desired.PeerTLS != current.PeerTLS {
+			current.Migration = etcdv1alpha1.EtcdTLSMigrationPeerTrust
+		} else if desired.ClientTLS != current.ClientTLS {
+			current.Migration = etcdv1alpha1.EtcdTLSMigrationClientDual
+		} else {
+			return nil, nil
+		}
+	case etcdv1alpha1.EtcdTLSMigrationPeerTrust:
+		current.Migration = ""
+		if desired.PeerTLS != current.PeerTLS {
+			current.PeerTLS = desired.PeerTLS
+			current.Migration = etcdv1alpha1.EtcdTLSMigrationPeerSwitch
+		}
+	case etcdv1alpha1.EtcdTLSMigrationClientDual:
+		current.Migration = ""
+		if desired.ClientTLS != current.ClientTLS {
+			current.ClientTLS = desired.ClientTLS
+			current.Migration = etcdv1alpha1.EtcdTLSMigrationClientSwitch
+		}
+	default:
+		current.Migration = ""
+	}
+
+	if current.Migration == "" {
+		logger.Info("TLS migration completed", "clientTLS", current.ClientTLS, "peerTLS", current.PeerTLS)
+		if !k8s.TLSRequired(cluster) {
+			// 成员不再使用证书，清除证书记录，模板中的证书版本随本次重启一并去掉
+			current.Revision = ""
+			current.Certificates = nil
+			metrics.SetCertificateExpiry(cluster, nil)
+		}
+	} else {
+		logger.Info("Advancing TLS migration", "from", phase, "to", current.Migration)
+	}
+	setTLSMigrationCondition(cluster)
+	if err := s.startRoll(ctx, cluster, sts); err != nil {
+		return nil, err
+	}
+	return &ctrl.Result{RequeueAfter: utils.DefaultRequeueInterval}, nil
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2025 ETCD Operator Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+	"github.com/your-org/etcd-k8s-operator/pkg/k8s"
+	"github.com/your-org/etcd-k8s-operator/pkg/utils"
+)
+
+// TestMigrateTLS 测试明文集群启用 TLS 时先迁移 peer 再迁移客户端，每个阶段都重启全部成员
+func TestMigrateTLS(t *testing.T) {
+	c, svc, cluster := newRotationFixture(t)
+	ctx := context.Background()
+
+	// 集群以明文创建，之后在 spec 中启用 TLS
+	cluster.Status.TLS = &etcdv1alpha1.EtcdTLSStatus{}
+	require.NoError(t, c.Status().Update(ctx, cluster))
+	sts := &appsv1.StatefulSet{}
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Name: "secure", Namespace: "default"}, sts))
+	sts.Spec = k8s.BuildStatefulSet(cluster, k8s.StatefulSetOptions{}).Spec
+	require.NoError(t, c.Update(ctx, sts))
+
+	steps := []struct {
+		phase      etcdv1alpha1.EtcdTLSMigrationPhase
+		clientTLS  bool
+		peerTLS    bool
+		restarting bool
+	}{
+		{phase: etcdv1alpha1.EtcdTLSMigrationPeerTrust, restarting: true},
+		{phase: etcdv1alpha1.EtcdTLSMigrationPeerSwitch, peerTLS: true, restarting: true},
+		{phase: "", peerTLS: true},
+		{phase: etcdv1alpha1.EtcdTLSMigrationClientDual, peerTLS: true, restarting: true},
+		{phase: etcdv1alpha1.EtcdTLSMigrationClientSwitch, clientTLS: true, peerTLS: true, restarting: true},
+		{phase: "", clientTLS: true, peerTLS: true, restarting: true},
+	}
+	for _, step := range steps {
+		require.NoError(t, c.Get(ctx, types.NamespacedName{Name: "secure", Namespace: "default"}, sts))
+		result, err := svc.migrateTLS(ctx, cluster, sts)
+		require.NoError(t, err)
+		require.NotNil(t, result)
+
+		assert.Equal(t, step.phase, cluster.Status.TLS.Migration)
+		assert.Equal(t, step.clientTLS, cluster.Status.TLS.ClientTLS)
+		assert.Equal(t, step.peerTLS, cluster.Status.TLS.PeerTLS)
+
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, utils.ConditionTypeTLSMigration)
+		require.NotNil(t, condition)
+		if step.phase == "" {
+			assert.Equal(t, metav1.ConditionFalse, condition.Status)
+			assert.Equal(t, utils.ReasonTLSMigrationCompleted, condition.Reason)
+		} else {
+			assert.Equal(t, metav1.ConditionTrue, condition.Status)
+			assert.Equal(t, string(step.phase), condition.Reason)
+		}
+
+		// 模板变化时用分区挡住全部成员；随后模拟所有成员已按新模板重启
+		require.NoError(t, c.Get(ctx, types.NamespacedName{Name: "secure", Namespace: "default"}, sts))
+		assert.Equal(t, step.restarting, rollPartition(sts) == 3, "phase %q", step.phase)
+		assert.Equal(t, k8s.BuildStatefulSet(cluster, k8s.StatefulSetOptions{}).Spec.Template.Spec.InitContainers, sts.Spec.Template.Spec.InitContainers)
+		sts.Spec.UpdateStrategy.RollingUpdate = nil
+		require.NoError(t, c.Update(ctx, sts))
+	}
+
+	// 成员配置与 spec 一致后不再迁移
+	result, err := svc.migrateTLS(ctx, cluster, sts)
+	require.NoError(t, err)
+	assert.Nil(t, result)
+}
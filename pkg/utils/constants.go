@@ -28,6 +28,9 @@ const (
 	// DefaultEtcdRepository is the default etcd image repository (官方镜像)
 	DefaultEtcdRepository = "quay.io/coreos/etcd"
 
+	// EtcdBootstrapCommand is the path of the etcd-bootstrap binary in the operator image
+	EtcdBootstrapCommand = "/etcd-bootstrap"
+
 	// DefaultClusterSize is the default cluster size
 	DefaultClusterSize = 3
 
@@ -52,8 +55,99 @@ const (
 	// DefaultHealthCheckInterval is the default health check interval
 	DefaultHealthCheckInterval = 5 * time.Minute
 
+	// DefaultMemberReplaceTimeout is how long a member may stay unreachable while quorum is intact before it is replaced
+	DefaultMemberReplaceTimeout = 15 * time.Minute
+
+	// DefaultQuorumLossTimeout is how long the cluster may stay without a quorum of healthy members before it is marked Failed
+	DefaultQuorumLossTimeout = 5 * time.Minute
+
+	// DefaultMaxRaftIndexLag is how far a member's raft index may trail the most advanced member before it is reported as lagging
+	DefaultMaxRaftIndexLag = 5000
+
+	// DefaultQuotaBackendBytes is etcd's default backend quota, used to report members whose database nears it
+	DefaultQuotaBackendBytes = 2 * 1024 * 1024 * 1024
+
+	// MaxQuotaBackendBytes is etcd's suggested maximum backend quota, the limit for growing the quota after a NOSPACE alarm
+	MaxQuotaBackendBytes = 8 * 1024 * 1024 * 1024
+
 	// DefaultReconcileTimeout is the default reconcile timeout
 	DefaultReconcileTimeout = 10 * time.Minute
+
+	// DefaultUpgradeMemberTimeout is how long an upgraded member may take to rejoin the cluster before the upgrade halts
+	DefaultUpgradeMemberTimeout = 10 * time.Minute
+
+	// DefaultLearnerTimeout is how long a member added as a learner may take to catch up with the leader
+	DefaultLearnerTimeout = 10 * time.Minute
+
+	// DefaultBackupDir is the directory where the operator's backup PVC is mounted
+	DefaultBackupDir = "/var/lib/etcd-backups"
+
+	// DefaultBackupTimeout is the maximum time allowed for a single snapshot
+	DefaultBackupTimeout = 30 * time.Minute
+
+	// DefaultMissedRunGracePeriod is how late a scheduled run may start before it counts as missed
+	DefaultMissedRunGracePeriod = 2 * time.Minute
+
+	// MaxMissedSchedules is how many missed cron ticks are walked before giving up on the schedule history
+	MaxMissedSchedules = 100
+
+	// DefaultScheduleStartingDeadline is how far back a schedule is searched once more than MaxMissedSchedules ticks were missed
+	DefaultScheduleStartingDeadline = time.Hour
+
+	// DefaultSnapshotServerAddress is the address the operator serves staged restore snapshots on
+	DefaultSnapshotServerAddress = ":8082"
+
+	// DefaultSnapshotServerURL is the in-cluster URL restore jobs download staged snapshots from
+	DefaultSnapshotServerURL = "http://etcd-k8s-operator-snapshot-server.etcd-k8s-operator-system.svc:8082"
+
+	// DefaultRestoreJobBackoffLimit is the number of retries for a member seed job
+	DefaultRestoreJobBackoffLimit = 3
+
+	// MaxClusterSize is the largest cluster size accepted by the EtcdCluster CRD
+	MaxClusterSize = 9
+
+	// EtcdTLSDir is the directory certificate secrets are mounted under in etcd pods
+	EtcdTLSDir = "/etc/etcd-tls"
+
+	// DefaultCAValidity is the validity of a CA generated for AutoTLS
+	DefaultCAValidity = 10 * 365 * 24 * time.Hour
+
+	// DefaultCertificateValidity is the validity of peer, server and client certificates generated for AutoTLS
+	DefaultCertificateValidity = 365 * 24 * time.Hour
+
+	// DefaultCertificateRenewBefore is how long before expiry generated certificates are reissued
+	DefaultCertificateRenewBefore = 30 * 24 * time.Hour
+)
+
+// Secret data keys
+const (
+	// SecretKeyS3AccessKey is the key holding the S3 access key in the secret referenced by AccessKeySecret
+	SecretKeyS3AccessKey = "accessKey"
+
+	// SecretKeyS3SecretKey is the key holding the S3 secret key in the secret referenced by SecretKeySecret
+	SecretKeyS3SecretKey = "secretKey"
+
+	// SecretKeyGCSCredentials is the key holding the service account key in the secret referenced by CredentialsSecret
+	SecretKeyGCSCredentials = "credentials.json"
+
+	// SecretKeyCACert is the key holding the PEM encoded CA bundle in a certificate secret
+	SecretKeyCACert = "ca.crt"
+
+	// SecretKeyRestoreToken is the key holding the snapshot download token in a restore's token secret
+	SecretKeyRestoreToken = "token"
+
+	// SecretKeyRestoreSourceToken is the key holding the password of a PVC snapshot source pod in a restore's token secret
+	SecretKeyRestoreSourceToken = "sourceToken"
+)
+
+// ConfigMap data keys
+const (
+	// ConfigKeyForceNewCluster is the key naming the member the init container starts with --force-new-cluster
+	ConfigKeyForceNewCluster = "force-new-cluster"
+
+	// ConfigKeyInitialClusterState is the key telling the init container that member 0 may start a new cluster.
+	// It is only present while the cluster is being created and no member has joined yet
+	ConfigKeyInitialClusterState = "initial-cluster-state"
 )
 
 // 标签键定义
@@ -78,6 +172,12 @@ const (
 
 	// LabelEtcdMember is the label key for etcd member
 	LabelEtcdMember = "etcd.etcd.io/member"
+
+	// LabelBackupSchedule is the label key linking a scheduled run to its parent EtcdBackup
+	LabelBackupSchedule = "etcd.etcd.io/backup-schedule"
+
+	// LabelEtcdRestore is the label key linking seed jobs to their EtcdRestore
+	LabelEtcdRestore = "etcd.etcd.io/restore"
 )
 
 // Annotation keys
@@ -90,6 +190,15 @@ const (
 
 	// AnnotationClusterID is the annotation key for cluster ID
 	AnnotationClusterID = "etcd.etcd.io/cluster-id"
+
+	// AnnotationRestoredFrom marks clusters and PVCs seeded by an EtcdRestore, the value is the restore name
+	AnnotationRestoredFrom = "etcd.etcd.io/restored-from"
+
+	// AnnotationPaused pauses cluster reconciliation while set, the value names the owner of the pause
+	AnnotationPaused = "etcd.etcd.io/paused"
+
+	// AnnotationTLSRevision carries the certificate revision on the pod template, changing it restarts the members
+	AnnotationTLSRevision = "etcd.etcd.io/tls-revision"
 )
 
 // Condition types
@@ -105,6 +214,24 @@ const (
 
 	// ConditionTypeAvailable indicates whether the cluster is available
 	ConditionTypeAvailable = "Available"
+
+	// ConditionTypeRetentionApplied indicates whether the backup retention policy was enforced successfully
+	ConditionTypeRetentionApplied = "RetentionApplied"
+
+	// ConditionTypeVerified indicates whether the stored backup snapshot passed integrity verification
+	ConditionTypeVerified = "Verified"
+
+	// ConditionTypeTLSReady indicates whether the certificates the cluster members need are available and valid
+	ConditionTypeTLSReady = "TLSReady"
+
+	// ConditionTypeTLSMigration indicates whether the members are being migrated between plaintext and TLS
+	ConditionTypeTLSMigration = "TLSMigration"
+
+	// ConditionTypeVersionUpgrade indicates whether the members are being upgraded to the version in the spec
+	ConditionTypeVersionUpgrade = "VersionUpgrade"
+
+	// ConditionTypeConfigApplied indicates whether the etcd configuration in the spec was accepted for the members
+	ConditionTypeConfigApplied = "ConfigApplied"
 )
 
 // Condition reasons
@@ -133,8 +260,116 @@ const (
 	// ReasonUnhealthy indicates the cluster is unhealthy
 	ReasonUnhealthy = "Unhealthy"
 
+	// ReasonQuorumAvailable indicates a quorum of healthy members agrees on a leader
+	ReasonQuorumAvailable = "QuorumAvailable"
+
+	// ReasonQuorumLost indicates fewer than a quorum of members are healthy or there is no leader
+	ReasonQuorumLost = "QuorumLost"
+
 	// ReasonStopped indicates the cluster is stopped
 	ReasonStopped = "Stopped"
+
+	// ReasonBackupInProgress indicates the backup is in progress
+	ReasonBackupInProgress = "BackupInProgress"
+
+	// ReasonBackupCompleted indicates the backup has completed
+	ReasonBackupCompleted = "BackupCompleted"
+
+	// ReasonBackupFailed indicates the backup has failed
+	ReasonBackupFailed = "BackupFailed"
+
+	// ReasonScheduled indicates the backup schedule is active
+	ReasonScheduled = "Scheduled"
+
+	// ReasonInvalidSchedule indicates the backup schedule cannot be parsed
+	ReasonInvalidSchedule = "InvalidSchedule"
+
+	// ReasonPruned indicates expired backups were pruned
+	ReasonPruned = "Pruned"
+
+	// ReasonPruneFailed indicates expired backups could not be pruned
+	ReasonPruneFailed = "PruneFailed"
+
+	// ReasonSnapshotVerified indicates the stored snapshot passed integrity verification
+	ReasonSnapshotVerified = "SnapshotVerified"
+
+	// ReasonVerificationFailed indicates the stored snapshot failed integrity verification
+	ReasonVerificationFailed = "VerificationFailed"
+
+	// ReasonRestoreInProgress indicates the restore is in progress
+	ReasonRestoreInProgress = "RestoreInProgress"
+
+	// ReasonRestoreCompleted indicates the restore has completed
+	ReasonRestoreCompleted = "RestoreCompleted"
+
+	// ReasonRestoreFailed indicates the restore has failed
+	ReasonRestoreFailed = "RestoreFailed"
+
+	// ReasonClusterStopping indicates the cluster members are being stopped before their data is replaced
+	ReasonClusterStopping = "ClusterStopping"
+
+	// ReasonClusterRestarting indicates the cluster members are restarting on restored data
+	ReasonClusterRestarting = "ClusterRestarting"
+
+	// ReasonWaitingForBackup indicates the source backup has not completed yet
+	ReasonWaitingForBackup = "WaitingForBackup"
+
+	// ReasonWaitingForSource indicates the pod reading a PVC snapshot source is not ready yet
+	ReasonWaitingForSource = "WaitingForSource"
+
+	// ReasonWaitingForCluster indicates the target cluster is not ready yet
+	ReasonWaitingForCluster = "WaitingForCluster"
+
+	// ReasonCertificatesReady indicates the cluster certificates are available and valid
+	ReasonCertificatesReady = "CertificatesReady"
+
+	// ReasonInvalidCertificate indicates the user provided certificates are missing or do not satisfy the cluster
+	ReasonInvalidCertificate = "InvalidCertificate"
+
+	// ReasonCertificateRotation indicates the members are being restarted to load renewed certificates
+	ReasonCertificateRotation = "CertificateRotation"
+
+	// ReasonTLSMigrationCompleted indicates the members serve with the transport security the spec requests
+	ReasonTLSMigrationCompleted = "TLSMigrationCompleted"
+
+	// ReasonUpgradeCompleted indicates all members run the version the spec requests
+	ReasonUpgradeCompleted = "UpgradeCompleted"
+
+	// ReasonUnsupportedUpgrade indicates the version change in the spec is not a supported upgrade
+	ReasonUnsupportedUpgrade = "UnsupportedUpgrade"
+
+	// ReasonConfigApplied indicates the etcd configuration in the spec was recorded and the members restart with it
+	ReasonConfigApplied = "ConfigApplied"
+
+	// ReasonInvalidConfig indicates the etcd configuration in the spec was rejected and members keep the previous one
+	ReasonInvalidConfig = "InvalidConfig"
+
+	// ReasonMissingBackupTemplate indicates there is no EtcdBackup to take the pre-upgrade backup with
+	ReasonMissingBackupTemplate = "MissingBackupTemplate"
+
+	// ReasonDowngrading indicates the members are being downgraded through the etcd downgrade API
+	ReasonDowngrading = "Downgrading"
+
+	// ReasonDowngradeCompleted indicates all members run the lower version the spec requests
+	ReasonDowngradeCompleted = "DowngradeCompleted"
+
+	// ReasonDowngradeRejected indicates etcd refused to enable the downgrade
+	ReasonDowngradeRejected = "DowngradeRejected"
+
+	// ReasonUpgradeHalted indicates an upgraded member did not rejoin the cluster and the upgrade stopped
+	ReasonUpgradeHalted = "UpgradeHalted"
+
+	// ReasonRecoveryInProgress indicates the cluster is being recovered after it lost quorum
+	ReasonRecoveryInProgress = "RecoveryInProgress"
+
+	// ReasonRecoveryFailed indicates the recovery policy cannot recover the cluster
+	ReasonRecoveryFailed = "RecoveryFailed"
+
+	// ReasonLearnerStalled indicates a member added as a learner did not catch up with the leader in time
+	ReasonLearnerStalled = "LearnerStalled"
+
+	// ReasonNoSpace indicates a member database exceeded the backend quota and etcd raised a NOSPACE alarm
+	ReasonNoSpace = "NoSpace"
 )
 
 // Event reasons
@@ -160,12 +395,57 @@ const (
 	// EventReasonMemberRemoved indicates member removed event
 	EventReasonMemberRemoved = "MemberRemoved"
 
+	// EventReasonMemberReplaced indicates a step of replacing a failed member with a fresh one
+	EventReasonMemberReplaced = "MemberReplaced"
+
+	// EventReasonClusterRecovery indicates a step of recovering a cluster that lost quorum
+	EventReasonClusterRecovery = "ClusterRecovery"
+
+	// EventReasonMemberDefragmented indicates a step of defragmenting the member databases
+	EventReasonMemberDefragmented = "MemberDefragmented"
+
+	// EventReasonDefragFailed indicates a member could not be defragmented
+	EventReasonDefragFailed = "DefragFailed"
+
+	// EventReasonDefragSkipped indicates a member was left fragmented because it could not be defragmented safely
+	EventReasonDefragSkipped = "DefragSkipped"
+
+	// EventReasonNoSpaceAlarm indicates a NOSPACE alarm was raised, remediated or cleared
+	EventReasonNoSpaceAlarm = "NoSpaceAlarm"
+
 	// EventReasonBackupCreated indicates backup created event
 	EventReasonBackupCreated = "BackupCreated"
 
 	// EventReasonBackupFailed indicates backup failed event
 	EventReasonBackupFailed = "BackupFailed"
 
+	// EventReasonBackupScheduled indicates a scheduled backup run was started
+	EventReasonBackupScheduled = "BackupScheduled"
+
+	// EventReasonBackupSkipped indicates a scheduled backup run was skipped
+	EventReasonBackupSkipped = "BackupSkipped"
+
+	// EventReasonTooManyMissedSchedules indicates a schedule missed too many runs and only recent ones are considered
+	EventReasonTooManyMissedSchedules = "TooManyMissedSchedules"
+
+	// EventReasonBackupReplaced indicates a running backup was replaced by a new scheduled run
+	EventReasonBackupReplaced = "BackupReplaced"
+
+	// EventReasonBackupPruned indicates expired backups were pruned
+	EventReasonBackupPruned = "BackupPruned"
+
+	// EventReasonBackupPruneFailed indicates expired backups could not be pruned
+	EventReasonBackupPruneFailed = "BackupPruneFailed"
+
+	// EventReasonClusterRestored indicates a cluster was restored from a snapshot
+	EventReasonClusterRestored = "ClusterRestored"
+
+	// EventReasonInvalidCertificate indicates the user provided certificates were rejected
+	EventReasonInvalidCertificate = "InvalidCertificate"
+
+	// EventReasonRestoreFailed indicates restore failed event
+	EventReasonRestoreFailed = "RestoreFailed"
+
 	// EventReasonClusterStopped indicates cluster stopped event
 	EventReasonClusterStopped = "ClusterStopped"
 )
@@ -37,6 +37,7 @@ import (
 
 	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
 	controller "github.com/your-org/etcd-k8s-operator/internal/controller"
+	"github.com/your-org/etcd-k8s-operator/pkg/k8s"
 )
 
 // IntegrationTestSuite 集成测试套件
@@ -76,6 +77,7 @@ func (suite *IntegrationTestSuite) SetupSuite() {
 		suite.k8sClient,
 		suite.scheme,
 		nil, // EventRecorder在集成测试中可以为nil
+		k8s.StatefulSetOptions{},
 	)
 
 	suite.namespace = "integration-test"
@@ -32,6 +32,7 @@ import (
 
 	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
 	controller "github.com/your-org/etcd-k8s-operator/internal/controller"
+	"github.com/your-org/etcd-k8s-operator/pkg/k8s"
 )
 
 // TestSimpleClusterReconcile 简化的集成测试
@@ -72,6 +73,7 @@ func TestSimpleClusterReconcile(t *testing.T) {
 		fakeClient,
 		testScheme,
 		nil, // EventRecorder可以为nil
+		k8s.StatefulSetOptions{},
 	)
 
 	// 5. 准备Reconcile请求
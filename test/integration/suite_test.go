@@ -38,6 +38,7 @@ import (
 
 	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
 	"github.com/your-org/etcd-k8s-operator/internal/controller"
+	"github.com/your-org/etcd-k8s-operator/pkg/k8s"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -111,16 +112,22 @@ var _ = BeforeSuite(func() {
 	}).SetupWithManager(k8sManager)
 	Expect(err).ToNot(HaveOccurred())
 
-	err = (&controller.EtcdBackupReconciler{
-		Client: k8sManager.GetClient(),
-		Scheme: k8sManager.GetScheme(),
-	}).SetupWithManager(k8sManager)
+	err = controller.NewEtcdBackupReconciler(
+		k8sManager.GetClient(),
+		k8sManager.GetScheme(),
+		k8sManager.GetEventRecorderFor("etcdbackup-controller"),
+		GinkgoT().TempDir(),
+	).SetupWithManager(k8sManager)
 	Expect(err).ToNot(HaveOccurred())
 
-	err = (&controller.EtcdRestoreReconciler{
-		Client: k8sManager.GetClient(),
-		Scheme: k8sManager.GetScheme(),
-	}).SetupWithManager(k8sManager)
+	err = controller.NewEtcdRestoreReconciler(
+		k8sManager.GetClient(),
+		k8sManager.GetScheme(),
+		k8sManager.GetEventRecorderFor("etcdrestore-controller"),
+		GinkgoT().TempDir(),
+		"http://127.0.0.1:8082",
+		k8s.StatefulSetOptions{},
+	).SetupWithManager(k8sManager)
 	Expect(err).ToNot(HaveOccurred())
 
 	// 7. 在后台启动 Manager
@@ -30,6 +30,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+	"github.com/your-org/etcd-k8s-operator/pkg/k8s"
 	resourcepkg "github.com/your-org/etcd-k8s-operator/pkg/resource"
 	"github.com/your-org/etcd-k8s-operator/pkg/service"
 	"github.com/your-org/etcd-k8s-operator/test/unit/mocks"
@@ -509,7 +510,7 @@ func TestStatefulSetManager_GetStatus(t *testing.T) {
 			mockK8sClient := &mocks.MockKubernetesClient{}
 			tt.mockSetup(mockK8sClient)
 
-			manager := resourcepkg.NewStatefulSetManager(mockK8sClient)
+			manager := resourcepkg.NewStatefulSetManager(mockK8sClient, k8s.StatefulSetOptions{})
 
 			// Act: 获取状态
 			status, err := manager.GetStatus(context.Background(), tt.cluster)
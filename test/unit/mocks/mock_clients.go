@@ -135,6 +135,11 @@ func (m *MockResourceManager) PVC() resource.PVCManager {
 	return args.Get(0).(resource.PVCManager)
 }
 
+func (m *MockResourceManager) Certificate() resource.CertificateManager {
+	args := m.Called()
+	return args.Get(0).(resource.CertificateManager)
+}
+
 // MockStatefulSetManager StatefulSet 管理器 Mock
 type MockStatefulSetManager struct {
 	mock.Mock
@@ -178,6 +183,11 @@ func (m *MockStatefulSetManager) NeedsUpdate(existing, desired *appsv1.StatefulS
 	return args.Bool(0)
 }
 
+func (m *MockStatefulSetManager) Desired(cluster *etcdv1alpha1.EtcdCluster, replicas int32) *appsv1.StatefulSet {
+	args := m.Called(cluster, replicas)
+	return args.Get(0).(*appsv1.StatefulSet)
+}
+
 // MockEtcdClient etcd 客户端 Mock
 type MockEtcdClient struct {
 	mock.Mock
@@ -29,6 +29,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+	"github.com/your-org/etcd-k8s-operator/pkg/k8s"
 	resourcepkg "github.com/your-org/etcd-k8s-operator/pkg/resource"
 	"github.com/your-org/etcd-k8s-operator/test/unit/mocks"
 )
@@ -134,7 +135,7 @@ func TestResourceManager_EnsureAllResources(t *testing.T) {
 			mockClient := &mocks.MockKubernetesClient{}
 			tt.mockSetup(mockClient)
 
-			manager := resourcepkg.NewResourceManager(mockClient)
+			manager := resourcepkg.NewResourceManager(mockClient, k8s.StatefulSetOptions{})
 
 			// Act: 执行测试
 			err := manager.EnsureAllResources(context.Background(), tt.cluster)
@@ -243,7 +244,7 @@ func TestResourceManager_CleanupResources(t *testing.T) {
 			mockClient := &mocks.MockKubernetesClient{}
 			tt.mockSetup(mockClient)
 
-			manager := resourcepkg.NewResourceManager(mockClient)
+			manager := resourcepkg.NewResourceManager(mockClient, k8s.StatefulSetOptions{})
 
 			// Act: 执行测试
 			err := manager.CleanupResources(context.Background(), tt.cluster)
@@ -32,6 +32,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	etcdv1alpha1 "github.com/your-org/etcd-k8s-operator/api/v1alpha1"
+	"github.com/your-org/etcd-k8s-operator/pkg/k8s"
 	resourcepkg "github.com/your-org/etcd-k8s-operator/pkg/resource"
 	"github.com/your-org/etcd-k8s-operator/test/unit/mocks"
 )
@@ -176,7 +177,7 @@ func TestStatefulSetManager_Ensure(t *testing.T) {
 			mockClient := &mocks.MockKubernetesClient{}
 			tt.mockSetup(mockClient)
 
-			manager := resourcepkg.NewStatefulSetManager(mockClient)
+			manager := resourcepkg.NewStatefulSetManager(mockClient, k8s.StatefulSetOptions{})
 
 			// Act: 执行测试
 			err := manager.Ensure(context.Background(), tt.cluster)
@@ -258,7 +259,7 @@ func TestStatefulSetManager_EnsureWithReplicas(t *testing.T) {
 			mockClient := &mocks.MockKubernetesClient{}
 			tt.mockSetup(mockClient)
 
-			manager := resourcepkg.NewStatefulSetManager(mockClient)
+			manager := resourcepkg.NewStatefulSetManager(mockClient, k8s.StatefulSetOptions{})
 
 			// Act: 执行测试
 			err := manager.EnsureWithReplicas(context.Background(), tt.cluster, tt.replicas)
@@ -340,7 +341,7 @@ func TestStatefulSetManager_GetStatus(t *testing.T) {
 			mockClient := &mocks.MockKubernetesClient{}
 			tt.mockSetup(mockClient)
 
-			manager := resourcepkg.NewStatefulSetManager(mockClient)
+			manager := resourcepkg.NewStatefulSetManager(mockClient, k8s.StatefulSetOptions{})
 
 			// Act: 执行测试
 			status, err := manager.GetStatus(context.Background(), tt.cluster)